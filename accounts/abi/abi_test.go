@@ -560,6 +560,46 @@ func TestDefaultFunctionParsing(t *testing.T) {
 	}
 }
 
+func TestConstStateMutability(t *testing.T) {
+	const definition = `[
+	{ "type" : "function", "name" : "legacyConst", "constant" : true },
+	{ "type" : "function", "name" : "legacyNonConst", "constant" : false },
+	{ "type" : "function", "name" : "view", "stateMutability" : "view" },
+	{ "type" : "function", "name" : "pure", "stateMutability" : "pure" },
+	{ "type" : "function", "name" : "nonpayable", "stateMutability" : "nonpayable" },
+	{ "type" : "function", "name" : "payable", "stateMutability" : "payable" },
+	{ "type" : "function", "name" : "viewNotConstant", "constant" : false, "stateMutability" : "view" },
+	{ "type" : "function", "name" : "plain" }
+	]`
+
+	abi, err := JSON(strings.NewReader(definition))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]bool{
+		"legacyConst":     true,
+		"legacyNonConst":  false,
+		"view":            true,
+		"pure":            true,
+		"nonpayable":      false,
+		"payable":         false,
+		"viewNotConstant": true,
+		"plain":           false,
+	}
+
+	for name, isConst := range expected {
+		method, ok := abi.Methods[name]
+		if !ok {
+			t.Fatalf("expected '%s' to be present", name)
+		}
+
+		if method.Const != isConst {
+			t.Errorf("method '%s': expected const %v, got %v", name, isConst, method.Const)
+		}
+	}
+}
+
 func TestBareEvents(t *testing.T) {
 	const definition = `[
 	{ "type" : "event", "name" : "balance" },
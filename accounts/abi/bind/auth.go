@@ -0,0 +1,33 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package bind
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/seeleteam/go-seele/common/keystore"
+	"github.com/seeleteam/go-seele/crypto"
+)
+
+// NewKeyedTransactor is a utility method to easily create a transaction signer
+// from a single private key.
+func NewKeyedTransactor(key *ecdsa.PrivateKey) *TransactOpts {
+	return &TransactOpts{
+		From:       *crypto.GetAddress(&key.PublicKey),
+		PrivateKey: key,
+	}
+}
+
+// NewKeyFileTransactor is a utility method to easily create a transaction signer
+// from an encrypted key file of the client.
+func NewKeyFileTransactor(fileName, password string) (*TransactOpts, error) {
+	key, err := keystore.GetKey(fileName, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyedTransactor(key.PrivateKey), nil
+}
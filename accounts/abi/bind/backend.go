@@ -0,0 +1,103 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package bind
+
+import (
+	"context"
+	"errors"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/core/types"
+)
+
+var (
+	// ErrNoCode is returned by call operations for which the requested contract
+	// returns nothing, i.e. there is no contract code deployed at the address.
+	ErrNoCode = errors.New("no contract code at given address")
+
+	// ErrNoReceipt is returned by the receipt query if the transaction is not packed yet.
+	ErrNoReceipt = errors.New("receipt not found")
+)
+
+// ContractCaller defines the methods needed to allow operating with contract on a read only basis.
+type ContractCaller interface {
+	// CallContract executes a read-only contract call on the state of the specified
+	// height, when height is -1 the chain head is used.
+	CallContract(ctx context.Context, contract common.Address, payload []byte, height int64) ([]byte, error)
+}
+
+// ContractTransactor defines the methods needed to allow operating with contract
+// on a write only basis.
+type ContractTransactor interface {
+	// AccountNonce returns the next nonce of the account at the chain head.
+	AccountNonce(ctx context.Context, account common.Address) (uint64, error)
+
+	// EstimateGas returns the gas needed to execute the transaction on the chain head.
+	EstimateGas(ctx context.Context, tx *types.Transaction) (uint64, error)
+
+	// SendTransaction injects the signed transaction into the pending pool for execution.
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// FilterQuery is the query of the contract event logs.
+type FilterQuery struct {
+	FromHeight uint64
+	ToHeight   *uint64 // nil means the chain head
+
+	Contract common.Address
+	ABI      string // the contract abi json, used to decode the event logs
+	Event    string
+
+	// Topics are the topics to match at each position, the empty position matches any topic.
+	// The first position is the event id, and the others are the indexed arguments.
+	Topics [][]common.Hash
+}
+
+// ContractFilterer defines the methods needed to access the contract event logs.
+type ContractFilterer interface {
+	// FilterLogs returns the logs of the contract event that match the query.
+	FilterLogs(ctx context.Context, query FilterQuery) ([]*types.Log, error)
+}
+
+// DeployBackend wraps the operations needed by WaitMined and WaitDeployed.
+type DeployBackend interface {
+	// TransactionReceipt returns the receipt of the packed transaction, or ErrNoReceipt if not packed yet.
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// ContractBackend defines the methods needed to work with contracts on a read-write basis.
+type ContractBackend interface {
+	ContractCaller
+	ContractTransactor
+	ContractFilterer
+}
+
+// MatchTopics returns true if the log topics match the query topics.
+func MatchTopics(log *types.Log, topics [][]common.Hash) bool {
+	if len(topics) > len(log.Topics) {
+		return false
+	}
+
+	for i, sub := range topics {
+		if len(sub) == 0 {
+			continue
+		}
+
+		matched := false
+		for _, topic := range sub {
+			if topic.Equal(log.Topics[i]) {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,184 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package backends
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/seeleteam/go-seele/accounts/abi/bind"
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/rpc"
+)
+
+// logsBatchSize is the number of heights to query the logs in a batch request
+const logsBatchSize = 100
+
+// This nil assignment ensures compile time that RPCBackend implements bind.ContractBackend.
+var _ bind.ContractBackend = (*RPCBackend)(nil)
+
+// RPCBackend implements the contract backend over the rpc client of a Seele node.
+type RPCBackend struct {
+	client *rpc.Client
+}
+
+// NewRPCBackend creates a contract backend with the rpc client.
+func NewRPCBackend(client *rpc.Client) *RPCBackend {
+	return &RPCBackend{client}
+}
+
+// callResult is the result of the seele_call request
+type callResult struct {
+	Result string `json:"result"`
+	Failed bool   `json:"failed"`
+}
+
+// CallContract executes the contract call by seele_call.
+func (b *RPCBackend) CallContract(ctx context.Context, contract common.Address, payload []byte, height int64) ([]byte, error) {
+	var result callResult
+	if err := b.client.CallContext(ctx, &result, "seele_call", contract.Hex(), hexutil.BytesToHex(payload), height); err != nil {
+		return nil, err
+	}
+
+	if result.Failed {
+		return nil, fmt.Errorf("contract call failed: %s", result.Result)
+	}
+
+	return hexutil.HexToBytes(result.Result)
+}
+
+// AccountNonce returns the account nonce of the chain head by seele_getAccountNonce.
+// Note, the transactions in the pool are not counted.
+func (b *RPCBackend) AccountNonce(ctx context.Context, account common.Address) (uint64, error) {
+	var nonce uint64
+	err := b.client.CallContext(ctx, &nonce, "seele_getAccountNonce", account, "", -1)
+
+	return nonce, err
+}
+
+// EstimateGas estimates the gas of the transaction by seele_estimateGas.
+func (b *RPCBackend) EstimateGas(ctx context.Context, tx *types.Transaction) (uint64, error) {
+	var gas uint64
+	err := b.client.CallContext(ctx, &gas, "seele_estimateGas", tx)
+
+	return gas, err
+}
+
+// SendTransaction adds the signed transaction into the pool by seele_addTx.
+func (b *RPCBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	var added bool
+	if err := b.client.CallContext(ctx, &added, "seele_addTx", *tx); err != nil {
+		return err
+	}
+
+	if !added {
+		return fmt.Errorf("failed to add tx %v", tx.Hash.Hex())
+	}
+
+	return nil
+}
+
+// FilterLogs queries the logs height by height with seele_getLogs in batch requests.
+func (b *RPCBackend) FilterLogs(ctx context.Context, query bind.FilterQuery) ([]*types.Log, error) {
+	var to uint64
+	if query.ToHeight != nil {
+		to = *query.ToHeight
+	} else if err := b.client.CallContext(ctx, &to, "seele_getHeight"); err != nil {
+		return nil, err
+	}
+
+	var logs []*types.Log
+	for from := query.FromHeight; from <= to; from += logsBatchSize {
+		end := from + logsBatchSize - 1
+		if end > to {
+			end = to
+		}
+
+		batch := make([]rpc.BatchElem, 0, end-from+1)
+		results := make([][]*types.Log, end-from+1)
+		for height := from; height <= end; height++ {
+			batch = append(batch, rpc.BatchElem{
+				Method: "seele_getLogs",
+				Args:   []interface{}{height, query.Contract, query.ABI, query.Event},
+				Result: &results[height-from],
+			})
+		}
+
+		if err := b.client.BatchCallContext(ctx, batch); err != nil {
+			return nil, err
+		}
+
+		for i, elem := range batch {
+			if elem.Error != nil {
+				return nil, fmt.Errorf("failed to get logs at height %d, %s", from+uint64(i), elem.Error)
+			}
+
+			for _, log := range results[i] {
+				if bind.MatchTopics(log, query.Topics) {
+					logs = append(logs, log)
+				}
+			}
+		}
+	}
+
+	return logs, nil
+}
+
+// rpcReceipt is the receipt returned by txpool_getReceiptByTxHash
+type rpcReceipt struct {
+	Result    string       `json:"result"`
+	PostState common.Hash  `json:"poststate"`
+	TxHash    common.Hash  `json:"txhash"`
+	Contract  string       `json:"contract"`
+	Failed    bool         `json:"failed"`
+	UsedGas   uint64       `json:"usedGas"`
+	TotalFee  uint64       `json:"totalFee"`
+	Logs      []*types.Log `json:"logs"`
+}
+
+// TransactionReceipt returns the receipt of the packed transaction by txpool_getReceiptByTxHash.
+func (b *RPCBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	var r *rpcReceipt
+	if err := b.client.CallContext(ctx, &r, "txpool_getReceiptByTxHash", txHash.Hex(), ""); err != nil {
+		return nil, err
+	}
+
+	if r == nil {
+		return nil, bind.ErrNoReceipt
+	}
+
+	receipt := &types.Receipt{
+		Failed:    r.Failed,
+		UsedGas:   r.UsedGas,
+		PostState: r.PostState,
+		Logs:      r.Logs,
+		TxHash:    r.TxHash,
+		TotalFee:  r.TotalFee,
+	}
+
+	if r.Failed {
+		receipt.Result = []byte(r.Result)
+	} else {
+		result, err := hexutil.HexToBytes(r.Result)
+		if err != nil {
+			return nil, err
+		}
+		receipt.Result = result
+	}
+
+	if r.Contract != "" && r.Contract != "0x" {
+		contract, err := common.HexToAddress(r.Contract)
+		if err != nil {
+			return nil, errors.New("invalid contract address in receipt")
+		}
+		receipt.ContractAddress = contract.Bytes()
+	}
+
+	return receipt, nil
+}
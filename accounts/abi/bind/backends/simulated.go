@@ -0,0 +1,410 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package backends
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/seeleteam/go-seele/accounts/abi/bind"
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/consensus"
+	"github.com/seeleteam/go-seele/consensus/dev"
+	"github.com/seeleteam/go-seele/core"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/core/store"
+	"github.com/seeleteam/go-seele/core/txs"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/seeleteam/go-seele/database"
+	"github.com/seeleteam/go-seele/database/leveldb"
+)
+
+const (
+	// simulatedShard is the shard number of the simulated blockchain
+	simulatedShard = uint(1)
+
+	// blockInterval is the timestamp interval in seconds between the simulated blocks
+	blockInterval = int64(10)
+)
+
+// This nil assignment ensures compile time that SimulatedBackend implements bind.ContractBackend.
+var _ bind.ContractBackend = (*SimulatedBackend)(nil)
+
+// errBlockNotEmpty is returned when adjust the time of the pending block with txs
+var errBlockNotEmpty = errors.New("could not adjust time on non-empty block")
+
+// SimulatedBackend implements the contract backend over an in-memory blockchain
+// for testing purposes. The sent transactions are executed in a pending block on
+// the chain head, which is written to the blockchain only when Commit is called.
+//
+// The genesis timestamp is 0 and each block is 10 seconds later than its parent,
+// so that the block time is deterministic and could be moved forward by AdjustTime.
+type SimulatedBackend struct {
+	mu       sync.Mutex
+	db       database.Database
+	chain    *core.Blockchain
+	pool     *core.TransactionPool
+	engine   *dev.Engine
+	coinbase common.Address
+
+	pendingTxs []*types.Transaction // txs of the pending block
+	timeShift  int64                // seconds added to the timestamp of the pending block
+}
+
+// pendingBlock is the block built on the chain head with the pending txs
+type pendingBlock struct {
+	header   *types.BlockHeader
+	statedb  *state.Statedb
+	txs      []*types.Transaction
+	receipts []*types.Receipt
+}
+
+// NewSimulatedBackend creates a simulated blockchain with the given accounts and balances in genesis.
+func NewSimulatedBackend(accounts map[common.Address]*big.Int) *SimulatedBackend {
+	db := leveldb.NewMemDatabase()
+	bcStore := store.NewCachedStore(store.NewBlockchainDatabase(db))
+
+	info := core.NewGenesisInfo(accounts, 1, simulatedShard, big.NewInt(0), types.PowConsensus, nil)
+	info.Supply = big.NewInt(0)
+	for i := 0; i < common.SubChainRootAccount; i++ {
+		info.Rootaccounts = append(info.Rootaccounts, *crypto.MustGenerateShardAddress(simulatedShard))
+	}
+
+	if err := core.GetGenesis(info).InitializeAndValidate(bcStore, db); err != nil {
+		panic(fmt.Sprintf("failed to initialize the simulated genesis, %s", err))
+	}
+
+	engine := dev.NewEngine(0)
+	chain, err := core.NewBlockchain(bcStore, db, leveldb.NewMemDatabase(), leveldb.NewMemDatabase(), "", engine, nil, -1)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create the simulated blockchain, %s", err))
+	}
+
+	return &SimulatedBackend{
+		db:       db,
+		chain:    chain,
+		pool:     core.NewTransactionPool(*core.DefaultTxPoolConfig(), chain),
+		engine:   engine,
+		coinbase: *crypto.MustGenerateShardAddress(simulatedShard),
+	}
+}
+
+// Blockchain returns the underlying blockchain of the simulated backend.
+func (b *SimulatedBackend) Blockchain() *core.Blockchain {
+	return b.chain
+}
+
+// Close releases the in-memory database of the simulated backend.
+func (b *SimulatedBackend) Close() {
+	b.db.Close()
+}
+
+// Commit writes the pending block with all the sent txs to the blockchain,
+// and starts a new empty pending block on it.
+func (b *SimulatedBackend) Commit() (*types.Block, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pending, err := b.buildPending(b.pendingTxs)
+	if err != nil {
+		return nil, err
+	}
+
+	if pending.header.StateHash, err = pending.statedb.Commit(b.db.NewBatch()); err != nil {
+		return nil, err
+	}
+
+	block := types.NewBlock(pending.header, pending.txs, pending.receipts, nil)
+	if err = b.chain.WriteBlock(block, b.pool.Pool); err != nil {
+		return nil, err
+	}
+
+	b.pendingTxs, b.timeShift = nil, 0
+
+	return block, nil
+}
+
+// Rollback discards all the pending txs and the time adjustment of the pending block.
+func (b *SimulatedBackend) Rollback() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pendingTxs, b.timeShift = nil, 0
+}
+
+// AdjustTime moves the timestamp of the pending block forward, which is only
+// allowed before any tx is sent to the pending block.
+func (b *SimulatedBackend) AdjustTime(adjustment time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.pendingTxs) > 0 {
+		return errBlockNotEmpty
+	}
+
+	if adjustment < 0 {
+		return fmt.Errorf("negative time adjustment %v", adjustment)
+	}
+
+	b.timeShift += int64(adjustment / time.Second)
+
+	return nil
+}
+
+// buildPending executes the txs in a new block on the chain head.
+func (b *SimulatedBackend) buildPending(transactions []*types.Transaction) (*pendingBlock, error) {
+	parent, statedb, err := b.chain.GetCurrentInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := parent.Header.CreateTimestamp.Int64() + blockInterval + b.timeShift
+	header := &types.BlockHeader{
+		PreviousBlockHash: parent.HeaderHash,
+		Creator:           b.coinbase,
+		Height:            parent.Header.Height + 1,
+		CreateTimestamp:   big.NewInt(timestamp),
+	}
+
+	if err = b.engine.Prepare(b.chain, header); err != nil {
+		return nil, err
+	}
+
+	// the reward tx will always be at the first of the block's transactions
+	rewardTx, err := txs.NewRewardTx(b.coinbase, consensus.GetReward(header.Height), uint64(timestamp))
+	if err != nil {
+		return nil, err
+	}
+
+	rewardReceipt, err := txs.ApplyRewardTx(rewardTx, statedb)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = txs.ApplyMasternodeRewards(statedb, header, rewardReceipt); err != nil {
+		return nil, err
+	}
+
+	pending := &pendingBlock{
+		header:   header,
+		statedb:  statedb,
+		txs:      []*types.Transaction{rewardTx},
+		receipts: []*types.Receipt{rewardReceipt},
+	}
+
+	for _, tx := range transactions {
+		if err = tx.Validate(statedb, header.Height); err != nil {
+			return nil, fmt.Errorf("failed to validate tx %v, %s", tx.Hash.Hex(), err)
+		}
+
+		receipt, err := b.chain.ApplyTransaction(tx, len(pending.txs), b.coinbase, statedb, header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply tx %v, %s", tx.Hash.Hex(), err)
+		}
+
+		pending.txs = append(pending.txs, tx)
+		pending.receipts = append(pending.receipts, receipt)
+	}
+
+	return pending, nil
+}
+
+// stateAt returns the block header and its statedb at the specified height, when height is -1 the chain head is used.
+func (b *SimulatedBackend) stateAt(height int64) (*types.BlockHeader, *state.Statedb, error) {
+	block := b.chain.CurrentBlock()
+	if height >= 0 {
+		var err error
+		if block, err = b.chain.GetStore().GetBlockByHeight(uint64(height)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	statedb, err := state.NewStatedb(block.Header.StateHash, b.db)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return block.Header, statedb, nil
+}
+
+// BalanceAt returns the account balance on the state of the specified height,
+// when height is -1 the chain head is used.
+func (b *SimulatedBackend) BalanceAt(ctx context.Context, account common.Address, height int64) (*big.Int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, statedb, err := b.stateAt(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return statedb.GetBalance(account), nil
+}
+
+// CallContract executes the contract call on the state of the specified height,
+// when height is -1 the chain head is used. The pending txs are not visible.
+func (b *SimulatedBackend) CallContract(ctx context.Context, contract common.Address, payload []byte, height int64) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	header, statedb, err := b.stateAt(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.callContract(statedb, header, contract, payload)
+}
+
+// PendingCallContract executes the contract call on the state of the pending block.
+func (b *SimulatedBackend) PendingCallContract(ctx context.Context, contract common.Address, payload []byte) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pending, err := b.buildPending(b.pendingTxs)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.callContract(pending.statedb, pending.header, contract, payload)
+}
+
+// callContract executes the contract call from a temporary account on the statedb.
+func (b *SimulatedBackend) callContract(statedb *state.Statedb, header *types.BlockHeader, contract common.Address, payload []byte) ([]byte, error) {
+	from := crypto.MustGenerateShardAddress(simulatedShard)
+	statedb.CreateAccount(*from)
+	statedb.SetBalance(*from, common.SeeleToFan)
+
+	tx, err := types.NewMessageTransaction(*from, contract, big.NewInt(0), big.NewInt(1), common.SeeleToFan.Uint64(), 0, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := b.chain.ApplyTransaction(tx, 0, b.coinbase, statedb, header)
+	if err != nil {
+		return nil, err
+	}
+
+	if receipt.Failed {
+		return nil, fmt.Errorf("contract call failed: %s", string(receipt.Result))
+	}
+
+	return receipt.Result, nil
+}
+
+// AccountNonce returns the account nonce of the pending block, so that
+// multiple txs of the same account could be sent before commit.
+func (b *SimulatedBackend) AccountNonce(ctx context.Context, account common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pending, err := b.buildPending(b.pendingTxs)
+	if err != nil {
+		return 0, err
+	}
+
+	return pending.statedb.GetNonce(account), nil
+}
+
+// EstimateGas executes the transaction on the pending block and returns the used gas.
+func (b *SimulatedBackend) EstimateGas(ctx context.Context, tx *types.Transaction) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	pending, err := b.buildPending(b.pendingTxs)
+	if err != nil {
+		return 0, err
+	}
+
+	receipt, err := b.chain.ApplyTransaction(tx, len(pending.txs), b.coinbase, pending.statedb, pending.header)
+	if err != nil {
+		return 0, err
+	}
+
+	if receipt.Failed {
+		return 0, errors.New(string(receipt.Result))
+	}
+
+	return receipt.UsedGas, nil
+}
+
+// SendTransaction validates and executes the transaction in the pending block.
+func (b *SimulatedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, pendingTx := range b.pendingTxs {
+		if pendingTx.Hash == tx.Hash {
+			return fmt.Errorf("tx %v already sent", tx.Hash.Hex())
+		}
+	}
+
+	transactions := append(append([]*types.Transaction(nil), b.pendingTxs...), tx)
+	if _, err := b.buildPending(transactions); err != nil {
+		return err
+	}
+
+	b.pendingTxs = transactions
+
+	return nil
+}
+
+// FilterLogs returns the logs of the contract that match the query topics in the height range.
+func (b *SimulatedBackend) FilterLogs(ctx context.Context, query bind.FilterQuery) ([]*types.Log, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	to := b.chain.CurrentBlock().Header.Height
+	if query.ToHeight != nil && *query.ToHeight < to {
+		to = *query.ToHeight
+	}
+
+	// the genesis block has no receipts
+	from := query.FromHeight
+	if from == 0 {
+		from = 1
+	}
+
+	bcStore := b.chain.GetStore()
+
+	var logs []*types.Log
+	for height := from; height <= to; height++ {
+		hash, err := bcStore.GetBlockHash(height)
+		if err != nil {
+			return nil, err
+		}
+
+		receipts, err := bcStore.GetReceiptsByBlockHash(hash)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, receipt := range receipts {
+			for _, log := range receipt.Logs {
+				if (query.Contract.IsEmpty() || log.Address == query.Contract) && bind.MatchTopics(log, query.Topics) {
+					logs = append(logs, log)
+				}
+			}
+		}
+	}
+
+	return logs, nil
+}
+
+// TransactionReceipt returns the receipt of the committed transaction,
+// ErrNoReceipt is returned for the txs in the pending block.
+func (b *SimulatedBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipt, err := b.chain.GetStore().GetReceiptByTxHash(txHash)
+	if err != nil {
+		return nil, bind.ErrNoReceipt
+	}
+
+	return receipt, nil
+}
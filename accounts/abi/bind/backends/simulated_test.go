@@ -0,0 +1,185 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package backends
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/seeleteam/go-seele/accounts/abi/bind"
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// simple storage contract, the get method emits the events getX(1, 2) and getY(3, 4)
+const (
+	storageABI = `[
+	{ "constant" : false, "inputs": [ { "name": "x", "type": "uint256" } ], "name": "set", "outputs": [], "payable": false, "stateMutability": "nonpayable", "type": "function" },
+	{ "constant" : false, "inputs": [], "name": "get", "outputs": [ { "name": "", "type": "uint256" } ], "payable": false, "stateMutability": "nonpayable", "type": "function" },
+	{ "inputs": [], "payable": false, "stateMutability": "nonpayable", "type": "constructor" },
+	{ "anonymous": false, "inputs": [ { "indexed": false, "name": "", "type": "uint256" }, { "indexed": false, "name": "", "type": "uint256" } ], "name": "getX", "type": "event" },
+	{ "anonymous": false, "inputs": [ { "indexed": false, "name": "", "type": "uint256" }, { "indexed": false, "name": "", "type": "uint256" } ], "name": "getY", "type": "event" }
+]`
+
+	storageBin = "0x608060405234801561001057600080fd5b506005600055610141806100256000396000f30060806040526004361061004b5763ffffffff7c010000000000000000000000000000000000000000000000000000000060003504166360fe47b181146100505780636d4ce63c1461006a575b600080fd5b34801561005c57600080fd5b50610068600435610091565b005b34801561007657600080fd5b5061007f610096565b60408051918252519081900360200190f35b600055565b60408051600181526002602082015281516000927f672e793f48f65acb771442258a567e553d1620c0684e1cbd9fe06ee380d1b642928290030190a160408051600381526004602082015281517f1086821eef716a909c39f2efe1e810bcd29246a6da19d04f9fc3f8d2889392e5929181900390910190a150600054905600a165627a7a72305820da608eada1eb6f77ba481c426f9c58dedad4df982b20f3f62efac1dbb710a7cc0029"
+)
+
+func newTestSimulatedBackend() (*SimulatedBackend, *bind.TransactOpts) {
+	addr, key := crypto.MustGenerateShardKeyPair(simulatedShard)
+	backend := NewSimulatedBackend(map[common.Address]*big.Int{
+		*addr: new(big.Int).Mul(big.NewInt(100), common.SeeleToFan),
+	})
+
+	return backend, bind.NewKeyedTransactor(key)
+}
+
+// newTestExternalAddress returns a random external address which accepts the transfer without payload.
+func newTestExternalAddress() *common.Address {
+	for {
+		if addr := crypto.MustGenerateShardAddress(simulatedShard); addr.Type() == common.AddressTypeExternal {
+			return addr
+		}
+	}
+}
+
+func Test_SimulatedBackend_Contract(t *testing.T) {
+	backend, opts := newTestSimulatedBackend()
+	defer backend.Close()
+
+	address, tx, contract, err := bind.DeployContract(opts, storageABI, hexutil.MustHexToBytes(storageBin), backend)
+	assert.NoError(t, err)
+
+	_, err = backend.TransactionReceipt(context.Background(), tx.Hash)
+	assert.Equal(t, err, bind.ErrNoReceipt)
+
+	_, err = backend.Commit()
+	assert.NoError(t, err)
+
+	deployed, err := bind.WaitDeployed(context.Background(), backend, tx)
+	assert.NoError(t, err)
+	assert.Equal(t, deployed, address)
+	assert.Equal(t, backend.Blockchain().CurrentBlock().Header.Height, uint64(1))
+
+	// initial value is 5 set by the constructor
+	var value *big.Int
+	assert.NoError(t, contract.Call(nil, &value, "get"))
+	assert.Equal(t, value.Int64(), int64(5))
+
+	_, err = contract.Transact(opts, "set", big.NewInt(7))
+	assert.NoError(t, err)
+
+	// the pending tx is only visible to the pending call
+	assert.NoError(t, contract.Call(nil, &value, "get"))
+	assert.Equal(t, value.Int64(), int64(5))
+	output, err := backend.PendingCallContract(context.Background(), address, hexutil.MustHexToBytes("0x6d4ce63c"))
+	assert.NoError(t, err)
+	assert.Equal(t, new(big.Int).SetBytes(output).Int64(), int64(7))
+
+	_, err = backend.Commit()
+	assert.NoError(t, err)
+	assert.NoError(t, contract.Call(nil, &value, "get"))
+	assert.Equal(t, value.Int64(), int64(7))
+
+	// call on the state of the deployment height
+	assert.NoError(t, contract.Call(&bind.CallOpts{Height: 1}, &value, "get"))
+	assert.Equal(t, value.Int64(), int64(5))
+
+	// the events are only emitted by the get transaction
+	_, err = contract.Transact(opts, "get")
+	assert.NoError(t, err)
+	_, err = backend.Commit()
+	assert.NoError(t, err)
+
+	logs, err := contract.FilterLogs(nil, "getX")
+	assert.NoError(t, err)
+	assert.Equal(t, len(logs), 1)
+
+	event := new(struct {
+		Arg0 *big.Int
+		Arg1 *big.Int
+	})
+	assert.NoError(t, contract.UnpackLog(&[]interface{}{&event.Arg0, &event.Arg1}, "getX", logs[0]))
+	assert.Equal(t, event.Arg0.Int64(), int64(1))
+	assert.Equal(t, event.Arg1.Int64(), int64(2))
+
+	end := uint64(2)
+	logs, err = contract.FilterLogs(&bind.FilterOpts{End: &end}, "getY")
+	assert.NoError(t, err)
+	assert.Equal(t, len(logs), 0)
+}
+
+func Test_SimulatedBackend_Rollback(t *testing.T) {
+	backend, opts := newTestSimulatedBackend()
+	defer backend.Close()
+
+	to := newTestExternalAddress()
+	contract, err := bind.NewBoundContract(*to, storageABI, backend, backend, backend)
+	assert.NoError(t, err)
+
+	opts.Value = big.NewInt(100)
+	opts.GasLimit = 21000
+	tx1, err := contract.Transfer(opts)
+	assert.NoError(t, err)
+
+	// the nonce of the pending block is used for the next tx
+	tx2, err := contract.Transfer(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, tx2.Data.AccountNonce, tx1.Data.AccountNonce+1)
+
+	// duplicated tx is rejected
+	assert.Error(t, backend.SendTransaction(context.Background(), tx2))
+
+	backend.Rollback()
+
+	nonce, err := backend.AccountNonce(context.Background(), opts.From)
+	assert.NoError(t, err)
+	assert.Equal(t, nonce, tx1.Data.AccountNonce)
+
+	block, err := backend.Commit()
+	assert.NoError(t, err)
+	assert.Equal(t, len(block.Transactions), 1)
+
+	balance, err := backend.BalanceAt(context.Background(), *to, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, balance.Sign(), 0)
+
+	_, err = backend.TransactionReceipt(context.Background(), tx1.Hash)
+	assert.Equal(t, err, bind.ErrNoReceipt)
+}
+
+func Test_SimulatedBackend_AdjustTime(t *testing.T) {
+	backend, opts := newTestSimulatedBackend()
+	defer backend.Close()
+
+	block, err := backend.Commit()
+	assert.NoError(t, err)
+	assert.Equal(t, block.Header.CreateTimestamp.Int64(), blockInterval)
+
+	assert.Error(t, backend.AdjustTime(-time.Minute))
+	assert.NoError(t, backend.AdjustTime(time.Hour))
+	block, err = backend.Commit()
+	assert.NoError(t, err)
+	assert.Equal(t, block.Header.CreateTimestamp.Int64(), 2*blockInterval+3600)
+
+	// the time adjustment is reset after commit
+	block, err = backend.Commit()
+	assert.NoError(t, err)
+	assert.Equal(t, block.Header.CreateTimestamp.Int64(), 3*blockInterval+3600)
+
+	opts.Value = big.NewInt(1)
+	opts.GasLimit = 21000
+	to := newTestExternalAddress()
+	contract, err := bind.NewBoundContract(*to, storageABI, backend, backend, backend)
+	assert.NoError(t, err)
+	_, err = contract.Transfer(opts)
+	assert.NoError(t, err)
+
+	assert.Equal(t, backend.AdjustTime(time.Minute), errBlockNotEmpty)
+}
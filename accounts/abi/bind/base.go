@@ -0,0 +1,296 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package bind
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/seeleteam/go-seele/accounts/abi"
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/crypto"
+)
+
+var (
+	// DefaultGasPrice is the gas price in Fan used if not specified in the transact options.
+	DefaultGasPrice = big.NewInt(10)
+
+	// estimateGasLimit is the gas limit of the transaction to estimate the gas
+	estimateGasLimit = uint64(10000000)
+
+	errNoPrivateKey = errors.New("private key is required to sign the transaction")
+)
+
+// CallOpts is the collection of options to fine tune a contract call request.
+type CallOpts struct {
+	Height  int64           // Height of the state to call the contract on, -1 means the chain head
+	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
+}
+
+// TransactOpts is the collection of authorization data required to create a
+// valid Seele transaction.
+type TransactOpts struct {
+	From       common.Address    // Seele account to send the transaction from
+	PrivateKey *ecdsa.PrivateKey // Private key of the From account to sign the transaction
+	Nonce      *uint64           // Nonce to use for the transaction execution (nil = use the account nonce)
+
+	Value    *big.Int // Funds to transfer along the transaction (nil = 0 = no funds)
+	GasPrice *big.Int // Gas price to use for the transaction execution (nil = DefaultGasPrice)
+	GasLimit uint64   // Gas limit to set for the transaction execution (0 = estimate)
+
+	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
+}
+
+// FilterOpts is the collection of options to fine tune filtering for events
+// within a bound contract.
+type FilterOpts struct {
+	Start uint64  // Start of the queried range
+	End   *uint64 // End of the range (nil = latest)
+
+	Context context.Context // Network context to support cancellation and timeouts (nil = no timeout)
+}
+
+// BoundContract is the base wrapper object that reflects a contract on the
+// Seele network. It contains a collection of methods that are used by the
+// higher level contract bindings to operate.
+type BoundContract struct {
+	address    common.Address     // Deployment address of the contract on the Seele blockchain
+	abiJSON    string             // Contract ABI json, which is required by the event logs query
+	abi        abi.ABI            // Reflect based ABI to access the correct Seele methods
+	caller     ContractCaller     // Read interface to interact with the blockchain
+	transactor ContractTransactor // Write interface to interact with the blockchain
+	filterer   ContractFilterer   // Event filtering to interact with the blockchain
+}
+
+// NewBoundContract creates a low level contract interface through which calls
+// and transactions may be made through.
+func NewBoundContract(address common.Address, abiJSON string, caller ContractCaller, transactor ContractTransactor, filterer ContractFilterer) (*BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	return &BoundContract{
+		address:    address,
+		abiJSON:    abiJSON,
+		abi:        parsed,
+		caller:     caller,
+		transactor: transactor,
+		filterer:   filterer,
+	}, nil
+}
+
+// DeployContract deploys a contract onto the Seele blockchain and binds the
+// deployment address with a Go wrapper.
+func DeployContract(opts *TransactOpts, abiJSON string, bytecode []byte, backend ContractBackend, params ...interface{}) (common.Address, *types.Transaction, *BoundContract, error) {
+	// Otherwise try to deploy the contract
+	c, err := NewBoundContract(common.EmptyAddress, abiJSON, backend, backend, backend)
+	if err != nil {
+		return common.EmptyAddress, nil, nil, err
+	}
+
+	input, err := c.abi.Pack("", params...)
+	if err != nil {
+		return common.EmptyAddress, nil, nil, err
+	}
+
+	tx, err := c.transact(opts, nil, append(bytecode, input...))
+	if err != nil {
+		return common.EmptyAddress, nil, nil, err
+	}
+
+	c.address = crypto.CreateAddress(opts.From, tx.Data.AccountNonce)
+	return c.address, tx, c, nil
+}
+
+// Address returns the deployment address of the contract.
+func (c *BoundContract) Address() common.Address {
+	return c.address
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (c *BoundContract) Call(opts *CallOpts, result interface{}, method string, params ...interface{}) error {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = &CallOpts{Height: -1}
+	}
+
+	// Pack the input, call and unpack the results
+	input, err := c.abi.Pack(method, params...)
+	if err != nil {
+		return err
+	}
+
+	output, err := c.caller.CallContract(ensureContext(opts.Context), c.address, input, opts.Height)
+	if err != nil {
+		return err
+	}
+
+	if len(output) == 0 {
+		if len(c.abi.Methods[method].Outputs) == 0 {
+			return nil
+		}
+
+		return ErrNoCode
+	}
+
+	return c.abi.Unpack(result, method, output)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (c *BoundContract) Transact(opts *TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	// Otherwise pack up the parameters and invoke the contract
+	input, err := c.abi.Pack(method, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.transact(opts, &c.address, input)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (c *BoundContract) Transfer(opts *TransactOpts) (*types.Transaction, error) {
+	return c.transact(opts, &c.address, nil)
+}
+
+// transact executes an actual transaction invocation, first deriving any missing
+// authorization fields, and then scheduling the transaction for execution.
+func (c *BoundContract) transact(opts *TransactOpts, contract *common.Address, input []byte) (*types.Transaction, error) {
+	if opts.PrivateKey == nil {
+		return nil, errNoPrivateKey
+	}
+
+	ctx := ensureContext(opts.Context)
+
+	// Ensure a valid value field and resolve the account nonce
+	value := opts.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	var nonce uint64
+	if opts.Nonce == nil {
+		var err error
+		if nonce, err = c.transactor.AccountNonce(ctx, opts.From); err != nil {
+			return nil, fmt.Errorf("failed to retrieve account nonce: %v", err)
+		}
+	} else {
+		nonce = *opts.Nonce
+	}
+
+	gasPrice := opts.GasPrice
+	if gasPrice == nil {
+		gasPrice = DefaultGasPrice
+	}
+
+	// Estimate the gas limit if not specified
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 {
+		tx, err := newTransaction(opts.From, contract, value, gasPrice, estimateGasLimit, nonce, input)
+		if err != nil {
+			return nil, err
+		}
+
+		if gasLimit, err = c.transactor.EstimateGas(ctx, tx); err != nil {
+			return nil, fmt.Errorf("failed to estimate gas needed: %v", err)
+		}
+	}
+
+	// Create the transaction, sign it and schedule it for execution
+	tx, err := newTransaction(opts.From, contract, value, gasPrice, gasLimit, nonce, input)
+	if err != nil {
+		return nil, err
+	}
+
+	tx.Sign(opts.PrivateKey)
+	if err := c.transactor.SendTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// newTransaction creates a contract creation transaction if contract is nil,
+// otherwise creates a message transaction to the contract.
+func newTransaction(from common.Address, contract *common.Address, amount, price *big.Int, gasLimit, nonce uint64, input []byte) (*types.Transaction, error) {
+	if contract == nil {
+		return types.NewContractTransaction(from, amount, price, gasLimit, nonce, input)
+	}
+
+	return types.NewMessageTransaction(from, *contract, amount, price, gasLimit, nonce, input)
+}
+
+// FilterLogs filters the contract logs of the event for the block range, the
+// query values are the indexed arguments to match, nil matches any value.
+func (c *BoundContract) FilterLogs(opts *FilterOpts, name string, query ...[]interface{}) ([]*types.Log, error) {
+	// Don't crash on a lazy user
+	if opts == nil {
+		opts = new(FilterOpts)
+	}
+
+	event, ok := c.abi.Events[name]
+	if !ok {
+		return nil, fmt.Errorf("event %s not found in abi", name)
+	}
+
+	// Append the event selector to the query parameters and construct the topic set
+	query = append([][]interface{}{{event.Id()}}, query...)
+
+	topics, err := makeTopics(query...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.filterer.FilterLogs(ensureContext(opts.Context), FilterQuery{
+		FromHeight: opts.Start,
+		ToHeight:   opts.End,
+		Contract:   c.address,
+		ABI:        c.abiJSON,
+		Event:      name,
+		Topics:     topics,
+	})
+}
+
+// UnpackLog unpacks a retrieved log into the provided output structure.
+func (c *BoundContract) UnpackLog(out interface{}, event string, log *types.Log) error {
+	if len(log.Data) > 0 {
+		if err := c.abi.Unpack(out, event, log.Data); err != nil {
+			return err
+		}
+	}
+
+	var indexed abi.Arguments
+	for _, arg := range c.abi.Events[event].Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+
+	if len(log.Topics) == 0 {
+		return errors.New("log has no topics")
+	}
+
+	return parseTopics(out, indexed, log.Topics[1:])
+}
+
+// ensureContext is a helper method to ensure a context is not nil, even if the
+// user specified it as such.
+func ensureContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.TODO()
+	}
+
+	return ctx
+}
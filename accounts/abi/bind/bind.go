@@ -16,13 +16,206 @@
 package bind
 
 import (
+	"bytes"
 	"fmt"
+	"go/format"
 	"regexp"
 	"strings"
+	"text/template"
+	"unicode"
 
 	"github.com/seeleteam/go-seele/accounts/abi"
 )
 
+// Bind generates a Go wrapper around a contract ABI. This wrapper isn't meant
+// to be used as is in client code, but rather as an intermediate struct which
+// enforces compile time type safety and naming convention opposed to having to
+// manually maintain hard coded strings that break on runtime.
+func Bind(types []string, abis []string, bytecodes []string, pkg string) (string, error) {
+	if len(types) != len(abis) || len(types) != len(bytecodes) {
+		return "", fmt.Errorf("count mismatch, %d types, %d abis and %d bytecodes", len(types), len(abis), len(bytecodes))
+	}
+
+	// Process each individual contract requested binding
+	contracts := make(map[string]*tmplContract)
+
+	for i := 0; i < len(types); i++ {
+		// Parse the actual ABI to generate the binding for
+		evmABI, err := abi.JSON(strings.NewReader(abis[i]))
+		if err != nil {
+			return "", err
+		}
+
+		// Strip any whitespace from the JSON ABI
+		strippedABI := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, abis[i])
+
+		// Extract the call and transact methods; events; and sort them alphabetically
+		var (
+			calls     = make(map[string]*tmplMethod)
+			transacts = make(map[string]*tmplMethod)
+			events    = make(map[string]*tmplEvent)
+		)
+
+		for _, original := range evmABI.Methods {
+			// Normalize the method for capital cases and non-anonymous inputs/outputs
+			normalized := original
+			normalized.Name = capitalise(original.Name)
+			normalized.Inputs = normalizeArgs(original.Inputs)
+
+			normalized.Outputs = make([]abi.Argument, len(original.Outputs))
+			copy(normalized.Outputs, original.Outputs)
+			for j, output := range normalized.Outputs {
+				if output.Name != "" {
+					normalized.Outputs[j].Name = capitalise(output.Name)
+				}
+			}
+
+			// Append the methods to the call or transact lists
+			if original.Const {
+				calls[original.Name] = &tmplMethod{Original: original, Normalized: normalized, Structured: structured(original.Outputs)}
+			} else {
+				transacts[original.Name] = &tmplMethod{Original: original, Normalized: normalized, Structured: structured(original.Outputs)}
+			}
+		}
+
+		for _, original := range evmABI.Events {
+			// Skip anonymous events as they don't support explicit filtering
+			if original.Anonymous {
+				continue
+			}
+
+			// Normalize the event for capital cases and non-anonymous inputs
+			normalized := original
+			normalized.Name = capitalise(original.Name)
+			normalized.Inputs = normalizeArgs(original.Inputs)
+
+			// Append the event to the accumulator list
+			events[original.Name] = &tmplEvent{Original: original, Normalized: normalized}
+		}
+
+		constructor := evmABI.Constructor
+		constructor.Inputs = normalizeArgs(constructor.Inputs)
+
+		contracts[types[i]] = &tmplContract{
+			Type:        capitalise(types[i]),
+			InputABI:    strings.Replace(strippedABI, "\"", "\\\"", -1),
+			InputBin:    strings.TrimPrefix(strings.TrimSpace(bytecodes[i]), "0x"),
+			Constructor: constructor,
+			Calls:       calls,
+			Transacts:   transacts,
+			Events:      events,
+		}
+	}
+
+	// Generate the contract template data content and render it
+	data := &tmplData{
+		Package:   pkg,
+		Contracts: contracts,
+	}
+
+	buffer := new(bytes.Buffer)
+	funcs := map[string]interface{}{
+		"bindtype":      bindTypeGo,
+		"bindtopictype": bindTopicTypeGo,
+		"capitalise":    capitalise,
+		"decapitalise":  decapitalise,
+	}
+
+	tmpl := template.Must(template.New("").Funcs(funcs).Parse(tmplSourceGo))
+	if err := tmpl.Execute(buffer, data); err != nil {
+		return "", err
+	}
+
+	// Pass the code through gofmt to clean it up
+	code, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("%v\n%s", err, buffer)
+	}
+
+	return string(code), nil
+}
+
+// normalizeArgs names the anonymous arguments by their positions.
+func normalizeArgs(args abi.Arguments) abi.Arguments {
+	normalized := make([]abi.Argument, len(args))
+	copy(normalized, args)
+
+	for i, arg := range normalized {
+		if arg.Name == "" {
+			normalized[i].Name = fmt.Sprintf("arg%d", i)
+		}
+	}
+
+	return normalized
+}
+
+// structured checks whether a list of ABI data types has enough information to
+// operate through a proper Go struct or if flat returns are needed.
+func structured(args abi.Arguments) bool {
+	if len(args) < 2 {
+		return false
+	}
+
+	exists := make(map[string]bool)
+	for _, out := range args {
+		// If the name is anonymous, we can't organize into a struct
+		if out.Name == "" {
+			return false
+		}
+
+		// If the field name is empty when normalized or collides (var, Var, _var, _Var),
+		// we can't organize into a struct
+		field := capitalise(out.Name)
+		if field == "" || exists[field] {
+			return false
+		}
+
+		exists[field] = true
+	}
+
+	return true
+}
+
+// capitalise makes the first character of a string upper case, also removing any
+// prefixing underscores from the variable names. It is the same as the one abi
+// uses to map the arguments to the struct fields.
+func capitalise(input string) string {
+	for len(input) > 0 && input[0] == '_' {
+		input = input[1:]
+	}
+
+	if len(input) == 0 {
+		return ""
+	}
+
+	return strings.ToUpper(input[:1]) + input[1:]
+}
+
+// decapitalise makes the first character of a string lower case.
+func decapitalise(input string) string {
+	if len(input) == 0 {
+		return ""
+	}
+
+	return strings.ToLower(input[:1]) + input[1:]
+}
+
+// bindTopicTypeGo converts a Solidity topic type to a Go one. It is almost the same
+// functionality as for simple types, but dynamic types get converted to hashes.
+func bindTopicTypeGo(kind abi.Type) string {
+	switch kind.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy:
+		return "common.Hash"
+	}
+
+	return bindTypeGo(kind)
+}
+
 // bindTypeGo converts a Solidity type to a Go one. Since there is no clear mapping
 // from all Solidity types to Go ones (e.g. uint17), those that cannot be exactly
 // mapped will use an upscaled type (e.g. *big.Int).
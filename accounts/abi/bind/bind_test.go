@@ -2,8 +2,18 @@ package bind
 
 import (
 	"fmt"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_bindUnnestedTypeGo(t *testing.T) {
@@ -44,3 +54,116 @@ func Test_bindUnnestedTypeGo(t *testing.T) {
 	fmt.Println("s:", s)
 
 }
+
+const tokenABI = `[
+	{ "constant": true, "inputs": [ { "name": "owner", "type": "address" } ], "name": "balanceOf", "outputs": [ { "name": "", "type": "uint256" } ], "stateMutability": "view", "type": "function" },
+	{ "constant": false, "inputs": [ { "name": "to", "type": "address" }, { "name": "value", "type": "uint256" } ], "name": "transfer", "outputs": [ { "name": "", "type": "bool" } ], "stateMutability": "nonpayable", "type": "function" },
+	{ "inputs": [ { "name": "supply", "type": "uint256" } ], "stateMutability": "nonpayable", "type": "constructor" },
+	{ "anonymous": false, "inputs": [ { "indexed": true, "name": "from", "type": "address" }, { "indexed": true, "name": "to", "type": "address" }, { "indexed": false, "name": "value", "type": "uint256" } ], "name": "Transfer", "type": "event" }
+]`
+
+func Test_Bind(t *testing.T) {
+	code, err := Bind([]string{"Token"}, []string{tokenABI}, []string{"0x6080"}, "token")
+	assert.NoError(t, err)
+
+	_, err = parser.ParseFile(token.NewFileSet(), "token.go", code, 0)
+	assert.NoError(t, err)
+
+	for _, expected := range []string{
+		"package token",
+		"func DeployToken(auth *bind.TransactOpts, backend bind.ContractBackend, supply *big.Int)",
+		"func NewToken(address common.Address, backend bind.ContractBackend) (*Token, error)",
+		"func (_Token *TokenCaller) BalanceOf(opts *bind.CallOpts, owner common.Address) (*big.Int, error)",
+		"func (_Token *TokenTransactor) Transfer(opts *bind.TransactOpts, to common.Address, value *big.Int) (*types.Transaction, error)",
+		"func (_Token *TokenFilterer) FilterTransfer(opts *bind.FilterOpts, from []common.Address, to []common.Address) (*TokenTransferIterator, error)",
+	} {
+		assert.True(t, strings.Contains(code, expected), expected)
+	}
+
+	// no deploy method without the bytecode
+	code, err = Bind([]string{"Token"}, []string{tokenABI}, []string{""}, "token")
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(code, "func DeployToken"))
+
+	_, err = Bind([]string{"Token"}, []string{"invalid"}, []string{""}, "token")
+	assert.Error(t, err)
+}
+
+// simple storage contract, please refer to contract/solidity/simple_storage.sol
+const (
+	storageABI = `[
+	{ "constant": false, "inputs": [ { "name": "x", "type": "uint256" } ], "name": "set", "outputs": [], "stateMutability": "nonpayable", "type": "function" },
+	{ "constant": false, "inputs": [], "name": "get", "outputs": [ { "name": "", "type": "uint256" } ], "stateMutability": "view", "type": "function" },
+	{ "inputs": [], "stateMutability": "nonpayable", "type": "constructor" }
+]`
+
+	storageBin = "0x608060405234801561001057600080fd5b50600560008190555060df806100276000396000f3006080604052600436106049576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806360fe47b114604e5780636d4ce63c146078575b600080fd5b348015605957600080fd5b5060766004803603810190808035906020019092919050505060a0565b005b348015608357600080fd5b50608a60aa565b6040518082815260200191505060405180910390f35b8060008190555050565b600080549050905600a165627a7a723058207f6dc43a0d648e9f5a0cad5071cde46657de72eb87ab4cded53a7f1090f51e6d0029"
+
+	// storageTest deploys the bound contract on the simulated backend and calls it
+	storageTest = `package storage
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/seeleteam/go-seele/accounts/abi/bind"
+	"github.com/seeleteam/go-seele/accounts/abi/bind/backends"
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/crypto"
+)
+
+func TestStorage(t *testing.T) {
+	addr, key := crypto.MustGenerateShardKeyPair(1)
+	backend := backends.NewSimulatedBackend(map[common.Address]*big.Int{
+		*addr: new(big.Int).Mul(big.NewInt(100), common.SeeleToFan),
+	})
+	defer backend.Close()
+
+	opts := bind.NewKeyedTransactor(key)
+	_, _, storage, err := DeployStorage(opts, backend)
+	if err != nil {
+		t.Fatalf("failed to deploy: %v", err)
+	}
+	backend.Commit()
+
+	// the view method is bound as a call
+	value, err := storage.Get(nil)
+	if err != nil || value.Int64() != 5 {
+		t.Fatalf("get: expected 5, got %v, %v", value, err)
+	}
+
+	if _, err = storage.Set(opts, big.NewInt(7)); err != nil {
+		t.Fatalf("failed to set: %v", err)
+	}
+	backend.Commit()
+
+	session := &StorageSession{Contract: storage, CallOpts: bind.CallOpts{Height: -1}, TransactOpts: *opts}
+	if value, err = session.Get(); err != nil || value.Int64() != 7 {
+		t.Fatalf("get: expected 7, got %v, %v", value, err)
+	}
+}
+`
+)
+
+// Test_Bind_Run compiles the generated binding and runs it against the simulated backend.
+func Test_Bind_Run(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skip running the generated binding in short mode")
+	}
+
+	code, err := Bind([]string{"Storage"}, []string{storageABI}, []string{storageBin}, "storage")
+	assert.NoError(t, err)
+
+	// the package is created in the source tree to import the repository packages
+	dir, err := ioutil.TempDir(".", "bindtest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "storage.go"), []byte(code), 0600))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "storage_test.go"), []byte(storageTest), 0600))
+
+	cmd := exec.Command(filepath.Join(runtime.GOROOT(), "bin", "go"), "test", "-v", "-vet=off", ".")
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err, string(output))
+}
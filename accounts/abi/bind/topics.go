@@ -0,0 +1,146 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package bind
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/seeleteam/go-seele/accounts/abi"
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/crypto"
+)
+
+var (
+	reflectHash    = reflect.TypeOf(common.Hash{})
+	reflectAddress = reflect.TypeOf(common.Address{})
+	reflectBigInt  = reflect.TypeOf(new(big.Int))
+
+	// tt256 is 2^256, used to decode the negative numbers in topics
+	tt256 = new(big.Int).Lsh(big.NewInt(1), 256)
+)
+
+// makeTopics converts a filter query argument list into a filter topic set.
+func makeTopics(query ...[]interface{}) ([][]common.Hash, error) {
+	topics := make([][]common.Hash, len(query))
+	for i, filter := range query {
+		for _, rule := range filter {
+			var topic common.Hash
+
+			// Try to generate the topic based on simple types
+			switch rule := rule.(type) {
+			case common.Hash:
+				copy(topic[:], rule[:])
+			case common.Address:
+				copy(topic[common.HashLength-common.AddressLen:], rule[:])
+			case *big.Int:
+				copy(topic[:], abi.U256(new(big.Int).Set(rule)))
+			case bool:
+				if rule {
+					topic[common.HashLength-1] = 1
+				}
+			case int8:
+				copy(topic[:], abi.U256(big.NewInt(int64(rule))))
+			case int16:
+				copy(topic[:], abi.U256(big.NewInt(int64(rule))))
+			case int32:
+				copy(topic[:], abi.U256(big.NewInt(int64(rule))))
+			case int64:
+				copy(topic[:], abi.U256(big.NewInt(rule)))
+			case uint8:
+				topic[common.HashLength-1] = rule
+			case uint16:
+				binary.BigEndian.PutUint16(topic[common.HashLength-2:], rule)
+			case uint32:
+				binary.BigEndian.PutUint32(topic[common.HashLength-4:], rule)
+			case uint64:
+				binary.BigEndian.PutUint64(topic[common.HashLength-8:], rule)
+			case string:
+				topic = crypto.Keccak256Hash([]byte(rule))
+			case []byte:
+				topic = crypto.Keccak256Hash(rule)
+
+			default:
+				// Attempt to generate the topic from fixed bytes
+				val := reflect.ValueOf(rule)
+				if val.Kind() != reflect.Array || val.Type().Elem().Kind() != reflect.Uint8 || val.Len() > common.HashLength {
+					return nil, fmt.Errorf("unsupported indexed type: %T", rule)
+				}
+
+				reflect.Copy(reflect.ValueOf(topic[:val.Len()]), val)
+			}
+
+			topics[i] = append(topics[i], topic)
+		}
+	}
+
+	return topics, nil
+}
+
+// parseTopics converts the indexed topic fields into actual log field values.
+//
+// Note, dynamic types cannot be reconstructed since they get mapped to Keccak256
+// hashes as the topic value!
+func parseTopics(out interface{}, fields abi.Arguments, topics []common.Hash) error {
+	// Sanity check that the fields and topics match up
+	if len(fields) != len(topics) {
+		return errors.New("topic/field count mismatch")
+	}
+
+	// Iterate over all the fields and reconstruct them from topics
+	for i, arg := range fields {
+		if !arg.Indexed {
+			return errors.New("non-indexed field in topic reconstruction")
+		}
+
+		topic := topics[i]
+		field := reflect.ValueOf(out).Elem().FieldByName(capitalise(arg.Name))
+		if !field.IsValid() {
+			return fmt.Errorf("field %s not found for the indexed argument", capitalise(arg.Name))
+		}
+
+		// the last 8 bytes are enough for the primitive integer types
+		word := binary.BigEndian.Uint64(topic[common.HashLength-8:])
+
+		// Try to parse the topic back into the fields based on primitive types
+		switch field.Kind() {
+		case reflect.Bool:
+			field.SetBool(topic[common.HashLength-1] == 1)
+		case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			field.SetInt(int64(word))
+		case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field.SetUint(word)
+
+		default:
+			// Ran out of plain primitive types, try custom types
+			switch field.Type() {
+			case reflectHash: // Also covers all dynamic types
+				field.Set(reflect.ValueOf(topic))
+			case reflectAddress:
+				field.Set(reflect.ValueOf(common.BytesToAddress(topic[common.HashLength-common.AddressLen:])))
+			case reflectBigInt:
+				num := new(big.Int).SetBytes(topic[:])
+				if arg.Type.T == abi.IntTy && topic[0]&0x80 != 0 {
+					num.Sub(num, tt256)
+				}
+				field.Set(reflect.ValueOf(num))
+
+			default:
+				// Ran out of custom types, try the fixed bytes
+				if arg.Type.T != abi.FixedBytesTy || field.Kind() != reflect.Array {
+					return fmt.Errorf("unsupported indexed type: %v", arg.Type)
+				}
+
+				reflect.Copy(field, reflect.ValueOf(topic[:arg.Type.Size]))
+			}
+		}
+	}
+
+	return nil
+}
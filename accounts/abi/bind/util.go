@@ -0,0 +1,68 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package bind
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/log"
+)
+
+// receiptQueryInterval is the interval to query the receipt of the pending transaction
+var receiptQueryInterval = time.Second
+
+// WaitMined waits for tx to be mined on the blockchain.
+// It stops waiting when the context is canceled.
+func WaitMined(ctx context.Context, b DeployBackend, tx *types.Transaction) (*types.Receipt, error) {
+	queryTicker := time.NewTicker(receiptQueryInterval)
+	defer queryTicker.Stop()
+
+	logger := log.GetLogger("bind")
+	for {
+		receipt, err := b.TransactionReceipt(ctx, tx.Hash)
+		if err == nil {
+			return receipt, nil
+		}
+
+		if err != ErrNoReceipt {
+			logger.Debug("failed to retrieve the receipt of tx %v, %s", tx.Hash.Hex(), err)
+		}
+
+		// Wait for the next round.
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-queryTicker.C:
+		}
+	}
+}
+
+// WaitDeployed waits for a contract deployment transaction and returns the on-chain
+// contract address when it is mined. It stops waiting when ctx is canceled.
+func WaitDeployed(ctx context.Context, b DeployBackend, tx *types.Transaction) (common.Address, error) {
+	if !tx.Data.To.IsEmpty() {
+		return common.EmptyAddress, errors.New("tx is not contract creation")
+	}
+
+	receipt, err := WaitMined(ctx, b, tx)
+	if err != nil {
+		return common.EmptyAddress, err
+	}
+
+	if receipt.Failed {
+		return common.EmptyAddress, errors.New("contract deployment failed: " + string(receipt.Result))
+	}
+
+	if len(receipt.ContractAddress) == 0 {
+		return common.EmptyAddress, errors.New("zero address")
+	}
+
+	return common.BytesToAddress(receipt.ContractAddress), nil
+}
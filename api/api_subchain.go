@@ -13,6 +13,7 @@ import (
 	"github.com/seeleteam/go-seele/common/errors"
 	"github.com/seeleteam/go-seele/common/hexutil"
 	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/core/txs"
 	"github.com/seeleteam/go-seele/core/types"
 	"github.com/seeleteam/go-seele/crypto"
 	"github.com/seeleteam/go-seele/merkle"
@@ -454,42 +455,86 @@ func (api *PublicSubchainAPI) GetUpdatedAccountInfo(height uint64) (map[string]i
 	return info, nil
 }
 
+// GetFee returns the fee of the relay period before the relay block, and the fee of each verifier of the block.
+// After the verifier fee fork height, the fee is the total paid to the verifiers by their commits at the relay block.
 func (api *PublicSubchainAPI) GetFee(height uint64) (map[string]interface{}, error) {
-	var curStatedb *state.Statedb
-	var prevStatedb *state.Statedb
-	var err error
 	if height < common.RelayInterval || height%common.RelayInterval != 0 {
 		return nil, errors.New("Must be a relay block")
 	}
-	prevHeight := height - common.RelayInterval
 
-	if curStatedb, err = api.GetStatedbByHeight(height); err != nil {
+	block, err := api.s.GetBlock(common.EmptyHash, int64(height)) // return subblock
+	if err != nil {
 		return nil, err
 	}
-
-	if prevStatedb, err = api.GetStatedbByHeight(prevHeight); err != nil {
+	bftExt, err := types.ExtractBftExtra(block.Header)
+	if err != nil {
 		return nil, err
 	}
-	account := common.SubchainFeeAccount
-	curBalance := curStatedb.GetBalance(account)
-	prevBalance := prevStatedb.GetBalance(account)
-	fee := big.NewInt(int64(0)).Sub(curBalance, prevBalance)
+	verNum := big.NewInt(int64(len(bftExt.Verifiers)))
+
+	info := make(map[string]interface{})
+	var total *big.Int
+	if height < common.VerifierFeeForkHeight {
+		var curStatedb *state.Statedb
+		var prevStatedb *state.Statedb
+		if curStatedb, err = api.GetStatedbByHeight(height); err != nil {
+			return nil, err
+		}
+
+		if prevStatedb, err = api.GetStatedbByHeight(height - common.RelayInterval); err != nil {
+			return nil, err
+		}
+
+		account := common.SubchainFeeAccount
+		total = new(big.Int).Sub(curStatedb.GetBalance(account), prevStatedb.GetBalance(account))
+	} else {
+		fees, err := api.GetVerifierFees(height)
+		if err != nil {
+			return nil, err
+		}
+
+		total = big.NewInt(0)
+		for _, fee := range fees {
+			total.Add(total, fee.Amount)
+		}
+
+		info["paidNum"] = len(fees)
+		info["fees"] = fees
+	}
+
+	fee := big.NewInt(0)
+	if verNum.Sign() > 0 {
+		fee.Div(total, verNum)
+	}
+
+	info["fee"] = fee
+	info["verNum"] = verNum
+	info["total"] = total
+	return info, nil
+}
+
+// GetVerifierFees returns the fees paid to the verifiers by their commits at the relay block
+func (api *PublicSubchainAPI) GetVerifierFees(height uint64) ([]*txs.VerifierFee, error) {
+	if height < common.RelayInterval || height%common.RelayInterval != 0 {
+		return nil, errors.New("Must be a relay block")
+	}
 
 	block, err := api.s.GetBlock(common.EmptyHash, int64(height)) // return subblock
 	if err != nil {
 		return nil, err
 	}
-	bftExt, err := types.ExtractBftExtra(block.Header)
+
+	receipts, err := api.s.ChainBackend().GetStore().GetReceiptsByBlockHash(block.HeaderHash)
 	if err != nil {
 		return nil, err
 	}
-	verNum := big.NewInt(int64(len(bftExt.Verifiers)))
-	fee.Div(fee, verNum)
-	info := map[string]interface{}{
-		"fee":    fee,
-		"verNum": verNum,
+
+	if len(receipts) == 0 {
+		return nil, nil
 	}
-	return info, nil
+
+	// the fees are recorded in the receipt of the reward tx
+	return txs.VerifierFeesFromReceipt(receipts[0])
 }
 
 // get block relay interval
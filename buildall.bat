@@ -22,6 +22,7 @@ call :client
 call :light
 call :tool
 call :vm
+call :abigen
 goto:eof
 
 :discovery
@@ -67,6 +68,13 @@ go build -o ./build/vm.exe ./cmd/vm
 @echo off
 goto:eof
 
+:abigen
+echo on
+go build -o ./build/abigen.exe ./cmd/abigen
+@echo "Done abigen building"
+@echo off
+goto:eof
+
 :clean
 del build\* /q /f /s
 @echo "Done clean the build dir"
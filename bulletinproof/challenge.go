@@ -0,0 +1,41 @@
+package bp
+
+import (
+	"crypto/sha256"
+	"math/big"
+)
+
+/*
+HashChallenge computes a Fiat-Shamir challenge from the previous challenge
+and the values sent by the prover since then. Chaining the challenges binds
+each of them to the whole transcript, including the commitments being proved,
+so that a proof cannot be reused for another commitment.
+*/
+func HashChallenge(prev *big.Int, points []ECPoint, scalars ...*big.Int) *big.Int {
+	s256 := sha256.New()
+
+	if prev != nil {
+		s256.Write(scalarBytes(prev))
+	}
+
+	for _, p := range points {
+		s256.Write(scalarBytes(p.X))
+		s256.Write(scalarBytes(p.Y))
+	}
+
+	for _, s := range scalars {
+		s256.Write(scalarBytes(s))
+	}
+
+	return new(big.Int).SetBytes(s256.Sum(nil))
+}
+
+// scalarBytes returns the big endian bytes of the value left padded to 32 bytes
+func scalarBytes(value *big.Int) []byte {
+	b := value.Bytes()
+	if len(b) >= 32 {
+		return b
+	}
+
+	return append(make([]byte, 32-len(b)), b...)
+}
@@ -26,3 +26,23 @@ func PedersonCommit(value []*big.Int) (ECPoint, []*big.Int) {
 	}
 	return commitment, R
 }
+
+/*
+Two Vector Pedersen Commitment
+Given the generators G, H and the vectors a, b, computes \sum_i a[i]*G[i] + b[i]*H[i]
+*/
+func TwoVectorPCommitWithGens(G, H []ECPoint, a, b []*big.Int) ECPoint {
+	if len(G) != len(H) || len(G) != len(a) || len(a) != len(b) {
+		panic("TwoVectorPCommitWithGens: arrays not of the same length")
+	}
+
+	commitment := EC.Zero()
+	for i := range G {
+		modA := new(big.Int).Mod(a[i], EC.N)
+		modB := new(big.Int).Mod(b[i], EC.N)
+
+		commitment = commitment.Add(G[i].Mult(modA)).Add(H[i].Mult(modB))
+	}
+
+	return commitment
+}
@@ -10,7 +10,7 @@ type ECPoint struct {
 
 // Equal returns true if points p (self) and p2 (arg) are the same.
 func (p ECPoint) Equal(p2 ECPoint) bool {
-	if p.X.Cmp(p2.X) == 0 && p2.Y.Cmp(p2.Y) == 0 {
+	if p.X.Cmp(p2.X) == 0 && p.Y.Cmp(p2.Y) == 0 {
 		return true
 	}
 	return false
@@ -35,3 +35,37 @@ func (p ECPoint) Neg() ECPoint {
 	modValue := negY.Mod(negY, EC.C.Params().P) // mod P is fine here because we're describing a curve point
 	return ECPoint{p.X, modValue}
 }
+
+// IsValid returns true if point p is the identity or on the curve,
+// points from untrusted input should be checked before any arithmetic.
+func (p ECPoint) IsValid() bool {
+	if p.X == nil || p.Y == nil {
+		return false
+	}
+
+	if p.X.Sign() == 0 && p.Y.Sign() == 0 {
+		return true
+	}
+
+	return EC.C.IsOnCurve(p.X, p.Y)
+}
+
+// ValidPoints returns true if all the points are valid
+func ValidPoints(points ...ECPoint) bool {
+	for _, p := range points {
+		if !p.IsValid() {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidScalars returns true if all the scalars are in range [0, N)
+func ValidScalars(scalars ...*big.Int) bool {
+	for _, s := range scalars {
+		if s == nil || s.Sign() < 0 || s.Cmp(EC.N) >= 0 {
+			return false
+		}
+	}
+	return true
+}
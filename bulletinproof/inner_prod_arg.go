@@ -1,11 +1,8 @@
 package bp
 
 import (
-	"crypto/sha256"
-	"fmt"
 	"math"
 	"math/big"
-	"strconv"
 )
 
 /*
@@ -73,12 +70,8 @@ func InnerProductProveSub(proof InnerProdArg, G, H []ECPoint, a []*big.Int, b []
 	proof.L[curIt] = L
 	proof.R[curIt] = R
 
-	// prover sends L & R and gets a challenge
-	s256 := sha256.Sum256([]byte(
-		L.X.String() + L.Y.String() +
-			R.X.String() + R.Y.String()))
-
-	x := new(big.Int).SetBytes(s256[:])
+	// prover sends L & R and gets a challenge chained from the one of the previous round
+	x := HashChallenge(proof.Challenges[curIt+1], []ECPoint{L, R})
 
 	proof.Challenges[curIt] = x
 
@@ -111,17 +104,36 @@ func InnerProductProve(a []*big.Int, b []*big.Int, c *big.Int, P, U ECPoint, G,
 		big.NewInt(0),
 		challenges}
 
-	// randomly generate an x value from public data
-	x := sha256.Sum256([]byte(P.X.String() + P.Y.String()))
+	// randomly generate an x value from public data, U is bound to the challenges of the range proof
+	x := HashChallenge(nil, []ECPoint{P, U})
 
-	runningProof.Challenges[loglen] = new(big.Int).SetBytes(x[:])
+	runningProof.Challenges[loglen] = x
 
-	Pprime := P.Add(U.Mult(new(big.Int).Mul(new(big.Int).SetBytes(x[:]), c)))
-	ux := U.Mult(new(big.Int).SetBytes(x[:]))
+	Pprime := P.Add(U.Mult(new(big.Int).Mul(x, c)))
+	ux := U.Mult(x)
 	//fmt.Printf("Prover Pprime value to run sub off of: %s\n", Pprime)
 	return InnerProductProveSub(runningProof, G, H, a, b, ux, Pprime)
 }
 
+/*
+WellFormed checks the structure of the inner product argument for the vector
+length of the params, i.e. the number of rounds, the points and the scalars.
+*/
+func (ipp InnerProdArg) WellFormed() bool {
+	loglen := int(math.Log2(float64(EC.V)))
+	if len(ipp.L) != loglen || len(ipp.R) != loglen || len(ipp.Challenges) != loglen+1 {
+		return false
+	}
+
+	for _, c := range ipp.Challenges {
+		if c == nil {
+			return false
+		}
+	}
+
+	return ValidPoints(ipp.L...) && ValidPoints(ipp.R...) && ValidScalars(ipp.A, ipp.B)
+}
+
 /* Inner Product Verify
 Given a inner product proof, verifies the correctness of the proof
 Since we're using the Fiat-Shamir transform, we need to verify all x hash computations,
@@ -132,13 +144,11 @@ ipp : the proof
 func InnerProductVerify(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp InnerProdArg) bool {
 	//fmt.Println("Verifying Inner Product Argument")
 	//fmt.Printf("Commitment Value: %s \n", P)
-	s1 := sha256.Sum256([]byte(P.X.String() + P.Y.String()))
-	chal1 := new(big.Int).SetBytes(s1[:])
+	chal1 := HashChallenge(nil, []ECPoint{P, U})
 	ux := U.Mult(chal1)
 	curIt := len(ipp.Challenges) - 1
 
 	if ipp.Challenges[curIt].Cmp(chal1) != 0 {
-		fmt.Println("IPVerify - Initial Challenge Failed")
 		return false
 	}
 
@@ -153,15 +163,10 @@ func InnerProductVerify(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp InnerProdA
 		Lval := ipp.L[curIt]
 		Rval := ipp.R[curIt]
 
-		// prover sends L & R and gets a challenge
-		s256 := sha256.Sum256([]byte(
-			Lval.X.String() + Lval.Y.String() +
-				Rval.X.String() + Rval.Y.String()))
-
-		chal2 := new(big.Int).SetBytes(s256[:])
+		// prover sends L & R and gets a challenge chained from the one of the previous round
+		chal2 := HashChallenge(ipp.Challenges[curIt+1], []ECPoint{Lval, Rval})
 
 		if ipp.Challenges[curIt].Cmp(chal2) != 0 {
-			fmt.Println("IPVerify - Challenge verification failed at index " + strconv.Itoa(curIt))
 			return false
 		}
 
@@ -176,9 +181,6 @@ func InnerProductVerify(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp InnerProdA
 	Pcalc := Pcalc1.Add(Pcalc2).Add(Pcalc3)
 
 	if !Pprime.Equal(Pcalc) {
-		fmt.Println("IPVerify - Final Commitment checking failed")
-		fmt.Printf("Final Pprime value: %s \n", Pprime)
-		fmt.Printf("Calculated Pprime value to check against: %s \n", Pcalc)
 		return false
 	}
 
@@ -193,14 +195,12 @@ we replace n separate exponentiations with a single multi-exponentiation.
 func InnerProductVerifyFast(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp InnerProdArg) bool {
 	//fmt.Println("Verifying Inner Product Argument")
 	//fmt.Printf("Commitment Value: %s \n", P)
-	s1 := sha256.Sum256([]byte(P.X.String() + P.Y.String()))
-	chal1 := new(big.Int).SetBytes(s1[:])
+	chal1 := HashChallenge(nil, []ECPoint{P, U})
 	ux := U.Mult(chal1)
 	curIt := len(ipp.Challenges) - 1
 
 	// check all challenges
 	if ipp.Challenges[curIt].Cmp(chal1) != 0 {
-		fmt.Println("IPVerify - Initial Challenge Failed")
 		return false
 	}
 
@@ -208,15 +208,10 @@ func InnerProductVerifyFast(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp InnerP
 		Lval := ipp.L[j]
 		Rval := ipp.R[j]
 
-		// prover sends L & R and gets a challenge
-		s256 := sha256.Sum256([]byte(
-			Lval.X.String() + Lval.Y.String() +
-				Rval.X.String() + Rval.Y.String()))
-
-		chal2 := new(big.Int).SetBytes(s256[:])
+		// prover sends L & R and gets a challenge chained from the one of the previous round
+		chal2 := HashChallenge(ipp.Challenges[j+1], []ECPoint{Lval, Rval})
 
 		if ipp.Challenges[j].Cmp(chal2) != 0 {
-			fmt.Println("IPVerify - Challenge verification failed at index " + strconv.Itoa(j))
 			return false
 		}
 	}
@@ -258,9 +253,6 @@ func InnerProductVerifyFast(c *big.Int, P, U ECPoint, G, H []ECPoint, ipp InnerP
 	lhs := TwoVectorPCommitWithGens(G, H, ScalarVectorMul(sScalars, ipp.A), ScalarVectorMul(invsScalars, ipp.B)).Add(ux.Mult(ccalc))
 
 	if !rhs.Equal(lhs) {
-		fmt.Println("IPVerify - Final Commitment checking failed")
-		fmt.Printf("Final rhs value: %s \n", rhs)
-		fmt.Printf("Final lhs value: %s \n", lhs)
 		return false
 	}
 
@@ -2,7 +2,6 @@ package bp
 
 import (
 	"crypto/rand"
-	"crypto/sha256"
 	"fmt"
 	"math/big"
 )
@@ -120,7 +119,7 @@ func MRPProve(values []*big.Int) MultiRangeProof {
 			panic("Value is below range! Not proving")
 		}
 
-		if v.Cmp(new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(bitsPerValue)), EC.N)) == 1 {
+		if v.Cmp(new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(bitsPerValue)), EC.N)) != -1 {
 			panic("Value is above range! Not proving.")
 		}
 
@@ -156,12 +155,10 @@ func MRPProve(values []*big.Int) MultiRangeProof {
 	S := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, sL, sR).Add(EC.H.Mult(rho))
 	MRPResult.S = S
 
-	chal1s256 := sha256.Sum256([]byte(A.X.String() + A.Y.String()))
-	cy := new(big.Int).SetBytes(chal1s256[:])
+	cy := HashChallenge(nil, append(append([]ECPoint{}, Comms...), A, S))
 	MRPResult.Cy = cy
 
-	chal2s256 := sha256.Sum256([]byte(S.X.String() + S.Y.String()))
-	cz := new(big.Int).SetBytes(chal2s256[:])
+	cz := HashChallenge(cy, nil)
 	MRPResult.Cz = cz
 
 	zPowersTimesTwoVec := make([]*big.Int, EC.V)
@@ -234,8 +231,7 @@ func MRPProve(values []*big.Int) MultiRangeProof {
 	MRPResult.T1 = T1
 	MRPResult.T2 = T2
 
-	chal3s256 := sha256.Sum256([]byte(T1.X.String() + T1.Y.String() + T2.X.String() + T2.Y.String()))
-	cx := new(big.Int).SetBytes(chal3s256[:])
+	cx := HashChallenge(cz, []ECPoint{T1, T2})
 
 	MRPResult.Cx = cx
 
@@ -281,7 +277,7 @@ func MRPProve(values []*big.Int) MultiRangeProof {
 	P := TwoVectorPCommitWithGens(EC.BPG, HPrime, left, right)
 	//fmt.Println(P)
 
-	MRPResult.IPP = InnerProductProve(left, right, that, P, EC.U, EC.BPG, HPrime)
+	MRPResult.IPP = InnerProductProve(left, right, that, P, innerProductU(cx, taux, that, mu), EC.BPG, HPrime)
 
 	return MRPResult
 }
@@ -290,7 +286,30 @@ func MRPProve(values []*big.Int) MultiRangeProof {
 MultiRangeProof Verify
 Takes in a MultiRangeProof and verifies its correctness
 */
-func MRPVerify(mrp MultiRangeProof) bool {
+/*
+WellFormed checks the structure of the multi range proof, i.e. the number of
+commitments divides the vector length, all the points are on the curve and
+all the scalars are in range. Proofs from untrusted input must be checked
+before verification.
+*/
+func (mrp MultiRangeProof) WellFormed() bool {
+	m := len(mrp.Comms)
+	if m == 0 || EC.V%m != 0 {
+		return false
+	}
+
+	return ValidPoints(mrp.Comms...) &&
+		ValidPoints(mrp.A, mrp.S, mrp.T1, mrp.T2) &&
+		ValidScalars(mrp.Tau, mrp.Th, mrp.Mu) &&
+		mrp.Cy != nil && mrp.Cz != nil && mrp.Cx != nil &&
+		mrp.IPP.WellFormed()
+}
+
+func MRPVerify(mrp MultiRangeProof) error {
+	if !mrp.WellFormed() {
+		return ErrMalformedProof
+	}
+
 	m := len(mrp.Comms)
 	bitsPerValue := EC.V / m
 
@@ -299,23 +318,17 @@ func MRPVerify(mrp MultiRangeProof) bool {
 	// check 2 commitment generation is also different
 
 	// verify the challenges
-	chal1s256 := sha256.Sum256([]byte(mrp.A.X.String() + mrp.A.Y.String()))
-	cy := new(big.Int).SetBytes(chal1s256[:])
+	cy := HashChallenge(nil, append(append([]ECPoint{}, mrp.Comms...), mrp.A, mrp.S))
 	if cy.Cmp(mrp.Cy) != 0 {
-		fmt.Println("MRPVerify - Challenge Cy failing!")
-		return false
+		return ErrChallengeMismatch
 	}
-	chal2s256 := sha256.Sum256([]byte(mrp.S.X.String() + mrp.S.Y.String()))
-	cz := new(big.Int).SetBytes(chal2s256[:])
+	cz := HashChallenge(cy, nil)
 	if cz.Cmp(mrp.Cz) != 0 {
-		fmt.Println("MRPVerify - Challenge Cz failing!")
-		return false
+		return ErrChallengeMismatch
 	}
-	chal3s256 := sha256.Sum256([]byte(mrp.T1.X.String() + mrp.T1.Y.String() + mrp.T2.X.String() + mrp.T2.Y.String()))
-	cx := new(big.Int).SetBytes(chal3s256[:])
+	cx := HashChallenge(cz, []ECPoint{mrp.T1, mrp.T2})
 	if cx.Cmp(mrp.Cx) != 0 {
-		fmt.Println("RPVerify - Challenge Cx failing!")
-		return false
+		return ErrChallengeMismatch
 	}
 
 	// given challenges are correct, very range proof
@@ -338,10 +351,7 @@ func MRPVerify(mrp MultiRangeProof) bool {
 		mrp.T2.Mult(new(big.Int).Mul(cx, cx))).Add(CommPowers)
 
 	if !lhs.Equal(rhs) {
-		fmt.Println("MRPVerify - Uh oh! Check line (63) of verification")
-		fmt.Println(rhs)
-		fmt.Println(lhs)
-		return false
+		return ErrInvalidRangeProof
 	}
 
 	tmp1 := EC.Zero()
@@ -374,10 +384,9 @@ func MRPVerify(mrp MultiRangeProof) bool {
 	P := mrp.A.Add(mrp.S.Mult(cx)).Add(tmp1).Add(tmp2).Add(EC.H.Mult(mrp.Mu).Neg())
 	//fmt.Println(P)
 
-	if !InnerProductVerifyFast(mrp.Th, P, EC.U, EC.BPG, HPrime, mrp.IPP) {
-		fmt.Println("MRPVerify - Uh oh! Check line (65) of verification!")
-		return false
+	if !InnerProductVerifyFast(mrp.Th, P, innerProductU(cx, mrp.Tau, mrp.Th, mrp.Mu), EC.BPG, HPrime, mrp.IPP) {
+		return ErrInvalidRangeProof
 	}
 
-	return true
+	return nil
 }
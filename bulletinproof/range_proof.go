@@ -2,11 +2,22 @@ package bp
 
 import (
 	"crypto/rand"
-	"crypto/sha256"
+	"errors"
 	"fmt"
 	"math/big"
 )
 
+var (
+	// ErrMalformedProof is returned if the points or scalars of the range proof are invalid
+	ErrMalformedProof = errors.New("malformed range proof")
+
+	// ErrChallengeMismatch is returned if the challenges of the range proof are not derived from the commitments
+	ErrChallengeMismatch = errors.New("range proof challenge mismatch")
+
+	// ErrInvalidRangeProof is returned if the range proof fails to verify
+	ErrInvalidRangeProof = errors.New("invalid range proof")
+)
+
 type RangeProof struct {
 	Comm ECPoint
 	A    ECPoint
@@ -86,6 +97,18 @@ RPProver : Range Proof Prove
 Given a value v, provides a range proof that v is inside 0 to 2^64-1
 */
 func RPProve(v *big.Int) RangeProof {
+	gamma, err := rand.Int(rand.Reader, EC.N)
+	check(err)
+
+	return RPProveWithBlinding(v, gamma)
+}
+
+/*
+RPProveWithBlinding : Range Proof Prove with the given blinding factor
+Given a value v and blinding factor gamma, provides a range proof that
+the commitment v*G + gamma*H opens to a value inside 0 to 2^64-1
+*/
+func RPProveWithBlinding(v, gamma *big.Int) RangeProof {
 
 	rpresult := RangeProof{}
 
@@ -95,12 +118,10 @@ func RPProve(v *big.Int) RangeProof {
 		panic("Value is below range! Not proving")
 	}
 
-	if v.Cmp(new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(EC.V)), EC.N)) == 1 {
+	if v.Cmp(new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(EC.V)), EC.N)) != -1 {
 		panic("Value is above range! Not proving.")
 	}
 
-	gamma, err := rand.Int(rand.Reader, EC.N)
-	check(err)
 	comm := EC.G.Mult(v).Add(EC.H.Mult(gamma))
 	rpresult.Comm = comm
 
@@ -124,13 +145,11 @@ func RPProve(v *big.Int) RangeProof {
 	S := TwoVectorPCommitWithGens(EC.BPG, EC.BPH, sL, sR).Add(EC.H.Mult(rho))
 	rpresult.S = S
 
-	chal1s256 := sha256.Sum256([]byte(A.X.String() + A.Y.String()))
-	cy := new(big.Int).SetBytes(chal1s256[:])
+	cy := HashChallenge(nil, []ECPoint{comm, A, S})
 
 	rpresult.Cy = cy
 
-	chal2s256 := sha256.Sum256([]byte(S.X.String() + S.Y.String()))
-	cz := new(big.Int).SetBytes(chal2s256[:])
+	cz := HashChallenge(cy, nil)
 
 	rpresult.Cz = cz
 	z2 := new(big.Int).Exp(cz, big.NewInt(2), EC.N)
@@ -181,8 +200,7 @@ func RPProve(v *big.Int) RangeProof {
 	rpresult.T1 = T1
 	rpresult.T2 = T2
 
-	chal3s256 := sha256.Sum256([]byte(T1.X.String() + T1.Y.String() + T2.X.String() + T2.Y.String()))
-	cx := new(big.Int).SetBytes(chal3s256[:])
+	cx := HashChallenge(cz, []ECPoint{T1, T2})
 
 	rpresult.Cx = cx
 
@@ -246,30 +264,49 @@ func RPProve(v *big.Int) RangeProof {
 	//fmt.Println(P1)
 	//fmt.Println(P2)
 
-	rpresult.IPP = InnerProductProve(left, right, that, P, EC.U, EC.BPG, HPrime)
+	rpresult.IPP = InnerProductProve(left, right, that, P, innerProductU(cx, taux, thatPrime, mu), EC.BPG, HPrime)
 
 	return rpresult
 }
 
-func RPVerify(rp RangeProof) bool {
+/*
+innerProductU returns the fixed group element of the inner product argument
+scaled by a challenge on the final prover values, so that the inner product
+argument is bound to the range proof it belongs to.
+*/
+func innerProductU(cx, tau, th, mu *big.Int) ECPoint {
+	return EC.U.Mult(HashChallenge(cx, nil, tau, th, mu))
+}
+
+/*
+WellFormed checks the structure of the range proof, i.e. all the points
+are on the curve and all the scalars are in range. Proofs from untrusted
+input must be checked before verification.
+*/
+func (rp RangeProof) WellFormed() bool {
+	return ValidPoints(rp.Comm, rp.A, rp.S, rp.T1, rp.T2) &&
+		ValidScalars(rp.Tau, rp.Th, rp.Mu) &&
+		rp.Cy != nil && rp.Cz != nil && rp.Cx != nil &&
+		rp.IPP.WellFormed()
+}
+
+func RPVerify(rp RangeProof) error {
+	if !rp.WellFormed() {
+		return ErrMalformedProof
+	}
+
 	// verify the challenges
-	chal1s256 := sha256.Sum256([]byte(rp.A.X.String() + rp.A.Y.String()))
-	cy := new(big.Int).SetBytes(chal1s256[:])
+	cy := HashChallenge(nil, []ECPoint{rp.Comm, rp.A, rp.S})
 	if cy.Cmp(rp.Cy) != 0 {
-		fmt.Println("RPVerify - Challenge Cy failing!")
-		return false
+		return ErrChallengeMismatch
 	}
-	chal2s256 := sha256.Sum256([]byte(rp.S.X.String() + rp.S.Y.String()))
-	cz := new(big.Int).SetBytes(chal2s256[:])
+	cz := HashChallenge(cy, nil)
 	if cz.Cmp(rp.Cz) != 0 {
-		fmt.Println("RPVerify - Challenge Cz failing!")
-		return false
+		return ErrChallengeMismatch
 	}
-	chal3s256 := sha256.Sum256([]byte(rp.T1.X.String() + rp.T1.Y.String() + rp.T2.X.String() + rp.T2.Y.String()))
-	cx := new(big.Int).SetBytes(chal3s256[:])
+	cx := HashChallenge(cz, []ECPoint{rp.T1, rp.T2})
 	if cx.Cmp(rp.Cx) != 0 {
-		fmt.Println("RPVerify - Challenge Cx failing!")
-		return false
+		return ErrChallengeMismatch
 	}
 
 	// given challenges are correct, very range proof
@@ -285,10 +322,7 @@ func RPVerify(rp RangeProof) bool {
 		rp.T2.Mult(new(big.Int).Mul(cx, cx)))
 
 	if !lhs.Equal(rhs) {
-		fmt.Println("RPVerify - Uh oh! Check line (63) of verification")
-		fmt.Println(rhs)
-		fmt.Println(lhs)
-		return false
+		return ErrInvalidRangeProof
 	}
 
 	tmp1 := EC.Zero()
@@ -318,10 +352,9 @@ func RPVerify(rp RangeProof) bool {
 	P := rp.A.Add(rp.S.Mult(cx)).Add(tmp1).Add(tmp2).Add(EC.H.Mult(rp.Mu).Neg())
 	//fmt.Println(P)
 
-	if !InnerProductVerifyFast(rp.Th, P, EC.U, EC.BPG, HPrime, rp.IPP) {
-		fmt.Println("RPVerify - Uh oh! Check line (65) of verification!")
-		return false
+	if !InnerProductVerifyFast(rp.Th, P, innerProductU(cx, rp.Tau, rp.Th, rp.Mu), EC.BPG, HPrime, rp.IPP) {
+		return ErrInvalidRangeProof
 	}
 
-	return true
+	return nil
 }
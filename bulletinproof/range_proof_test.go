@@ -0,0 +1,99 @@
+package bp
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ECPoint_Equal(t *testing.T) {
+	assert.Equal(t, EC.G.Equal(EC.G), true)
+	assert.Equal(t, EC.G.Equal(EC.H), false)
+
+	// the negative point has the same x
+	assert.Equal(t, EC.G.Equal(EC.G.Neg()), false)
+}
+
+func Test_ECPoint_IsValid(t *testing.T) {
+	assert.Equal(t, EC.G.IsValid(), true)
+	assert.Equal(t, EC.Zero().IsValid(), true)
+	assert.Equal(t, ECPoint{big.NewInt(1), big.NewInt(2)}.IsValid(), false)
+	assert.Equal(t, ECPoint{}.IsValid(), false)
+}
+
+func Test_RPVerify(t *testing.T) {
+	maxValue := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(EC.V)), big.NewInt(1))
+
+	for _, v := range []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(123456789), maxValue} {
+		assert.Nil(t, RPVerify(RPProve(v)))
+	}
+}
+
+func Test_RPProve_OutOfRange(t *testing.T) {
+	assert.Panics(t, func() { RPProve(big.NewInt(-1)) })
+	assert.Panics(t, func() { RPProve(new(big.Int).Lsh(big.NewInt(1), uint(EC.V))) })
+}
+
+func Test_RPProveWithBlinding(t *testing.T) {
+	v, gamma := big.NewInt(100), big.NewInt(12345)
+	rp := RPProveWithBlinding(v, gamma)
+
+	assert.Equal(t, rp.Comm.Equal(EC.G.Mult(v).Add(EC.H.Mult(gamma))), true)
+	assert.Nil(t, RPVerify(rp))
+}
+
+func Test_RPVerify_ForgedCommitment(t *testing.T) {
+	rp := RPProve(big.NewInt(100))
+
+	// the proof is bound to the commitment
+	rp.Comm = rp.Comm.Add(EC.G)
+	assert.NotNil(t, RPVerify(rp))
+
+	// the challenges are recomputed by the verifier
+	rp = RPProve(big.NewInt(100))
+	rp.Comm = rp.Comm.Add(EC.G)
+	rp.Cy = HashChallenge(nil, []ECPoint{rp.Comm, rp.A, rp.S})
+	rp.Cz = HashChallenge(rp.Cy, nil)
+	rp.Cx = HashChallenge(rp.Cz, []ECPoint{rp.T1, rp.T2})
+	assert.NotNil(t, RPVerify(rp))
+}
+
+func Test_RPVerify_Malformed(t *testing.T) {
+	rp := RPProve(big.NewInt(100))
+	rp.A = ECPoint{big.NewInt(1), big.NewInt(2)}
+	assert.NotNil(t, RPVerify(rp))
+
+	rp = RPProve(big.NewInt(100))
+	rp.Tau = nil
+	assert.NotNil(t, RPVerify(rp))
+
+	rp = RPProve(big.NewInt(100))
+	rp.IPP.L = rp.IPP.L[1:]
+	assert.NotNil(t, RPVerify(rp))
+
+	rp = RPProve(big.NewInt(100))
+	rp.IPP.Challenges[0] = nil
+	assert.NotNil(t, RPVerify(rp))
+}
+
+func Test_MRPVerify(t *testing.T) {
+	mrp := MRPProve([]*big.Int{big.NewInt(1), big.NewInt(2)})
+	assert.Nil(t, MRPVerify(mrp))
+
+	mrp.Comms[1] = mrp.Comms[1].Add(EC.G)
+	assert.NotNil(t, MRPVerify(mrp))
+
+	mrp = MRPProve([]*big.Int{big.NewInt(1), big.NewInt(2)})
+	mrp.Comms = append(mrp.Comms, EC.G)
+	assert.NotNil(t, MRPVerify(mrp))
+}
+
+func Test_RPVerify_ForgedRound(t *testing.T) {
+	// the round challenges are chained, so a replaced round invalidates the later ones
+	rp := RPProve(big.NewInt(100))
+	last := len(rp.IPP.L) - 1
+	rp.IPP.L[last], rp.IPP.R[last] = rp.IPP.R[last], rp.IPP.L[last]
+	rp.IPP.Challenges[last] = HashChallenge(rp.IPP.Challenges[last+1], []ECPoint{rp.IPP.L[last], rp.IPP.R[last]})
+	assert.NotNil(t, RPVerify(rp))
+}
@@ -0,0 +1,113 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/seeleteam/go-seele/accounts/abi/bind"
+	"github.com/spf13/cobra"
+)
+
+var (
+	abiFile  string // path to the contract abi json
+	binFile  string // path to the contract bytecode, optional
+	typeName string // go struct name of the binding
+	pkgName  string // go package name of the binding
+	outFile  string // output file, stdout if empty
+)
+
+// rootCmd represents the base command called without any subcommands
+var rootCmd = &cobra.Command{
+	Use:   "abigen",
+	Short: "abigen command for generating the go bindings of contracts",
+	Long: `usage example:
+    abigen --abi token.abi --bin token.bin --pkg token --out token.go
+        generate the go binding of the token contract with the deploy method into token.go.
+    abigen --abi token.abi --pkg token --type Token
+        generate the go binding without the deploy method and print it to stdout.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := generate(); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+// Execute adds all child commands to the root command and sets flags appropriately.
+// This is called by main.main(). It only needs to happen once to the rootCmd.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&abiFile, "abi", "", "path to the contract abi json file, - for stdin")
+	rootCmd.MarkFlagRequired("abi")
+
+	rootCmd.Flags().StringVar(&binFile, "bin", "", "path to the contract bytecode file, used to generate the deploy method")
+	rootCmd.Flags().StringVar(&typeName, "type", "", "go struct name of the binding (default is the abi file name)")
+
+	rootCmd.Flags().StringVar(&pkgName, "pkg", "", "go package name of the binding")
+	rootCmd.MarkFlagRequired("pkg")
+
+	rootCmd.Flags().StringVar(&outFile, "out", "", "output file of the binding (default is stdout)")
+}
+
+// generate reads the abi and bytecode, and writes the generated binding.
+func generate() error {
+	var abiJSON []byte
+	var err error
+	if abiFile == "-" {
+		abiJSON, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		abiJSON, err = ioutil.ReadFile(abiFile)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read the abi, %s", err)
+	}
+
+	var bytecode string
+	if binFile != "" {
+		content, err := ioutil.ReadFile(binFile)
+		if err != nil {
+			return fmt.Errorf("failed to read the bytecode, %s", err)
+		}
+
+		bytecode = strings.TrimSpace(string(content))
+	}
+
+	if typeName == "" {
+		if abiFile == "-" {
+			return fmt.Errorf("the type name is required when the abi is read from stdin")
+		}
+
+		typeName = strings.TrimSuffix(filepath.Base(abiFile), filepath.Ext(abiFile))
+	}
+
+	code, err := bind.Bind([]string{typeName}, []string{string(abiJSON)}, []string{bytecode}, pkgName)
+	if err != nil {
+		return fmt.Errorf("failed to generate the binding, %s", err)
+	}
+
+	if outFile == "" {
+		fmt.Print(code)
+		return nil
+	}
+
+	if err = ioutil.WriteFile(outFile, []byte(code), 0600); err != nil {
+		return fmt.Errorf("failed to write the binding, %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package main
+
+import "github.com/seeleteam/go-seele/cmd/abigen/cmd"
+
+func main() {
+	cmd.Execute()
+}
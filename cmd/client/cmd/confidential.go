@@ -0,0 +1,263 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	bp "github.com/seeleteam/go-seele/bulletinproof"
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/rpc"
+	"github.com/urfave/cli"
+)
+
+var errBalanceOpening = errors.New("the balance and blinding do not match the confidential balance on chain")
+
+// confidentialOpening is the value and blinding factor of a commitment
+type confidentialOpening struct {
+	value    *big.Int
+	blinding *big.Int
+}
+
+// confidentialDeposit deposits the amount into the confidential balance of the sender
+func confidentialDeposit(client *rpc.Client) (interface{}, interface{}, error) {
+	blinding, err := parseBlinding(blindingValue)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dataBytes, err := json.Marshal(system.ConfidentialDeposit{Blinding: blinding})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := sendSystemContractTx(client, system.ConfidentialTransferContractAddress, system.CmdConfidentialDeposit, dataBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := make(map[string]interface{})
+	output["Tx"] = *tx
+	output["amount"] = tx.Data.Amount
+	output["blinding"] = blinding
+	return output, tx, err
+}
+
+// confidentialTransfer transfers a hidden amount from the confidential balance of the sender
+func confidentialTransfer(client *rpc.Client) (interface{}, interface{}, error) {
+	toAddr, err := resolveAddress(client, toValue)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid receiver address: %s", err)
+	}
+
+	amount, balance, err := parseConfidentialAmount()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var sent, remaining confidentialOpening
+	tx, err := sendSystemContractTxWithBuilder(client, system.ConfidentialTransferContractAddress, system.CmdConfidentialTransfer, func(txd *types.TransactionData) ([]byte, error) {
+		if err := checkConfidentialBalance(client, txd.From, balance); err != nil {
+			return nil, err
+		}
+
+		amountBlinding, err := rand.Int(rand.Reader, bp.EC.N)
+		if err != nil {
+			return nil, err
+		}
+
+		sent = confidentialOpening{amount, amountBlinding}
+		remaining = confidentialOpening{
+			new(big.Int).Sub(balance.value, amount),
+			new(big.Int).Mod(new(big.Int).Sub(balance.blinding, amountBlinding), bp.EC.N),
+		}
+
+		transfer := system.ConfidentialTransfer{
+			To:        toAddr,
+			Amount:    bp.RPProveWithBlinding(sent.value, sent.blinding),
+			Remaining: bp.RPProveWithBlinding(remaining.value, remaining.blinding),
+		}
+
+		// the hidden amount is not transferred with the tx
+		txd.Amount = big.NewInt(0)
+		return buildConfidentialPayload(txd, system.CmdConfidentialTransfer, transfer)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := make(map[string]interface{})
+	output["Tx"] = *tx
+	output["to"] = toAddr.Hex()
+	output["amount"] = sent.value
+	output["amountBlinding"] = sent.blinding
+	output["balance"] = remaining.value
+	output["blinding"] = remaining.blinding
+	return output, tx, err
+}
+
+// confidentialWithdraw withdraws the amount from the confidential balance of the sender
+func confidentialWithdraw(client *rpc.Client) (interface{}, interface{}, error) {
+	amount, balance, err := parseConfidentialAmount()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	remaining := confidentialOpening{new(big.Int).Sub(balance.value, amount), balance.blinding}
+	tx, err := sendSystemContractTxWithBuilder(client, system.ConfidentialTransferContractAddress, system.CmdConfidentialWithdraw, func(txd *types.TransactionData) ([]byte, error) {
+		if err := checkConfidentialBalance(client, txd.From, balance); err != nil {
+			return nil, err
+		}
+
+		withdrawal := system.ConfidentialWithdrawal{
+			Amount:    amount,
+			Remaining: bp.RPProveWithBlinding(remaining.value, remaining.blinding),
+		}
+
+		// the amount is withdrawn from the contract rather than transferred with the tx
+		txd.Amount = big.NewInt(0)
+		return buildConfidentialPayload(txd, system.CmdConfidentialWithdraw, withdrawal)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := make(map[string]interface{})
+	output["Tx"] = *tx
+	output["amount"] = amount
+	output["balance"] = remaining.value
+	output["blinding"] = remaining.blinding
+	return output, tx, err
+}
+
+// getConfidentialBalance gets the confidential balance commitment of the account
+func getConfidentialBalance(c *cli.Context) error {
+	account, err := common.HexToAddress(accountValue)
+	if err != nil {
+		return fmt.Errorf("invalid account address: %s", err)
+	}
+
+	client, err := dialRPC()
+	if err != nil {
+		return err
+	}
+
+	balance, err := callConfidentialBalance(client, account)
+	if err != nil {
+		return err
+	}
+
+	return handleCallResult(nil, balance)
+}
+
+// parseConfidentialAmount parses the amount to spend and the opening of the current balance
+func parseConfidentialAmount() (*big.Int, confidentialOpening, error) {
+	var balance confidentialOpening
+
+	amount, ok := new(big.Int).SetString(amountValue, 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, balance, errors.New("invalid amount value")
+	}
+
+	value, ok := new(big.Int).SetString(balanceValue, 10)
+	if !ok || value.Sign() < 0 {
+		return nil, balance, errors.New("invalid balance value")
+	}
+
+	if value.Cmp(amount) < 0 {
+		return nil, balance, errors.New("insufficient confidential balance")
+	}
+
+	blinding, ok := new(big.Int).SetString(blindingValue, 10)
+	if !ok || !bp.ValidScalars(blinding) {
+		return nil, balance, errors.New("invalid blinding value")
+	}
+
+	return amount, confidentialOpening{value, blinding}, nil
+}
+
+// parseBlinding parses the blinding factor, or generates a random one if empty
+func parseBlinding(value string) (*big.Int, error) {
+	if len(value) == 0 {
+		return rand.Int(rand.Reader, bp.EC.N)
+	}
+
+	blinding, ok := new(big.Int).SetString(value, 10)
+	if !ok || !bp.ValidScalars(blinding) {
+		return nil, errors.New("invalid blinding value")
+	}
+
+	return blinding, nil
+}
+
+// checkConfidentialBalance checks the opening matches the confidential balance of the account on chain,
+// so that the proofs are not built against a stale balance.
+func checkConfidentialBalance(client *rpc.Client, account common.Address, balance confidentialOpening) error {
+	onChain, err := callConfidentialBalance(client, account)
+	if err != nil {
+		return err
+	}
+
+	if !onChain.Equal(bp.EC.G.Mult(balance.value).Add(bp.EC.H.Mult(balance.blinding))) {
+		return errBalanceOpening
+	}
+
+	return nil
+}
+
+// callConfidentialBalance calls the contract to get the confidential balance commitment of the account
+func callConfidentialBalance(client *rpc.Client, account common.Address) (bp.ECPoint, error) {
+	var balance bp.ECPoint
+
+	payload := append([]byte{system.CmdGetConfidentialBalance}, account.Bytes()...)
+	var result map[string]interface{}
+	if err := client.Call(&result, "seele_call", system.ConfidentialTransferContractAddress.Hex(), hexutil.BytesToHex(payload), -1); err != nil {
+		return balance, fmt.Errorf("Failed to call rpc, %s", err)
+	}
+
+	if failed, ok := result["failed"].(bool); !ok || failed {
+		return balance, fmt.Errorf("Failed to get the confidential balance, %v", result["result"])
+	}
+
+	value, _ := result["result"].(string)
+	data, err := hexutil.HexToBytes(value)
+	if err != nil {
+		return balance, fmt.Errorf("Failed to convert Hex to Bytes %s", err)
+	}
+
+	if err = json.Unmarshal(data, &balance); err != nil {
+		return balance, fmt.Errorf("Failed to unmarshal the confidential balance, %s", err)
+	}
+
+	return balance, nil
+}
+
+// buildConfidentialPayload encodes the input, and raises the gas limit to cover
+// the payload and the proof verification if necessary.
+func buildConfidentialPayload(txd *types.TransactionData, method byte, input interface{}) ([]byte, error) {
+	dataBytes, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := append([]byte{method}, dataBytes...)
+	tx := &types.Transaction{Data: types.TransactionData{From: txd.From, To: txd.To, Payload: payload}}
+	// the confidential transfer is only available since the fork of the system contracts
+	gas := tx.IntrinsicGas() + system.GetContractByAddress(txd.To).RequiredGas(payload, common.SystemContractForkHeight)
+	if txd.GasLimit < gas {
+		fmt.Printf("the gas limit is raised to %d to verify the range proofs\n", gas)
+		txd.GasLimit = gas
+	}
+
+	return dataBytes, nil
+}
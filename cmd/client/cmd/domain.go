@@ -6,6 +6,12 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/contract/system"
 	"github.com/seeleteam/go-seele/rpc"
 )
@@ -40,3 +46,121 @@ func getDomainNameOwner(client *rpc.Client) (interface{}, interface{}, error) {
 
 	return tx, tx, err
 }
+
+// transferDomainName transfer the domain name to the new owner
+func transferDomainName(client *rpc.Client) (interface{}, interface{}, error) {
+	amountValue = "0"
+	if err := system.ValidateDomainName([]byte(nameValue)); err != nil {
+		return nil, nil, err
+	}
+
+	toAddr, err := resolveAddress(client, toValue)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid new owner address: %s", err)
+	}
+
+	dataBytes, err := json.Marshal(system.DomainNameTransfer{Name: nameValue, To: toAddr})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := sendSystemContractTx(client, system.DomainNameContractAddress, system.CmdTransferDomainName, dataBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tx, tx, err
+}
+
+// renewDomainName extend the expiry of the domain name
+func renewDomainName(client *rpc.Client) (interface{}, interface{}, error) {
+	return sendDomainNameTx(client, system.CmdRenewDomainName)
+}
+
+// releaseDomainName release the domain name
+func releaseDomainName(client *rpc.Client) (interface{}, interface{}, error) {
+	return sendDomainNameTx(client, system.CmdReleaseDomainName)
+}
+
+// setReverseDomainName set the domain name of the sender address
+func setReverseDomainName(client *rpc.Client) (interface{}, interface{}, error) {
+	return sendDomainNameTx(client, system.CmdSetReverseDomainName)
+}
+
+// setDomainNameResolver set the resolver records of the domain name
+func setDomainNameResolver(client *rpc.Client) (interface{}, interface{}, error) {
+	amountValue = "0"
+	if err := system.ValidateDomainName([]byte(nameValue)); err != nil {
+		return nil, nil, err
+	}
+
+	update := system.DomainNameResolverUpdate{Name: nameValue}
+	if len(accountValue) > 0 {
+		addr, err := common.HexToAddress(accountValue)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid address record: %s", err)
+		}
+		update.Address = addr
+	}
+
+	if len(hashValue) > 0 {
+		abiHash, err := common.HexToHash(hashValue)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid abi hash: %s", err)
+		}
+		update.ABIHash = abiHash
+	}
+
+	update.Text = textValue
+
+	dataBytes, err := json.Marshal(update)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := sendSystemContractTx(client, system.DomainNameContractAddress, system.CmdSetDomainNameResolver, dataBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tx, tx, err
+}
+
+// sendDomainNameTx send the domain name contract tx with the domain name as the input
+func sendDomainNameTx(client *rpc.Client, method byte) (interface{}, interface{}, error) {
+	amountValue = "0"
+	if err := system.ValidateDomainName([]byte(nameValue)); err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := sendSystemContractTx(client, system.DomainNameContractAddress, method, []byte(nameValue))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tx, tx, err
+}
+
+// resolveAddress returns the address in hex, or the address record of the domain name
+// with the domain name suffix, e.g. seele-fan.seele
+func resolveAddress(client *rpc.Client, value string) (common.Address, error) {
+	if !strings.HasSuffix(value, system.DomainNameSuffix) {
+		return common.HexToAddress(value)
+	}
+
+	if client == nil {
+		return common.EmptyAddress, errors.New("domain name could only be resolved with the rpc connection")
+	}
+
+	var record system.DomainNameRecord
+	if err := client.Call(&record, "seele_resolveDomainName", value); err != nil {
+		return common.EmptyAddress, fmt.Errorf("failed to resolve domain name %s, %s", value, err)
+	}
+
+	if record.Address.IsEmpty() {
+		return common.EmptyAddress, fmt.Errorf("no address record of domain name %s", value)
+	}
+
+	fmt.Printf("domain name %s resolves to %s\n", value, record.Address.Hex())
+	return record.Address, nil
+}
@@ -6,7 +6,10 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 
 	"github.com/seeleteam/go-seele/common"
 	"github.com/urfave/cli"
@@ -44,6 +47,37 @@ func (flag abiFlag) getValue() (interface{}, error) {
 	return "", nil
 }
 
+type traceConfigFlag struct {
+	cli.StringFlag
+}
+
+func (flag traceConfigFlag) getValue() (interface{}, error) {
+	return map[string]interface{}{"tracer": *flag.Destination}, nil
+}
+
+// jsonFileFlag is the flag of a json file, which is sent as a json object in the rpc request.
+type jsonFileFlag struct {
+	cli.StringFlag
+}
+
+func (flag jsonFileFlag) getValue() (interface{}, error) {
+	val := *flag.Destination
+	if len(val) == 0 {
+		return nil, nil
+	}
+
+	data, err := ioutil.ReadFile(val)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s file, err: %s", flag.Name, err)
+	}
+
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("invalid json in %s file %s", flag.Name, val)
+	}
+
+	return json.RawMessage(data), nil
+}
+
 var (
 	addressValue string
 	addressFlag  = cli.StringFlag{
@@ -119,7 +153,7 @@ var (
 	toValue string
 	toFlag  = cli.StringFlag{
 		Name:        "to",
-		Usage:       "to address",
+		Usage:       "to address, or domain name with the .seele suffix, e.g. seele-fan.seele",
 		Destination: &toValue,
 	}
 
@@ -250,6 +284,20 @@ var (
 		Destination: &preimageValue,
 	}
 
+	lockHeightValue uint64
+	lockHeightFlag  = cli.Uint64Flag{
+		Name:        "lockheight",
+		Usage:       "block height from which the HTLC could no longer be withdrawn, and could be refunded",
+		Destination: &lockHeightValue,
+	}
+
+	receiversValue string
+	receiversFlag  = cli.StringFlag{
+		Name:        "receivers",
+		Usage:       "receivers of the HTLC separated by comma, each is address:hashlock:amount",
+		Destination: &receiversValue,
+	}
+
 	nameValue string
 	nameFlag  = cli.StringFlag{
 		Name:        "name",
@@ -257,6 +305,13 @@ var (
 		Destination: &nameValue,
 	}
 
+	textValue string
+	textFlag  = cli.StringFlag{
+		Name:        "text",
+		Usage:       "text record of the domain name, e.g. a url or an email",
+		Destination: &textValue,
+	}
+
 	subChainJSONFileVale string
 	subChainJSONFileFlag = cli.StringFlag{
 		Name:        "file",
@@ -264,6 +319,41 @@ var (
 		Destination: &subChainJSONFileVale,
 	}
 
+	subChainVersionValue string
+	subChainVersionFlag  = cli.StringFlag{
+		Name:        "version",
+		Usage:       "subchain version",
+		Destination: &subChainVersionValue,
+	}
+
+	endpointsValue string
+	endpointsFlag  = cli.StringFlag{
+		Name:        "endpoints",
+		Usage:       "subchain rpc endpoints separated by comma",
+		Destination: &endpointsValue,
+	}
+
+	genesisHashValue string
+	genesisHashFlag  = cli.StringFlag{
+		Name:        "genesishash",
+		Usage:       "subchain genesis block hash",
+		Destination: &genesisHashValue,
+	}
+
+	verifiersValue string
+	verifiersFlag  = cli.StringFlag{
+		Name:        "verifiers",
+		Usage:       "new subchain verifier addresses separated by comma",
+		Destination: &verifiersValue,
+	}
+
+	signaturesValue string
+	signaturesFlag  = cli.StringFlag{
+		Name:        "signatures",
+		Usage:       "signatures of the current subchain verifiers separated by comma to approve the new verifiers",
+		Destination: &signaturesValue,
+	}
+
 	outPutValue string
 	outPutFlag  = cli.StringFlag{
 		Name:        "output,o",
@@ -285,6 +375,68 @@ var (
 		Value:       "sha256",
 		Destination: &algorithmValue,
 	}
+
+	moduleValue string
+	moduleFlag  = cli.StringFlag{
+		Name:        "module",
+		Usage:       "log module name, e.g. p2p, seele, bft_core",
+		Destination: &moduleValue,
+	}
+
+	levelValue string
+	levelFlag  = cli.StringFlag{
+		Name:        "level",
+		Usage:       "log level, could be debug, info, warn, error, fatal or panic",
+		Value:       "info",
+		Destination: &levelValue,
+	}
+
+	nodeIDValue string
+	nodeIDFlag  = cli.StringFlag{
+		Name:        "nodeid",
+		Usage:       "node ID of the peer",
+		Destination: &nodeIDValue,
+	}
+)
+
+// rpc connection, the token and tls flags apply to http(s) and ws(s) addresses
+var (
+	tokenValue string
+	tokenFlag  = cli.StringFlag{
+		Name:        "token",
+		Usage:       "bearer token to authenticate with the http or websocket rpc server",
+		Destination: &tokenValue,
+	}
+
+	tlsCAValue string
+	tlsCAFlag  = cli.StringFlag{
+		Name:        "tls-ca",
+		Usage:       "CA certificate file to verify the rpc server certificate",
+		Destination: &tlsCAValue,
+	}
+
+	tlsCertValue string
+	tlsCertFlag  = cli.StringFlag{
+		Name:        "tls-cert",
+		Usage:       "client certificate file for mutual TLS",
+		Destination: &tlsCertValue,
+	}
+
+	tlsKeyValue string
+	tlsKeyFlag  = cli.StringFlag{
+		Name:        "tls-key",
+		Usage:       "client private key file for mutual TLS",
+		Destination: &tlsKeyValue,
+	}
+
+	tlsInsecureValue bool
+	tlsInsecureFlag  = cli.BoolFlag{
+		Name:        "tls-insecure",
+		Usage:       "skip the verification of the rpc server certificate",
+		Destination: &tlsInsecureValue,
+	}
+
+	connectionFlags = []cli.Flag{tokenFlag, tlsCAFlag, tlsCertFlag, tlsKeyFlag, tlsInsecureFlag}
 )
 
 // GeneratePayload
@@ -317,4 +469,121 @@ var (
 		Name:  "args",
 		Usage: "the parameters of contract method",
 	}
+
+	tracerValue string
+	tracerFlag  = traceConfigFlag{
+		StringFlag: cli.StringFlag{
+			Name:        "tracer",
+			Usage:       "tracer name, could be callTracer or prestateTracer, opcode logs are traced if empty",
+			Destination: &tracerValue,
+		},
+	}
+
+	stateOverrideValue string
+	stateOverrideFlag  = jsonFileFlag{
+		StringFlag: cli.StringFlag{
+			Name:        "stateoverride",
+			Usage:       "json file of the account overrides, e.g. {\"0x...\": {\"balance\": 1, \"nonce\": 1, \"code\": \"0x...\", \"storage\": {\"0x...\": \"0x...\"}}}",
+			Destination: &stateOverrideValue,
+		},
+	}
+
+	blockOverrideValue string
+	blockOverrideFlag  = jsonFileFlag{
+		StringFlag: cli.StringFlag{
+			Name:        "blockoverride",
+			Usage:       "json file of the block context overrides, e.g. {\"height\": 100, \"timestamp\": 1560000000, \"creator\": \"0x...\"}",
+			Destination: &blockOverrideValue,
+		},
+	}
+
+	callsValue string
+	callsFlag  = jsonFileFlag{
+		StringFlag: cli.StringFlag{
+			Name:        "calls",
+			Usage:       "json file of the read-only calls, e.g. [{\"to\": \"0x...\", \"payload\": \"0x...\"}]",
+			Destination: &callsValue,
+		},
+	}
+
+	cumulativeValue bool
+	cumulativeFlag  = cli.BoolFlag{
+		Name:        "cumulative",
+		Usage:       "whether the later calls see the state changes of the earlier calls, default false",
+		Destination: &cumulativeValue,
+	}
+
+	verifyRequestValue string
+	verifyRequestFlag  = jsonFileFlag{
+		StringFlag: cli.StringFlag{
+			Name:        "request",
+			Usage:       "json file of the contract verification, e.g. {\"address\": \"0x...\", \"name\": \"Token\", \"source\": \"...\", \"compiler\": {\"version\": \"0.4.25\", \"optimize\": true, \"runs\": 200}}",
+			Destination: &verifyRequestValue,
+		},
+	}
+
+	balanceValue string
+	balanceFlag  = cli.StringFlag{
+		Name:        "balance",
+		Usage:       "current confidential balance, unit is fan",
+		Destination: &balanceValue,
+	}
+
+	blindingValue string
+	blindingFlag  = cli.StringFlag{
+		Name:        "blinding",
+		Usage:       "blinding factor in decimal of the confidential balance or deposit",
+		Destination: &blindingValue,
+	}
+
+	peerAddressValue string
+	peerAddressFlag  = cli.StringFlag{
+		Name:        "peeraddress",
+		Usage:       "address of the counterparty chain in the atomic swap",
+		Destination: &peerAddressValue,
+	}
+
+	peerAmountValue string
+	peerAmountFlag  = cli.StringFlag{
+		Name:        "peeramount",
+		Usage:       "amount in fan that the counterparty should lock in the atomic swap",
+		Destination: &peerAmountValue,
+	}
+
+	swapOfferValue string
+	swapOfferFlag  = cli.StringFlag{
+		Name:        "offer",
+		Usage:       "offer file of the atomic swap from the initiator",
+		Destination: &swapOfferValue,
+	}
+
+	swapIDValue string
+	swapIDFlag  = cli.StringFlag{
+		Name:        "id",
+		Usage:       "atomic swap id, which is the hash lock in hex",
+		Destination: &swapIDValue,
+	}
+
+	swapDirValue string
+	swapDirFlag  = cli.StringFlag{
+		Name:        "swapdir",
+		Value:       filepath.Join(common.GetDefaultDataFolder(), "swaps"),
+		Usage:       "directory to persist the atomic swaps",
+		Destination: &swapDirValue,
+	}
+
+	swapIntervalValue int64
+	swapIntervalFlag  = cli.Int64Flag{
+		Name:        "interval",
+		Value:       10,
+		Usage:       "interval in seconds to watch the HTLCs of the atomic swap",
+		Destination: &swapIntervalValue,
+	}
+
+	candidateValue string
+	candidateFlag  = cli.StringFlag{
+		Name:        "candidate",
+		Usage:       "address of the verifier candidate to stake",
+		Destination: &candidateValue,
+	}
 )
@@ -8,7 +8,10 @@ package cmd
 import (
 	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 	"time"
 
 	"github.com/seeleteam/go-seele/common"
@@ -28,7 +31,7 @@ func createHTLC(client *rpc.Client) (interface{}, interface{}, error) {
 	var data system.HashTimeLock
 	data.HashLock = hashLockBytes
 	data.TimeLock = timeLockValue
-	toAddr, err := common.HexToAddress(toValue)
+	toAddr, err := resolveAddress(client, toValue)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -123,6 +126,147 @@ func getHTLC(client *rpc.Client) (interface{}, interface{}, error) {
 	return output, tx, err
 }
 
+// createHeightHTLC create HTLC locked by block height with multiple receivers
+func createHeightHTLC(client *rpc.Client) (interface{}, interface{}, error) {
+	receivers, err := parseHTLCReceivers(client, receiversValue)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	amount := big.NewInt(0)
+	for _, receiver := range receivers {
+		amount.Add(amount, receiver.Amount)
+	}
+	amountValue = amount.String()
+
+	data := system.HeightHashTimeLock{LockHeight: lockHeightValue, Receivers: receivers}
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := sendSystemContractTx(client, system.HashTimeLockContractAddress, system.CmdNewHeightContract, dataBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := make(map[string]interface{})
+	output["Tx"] = *tx
+	output["LockHeight"] = lockHeightValue
+	output["Receivers"] = receivers
+	return output, tx, err
+}
+
+// withdrawHeightHTLC withdraw seele partially or fully from HTLC locked by block height
+func withdrawHeightHTLC(client *rpc.Client) (interface{}, interface{}, error) {
+	txHashBytes, err := common.HexToHash(hashValue)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to convert Hex to Hash %s", err)
+	}
+
+	preimageBytes, err := hexutil.HexToBytes(preimageValue)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to convert Hex to Bytes %s", err)
+	}
+
+	// the amount flag is the amount to withdraw rather than the tx amount
+	data := system.HeightWithdrawing{Hash: txHashBytes, Preimage: preimageBytes}
+	if len(amountValue) > 0 {
+		amount, ok := new(big.Int).SetString(amountValue, 10)
+		if !ok || amount.Sign() < 0 {
+			return nil, nil, errors.New("invalid amount value")
+		}
+		data.Amount = amount
+	}
+	amountValue = "0"
+
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := sendSystemContractTx(client, system.HashTimeLockContractAddress, system.CmdWithdrawHeight, dataBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := make(map[string]interface{})
+	output["Tx"] = *tx
+	output["hash"] = hashValue
+	output["preimage"] = preimageValue
+	output["amount"] = data.Amount
+	return output, tx, err
+}
+
+// refundHeightHTLC refund the seele not withdrawn from HTLC locked by block height
+func refundHeightHTLC(client *rpc.Client) (interface{}, interface{}, error) {
+	amountValue = "0"
+	txHashBytes, err := hexutil.HexToBytes(hashValue)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to convert Hex to Bytes %s", err)
+	}
+
+	tx, err := sendSystemContractTx(client, system.HashTimeLockContractAddress, system.CmdRefundHeight, txHashBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := make(map[string]interface{})
+	output["Tx"] = *tx
+	output["hash"] = hashValue
+	return output, tx, err
+}
+
+// getHeightHTLC used to get HTLC locked by block height
+func getHeightHTLC(client *rpc.Client) (interface{}, interface{}, error) {
+	amountValue = "0"
+	priceValue = "1"
+	txHashBytes, err := hexutil.HexToBytes(hashValue)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to convert Hex to Bytes %s", err)
+	}
+
+	tx, err := sendSystemContractTx(client, system.HashTimeLockContractAddress, system.CmdGetHeightContract, txHashBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := make(map[string]interface{})
+	output["Tx"] = *tx
+	output["hash"] = hashValue
+	return output, tx, err
+}
+
+// parseHTLCReceivers parses the receivers in the format of address:hashlock:amount separated by comma
+func parseHTLCReceivers(client *rpc.Client, value string) ([]*system.HTLCReceiver, error) {
+	var receivers []*system.HTLCReceiver
+	for _, item := range strings.Split(value, ",") {
+		fields := strings.Split(strings.TrimSpace(item), ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid receiver %s, it should be address:hashlock:amount", item)
+		}
+
+		to, err := resolveAddress(client, fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid receiver address: %s", err)
+		}
+
+		hashLock, err := hexutil.HexToBytes(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("Failed to convert Hex to Bytes %s", err)
+		}
+
+		amount, ok := new(big.Int).SetString(fields[2], 10)
+		if !ok || amount.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid receiver amount %s", fields[2])
+		}
+
+		receivers = append(receivers, &system.HTLCReceiver{To: to, HashLock: hashLock, Amount: amount})
+	}
+
+	return receivers, nil
+}
+
 // generateHTLCKey generate HTLC preimage and preimage hash
 func generateHTLCKey(c *cli.Context) error {
 	secret := make([]byte, 32)
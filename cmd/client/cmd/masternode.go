@@ -0,0 +1,23 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package cmd
+
+import (
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/rpc"
+)
+
+// sendMasternodeHeartbeat sends the heartbeat of the masternode in the current slot
+func sendMasternodeHeartbeat(client *rpc.Client) (interface{}, interface{}, error) {
+	amountValue = "0"
+
+	tx, err := sendSystemContractTx(client, system.MasternodeContractAddress, system.CmdHeartbeat, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tx, tx, err
+}
@@ -6,7 +6,6 @@
 package cmd
 
 import (
-	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,14 +23,25 @@ type callArgsFactory func(*cli.Context, *rpc.Client) ([]interface{}, error)
 type callResultHandler func(inputs []interface{}, result interface{}) error
 
 func rpcFlags(callArgFlags ...cli.Flag) []cli.Flag {
-	return append([]cli.Flag{addressFlag}, callArgFlags...)
+	flags := append([]cli.Flag{addressFlag}, connectionFlags...)
+	return append(flags, callArgFlags...)
+}
+
+func isConnectionFlag(flag cli.Flag) bool {
+	for _, f := range connectionFlags {
+		if f == flag {
+			return true
+		}
+	}
+
+	return flag == addressFlag
 }
 
 func parseCallArgs(context *cli.Context, client *rpc.Client) ([]interface{}, error) {
 	var args []interface{}
 
 	for _, flag := range context.Command.Flags {
-		if flag == addressFlag || flag == cli.HelpFlag {
+		if isConnectionFlag(flag) || flag == cli.HelpFlag {
 			continue
 		}
 
@@ -95,11 +105,11 @@ func rpcActionEx(namespace string, method string, argsFactory callArgsFactory, r
 		}
 
 		if namespace == "miner" {
-			if !strings.HasPrefix(addressValue, "127.0.0.1") && !strings.HasPrefix(addressValue, "localhost") {
+			if host := addressHost(addressValue); !strings.HasPrefix(host, "127.0.0.1") && !strings.HasPrefix(host, "localhost") {
 				return fmt.Errorf("miner methods only work for 127.0.0.1 (localhost)")
 			}
 		}
-		client, err := rpc.DialTCP(context.Background(), addressValue)
+		client, err := dialRPC()
 		if err != nil {
 			return err
 		}
@@ -121,7 +131,7 @@ func rpcActionEx(namespace string, method string, argsFactory callArgsFactory, r
 
 func rpcActionSystemContract(namespace string, method string, resultHandler callResultHandler) cli.ActionFunc {
 	return func(c *cli.Context) error {
-		client, err := rpc.DialTCP(context.Background(), addressValue)
+		client, err := dialRPC()
 		if err != nil {
 			return err
 		}
@@ -179,6 +179,36 @@ func AddCommands(app *cli.App, isFullNode bool) {
 				Flags:  rpcFlags(fromFlag, hashFlag),
 				Action: rpcActionSystemContract("htlc", "get", handleCallResult),
 			},
+			{
+				Name:   "createbyheight",
+				Usage:  "create HTLC locked by block height with multiple receivers, the amount is the sum of the receiver amounts",
+				Flags:  rpcFlags(fromFlag, receiversFlag, lockHeightFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("htlc", "createByHeight", handleCallResult),
+			},
+			{
+				Name:   "withdrawbyheight",
+				Usage:  "withdraw from HTLC locked by block height, all the remaining amount of the receiver is withdrawn if the amount is not specified",
+				Flags:  rpcFlags(fromFlag, amountFlag, priceFlag, gasLimitFlag, nonceFlag, hashFlag, preimageFlag),
+				Action: rpcActionSystemContract("htlc", "withdrawByHeight", handleCallResult),
+			},
+			{
+				Name:   "refundbyheight",
+				Usage:  "refund the amount not withdrawn from HTLC locked by block height",
+				Flags:  rpcFlags(fromFlag, priceFlag, gasLimitFlag, nonceFlag, hashFlag),
+				Action: rpcActionSystemContract("htlc", "refundByHeight", handleCallResult),
+			},
+			{
+				Name:   "getbyheight",
+				Usage:  "get HTLC locked by block height information",
+				Flags:  rpcFlags(fromFlag, hashFlag),
+				Action: rpcActionSystemContract("htlc", "getByHeight", handleCallResult),
+			},
+			{
+				Name:   "list",
+				Usage:  "list the HTLCs neither withdrawn nor refunded that the account sends or receives",
+				Flags:  rpcFlags(accountFlag),
+				Action: rpcAction("seele", "getHTLCs"),
+			},
 			{
 				Name:  "decode",
 				Usage: "decode HTLC contract information",
@@ -203,6 +233,68 @@ func AddCommands(app *cli.App, isFullNode bool) {
 		},
 	}
 
+	swapCommands := cli.Command{
+		Name:  "swap",
+		Usage: "atomic swap commands, the HTLCs on both chains are watched to redeem or refund",
+		Subcommands: []cli.Command{
+			{
+				Name:   "initiate",
+				Usage:  "lock the amount to the participant with a new secret, and print the offer. --time is the lock duration in seconds",
+				Flags:  rpcFlags(fromFlag, toFlag, amountFlag, peerAddressFlag, peerAmountFlag, priceFlag, gasLimitFlag, timeLockFlag, swapDirFlag),
+				Action: swapInitiate,
+			},
+			{
+				Name:   "accept",
+				Usage:  "validate the offer, and lock the amount to the initiator. --time is the lock duration in seconds",
+				Flags:  rpcFlags(fromFlag, swapOfferFlag, peerAddressFlag, amountFlag, priceFlag, gasLimitFlag, timeLockFlag, swapDirFlag),
+				Action: swapAccept,
+			},
+			{
+				Name:   "run",
+				Usage:  "watch the HTLCs of the swap until it is redeemed or refunded, the initiator specifies the participant HTLC by --hash",
+				Flags:  rpcFlags(fromFlag, swapIDFlag, hashFlag, priceFlag, gasLimitFlag, swapDirFlag, swapIntervalFlag),
+				Action: swapRun,
+			},
+			{
+				Name:   "list",
+				Usage:  "list the atomic swaps",
+				Flags:  []cli.Flag{swapDirFlag},
+				Action: swapList,
+			},
+		},
+	}
+
+	confidentialCommands := cli.Command{
+		Name:  "ct",
+		Usage: "confidential transfer commands, the balances are hidden by commitments and only the owners know the openings",
+		Subcommands: []cli.Command{
+			{
+				Name:   "deposit",
+				Usage:  "deposit the amount into the confidential balance, a random blinding is used if not specified",
+				Flags:  rpcFlags(fromFlag, amountFlag, priceFlag, gasLimitFlag, nonceFlag, blindingFlag),
+				Action: rpcActionSystemContract("ct", "deposit", handleCallResult),
+			},
+			{
+				Name:   "transfer",
+				Usage:  "transfer a hidden amount, the receiver should be told the amount and its blinding",
+				Flags:  rpcFlags(fromFlag, toFlag, amountFlag, balanceFlag, blindingFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("ct", "transfer", handleCallResult),
+			},
+			{
+				Name:   "withdraw",
+				Usage:  "withdraw the amount from the confidential balance",
+				Flags:  rpcFlags(fromFlag, amountFlag, balanceFlag, blindingFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("ct", "withdraw", handleCallResult),
+			},
+			{
+				Name:   "getbalance",
+				Usage:  "get the confidential balance commitment of the account",
+				Flags:  rpcFlags(accountFlag),
+				Action: getConfidentialBalance,
+			},
+		},
+	}
+
 	domainCommands := cli.Command{
 		Name:  "domain",
 		Usage: "system domain name commands",
@@ -219,6 +311,110 @@ func AddCommands(app *cli.App, isFullNode bool) {
 				Flags:  rpcFlags(fromFlag, priceFlag, gasLimitFlag, nameFlag, nonceFlag),
 				Action: rpcActionSystemContract("domain", "getOwner", handleCallResult),
 			},
+			{
+				Name:   "transfer",
+				Usage:  "transfer the domain name to the new owner",
+				Flags:  rpcFlags(fromFlag, priceFlag, gasLimitFlag, nameFlag, toFlag, nonceFlag),
+				Action: rpcActionSystemContract("domain", "transfer", handleCallResult),
+			},
+			{
+				Name:   "renew",
+				Usage:  "extend the expiry of the domain name by a year",
+				Flags:  rpcFlags(fromFlag, priceFlag, gasLimitFlag, nameFlag, nonceFlag),
+				Action: rpcActionSystemContract("domain", "renew", handleCallResult),
+			},
+			{
+				Name:   "release",
+				Usage:  "release the domain name, so that anyone could register it",
+				Flags:  rpcFlags(fromFlag, priceFlag, gasLimitFlag, nameFlag, nonceFlag),
+				Action: rpcActionSystemContract("domain", "release", handleCallResult),
+			},
+			{
+				Name:   "setresolver",
+				Usage:  "set the address, text and contract abi hash records of the domain name, the records not specified are cleared",
+				Flags:  rpcFlags(fromFlag, priceFlag, gasLimitFlag, nameFlag, accountFlag, textFlag, hashFlag, nonceFlag),
+				Action: rpcActionSystemContract("domain", "setResolver", handleCallResult),
+			},
+			{
+				Name:   "setreverse",
+				Usage:  "set the domain name of the sender, which should resolve to the sender",
+				Flags:  rpcFlags(fromFlag, priceFlag, gasLimitFlag, nameFlag, nonceFlag),
+				Action: rpcActionSystemContract("domain", "setReverse", handleCallResult),
+			},
+			{
+				Name:   "resolve",
+				Usage:  "get the records of the domain name",
+				Flags:  rpcFlags(nameFlag),
+				Action: rpcAction("seele", "resolveDomainName"),
+			},
+			{
+				Name:   "lookup",
+				Usage:  "get the domain name of the account",
+				Flags:  rpcFlags(accountFlag),
+				Action: rpcAction("seele", "lookupDomainName"),
+			},
+		},
+	}
+
+	masternodeCommands := cli.Command{
+		Name:  "masternode",
+		Usage: "system masternode commands",
+		Subcommands: []cli.Command{
+			{
+				Name:   "heartbeat",
+				Usage:  "send the heartbeat of the masternode, at least in 2/3 of the slots of an epoch to get the rewards",
+				Flags:  rpcFlags(fromFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("masternode", "heartbeat", handleCallResult),
+			},
+			{
+				Name:   "list",
+				Usage:  "list all the masternodes with their liveness, uptime and rewards",
+				Flags:  rpcFlags(),
+				Action: rpcAction("seele", "getMasternodes"),
+			},
+		},
+	}
+
+	stakingCommands := cli.Command{
+		Name:  "staking",
+		Usage: "system verifier staking commands",
+		Subcommands: []cli.Command{
+			{
+				Name:   "bond",
+				Usage:  "bond the amount to be a verifier candidate, the candidates with the most stake become the verifiers at each epoch",
+				Flags:  rpcFlags(fromFlag, amountFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("staking", "bond", handleCallResult),
+			},
+			{
+				Name:   "delegate",
+				Usage:  "delegate the amount to the verifier candidate",
+				Flags:  rpcFlags(fromFlag, candidateFlag, amountFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("staking", "delegate", handleCallResult),
+			},
+			{
+				Name:   "unbond",
+				Usage:  "unbond the amount from the verifier candidate, which could be withdrawn after the unbonding delay",
+				Flags:  rpcFlags(fromFlag, candidateFlag, amountFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("staking", "unbond", handleCallResult),
+			},
+			{
+				Name:   "withdraw",
+				Usage:  "withdraw the unbonded stake released after the unbonding delay",
+				Flags:  rpcFlags(fromFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("staking", "withdraw", handleCallResult),
+			},
+			{
+				Name:   "get",
+				Usage:  "get the verifier candidate with the stake delegated and unbonding by the sender",
+				Flags:  rpcFlags(fromFlag, candidateFlag),
+				Action: rpcActionSystemContract("staking", "get", handleCallResult),
+			},
+			{
+				Name:   "list",
+				Usage:  "list all the verifier candidates by stake, and the verifiers elected at the next epoch",
+				Flags:  rpcFlags(),
+				Action: rpcAction("seele", "getStakingCandidates"),
+			},
 		},
 	}
 
@@ -226,6 +422,60 @@ func AddCommands(app *cli.App, isFullNode bool) {
 		Name:  "subchain",
 		Usage: "system sub chain commands",
 		Subcommands: []cli.Command{
+			{
+				Name:   "register",
+				Usage:  "register the subchain of the json file",
+				Flags:  rpcFlags(fromFlag, subChainJSONFileFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("subchain", "register", handleCallResult),
+			},
+			{
+				Name:   "query",
+				Usage:  "query the registration information of the subchain",
+				Flags:  rpcFlags(fromFlag, nameFlag),
+				Action: rpcActionSystemContract("subchain", "query", handleCallResult),
+			},
+			{
+				Name:   "update",
+				Usage:  "update the version, static nodes, endpoints or genesis hash of the subchain by the owner",
+				Flags:  rpcFlags(fromFlag, nameFlag, subChainVersionFlag, staticNodesFlag, endpointsFlag, genesisHashFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("subchain", "update", handleCallResult),
+			},
+			{
+				Name:   "signverifiers",
+				Usage:  "sign the new verifiers by a current verifier to approve the change",
+				Flags:  rpcFlags(fromFlag, nameFlag, verifiersFlag),
+				Action: signSubChainVerifiers,
+			},
+			{
+				Name:   "updateverifiers",
+				Usage:  "replace the verifiers with the signatures of more than 2/3 current verifiers, or by the owner if no verifiers yet",
+				Flags:  rpcFlags(fromFlag, nameFlag, verifiersFlag, signaturesFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("subchain", "updateVerifiers", handleCallResult),
+			},
+			{
+				Name:   "pause",
+				Usage:  "pause the subchain by the owner",
+				Flags:  rpcFlags(fromFlag, nameFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("subchain", "pause", handleCallResult),
+			},
+			{
+				Name:   "resume",
+				Usage:  "resume the paused subchain by the owner",
+				Flags:  rpcFlags(fromFlag, nameFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("subchain", "resume", handleCallResult),
+			},
+			{
+				Name:   "deregister",
+				Usage:  "deregister the subchain by the owner, which could not be resumed",
+				Flags:  rpcFlags(fromFlag, nameFlag, priceFlag, gasLimitFlag, nonceFlag),
+				Action: rpcActionSystemContract("subchain", "deregister", handleCallResult),
+			},
+			{
+				Name:   "list",
+				Usage:  "list all the registered subchains with their status",
+				Flags:  rpcFlags(),
+				Action: rpcAction("seele", "getSubChains"),
+			},
 			{
 				Name:   "getblockcreator",
 				Usage:  "get block creator",
@@ -298,6 +548,12 @@ func AddCommands(app *cli.App, isFullNode bool) {
 				Flags:  rpcFlags(heightFlag),
 				Action: rpcAction("subchain", "getFee"),
 			},
+			{
+				Name:   "getverifierfees",
+				Usage:  "get the fees paid to the verifiers by their commits at the relay block",
+				Flags:  rpcFlags(heightFlag),
+				Action: rpcAction("subchain", "getVerifierFees"),
+			},
 			{
 				Name:   "sendtx",
 				Usage:  "send subchain transaction to node",
@@ -399,6 +655,61 @@ func AddCommands(app *cli.App, isFullNode bool) {
 		},
 	}
 
+	adminCommands := cli.Command{
+		Name:  "admin",
+		Usage: "node administration commands",
+		Subcommands: []cli.Command{
+			{
+				Name:   "setloglevel",
+				Usage:  "set log level of a module",
+				Flags:  rpcFlags(moduleFlag, levelFlag),
+				Action: rpcAction("admin", "setLogLevel"),
+			},
+			{
+				Name:   "getloglevels",
+				Usage:  "get log level of each module",
+				Flags:  rpcFlags(),
+				Action: rpcAction("admin", "getLogLevels"),
+			},
+			{
+				Name:   "getpeerpermission",
+				Usage:  "get the allowlist and denylist of the peers",
+				Flags:  rpcFlags(),
+				Action: rpcAction("admin", "getPeerPermission"),
+			},
+			{
+				Name:   "addallowedpeer",
+				Usage:  "add a node into the allowlist of the peers",
+				Flags:  rpcFlags(nodeIDFlag),
+				Action: rpcAction("admin", "addAllowedPeer"),
+			},
+			{
+				Name:   "removeallowedpeer",
+				Usage:  "remove a node from the allowlist of the peers",
+				Flags:  rpcFlags(nodeIDFlag),
+				Action: rpcAction("admin", "removeAllowedPeer"),
+			},
+			{
+				Name:   "adddeniedpeer",
+				Usage:  "add a node into the denylist of the peers",
+				Flags:  rpcFlags(nodeIDFlag),
+				Action: rpcAction("admin", "addDeniedPeer"),
+			},
+			{
+				Name:   "removedeniedpeer",
+				Usage:  "remove a node from the denylist of the peers",
+				Flags:  rpcFlags(nodeIDFlag),
+				Action: rpcAction("admin", "removeDeniedPeer"),
+			},
+			{
+				Name:   "reloadpeerpermission",
+				Usage:  "reload the permission file of the peers",
+				Flags:  rpcFlags(),
+				Action: rpcAction("admin", "reloadPeerPermission"),
+			},
+		},
+	}
+
 	// add full node support api
 	if isFullNode {
 		baseCommands = append(baseCommands, []cli.Command{
@@ -426,18 +737,48 @@ func AddCommands(app *cli.App, isFullNode bool) {
 				Flags:  rpcFlags(dumpFileFlag, gcBeforeDumpFlag),
 				Action: rpcAction("debug", "dumpHeap"),
 			},
+			{
+				Name:   "tracetx",
+				Usage:  "trace the execution of a packed transaction",
+				Flags:  rpcFlags(hashFlag, tracerFlag),
+				Action: rpcAction("debug", "traceTransaction"),
+			},
+			{
+				Name:   "traceblock",
+				Usage:  "trace the execution of the transactions in a block",
+				Flags:  rpcFlags(heightFlag, tracerFlag),
+				Action: rpcAction("debug", "traceBlock"),
+			},
 			{
 				Name:   "call",
 				Usage:  "call contract",
-				Flags:  rpcFlags(toFlag, payloadFlag, heightFlag),
+				Flags:  rpcFlags(toFlag, payloadFlag, heightFlag, stateOverrideFlag, blockOverrideFlag),
 				Action: rpcAction("seele", "call"),
 			},
+			{
+				Name:   "multicall",
+				Usage:  "call contracts in batch on the state of the same height",
+				Flags:  rpcFlags(heightFlag, callsFlag, cumulativeFlag),
+				Action: rpcAction("seele", "multiCall"),
+			},
 			{
 				Name:   "getlogs",
 				Usage:  "get logs",
 				Flags:  rpcFlags(heightFlag, contractFlag, abiFileFlag, eventNameFlag),
 				Action: rpcAction("seele", "getLogs"),
 			},
+			{
+				Name:   "verifycontract",
+				Usage:  "verify the solidity source of the deployed contract",
+				Flags:  rpcFlags(verifyRequestFlag),
+				Action: rpcAction("seele", "verifyContract"),
+			},
+			{
+				Name:   "getverifiedcontract",
+				Usage:  "get the verified source of the contract",
+				Flags:  rpcFlags(contractFlag),
+				Action: rpcAction("seele", "getVerifiedContract"),
+			},
 			{
 				Name:   "getdebtbyhash",
 				Usage:  "get debt by debt hash",
@@ -448,12 +789,16 @@ func AddCommands(app *cli.App, isFullNode bool) {
 
 		baseCommands = append(baseCommands,
 			htlcCommands,
+			swapCommands,
+			confidentialCommands,
 			domainCommands,
 			subChainCommands,
+			masternodeCommands,
+			stakingCommands,
 			minerCommands)
 	}
 
-	baseCommands = append(baseCommands, p2pCommands)
+	baseCommands = append(baseCommands, p2pCommands, adminCommands)
 
 	app.Commands = baseCommands
 
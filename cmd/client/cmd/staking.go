@@ -0,0 +1,107 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/rpc"
+)
+
+// bondStake bonds the amount by the sender to be a verifier candidate
+func bondStake(client *rpc.Client) (interface{}, interface{}, error) {
+	tx, err := sendSystemContractTx(client, system.StakingContractAddress, system.CmdStakingBond, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tx, tx, err
+}
+
+// delegateStake delegates the amount to the candidate
+func delegateStake(client *rpc.Client) (interface{}, interface{}, error) {
+	candidate, err := common.HexToAddress(candidateValue)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := sendSystemContractTx(client, system.StakingContractAddress, system.CmdStakingDelegate, candidate.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := make(map[string]interface{})
+	output["Tx"] = *tx
+	output["Candidate"] = candidate
+	return output, tx, err
+}
+
+// unbondStake unbonds the amount delegated to the candidate
+func unbondStake(client *rpc.Client) (interface{}, interface{}, error) {
+	candidate, err := common.HexToAddress(candidateValue)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	amount, ok := new(big.Int).SetString(amountValue, 10)
+	if !ok {
+		return nil, nil, errors.New("invalid amount value")
+	}
+
+	dataBytes, err := json.Marshal(system.StakingUnbond{Candidate: candidate, Amount: amount})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	amountValue = "0"
+	tx, err := sendSystemContractTx(client, system.StakingContractAddress, system.CmdStakingUnbond, dataBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := make(map[string]interface{})
+	output["Tx"] = *tx
+	output["Candidate"] = candidate
+	output["Amount"] = amount
+	return output, tx, err
+}
+
+// withdrawStake withdraws the released stake of the sender
+func withdrawStake(client *rpc.Client) (interface{}, interface{}, error) {
+	amountValue = "0"
+
+	tx, err := sendSystemContractTx(client, system.StakingContractAddress, system.CmdStakingWithdraw, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tx, tx, err
+}
+
+// getStakingCandidate gets the candidate with the stake delegated by the sender
+func getStakingCandidate(client *rpc.Client) (interface{}, interface{}, error) {
+	amountValue = "0"
+	priceValue = "1"
+
+	candidate, err := common.HexToAddress(candidateValue)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := sendSystemContractTx(client, system.StakingContractAddress, system.CmdGetStakingCandidate, candidate.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := make(map[string]interface{})
+	output["Tx"] = *tx
+	output["Candidate"] = candidate
+	return output, tx, err
+}
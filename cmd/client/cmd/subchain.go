@@ -6,7 +6,6 @@
 package cmd
 
 import (
-	"context"
 	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
@@ -20,6 +19,7 @@ import (
 	"github.com/seeleteam/go-seele/cmd/util"
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/common/keystore"
 	"github.com/seeleteam/go-seele/contract/system"
 	"github.com/seeleteam/go-seele/core"
 	"github.com/seeleteam/go-seele/crypto"
@@ -105,8 +105,189 @@ func querySubChain(client *rpc.Client) (interface{}, interface{}, error) {
 	return tx, tx, err
 }
 
+// updateSubChain updates the version, static nodes, endpoints or genesis hash of the subchain
+func updateSubChain(client *rpc.Client) (interface{}, interface{}, error) {
+	amountValue = "0"
+
+	if err := system.ValidateDomainName([]byte(nameValue)); err != nil {
+		return nil, nil, err
+	}
+
+	staticNodes, err := getStaticNodes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	update := system.SubChainUpdate{
+		Name:        nameValue,
+		Version:     subChainVersionValue,
+		StaticNodes: staticNodes,
+		Endpoints:   splitList(endpointsValue),
+	}
+
+	if len(genesisHashValue) > 0 {
+		if update.GenesisHash, err = common.HexToHash(genesisHashValue); err != nil {
+			return nil, nil, fmt.Errorf("invalid genesis hash: %s", err)
+		}
+	}
+
+	updateBytes, err := json.Marshal(update)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := sendSystemContractTx(client, system.SubChainContractAddress, system.CmdSubChainUpdate, updateBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := make(map[string]interface{})
+	output["Tx"] = *tx
+	output["SubChainUpdate"] = update
+	return output, tx, err
+}
+
+// updateSubChainVerifiers replaces the verifiers with the approvals of the current verifiers
+func updateSubChainVerifiers(client *rpc.Client) (interface{}, interface{}, error) {
+	amountValue = "0"
+
+	info, verifiers, err := getSubChainVerifierChange(client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	update := system.SubChainVerifierUpdate{Name: info.Name, Verifiers: verifiers, Nonce: info.VerifierNonce}
+	for _, value := range splitList(signaturesValue) {
+		sig, err := hexutil.HexToBytes(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid signature %s: %s", value, err)
+		}
+		update.Signatures = append(update.Signatures, crypto.Signature{Sig: sig})
+	}
+
+	updateBytes, err := json.Marshal(update)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := sendSystemContractTx(client, system.SubChainContractAddress, system.CmdSubChainUpdateVerifiers, updateBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := make(map[string]interface{})
+	output["Tx"] = *tx
+	output["SubChainName"] = info.Name
+	output["Verifiers"] = verifiers
+	output["Nonce"] = info.VerifierNonce
+	return output, tx, err
+}
+
+// pauseSubChain pauses the subchain
+func pauseSubChain(client *rpc.Client) (interface{}, interface{}, error) {
+	return changeSubChainStatus(client, system.CmdSubChainPause)
+}
+
+// resumeSubChain resumes the paused subchain
+func resumeSubChain(client *rpc.Client) (interface{}, interface{}, error) {
+	return changeSubChainStatus(client, system.CmdSubChainResume)
+}
+
+// deregisterSubChain deregisters the subchain, which could not be resumed
+func deregisterSubChain(client *rpc.Client) (interface{}, interface{}, error) {
+	return changeSubChainStatus(client, system.CmdSubChainDeregister)
+}
+
+func changeSubChainStatus(client *rpc.Client, method byte) (interface{}, interface{}, error) {
+	amountValue = "0"
+
+	if err := system.ValidateDomainName([]byte(nameValue)); err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := sendSystemContractTx(client, system.SubChainContractAddress, method, []byte(nameValue))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := make(map[string]interface{})
+	output["Tx"] = *tx
+	output["SubChainName"] = nameValue
+	return output, tx, err
+}
+
+// signSubChainVerifiers signs the new verifiers by a current verifier to approve the change
+func signSubChainVerifiers(c *cli.Context) error {
+	client, err := dialRPC()
+	if err != nil {
+		return err
+	}
+
+	info, verifiers, err := getSubChainVerifierChange(client)
+	if err != nil {
+		return err
+	}
+
+	pass, err := common.GetPassword()
+	if err != nil {
+		return fmt.Errorf("failed to get password %s", err)
+	}
+
+	key, err := keystore.GetKey(fromValue, pass)
+	if err != nil {
+		return fmt.Errorf("invalid verifier key file. it should be a private key: %s", err)
+	}
+
+	hash := system.SubChainVerifiersHash(info.Owner.Shard(), info.Name, info.VerifierNonce, verifiers)
+	sig, err := crypto.Sign(key.PrivateKey, hash.Bytes())
+	if err != nil {
+		return err
+	}
+
+	output := make(map[string]interface{})
+	output["verifier"] = crypto.GetAddress(&key.PrivateKey.PublicKey).Hex()
+	output["nonce"] = info.VerifierNonce
+	output["signature"] = hexutil.BytesToHex(sig.Sig)
+	return handleCallResult(nil, output)
+}
+
+// getSubChainVerifierChange returns the subchain on chain and the new verifiers of the flags
+func getSubChainVerifierChange(client *rpc.Client) (*system.SubChainInfo, []common.Address, error) {
+	info, err := getSubChainFromReceipt(client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var verifiers []common.Address
+	for _, value := range splitList(verifiersValue) {
+		verifier, err := common.HexToAddress(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid verifier address %s: %s", value, err)
+		}
+		verifiers = append(verifiers, verifier)
+	}
+
+	if len(verifiers) == 0 {
+		return nil, nil, errors.New("no verifiers specified")
+	}
+
+	return info, verifiers, nil
+}
+
+// splitList splits the comma separated values, and the empty values are skipped
+func splitList(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			values = append(values, item)
+		}
+	}
+
+	return values
+}
+
 func createSubChainConfigFile(c *cli.Context) error {
-	client, err := rpc.DialTCP(context.Background(), addressValue)
+	client, err := dialRPC()
 	if err != nil {
 		return err
 	}
@@ -51,16 +51,16 @@ func Test_getConfigFromSubChain(t *testing.T) {
 	assert.Equalf(t, 8, reflectBasic.NumField(), errFormat, "Node.BasicConfig")
 
 	reflectP2p := reflect.TypeOf(config.P2PConfig)
-	assert.Equalf(t, 5, reflectP2p.NumField(), errFormat, "p2p.Config")
+	assert.Equalf(t, 6, reflectP2p.NumField(), errFormat, "p2p.Config")
 
 	reflectLog := reflect.TypeOf(config.LogConfig)
-	assert.Equalf(t, 3, reflectLog.NumField(), errFormat, "comm.LogConfig")
+	assert.Equalf(t, 6, reflectLog.NumField(), errFormat, "comm.LogConfig")
 
 	reflectHTTPServer := reflect.TypeOf(config.HTTPServer)
-	assert.Equalf(t, 3, reflectHTTPServer.NumField(), errFormat, "node.HTTPServer")
+	assert.Equalf(t, 5, reflectHTTPServer.NumField(), errFormat, "node.HTTPServer")
 
 	reflectWSServer := reflect.TypeOf(config.WSServerConfig)
-	assert.Equalf(t, 2, reflectWSServer.NumField(), errFormat, "node.WSServerConfig")
+	assert.Equalf(t, 4, reflectWSServer.NumField(), errFormat, "node.WSServerConfig")
 
 	reflectGenesis := reflect.TypeOf(config.GenesisConfig)
 	assert.Equalf(t, 8, reflectGenesis.NumField(), errFormat, "core.GenesisInfo")
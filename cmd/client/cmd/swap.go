@@ -0,0 +1,575 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/urfave/cli"
+)
+
+const (
+	swapRoleInitiator   = "initiator"
+	swapRoleParticipant = "participant"
+
+	// swapStatusLocked the own HTLC is created, and the swap is in progress
+	swapStatusLocked = "locked"
+	// swapStatusRedeemed the HTLC of the counterparty is withdrawn
+	swapStatusRedeemed = "redeemed"
+	// swapStatusRefunded the own HTLC is refunded after the time lock
+	swapStatusRefunded = "refunded"
+	// swapStatusFailed the own HTLC is not created, or the counterparty HTLC could not be redeemed
+	swapStatusFailed = "failed"
+
+	// default time lock durations in seconds, the participant must be able to redeem
+	// after the preimage is revealed before the time lock of the initiator expires.
+	swapInitiatorTimeLock   = int64(48 * 3600)
+	swapParticipantTimeLock = int64(24 * 3600)
+
+	// swapTimeLockMargin is the least seconds that the participant HTLC expires
+	// before the initiator HTLC.
+	swapTimeLockMargin = int64(3600)
+
+	// swapRedeemMargin is the least seconds before the participant HTLC expires for
+	// the initiator to reveal the preimage, so that the withdrawal is included in time.
+	swapRedeemMargin = int64(600)
+)
+
+var (
+	errSwapNotFound      = errors.New("atomic swap not found")
+	errSwapInvalidOffer  = errors.New("the HTLC of the offer does not match")
+	errSwapTimeLockShort = errors.New("the time lock of the offer is too short")
+	errHTLCNotFound      = errors.New("HTLC not found")
+)
+
+// swapOffer is sent by the initiator to the participant to accept the atomic swap
+type swapOffer struct {
+	HashLock common.Bytes
+	// HTLC is the hash of the tx creating the initiator HTLC on the initiator chain
+	HTLC     common.Hash
+	Amount   *big.Int
+	TimeLock int64
+	// Recipient is the initiator account on the participant chain
+	Recipient common.Address
+	// PeerAmount is the amount that the participant should lock
+	PeerAmount *big.Int
+}
+
+// swapHTLC is the HTLC of a party in the atomic swap
+type swapHTLC struct {
+	// Address is the rpc address of the chain
+	Address string
+	// Hash is the hash of the tx creating the HTLC, empty if not known yet
+	Hash     common.Hash
+	Amount   *big.Int
+	TimeLock int64
+	To       common.Address
+	// PendingTx is the withdraw or refund tx not included yet
+	PendingTx common.Hash
+}
+
+// atomicSwap is the state of an atomic swap, which is persisted to resume after restarts
+type atomicSwap struct {
+	Role     string
+	HashLock common.Bytes
+	// Preimage is known by the initiator, and revealed to the participant after the initiator redeems
+	Preimage common.Bytes
+	// Own is the HTLC locked by this party on its chain
+	Own swapHTLC
+	// Peer is the HTLC locked by the counterparty on the other chain
+	Peer   swapHTLC
+	Status string
+}
+
+// ID returns the id of the swap, which is the hash lock in hex
+func (s *atomicSwap) ID() string {
+	return hexutil.BytesToHex(s.HashLock)
+}
+
+// swapTxStatus is the status of a tx sent to a chain
+type swapTxStatus int
+
+const (
+	swapTxPending swapTxStatus = iota
+	swapTxSucceeded
+	swapTxFailed
+	swapTxDropped
+)
+
+// swapChain is the chain that the HTLCs of an atomic swap are locked on
+type swapChain interface {
+	// account returns the account of this party on the chain
+	account() common.Address
+	// now returns the timestamp of the current block, which the time locks are checked against
+	now() (int64, error)
+	// getHTLC returns the HTLC created by the tx, or errHTLCNotFound if the tx is not included
+	getHTLC(hash common.Hash) (*system.HTLC, error)
+	newHTLC(lock system.HashTimeLock, amount *big.Int) (common.Hash, error)
+	withdraw(hash common.Hash, preimage []byte) (common.Hash, error)
+	refund(hash common.Hash) (common.Hash, error)
+	txStatus(hash common.Hash) (swapTxStatus, error)
+}
+
+// swapStore persists the atomic swaps in json files of the directory
+type swapStore struct {
+	dir string
+}
+
+func (store *swapStore) path(id string) string {
+	return filepath.Join(store.dir, id+".json")
+}
+
+func (store *swapStore) save(swap *atomicSwap) error {
+	if err := os.MkdirAll(store.dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(swap, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	// replace the file at once, so that a crash never leaves a partial state
+	tmp := store.path(swap.ID()) + ".tmp"
+	if err = ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, store.path(swap.ID()))
+}
+
+func (store *swapStore) load(id string) (*atomicSwap, error) {
+	data, err := ioutil.ReadFile(store.path(id))
+	if os.IsNotExist(err) {
+		return nil, errSwapNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var swap atomicSwap
+	if err = json.Unmarshal(data, &swap); err != nil {
+		return nil, fmt.Errorf("invalid atomic swap file, %s", err)
+	}
+
+	return &swap, nil
+}
+
+func (store *swapStore) list() ([]*atomicSwap, error) {
+	files, err := filepath.Glob(filepath.Join(store.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var swaps []*atomicSwap
+	for _, file := range files {
+		swap, err := store.load(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		swaps = append(swaps, swap)
+	}
+
+	return swaps, nil
+}
+
+// swapCoordinator advances the atomic swap by the HTLCs on both chains. It withdraws
+// the counterparty HTLC once the preimage is known, and refunds the own HTLC after
+// the time lock if the counterparty has not withdrawn it.
+type swapCoordinator struct {
+	swap  *atomicSwap
+	own   swapChain
+	peer  swapChain
+	store *swapStore
+}
+
+// run advances the swap at the interval until it is finished
+func (c *swapCoordinator) run(interval time.Duration) error {
+	for {
+		finished, err := c.step()
+		if err != nil {
+			fmt.Printf("failed to advance the atomic swap %s, %s\n", c.swap.ID(), err)
+		} else if finished {
+			fmt.Printf("atomic swap %s is %s\n", c.swap.ID(), c.swap.Status)
+			return nil
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// step advances the swap once, and returns whether the swap is finished
+func (c *swapCoordinator) step() (bool, error) {
+	s := c.swap
+	if s.Status != swapStatusLocked {
+		return true, nil
+	}
+
+	own, err := c.own.getHTLC(s.Own.Hash)
+	if err == errHTLCNotFound {
+		// nothing is locked if the tx creating the own HTLC is not included
+		status, err := c.own.txStatus(s.Own.Hash)
+		if err != nil || status == swapTxPending || status == swapTxSucceeded {
+			return false, err
+		}
+
+		return c.finish(swapStatusFailed)
+	} else if err != nil {
+		return false, err
+	}
+
+	if own.Refunded {
+		return c.finish(swapStatusRefunded)
+	}
+
+	// the counterparty reveals the preimage to withdraw the own HTLC
+	if len(s.Preimage) == 0 && own.Withdrawed {
+		s.Preimage = own.Preimage
+		fmt.Printf("the preimage %s is revealed by the counterparty\n", hexutil.BytesToHex(s.Preimage))
+		if err = c.store.save(s); err != nil {
+			return false, err
+		}
+	}
+
+	peer, err := c.redeem()
+	if err != nil {
+		return false, err
+	}
+
+	if peer != nil && peer.Withdrawed {
+		return c.finish(swapStatusRedeemed)
+	}
+
+	// too late to redeem the counterparty HTLC which is refunded
+	if peer != nil && peer.Refunded && own.Withdrawed {
+		return c.finish(swapStatusFailed)
+	}
+
+	return false, c.refund(own)
+}
+
+// redeem withdraws the counterparty HTLC if the preimage is known, and returns the HTLC if found
+func (c *swapCoordinator) redeem() (*system.HTLC, error) {
+	s := c.swap
+	if s.Peer.Hash.IsEmpty() {
+		return nil, nil
+	}
+
+	if pending, err := c.pending(c.peer, &s.Peer); err != nil || pending {
+		return nil, err
+	}
+
+	peer, err := c.peer.getHTLC(s.Peer.Hash)
+	if err == errHTLCNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if peer.Withdrawed || peer.Refunded || len(s.Preimage) == 0 {
+		return peer, nil
+	}
+
+	// never reveal the preimage to an invalid HTLC, the own HTLC is refunded after the time lock
+	if err = c.validatePeer(peer); err != nil {
+		fmt.Printf("the counterparty HTLC %s is invalid, %s\n", s.Peer.Hash.Hex(), err)
+		return nil, nil
+	}
+
+	now, err := c.peer.now()
+	if err != nil {
+		return nil, err
+	}
+
+	// the preimage is public once the initiator sends the withdrawal
+	margin := int64(0)
+	if s.Role == swapRoleInitiator {
+		margin = swapRedeemMargin
+	}
+
+	if now+margin >= peer.TimeLock {
+		return peer, nil
+	}
+
+	if s.Peer.PendingTx, err = c.peer.withdraw(s.Peer.Hash, s.Preimage); err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("withdraw the counterparty HTLC %s, tx %s\n", s.Peer.Hash.Hex(), s.Peer.PendingTx.Hex())
+	return peer, c.store.save(s)
+}
+
+// refund refunds the own HTLC after the time lock if the counterparty has not withdrawn it
+func (c *swapCoordinator) refund(own *system.HTLC) error {
+	s := c.swap
+	if own.Withdrawed {
+		return nil
+	}
+
+	if pending, err := c.pending(c.own, &s.Own); err != nil || pending {
+		return err
+	}
+
+	now, err := c.own.now()
+	if err != nil || now < own.TimeLock {
+		return err
+	}
+
+	if s.Own.PendingTx, err = c.own.refund(s.Own.Hash); err != nil {
+		return err
+	}
+
+	fmt.Printf("refund the own HTLC %s, tx %s\n", s.Own.Hash.Hex(), s.Own.PendingTx.Hex())
+	return c.store.save(s)
+}
+
+// pending returns whether the last tx sent for the HTLC is not included yet
+func (c *swapCoordinator) pending(chain swapChain, htlc *swapHTLC) (bool, error) {
+	if htlc.PendingTx.IsEmpty() {
+		return false, nil
+	}
+
+	status, err := chain.txStatus(htlc.PendingTx)
+	if err != nil || status == swapTxPending {
+		return true, err
+	}
+
+	if status != swapTxSucceeded {
+		fmt.Printf("tx %s of the HTLC %s is failed or dropped\n", htlc.PendingTx.Hex(), htlc.Hash.Hex())
+	}
+
+	htlc.PendingTx = common.EmptyHash
+	return false, c.store.save(c.swap)
+}
+
+// validatePeer checks the counterparty HTLC pays this party with the agreed terms
+func (c *swapCoordinator) validatePeer(peer *system.HTLC) error {
+	s := c.swap
+	if !peer.To.Equal(c.peer.account()) || !bytes.Equal(peer.HashLock, s.HashLock) {
+		return errSwapInvalidOffer
+	}
+
+	if s.Peer.Amount != nil && peer.Tx.Data.Amount.Cmp(s.Peer.Amount) < 0 {
+		return errSwapInvalidOffer
+	}
+
+	if s.Role == swapRoleInitiator && peer.TimeLock+swapTimeLockMargin > s.Own.TimeLock {
+		return errSwapTimeLockShort
+	}
+
+	return nil
+}
+
+func (c *swapCoordinator) finish(status string) (bool, error) {
+	c.swap.Status = status
+	return true, c.store.save(c.swap)
+}
+
+// swapInitiate locks the amount to the participant with a new secret, and prints the offer
+func swapInitiate(c *cli.Context) error {
+	amount, ok := new(big.Int).SetString(amountValue, 10)
+	if !ok || amount.Sign() <= 0 {
+		return errors.New("invalid amount value")
+	}
+
+	peerAmount, ok := new(big.Int).SetString(peerAmountValue, 10)
+	if !ok || peerAmount.Sign() <= 0 {
+		return errors.New("invalid peer amount value")
+	}
+
+	own, peer, err := dialSwapChains(addressValue, peerAddressValue)
+	if err != nil {
+		return err
+	}
+
+	to, err := resolveAddress(own.client, toValue)
+	if err != nil {
+		return fmt.Errorf("invalid participant address: %s", err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err = rand.Read(secret); err != nil {
+		return err
+	}
+
+	now, err := own.now()
+	if err != nil {
+		return err
+	}
+
+	lock := system.HashTimeLock{
+		HashLock: system.Sha256Hash(secret),
+		TimeLock: now + swapTimeLock(swapInitiatorTimeLock),
+		To:       to,
+	}
+
+	hash, err := own.newHTLC(lock, amount)
+	if err != nil {
+		return err
+	}
+
+	swap := &atomicSwap{
+		Role:     swapRoleInitiator,
+		HashLock: lock.HashLock,
+		Preimage: secret,
+		Own:      swapHTLC{Address: addressValue, Hash: hash, Amount: amount, TimeLock: lock.TimeLock, To: to},
+		Peer:     swapHTLC{Address: peerAddressValue, Amount: peerAmount, To: peer.account()},
+		Status:   swapStatusLocked,
+	}
+
+	if err = (&swapStore{swapDirValue}).save(swap); err != nil {
+		return err
+	}
+
+	return handleCallResult(nil, swapOffer{
+		HashLock:   swap.HashLock,
+		HTLC:       hash,
+		Amount:     amount,
+		TimeLock:   lock.TimeLock,
+		Recipient:  peer.account(),
+		PeerAmount: peerAmount,
+	})
+}
+
+// swapAccept validates the initiator HTLC of the offer, and locks the amount to the initiator
+func swapAccept(c *cli.Context) error {
+	data, err := ioutil.ReadFile(swapOfferValue)
+	if err != nil {
+		return fmt.Errorf("failed to read the offer file, %s", err)
+	}
+
+	var offer swapOffer
+	if err = json.Unmarshal(data, &offer); err != nil {
+		return fmt.Errorf("invalid offer, %s", err)
+	}
+
+	amount := offer.PeerAmount
+	if len(amountValue) > 0 {
+		var ok bool
+		if amount, ok = new(big.Int).SetString(amountValue, 10); !ok {
+			return errors.New("invalid amount value")
+		}
+	}
+
+	if amount == nil || amount.Sign() <= 0 {
+		return errors.New("invalid amount value")
+	}
+
+	own, peer, err := dialSwapChains(addressValue, peerAddressValue)
+	if err != nil {
+		return err
+	}
+
+	// the terms are checked against the HTLC on chain instead of the offer
+	initiator, err := peer.getHTLC(offer.HTLC)
+	if err != nil {
+		return err
+	}
+
+	if !initiator.To.Equal(peer.account()) || !bytes.Equal(initiator.HashLock, offer.HashLock) ||
+		initiator.Tx.Data.Amount.Cmp(offer.Amount) != 0 || initiator.Withdrawed || initiator.Refunded {
+		return errSwapInvalidOffer
+	}
+
+	now, err := own.now()
+	if err != nil {
+		return err
+	}
+
+	lock := system.HashTimeLock{
+		HashLock: offer.HashLock,
+		TimeLock: now + swapTimeLock(swapParticipantTimeLock),
+		To:       offer.Recipient,
+	}
+
+	if lock.TimeLock+swapTimeLockMargin > initiator.TimeLock {
+		return errSwapTimeLockShort
+	}
+
+	hash, err := own.newHTLC(lock, amount)
+	if err != nil {
+		return err
+	}
+
+	swap := &atomicSwap{
+		Role:     swapRoleParticipant,
+		HashLock: offer.HashLock,
+		Own:      swapHTLC{Address: addressValue, Hash: hash, Amount: amount, TimeLock: lock.TimeLock, To: offer.Recipient},
+		Peer:     swapHTLC{Address: peerAddressValue, Hash: offer.HTLC, Amount: offer.Amount, TimeLock: initiator.TimeLock, To: peer.account()},
+		Status:   swapStatusLocked,
+	}
+
+	if err = (&swapStore{swapDirValue}).save(swap); err != nil {
+		return err
+	}
+
+	output := make(map[string]interface{})
+	output["id"] = swap.ID()
+	output["HTLC"] = hash
+	output["TimeLock"] = lock.TimeLock
+	return handleCallResult(nil, output)
+}
+
+// swapRun watches the HTLCs of the swap until it is redeemed or refunded
+func swapRun(c *cli.Context) error {
+	store := &swapStore{swapDirValue}
+	swap, err := store.load(swapIDValue)
+	if err != nil {
+		return err
+	}
+
+	// the initiator learns the participant HTLC after the offer is accepted
+	if len(hashValue) > 0 {
+		if swap.Peer.Hash, err = common.HexToHash(hashValue); err != nil {
+			return fmt.Errorf("invalid participant HTLC hash, %s", err)
+		}
+
+		if err = store.save(swap); err != nil {
+			return err
+		}
+	}
+
+	own, peer, err := dialSwapChains(swap.Own.Address, swap.Peer.Address)
+	if err != nil {
+		return err
+	}
+
+	coordinator := &swapCoordinator{swap, own, peer, store}
+	return coordinator.run(time.Duration(swapIntervalValue) * time.Second)
+}
+
+// swapList lists the atomic swaps
+func swapList(c *cli.Context) error {
+	swaps, err := (&swapStore{swapDirValue}).list()
+	if err != nil {
+		return err
+	}
+
+	return handleCallResult(nil, swaps)
+}
+
+// swapTimeLock returns the time lock duration flag, or the default one if not specified
+func swapTimeLock(defaultValue int64) int64 {
+	if timeLockValue > 0 {
+		return timeLockValue
+	}
+
+	return defaultValue
+}
@@ -0,0 +1,167 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/seeleteam/go-seele/cmd/util"
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/common/keystore"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/seeleteam/go-seele/rpc"
+)
+
+// rpcSwapChain is the chain of an atomic swap accessed by rpc, which signs txs with the key
+type rpcSwapChain struct {
+	client   *rpc.Client
+	key      *keystore.Key
+	price    *big.Int
+	gasLimit uint64
+}
+
+// dialSwapChains connects to the own chain and the counterparty chain of the swap, which share the key of the from flag
+func dialSwapChains(ownAddress, peerAddress string) (*rpcSwapChain, *rpcSwapChain, error) {
+	if len(peerAddress) == 0 {
+		return nil, nil, errors.New("the address of the counterparty chain is required")
+	}
+
+	price, ok := new(big.Int).SetString(priceValue, 10)
+	if !ok {
+		return nil, nil, errors.New("invalid gas price value")
+	}
+
+	pass, err := common.GetPassword()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get password %s", err)
+	}
+
+	key, err := keystore.GetKey(fromValue, pass)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid sender key file. it should be a private key: %s", err)
+	}
+
+	ownClient, err := dialRPCAddress(ownAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	peerClient, err := dialRPCAddress(peerAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	own := &rpcSwapChain{ownClient, key, price, gasLimitValue}
+	peer := &rpcSwapChain{peerClient, key, price, gasLimitValue}
+	return own, peer, nil
+}
+
+func (chain *rpcSwapChain) account() common.Address {
+	return *crypto.GetAddress(&chain.key.PrivateKey.PublicKey)
+}
+
+func (chain *rpcSwapChain) now() (int64, error) {
+	var block struct {
+		Header types.BlockHeader `json:"header"`
+	}
+
+	if err := chain.client.Call(&block, "seele_getBlockByHeight", -1, false); err != nil {
+		return 0, fmt.Errorf("Failed to get the current block, %s", err)
+	}
+
+	return block.Header.CreateTimestamp.Int64(), nil
+}
+
+func (chain *rpcSwapChain) getHTLC(hash common.Hash) (*system.HTLC, error) {
+	payload := append([]byte{system.CmdGetContract}, hash.Bytes()...)
+	result, err := util.CallContract(chain.client, system.HashTimeLockContractAddress.Hex(), hexutil.BytesToHex(payload), -1)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to call rpc, %s", err)
+	}
+
+	// the HTLC is not found if the call failed
+	if failed, ok := result["failed"].(bool); !ok || failed {
+		return nil, errHTLCNotFound
+	}
+
+	value, _ := result["result"].(string)
+	data, err := hexutil.HexToBytes(value)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to convert Hex to Bytes %s", err)
+	}
+
+	var htlc system.HTLC
+	if err = json.Unmarshal(data, &htlc); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal HTLC, %s", err)
+	}
+
+	return &htlc, nil
+}
+
+func (chain *rpcSwapChain) newHTLC(lock system.HashTimeLock, amount *big.Int) (common.Hash, error) {
+	dataBytes, err := json.Marshal(lock)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+
+	return chain.sendTx(amount, system.CmdNewContract, dataBytes)
+}
+
+func (chain *rpcSwapChain) withdraw(hash common.Hash, preimage []byte) (common.Hash, error) {
+	dataBytes, err := json.Marshal(system.Withdrawing{Hash: hash, Preimage: preimage})
+	if err != nil {
+		return common.EmptyHash, err
+	}
+
+	return chain.sendTx(big.NewInt(0), system.CmdWithdraw, dataBytes)
+}
+
+func (chain *rpcSwapChain) refund(hash common.Hash) (common.Hash, error) {
+	return chain.sendTx(big.NewInt(0), system.CmdRefund, hash.Bytes())
+}
+
+func (chain *rpcSwapChain) txStatus(hash common.Hash) (swapTxStatus, error) {
+	var receipt map[string]interface{}
+	if err := chain.client.Call(&receipt, "txpool_getReceiptByTxHash", hash.Hex(), ""); err == nil {
+		if failed, _ := receipt["failed"].(bool); failed {
+			return swapTxFailed, nil
+		}
+
+		return swapTxSucceeded, nil
+	}
+
+	if _, err := util.GetTransactionByHash(chain.client, hash.Hex()); err != nil {
+		return swapTxDropped, nil
+	}
+
+	return swapTxPending, nil
+}
+
+// sendTx sends the tx to the HTLC contract, and returns the tx hash
+func (chain *rpcSwapChain) sendTx(amount *big.Int, method byte, payload []byte) (common.Hash, error) {
+	nonce, err := util.GetAccountNonce(chain.client, chain.account(), "", -1)
+	if err != nil {
+		return common.EmptyHash, fmt.Errorf("failed to get the sender account nonce: %s", err)
+	}
+
+	payload = append([]byte{method}, payload...)
+	tx, err := util.GenerateTx(chain.key.PrivateKey, system.HashTimeLockContractAddress, amount, chain.price, chain.gasLimit, nonce, payload)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+
+	if ok, err := util.SendTx(chain.client, tx); err != nil || !ok {
+		return common.EmptyHash, fmt.Errorf("Failed to send tx, %v", err)
+	}
+
+	return tx.Hash, nil
+}
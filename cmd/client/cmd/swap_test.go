@@ -0,0 +1,299 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// testSwapLedger is the state of a chain shared by both parties
+type testSwapLedger struct {
+	time  int64
+	htlcs map[common.Hash]*system.HTLC
+	txs   map[common.Hash]swapTxStatus
+	// hold keeps the sent txs pending
+	hold bool
+	// applies are the effects of the pending txs
+	applies map[common.Hash]func(common.Hash)
+}
+
+func newTestSwapLedger() *testSwapLedger {
+	return &testSwapLedger{
+		time:    1000,
+		htlcs:   make(map[common.Hash]*system.HTLC),
+		txs:     make(map[common.Hash]swapTxStatus),
+		applies: make(map[common.Hash]func(common.Hash)),
+	}
+}
+
+// mine includes the pending txs
+func (ledger *testSwapLedger) mine() {
+	for hash, apply := range ledger.applies {
+		apply(hash)
+		ledger.txs[hash] = swapTxSucceeded
+	}
+	ledger.applies = make(map[common.Hash]func(common.Hash))
+}
+
+func (ledger *testSwapLedger) send(apply func(hash common.Hash)) common.Hash {
+	hash := crypto.MustHash(uint64(len(ledger.txs)))
+	ledger.txs[hash] = swapTxPending
+	ledger.applies[hash] = apply
+	if !ledger.hold {
+		ledger.mine()
+	}
+
+	return hash
+}
+
+// testSwapChain is the view of a party on the ledger
+type testSwapChain struct {
+	ledger *testSwapLedger
+	from   common.Address
+}
+
+func (chain *testSwapChain) account() common.Address { return chain.from }
+
+func (chain *testSwapChain) now() (int64, error) { return chain.ledger.time, nil }
+
+func (chain *testSwapChain) getHTLC(hash common.Hash) (*system.HTLC, error) {
+	htlc, ok := chain.ledger.htlcs[hash]
+	if !ok {
+		return nil, errHTLCNotFound
+	}
+
+	copied := *htlc
+	return &copied, nil
+}
+
+func (chain *testSwapChain) newHTLC(lock system.HashTimeLock, amount *big.Int) (common.Hash, error) {
+	return chain.ledger.send(func(hash common.Hash) {
+		tx := &types.Transaction{Hash: hash, Data: types.TransactionData{From: chain.from, Amount: amount}}
+		chain.ledger.htlcs[hash] = &system.HTLC{Tx: tx, HashTimeLock: lock}
+	}), nil
+}
+
+func (chain *testSwapChain) withdraw(hash common.Hash, preimage []byte) (common.Hash, error) {
+	return chain.ledger.send(func(common.Hash) {
+		htlc := chain.ledger.htlcs[hash]
+		htlc.Withdrawed = true
+		htlc.Preimage = preimage
+	}), nil
+}
+
+func (chain *testSwapChain) refund(hash common.Hash) (common.Hash, error) {
+	return chain.ledger.send(func(common.Hash) {
+		chain.ledger.htlcs[hash].Refunded = true
+	}), nil
+}
+
+func (chain *testSwapChain) txStatus(hash common.Hash) (swapTxStatus, error) {
+	status, ok := chain.ledger.txs[hash]
+	if !ok {
+		return swapTxDropped, nil
+	}
+
+	return status, nil
+}
+
+type testSwap struct {
+	initiator, participant *swapCoordinator
+	// chainI is the chain of the initiator HTLC, chainP is the chain of the participant HTLC
+	chainI, chainP *testSwapLedger
+}
+
+// newTestSwap creates the HTLCs of both parties, the participant HTLC expires after the time lock
+func newTestSwap(t *testing.T, participantAmount *big.Int, participantTimeLock int64) *testSwap {
+	dir, err := ioutil.TempDir("", "swap")
+	assert.Equal(t, err, nil)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	addrI := common.BytesToAddress([]byte{1})
+	addrP := common.BytesToAddress([]byte{2})
+	chainI, chainP := newTestSwapLedger(), newTestSwapLedger()
+
+	preimage := []byte("secret")
+	hashLock := system.Sha256Hash(preimage)
+
+	lockI := system.HashTimeLock{HashLock: hashLock, TimeLock: chainI.time + swapInitiatorTimeLock, To: addrP}
+	hashI, _ := (&testSwapChain{chainI, addrI}).newHTLC(lockI, big.NewInt(100))
+
+	lockP := system.HashTimeLock{HashLock: hashLock, TimeLock: chainP.time + participantTimeLock, To: addrI}
+	hashP, _ := (&testSwapChain{chainP, addrP}).newHTLC(lockP, participantAmount)
+
+	initiator := &atomicSwap{
+		Role:     swapRoleInitiator,
+		HashLock: hashLock,
+		Preimage: preimage,
+		Own:      swapHTLC{Hash: hashI, Amount: big.NewInt(100), TimeLock: lockI.TimeLock, To: addrP},
+		Peer:     swapHTLC{Hash: hashP, Amount: big.NewInt(50), To: addrI},
+		Status:   swapStatusLocked,
+	}
+
+	participant := &atomicSwap{
+		Role:     swapRoleParticipant,
+		HashLock: hashLock,
+		Own:      swapHTLC{Hash: hashP, Amount: participantAmount, TimeLock: lockP.TimeLock, To: addrI},
+		Peer:     swapHTLC{Hash: hashI, Amount: big.NewInt(100), TimeLock: lockI.TimeLock, To: addrP},
+		Status:   swapStatusLocked,
+	}
+
+	return &testSwap{
+		initiator:   &swapCoordinator{initiator, &testSwapChain{chainI, addrI}, &testSwapChain{chainP, addrI}, &swapStore{dir + "/initiator"}},
+		participant: &swapCoordinator{participant, &testSwapChain{chainP, addrP}, &testSwapChain{chainI, addrP}, &swapStore{dir + "/participant"}},
+		chainI:      chainI,
+		chainP:      chainP,
+	}
+}
+
+func Test_Swap_Redeem(t *testing.T) {
+	s := newTestSwap(t, big.NewInt(50), swapParticipantTimeLock)
+
+	// the participant waits for the preimage
+	finished, err := s.participant.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, false)
+	assert.Equal(t, s.participant.swap.Own.PendingTx, common.EmptyHash)
+
+	// the initiator redeems the participant HTLC, and reveals the preimage
+	finished, err = s.initiator.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, false)
+	assert.Equal(t, s.chainP.htlcs[s.initiator.swap.Peer.Hash].Withdrawed, true)
+
+	finished, err = s.initiator.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, true)
+	assert.Equal(t, s.initiator.swap.Status, swapStatusRedeemed)
+
+	// the participant learns the preimage and redeems the initiator HTLC
+	finished, err = s.participant.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, false)
+	assert.Equal(t, s.chainI.htlcs[s.participant.swap.Peer.Hash].Withdrawed, true)
+
+	finished, err = s.participant.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, true)
+	assert.Equal(t, s.participant.swap.Status, swapStatusRedeemed)
+	assert.Equal(t, s.participant.swap.Preimage, common.Bytes("secret"))
+	assert.Equal(t, s.chainI.htlcs[s.participant.swap.Peer.Hash].Withdrawed, true)
+
+	// the swap is persisted
+	stored, err := s.participant.store.load(s.participant.swap.ID())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, stored.Status, swapStatusRedeemed)
+	assert.Equal(t, stored.Preimage, common.Bytes("secret"))
+}
+
+func Test_Swap_PendingTx(t *testing.T) {
+	s := newTestSwap(t, big.NewInt(50), swapParticipantTimeLock)
+	s.chainP.hold = true
+
+	finished, err := s.initiator.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, false)
+	pendingTx := s.initiator.swap.Peer.PendingTx
+	assert.Equal(t, pendingTx.IsEmpty(), false)
+
+	// the withdrawal is not sent again while pending
+	finished, err = s.initiator.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, false)
+	assert.Equal(t, s.initiator.swap.Peer.PendingTx, pendingTx)
+	assert.Equal(t, len(s.chainP.applies), 1)
+
+	// resume from the store after the tx is included
+	s.chainP.mine()
+	swap, err := s.initiator.store.load(s.initiator.swap.ID())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, swap.Peer.PendingTx, pendingTx)
+
+	s.initiator.swap = swap
+	finished, err = s.initiator.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, true)
+	assert.Equal(t, swap.Status, swapStatusRedeemed)
+	assert.Equal(t, swap.Peer.PendingTx, common.EmptyHash)
+}
+
+func Test_Swap_Refund(t *testing.T) {
+	s := newTestSwap(t, big.NewInt(50), swapParticipantTimeLock)
+
+	// the participant HTLC is unknown to the initiator
+	s.initiator.swap.Peer.Hash = common.EmptyHash
+	finished, err := s.initiator.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, false)
+	assert.Equal(t, s.chainI.htlcs[s.initiator.swap.Own.Hash].Refunded, false)
+
+	// refund after the time lock
+	s.chainI.time += swapInitiatorTimeLock
+	finished, err = s.initiator.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, false)
+	assert.Equal(t, s.chainI.htlcs[s.initiator.swap.Own.Hash].Refunded, true)
+
+	finished, err = s.initiator.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, true)
+	assert.Equal(t, s.initiator.swap.Status, swapStatusRefunded)
+}
+
+func Test_Swap_InvalidPeer(t *testing.T) {
+	// the participant locks less than agreed
+	s := newTestSwap(t, big.NewInt(10), swapParticipantTimeLock)
+	finished, err := s.initiator.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, false)
+	assert.Equal(t, s.chainP.htlcs[s.initiator.swap.Peer.Hash].Withdrawed, false)
+
+	// the participant HTLC expires too close to the initiator HTLC
+	s = newTestSwap(t, big.NewInt(50), swapInitiatorTimeLock)
+	finished, err = s.initiator.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, false)
+	assert.Equal(t, s.chainP.htlcs[s.initiator.swap.Peer.Hash].Withdrawed, false)
+}
+
+func Test_Swap_RedeemMargin(t *testing.T) {
+	s := newTestSwap(t, big.NewInt(50), swapParticipantTimeLock)
+
+	// the preimage is not revealed if the withdrawal may not be included before the participant HTLC expires
+	s.chainP.time += swapParticipantTimeLock - swapRedeemMargin
+	finished, err := s.initiator.step()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, finished, false)
+	assert.Equal(t, s.chainP.htlcs[s.initiator.swap.Peer.Hash].Withdrawed, false)
+}
+
+func Test_SwapStore_List(t *testing.T) {
+	s := newTestSwap(t, big.NewInt(50), swapParticipantTimeLock)
+	store := s.initiator.store
+
+	swaps, err := store.list()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(swaps), 0)
+
+	_, err = store.load(s.initiator.swap.ID())
+	assert.Equal(t, err, errSwapNotFound)
+
+	assert.Equal(t, store.save(s.initiator.swap), nil)
+	swaps, err = store.list()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(swaps), 1)
+	assert.Equal(t, swaps[0].ID(), s.initiator.swap.ID())
+	assert.Equal(t, swaps[0].Own.Amount, big.NewInt(100))
+}
@@ -28,27 +28,74 @@ var (
 			"withdraw": withdraw,
 			"refund":   refund,
 			"get":      getHTLC,
+
+			"createByHeight":   createHeightHTLC,
+			"withdrawByHeight": withdrawHeightHTLC,
+			"refundByHeight":   refundHeightHTLC,
+			"getByHeight":      getHeightHTLC,
 		},
 		"domain": map[string]handler{
-			"create":   createDomainName,
-			"getOwner": getDomainNameOwner,
+			"create":      createDomainName,
+			"getOwner":    getDomainNameOwner,
+			"transfer":    transferDomainName,
+			"renew":       renewDomainName,
+			"release":     releaseDomainName,
+			"setResolver": setDomainNameResolver,
+			"setReverse":  setReverseDomainName,
 		},
 		"subchain": map[string]handler{
-			"register": registerSubChain,
-			"query":    querySubChain,
+			"register":        registerSubChain,
+			"query":           querySubChain,
+			"update":          updateSubChain,
+			"updateVerifiers": updateSubChainVerifiers,
+			"pause":           pauseSubChain,
+			"resume":          resumeSubChain,
+			"deregister":      deregisterSubChain,
+		},
+		"masternode": map[string]handler{
+			"heartbeat": sendMasternodeHeartbeat,
+		},
+		"staking": map[string]handler{
+			"bond":     bondStake,
+			"delegate": delegateStake,
+			"unbond":   unbondStake,
+			"withdraw": withdrawStake,
+			"get":      getStakingCandidate,
+		},
+		"ct": map[string]handler{
+			"deposit":  confidentialDeposit,
+			"transfer": confidentialTransfer,
+			"withdraw": confidentialWithdraw,
 		},
 	}
 
 	// if the method have key-value, use the call method to get receipt
 	callFlags = map[string]map[string]string{
 		"htlc": map[string]string{
+			"get":         "1",
+			"getByHeight": "1",
+		},
+		"subchain": map[string]string{
+			"query": "1",
+		},
+		"staking": map[string]string{
 			"get": "1",
 		},
 	}
 )
 
+// payloadBuilder builds the system contract payload from the transaction data of the sender
+type payloadBuilder func(txd *types.TransactionData) ([]byte, error)
+
 // sendSystemContractTx send system contract transaction
 func sendSystemContractTx(client *rpc.Client, to common.Address, method byte, payload []byte) (*types.Transaction, error) {
+	return sendSystemContractTxWithBuilder(client, to, method, func(*types.TransactionData) ([]byte, error) {
+		return payload, nil
+	})
+}
+
+// sendSystemContractTxWithBuilder send system contract transaction with the payload depending on the sender
+func sendSystemContractTxWithBuilder(client *rpc.Client, to common.Address, method byte, build payloadBuilder) (*types.Transaction, error) {
 	key, txd, err := makeTransactionData(client)
 	if err != nil {
 		return nil, err
@@ -59,6 +106,11 @@ func sendSystemContractTx(client *rpc.Client, to common.Address, method byte, pa
 	if err != nil {
 		return nil, err
 	}
+
+	payload, err := build(txd)
+	if err != nil {
+		return nil, err
+	}
 	txd.Payload = append([]byte{method}, payload...)
 	tx, err := util.GenerateTx(key.PrivateKey, txd.To, txd.Amount, txd.GasPrice, txd.GasLimit, txd.AccountNonce, txd.Payload)
 	if err != nil {
@@ -6,7 +6,6 @@
 package cmd
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -82,7 +81,7 @@ func SaveKeyAction(c *cli.Context) error {
 func SignTxAction(c *cli.Context) error {
 	var client *rpc.Client
 	if addressValue != "" {
-		c, err := rpc.DialTCP(context.Background(), addressValue)
+		c, err := dialRPC()
 		if err != nil {
 			return err
 		}
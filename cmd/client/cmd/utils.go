@@ -6,12 +6,13 @@
 package cmd
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/seeleteam/go-seele/cmd/util"
-	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/common/hexutil"
 	"github.com/seeleteam/go-seele/core/types"
 	"github.com/seeleteam/go-seele/crypto"
@@ -24,11 +25,56 @@ const (
 	DefaultNonce uint64 = 0
 )
 
+// dialRPC connects to the node at the address flag, which is a tcp address (ip:port)
+// or a http, https, ws or wss url. The token and tls flags apply to the url addresses.
+func dialRPC() (*rpc.Client, error) {
+	return dialRPCAddress(addressValue)
+}
+
+// dialRPCAddress connects to the node at the address, e.g. the counterparty chain of an atomic swap.
+func dialRPCAddress(address string) (*rpc.Client, error) {
+	scheme := ""
+	if pos := strings.Index(address, "://"); pos > 0 {
+		scheme = strings.ToLower(address[:pos])
+	}
+
+	if scheme == "" {
+		return rpc.DialTCP(context.Background(), address)
+	}
+
+	tlsConfig, err := rpc.NewClientTLSConfig(tlsCAValue, tlsCertValue, tlsKeyValue, tlsInsecureValue)
+	if err != nil {
+		return nil, err
+	}
+
+	switch scheme {
+	case "http", "https":
+		return rpc.DialHTTPWithTLS(address, tlsConfig, tokenValue)
+	case "ws", "wss":
+		return rpc.DialWebsocketWithTLS(context.Background(), address, "", tlsConfig, tokenValue)
+	default:
+		return nil, fmt.Errorf("unsupported rpc address scheme %s", scheme)
+	}
+}
+
+// addressHost returns the host and port of the address flag without the url scheme and path.
+func addressHost(address string) string {
+	if pos := strings.Index(address, "://"); pos > 0 {
+		address = address[pos+3:]
+	}
+
+	if pos := strings.Index(address, "/"); pos > 0 {
+		address = address[:pos]
+	}
+
+	return address
+}
+
 func checkParameter(publicKey *ecdsa.PublicKey, client *rpc.Client) (*types.TransactionData, error) {
 	info := &types.TransactionData{}
 	var err error
 	if len(toValue) > 0 {
-		toAddr, err := common.HexToAddress(toValue)
+		toAddr, err := resolveAddress(client, toValue)
 		if err != nil {
 			return info, fmt.Errorf("invalid receiver address: %s", err)
 		}
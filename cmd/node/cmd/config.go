@@ -89,8 +89,7 @@ func LoadConfigFromFile(configFile string, accounts string) (*node.Config, error
 	config.P2PConfig.PrivateKey = config.SeeleConfig.CoinbasePrivateKey
 	config.SeeleConfig.TxConf = *core.DefaultTxPoolConfig()
 	config.SeeleConfig.GenesisConfig = cmdConfig.GenesisConfig
-	comm.LogConfiguration.PrintLog = config.LogConfig.PrintLog
-	comm.LogConfiguration.IsDebug = config.LogConfig.IsDebug
+	*comm.LogConfiguration = config.LogConfig
 	comm.LogConfiguration.DataDir = config.BasicConfig.DataDir
 	config.BasicConfig.DataDir = filepath.Join(common.GetDefaultDataFolder(), config.BasicConfig.DataDir)
 	config.BasicConfig.DataSetDir = filepath.Join(common.GetTempFolder(), config.BasicConfig.DataDir)
@@ -100,6 +99,7 @@ func LoadConfigFromFile(configFile string, accounts string) (*node.Config, error
 
 // convertIPCServerPath convert the config to the real path
 func convertIPCServerPath(cmdConfig *util.Config, config *node.Config) {
+	config.IpcConfig.Access = cmdConfig.Ipcconfig.Access
 	if cmdConfig.Ipcconfig.PipeName == "" {
 		config.IpcConfig.PipeName = common.GetDefaultIPCPath()
 	} else if runtime.GOOS == "windows" {
@@ -119,6 +119,8 @@ func CopyConfig(cmdConfig *util.Config) *node.Config {
 		P2PConfig:      cmdConfig.P2PConfig,
 		SeeleConfig:    node.SeeleConfig{},
 		MetricsConfig:  cmdConfig.MetricsConfig,
+
+		LightCheckpoint: cmdConfig.LightCheckpoint,
 	}
 	return config
 }
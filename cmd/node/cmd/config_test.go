@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/seeleteam/go-seele/log/comm"
 	"github.com/seeleteam/go-seele/node"
 	"github.com/stretchr/testify/assert"
 )
@@ -46,6 +47,21 @@ func Test_LoadConfigFromFile(t *testing.T) {
 	assert.Equal(t, config.SeeleConfig.GenesisConfig.ShardNumber, uint(1), "16")
 }
 
+func Test_LoadConfigFromFile_LogConfig(t *testing.T) {
+	config := getConfig(t)
+
+	assert.Equal(t, config.LogConfig.Levels["p2p"], "warn")
+	assert.Equal(t, config.LogConfig.Levels["bft_core"], "debug")
+
+	// the whole log config is applied to the logger
+	assert.Equal(t, comm.LogConfiguration.Levels, map[string]string{"p2p": "warn", "bft_core": "debug"})
+	assert.Equal(t, comm.LogConfiguration.Format, comm.FormatJSON)
+	assert.Equal(t, comm.LogConfiguration.Rotation.MaxSize, int64(100))
+	assert.Equal(t, comm.LogConfiguration.Rotation.MaxBackups, 5)
+	assert.Equal(t, comm.LogConfiguration.IsDebug, true)
+	assert.Equal(t, comm.LogConfiguration.DataDir, "node2")
+}
+
 func Test_CopyConfig(t *testing.T) {
 	config := getConfig(t)
 	copied := config.Clone()
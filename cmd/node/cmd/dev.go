@@ -0,0 +1,173 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/core"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/seeleteam/go-seele/log/comm"
+	"github.com/seeleteam/go-seele/node"
+	"github.com/seeleteam/go-seele/p2p"
+	"github.com/seeleteam/go-seele/rpc"
+)
+
+const (
+	// devShard is the only shard of the dev node
+	devShard = uint(1)
+
+	// devNetworkID is the network id of the dev node, which is different from any public network
+	devNetworkID = "seele-dev"
+)
+
+// devAccountBalance is the genesis balance of each funded account in dev mode, 1 million seele
+var devAccountBalance = new(big.Int).Mul(big.NewInt(1000000), common.SeeleToFan)
+
+// devAccount is a funded account generated in dev mode
+type devAccount struct {
+	address    common.Address
+	privateKey *ecdsa.PrivateKey
+}
+
+// generateDevAccount generates an external account in the dev shard,
+// so that it could receive the transfers without payload.
+func generateDevAccount() devAccount {
+	for {
+		addr, key := crypto.MustGenerateShardKeyPair(devShard)
+		if addr.Type() == common.AddressTypeExternal {
+			return devAccount{*addr, key}
+		}
+	}
+}
+
+// NewDevConfig creates the config of an ephemeral single node for development,
+// with a fresh genesis funding the given number of generated accounts.
+func NewDevConfig(accountCount int) (*node.Config, []devAccount, error) {
+	if accountCount <= 0 {
+		return nil, nil, errors.New("the number of dev accounts should be positive")
+	}
+
+	dataDir, err := ioutil.TempDir("", "seele-dev")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	coinbase := generateDevAccount()
+	accounts := make([]devAccount, accountCount)
+	balances := make(map[common.Address]*big.Int)
+	for i := range accounts {
+		accounts[i] = generateDevAccount()
+		balances[accounts[i].address] = new(big.Int).Set(devAccountBalance)
+	}
+
+	// the genesis requires the root accounts, and the first one is the master account
+	rootAccounts := []common.Address{coinbase.address}
+	for len(rootAccounts) < common.SubChainRootAccount {
+		rootAccounts = append(rootAccounts, generateDevAccount().address)
+	}
+
+	genesis := core.NewGenesisInfo(balances, 1, devShard, big.NewInt(time.Now().Unix()), types.PowConsensus, nil)
+	genesis.Rootaccounts = rootAccounts
+	genesis.Supply = big.NewInt(0)
+
+	// all the namespaces including the private ones are served in dev mode,
+	// so that a random bearer token is required to access them.
+	token := make([]byte, 32)
+	if _, err = rand.Read(token); err != nil {
+		os.RemoveAll(dataDir)
+		return nil, nil, err
+	}
+
+	access := rpc.AccessConfig{
+		Modules: []string{rpc.AllModules},
+		Tokens:  []string{hexutil.BytesToHex(token)},
+	}
+
+	config := &node.Config{
+		LogConfig: comm.LogConfig{PrintLog: true},
+		BasicConfig: node.BasicConfig{
+			Name:       "seele dev node",
+			Version:    common.SeeleNodeVersion,
+			DataDir:    dataDir,
+			DataSetDir: dataDir,
+			RPCAddr:    "127.0.0.1:8027",
+			Coinbase:   coinbase.address.Hex(),
+			PrivateKey: hexutil.BytesToHex(crypto.FromECDSA(coinbase.privateKey)),
+		},
+		P2PConfig: p2p.Config{
+			ListenAddr: "127.0.0.1:8057",
+			NetworkID:  devNetworkID,
+			PrivateKey: coinbase.privateKey,
+		},
+		HTTPServer: node.HTTPServer{
+			HTTPAddr:      "127.0.0.1:8037",
+			HTTPCors:      []string{"http://localhost", "http://localhost:*", "http://127.0.0.1", "http://127.0.0.1:*"},
+			HTTPWhiteHost: []string{"localhost", "127.0.0.1"},
+			Access:        access,
+		},
+		WSServerConfig: node.WSServerConfig{
+			Address:      "127.0.0.1:8047",
+			CrossOrigins: []string{"http://localhost", "http://127.0.0.1"},
+			Access:       access,
+		},
+		SeeleConfig: node.SeeleConfig{
+			TxConf:             *core.DefaultTxPoolConfig(),
+			Coinbase:           coinbase.address,
+			CoinbasePrivateKey: coinbase.privateKey,
+			GenesisConfig:      *genesis,
+		},
+	}
+
+	if runtime.GOOS == "windows" {
+		config.IpcConfig.PipeName = common.WindowsPipeDir + filepath.Base(dataDir) + ".ipc"
+	} else {
+		config.IpcConfig.PipeName = filepath.Join(dataDir, "seele.ipc")
+	}
+
+	*comm.LogConfiguration = config.LogConfig
+	comm.LogConfiguration.DataDir = filepath.Base(dataDir)
+
+	return config, accounts, nil
+}
+
+// printDevAccounts prints the funded accounts, so that the developers could import them into wallets,
+// and the bearer token to access the rpc endpoints.
+func printDevAccounts(config *node.Config, accounts []devAccount) {
+	fmt.Printf("dev mode is ephemeral, all the data will be lost after the node exits\n")
+	fmt.Printf("http and websocket rpc require the header \"Authorization: Bearer %s\"\n", config.HTTPServer.Access.Tokens[0])
+	fmt.Printf("funded accounts with %s fan each:\n", devAccountBalance)
+	for i, account := range accounts {
+		fmt.Printf("(%d) %s %s\n", i, account.address.Hex(), hexutil.BytesToHex(crypto.FromECDSA(account.privateKey)))
+	}
+}
+
+// removeDevDataOnExit removes the ephemeral data folder of the dev node when the process is interrupted.
+func removeDevDataOnExit(seeleNode *node.Node, dataDir string) {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-interrupt
+		seeleNode.Stop()
+		os.RemoveAll(dataDir)
+		os.Exit(0)
+	}()
+}
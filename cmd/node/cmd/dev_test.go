@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewDevConfig(t *testing.T) {
+	_, _, err := NewDevConfig(0)
+	assert.Error(t, err)
+
+	config, accounts, err := NewDevConfig(3)
+	assert.NoError(t, err)
+	defer os.RemoveAll(config.BasicConfig.DataDir)
+
+	assert.Equal(t, len(accounts), 3)
+	genesis := config.SeeleConfig.GenesisConfig
+	assert.Equal(t, len(genesis.Accounts), 3)
+	for _, account := range accounts {
+		assert.Equal(t, account.address.Type(), common.AddressTypeExternal)
+		assert.Equal(t, genesis.Accounts[account.address], devAccountBalance)
+	}
+
+	assert.Equal(t, genesis.ShardNumber, devShard)
+	assert.Equal(t, len(genesis.Rootaccounts), common.SubChainRootAccount)
+	assert.Equal(t, genesis.Rootaccounts[0], config.SeeleConfig.Coinbase)
+	assert.Equal(t, config.SeeleConfig.Coinbase.Shard(), devShard)
+	assert.Equal(t, config.P2PConfig.NetworkID, devNetworkID)
+	assert.Equal(t, config.HTTPServer.Access.ModuleAllowed("miner"), true)
+	assert.Equal(t, config.WSServerConfig.Access.Modules, []string{rpc.AllModules})
+
+	// the private namespaces require the token, and only the local hosts are allowed
+	assert.Equal(t, config.HTTPServer.Access.AuthEnabled(), true)
+	assert.Equal(t, config.WSServerConfig.Access.Tokens, config.HTTPServer.Access.Tokens)
+	assert.Equal(t, len(config.HTTPServer.Access.Tokens[0]), 66)
+	assert.Equal(t, config.HTTPServer.HTTPWhiteHost, []string{"localhost", "127.0.0.1"})
+	for _, origin := range append(config.HTTPServer.HTTPCors, config.WSServerConfig.CrossOrigins...) {
+		assert.NotEqual(t, origin, "*")
+	}
+
+	// the tokens are different for each dev node
+	config2, _, err := NewDevConfig(1)
+	assert.NoError(t, err)
+	defer os.RemoveAll(config2.BasicConfig.DataDir)
+	assert.NotEqual(t, config2.HTTPServer.Access.Tokens[0], config.HTTPServer.Access.Tokens[0])
+}
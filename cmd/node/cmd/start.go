@@ -19,7 +19,9 @@ import (
 
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/consensus"
+	"github.com/seeleteam/go-seele/consensus/dev"
 	"github.com/seeleteam/go-seele/consensus/factory"
+	"github.com/seeleteam/go-seele/core/types"
 	"github.com/seeleteam/go-seele/light"
 	"github.com/seeleteam/go-seele/log"
 	"github.com/seeleteam/go-seele/log/comm"
@@ -51,6 +53,11 @@ var (
 
 	maxConns       = int(0)
 	maxActiveConns = int(0)
+
+	// dev mode with an ephemeral single node
+	devMode     bool
+	devPeriod   uint64
+	devAccounts int
 )
 
 // startCmd represents the start command
@@ -59,16 +66,34 @@ var startCmd = &cobra.Command{
 	Short: "start the node of seele",
 	Long: `usage example:
 		node.exe start -c cmd\node.json
-		start a node.`,
+		start a node.
+		node.exe start --dev
+		start an ephemeral dev node with funded accounts.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		var wg sync.WaitGroup
-		nCfg, err := LoadConfigFromFile(seeleNodeConfigFile, accountsConfig)
-		if err != nil {
-			fmt.Printf("failed to reading the config file: %s\n", err.Error())
-			return
+		var nCfg *node.Config
+		var err error
+		if devMode {
+			var accounts []devAccount
+			if nCfg, accounts, err = NewDevConfig(devAccounts); err != nil {
+				fmt.Printf("failed to create the dev config: %s\n", err.Error())
+				return
+			}
+			defer os.RemoveAll(nCfg.BasicConfig.DataDir)
+			printDevAccounts(nCfg, accounts)
+		} else {
+			if seeleNodeConfigFile == "" {
+				fmt.Println("the config file is required unless in dev mode")
+				return
+			}
+
+			if nCfg, err = LoadConfigFromFile(seeleNodeConfigFile, accountsConfig); err != nil {
+				fmt.Printf("failed to reading the config file: %s\n", err.Error())
+				return
+			}
+			Cast(nCfg)
 		}
-		Cast(nCfg)
 		if !comm.LogConfiguration.PrintLog {
 			fmt.Printf("log folder: %s\n", filepath.Join(log.LogFolder, comm.LogConfiguration.DataDir))
 		}
@@ -80,16 +105,23 @@ var startCmd = &cobra.Command{
 			return
 		}
 
+		if devMode {
+			removeDevDataOnExit(seeleNode, nCfg.BasicConfig.DataDir)
+		}
+
 		// Create seele service and register the service
 		slog := log.GetLogger("seele")
 		lightLog := log.GetLogger("seele-light")
 		serviceContext := seele.ServiceContext{
-			DataDir: nCfg.BasicConfig.DataDir,
+			DataDir:  nCfg.BasicConfig.DataDir,
+			InMemory: devMode,
 		}
 		ctx := context.WithValue(context.Background(), "ServiceContext", serviceContext)
 
 		var engine consensus.Engine
-		if nCfg.BasicConfig.MinerAlgorithm == common.BFTEngine {
+		if devMode {
+			engine = dev.NewEngine(devPeriod)
+		} else if nCfg.BasicConfig.MinerAlgorithm == common.BFTEngine {
 			engine, err = factory.GetBFTEngine(nCfg.SeeleConfig.CoinbasePrivateKey, nCfg.BasicConfig.DataDir)
 		} else if nCfg.BasicConfig.MinerAlgorithm == common.BFTSubchainEngine {
 			engine, err = factory.GetBFTSubchainEngine(nCfg.SeeleConfig.CoinbasePrivateKey, nCfg.BasicConfig.DataDir)
@@ -134,15 +166,19 @@ var startCmd = &cobra.Command{
 				return
 			}
 		} else {
-			// light client manager
-			manager, err := lightclients.NewLightClientManager(seeleNode.GetShardNumber(), ctx, nCfg, engine)
-			if err != nil {
-				fmt.Printf("create light client manager failed. %s", err)
-				return
+			// light client manager, the dev node has no other shards to verify the debts
+			var manager *lightclients.LightClientsManager
+			var verifier types.DebtVerifier
+			if !devMode {
+				if manager, err = lightclients.NewLightClientManager(seeleNode.GetShardNumber(), ctx, nCfg, engine); err != nil {
+					fmt.Printf("create light client manager failed. %s", err)
+					return
+				}
+				verifier = manager
 			}
 
 			// fullnode mode
-			seeleService, err := seele.NewSeeleService(ctx, nCfg, slog, engine, manager, startHeight)
+			seeleService, err := seele.NewSeeleService(ctx, nCfg, slog, engine, verifier, startHeight)
 			if err != nil {
 				fmt.Println(err.Error())
 				return
@@ -163,7 +199,10 @@ var startCmd = &cobra.Command{
 				return
 			}
 
-			services := manager.GetServices()
+			var services []node.Service
+			if manager != nil {
+				services = manager.GetServices()
+			}
 			services = append(services, seeleService, monitorService, lightServerService)
 			for _, service := range services {
 				if err := seeleNode.Register(service); err != nil {
@@ -218,8 +257,7 @@ var startCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(startCmd)
 
-	startCmd.Flags().StringVarP(&seeleNodeConfigFile, "config", "c", "", "seele node config file (required)")
-	startCmd.MustMarkFlagRequired("config")
+	startCmd.Flags().StringVarP(&seeleNodeConfigFile, "config", "c", "", "seele node config file (required unless in dev mode)")
 
 	startCmd.Flags().StringVarP(&miner, "miner", "m", "start", "miner start or not, [start, stop]")
 	startCmd.Flags().BoolVarP(&metricsEnableFlag, "metrics", "t", false, "start metrics")
@@ -230,6 +268,9 @@ func init() {
 	startCmd.Flags().IntVarP(&startHeight, "startheight", "", -1, "the block height to start from")
 	startCmd.Flags().IntVarP(&maxConns, "maxConns", "", 0, "node max connections")
 	startCmd.Flags().IntVarP(&maxActiveConns, "maxActiveConns", "", 0, "node max active connections")
+	startCmd.Flags().BoolVarP(&devMode, "dev", "", false, "start an ephemeral single node with funded accounts for development")
+	startCmd.Flags().Uint64VarP(&devPeriod, "devperiod", "", 0, "block period in seconds in dev mode, 0 to seal blocks only when there are txs")
+	startCmd.Flags().IntVarP(&devAccounts, "devaccounts", "", 5, "number of funded accounts in dev mode")
 }
 
 func monitorPC() {
@@ -38,4 +38,7 @@ type Config struct {
 
 	// genesis config info
 	GenesisConfig core.GenesisInfo `json:"genesis"`
+
+	// trusted checkpoint for the light node of a BFT subchain
+	LightCheckpoint *node.BFTCheckpoint `json:"lightCheckpoint"`
 }
@@ -0,0 +1,245 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package compiler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/seeleteam/go-seele/common/errors"
+	"github.com/seeleteam/go-seele/common/hexutil"
+)
+
+const (
+	// defaultSolc is the solc executable in the PATH
+	defaultSolc = "solc"
+
+	// sourceFileName is the name of the temp source file to compile
+	sourceFileName = "contract.sol"
+)
+
+var (
+	// ErrSolcNotFound is returned when no solc executable of the requested version is found
+	ErrSolcNotFound = errors.New("solc of the requested version not found")
+
+	// ErrUnlinkedLibrary is returned when the compiled bytecode contains library placeholders
+	ErrUnlinkedLibrary = errors.New("contracts with unlinked libraries are not supported")
+
+	// ErrInvalidSolcVersion is returned when the requested version is not in the form of major.minor.patch
+	ErrInvalidSolcVersion = errors.New("invalid solc version, should be major.minor.patch, e.g. 0.4.25")
+
+	// solcVersionRegexp is the form of the requested solc version, which is a part of the executable name
+	solcVersionRegexp = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+)
+
+// SolidityOptions is the compiler version and settings to compile the solidity source.
+type SolidityOptions struct {
+	// Version is the solc version in the form of major.minor.patch, e.g. 0.4.25.
+	// The solc executable named solc-<version> in the PATH is preferred to solc.
+	Version string `json:"version"`
+
+	Optimize bool `json:"optimize"`
+
+	// Runs is the optimizer runs, 0 means the solc default
+	Runs uint `json:"runs,omitempty"`
+
+	// EVMVersion is the target evm version, empty means the solc default
+	EVMVersion string `json:"evmVersion,omitempty"`
+}
+
+// Contract is a compiled solidity contract.
+type Contract struct {
+	Name        string
+	RuntimeCode []byte
+	ABI         string
+}
+
+// SolidityOutput is the compilation output of the solidity source.
+type SolidityOutput struct {
+	// Version is the full version reported by solc
+	Version   string
+	Contracts map[string]*Contract
+}
+
+// CompileSolidity compiles the solidity source with the solc of the requested version.
+func CompileSolidity(source string, options SolidityOptions) (*SolidityOutput, error) {
+	solc, version, err := findSolc(options.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	tempDir, err := ioutil.TempDir("", "SolCompile-")
+	if err != nil {
+		return nil, errors.NewStackedError(err, "failed to create temp folder for solidity compilation")
+	}
+	defer os.RemoveAll(tempDir)
+
+	sourceFile := filepath.Join(tempDir, sourceFileName)
+	if err = ioutil.WriteFile(sourceFile, []byte(source), 0600); err != nil {
+		return nil, errors.NewStackedError(err, "failed to write the solidity source")
+	}
+
+	args := []string{"--combined-json", "abi,bin-runtime"}
+	if options.Optimize {
+		args = append(args, "--optimize")
+		if options.Runs > 0 {
+			args = append(args, "--optimize-runs", strconv.FormatUint(uint64(options.Runs), 10))
+		}
+	}
+
+	if len(options.EVMVersion) > 0 {
+		args = append(args, "--evm-version", options.EVMVersion)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(solc, append(args, sourceFile)...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err = cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to compile the solidity source, %s, %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	contracts, err := ParseCombinedJSON(stdout.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &SolidityOutput{version, contracts}, nil
+}
+
+// findSolc returns the path and full version of the solc executable matching the requested version.
+func findSolc(version string) (string, string, error) {
+	candidates := []string{defaultSolc}
+	if len(version) > 0 {
+		// the version is validated before it is used to look up the executable
+		if !solcVersionRegexp.MatchString(version) {
+			return "", "", ErrInvalidSolcVersion
+		}
+
+		candidates = append([]string{defaultSolc + "-" + version}, candidates...)
+	}
+
+	for _, name := range candidates {
+		path, err := exec.LookPath(name)
+		if err != nil {
+			continue
+		}
+
+		output, err := exec.Command(path, "--version").Output()
+		if err != nil {
+			continue
+		}
+
+		if solcVersion := parseSolcVersion(string(output)); matchVersion(solcVersion, version) {
+			return path, solcVersion, nil
+		}
+	}
+
+	return "", "", ErrSolcNotFound
+}
+
+// parseSolcVersion returns the version in the output of solc --version, e.g. 0.4.25+commit.59dbf8f1.Linux.g++
+func parseSolcVersion(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "Version:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "Version:"))
+		}
+	}
+
+	return ""
+}
+
+// matchVersion returns true if the solc version is the requested one, an empty request matches any version.
+func matchVersion(solcVersion, version string) bool {
+	if len(solcVersion) == 0 || !strings.HasPrefix(solcVersion, version) {
+		return false
+	}
+
+	if len(version) == 0 || len(solcVersion) == len(version) {
+		return true
+	}
+
+	next := solcVersion[len(version)]
+	return next == '+' || next == '.' || next == '-'
+}
+
+// ParseCombinedJSON parses the contracts in the solc --combined-json abi,bin-runtime output.
+func ParseCombinedJSON(output []byte) (map[string]*Contract, error) {
+	var combined struct {
+		Contracts map[string]struct {
+			ABI         json.RawMessage `json:"abi"`
+			RuntimeCode string          `json:"bin-runtime"`
+		} `json:"contracts"`
+	}
+
+	if err := json.Unmarshal(output, &combined); err != nil {
+		return nil, errors.NewStackedError(err, "failed to parse the solc output")
+	}
+
+	contracts := make(map[string]*Contract)
+	for fullName, info := range combined.Contracts {
+		// the full name is <source file>:<contract name>
+		name := fullName[strings.LastIndex(fullName, ":")+1:]
+
+		// the abi is a json string before solc 0.8, and a json array since then
+		abi := string(info.ABI)
+		var abiStr string
+		if err := json.Unmarshal(info.ABI, &abiStr); err == nil {
+			abi = abiStr
+		}
+
+		if strings.Contains(info.RuntimeCode, "__") {
+			return nil, ErrUnlinkedLibrary
+		}
+
+		code, err := hexutil.HexToBytes(ensureHexPrefix(info.RuntimeCode))
+		if err != nil {
+			return nil, errors.NewStackedErrorf(err, "invalid runtime bytecode of contract %s", name)
+		}
+
+		contracts[name] = &Contract{name, code, abi}
+	}
+
+	return contracts, nil
+}
+
+func ensureHexPrefix(code string) string {
+	if hexutil.Has0xPrefix(code) {
+		return code
+	}
+
+	return "0x" + code
+}
+
+// StripMetadata removes the cbor encoded metadata appended to the bytecode by solc.
+// The metadata is followed by its length in 2 bytes, and the bytecode is returned
+// as it is if there is no valid metadata.
+func StripMetadata(code []byte) []byte {
+	if len(code) < 2 {
+		return code
+	}
+
+	size := int(binary.BigEndian.Uint16(code[len(code)-2:]))
+	start := len(code) - 2 - size
+	if size == 0 || start < 0 {
+		return code
+	}
+
+	// the metadata is a cbor map with no more than 15 entries, e.g. bzzr0, ipfs and solc
+	if header := code[start]; header < 0xa1 || header > 0xaf {
+		return code
+	}
+
+	return code[:start]
+}
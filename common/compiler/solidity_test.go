@@ -0,0 +1,83 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package compiler
+
+import (
+	"testing"
+
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// runtime code followed by the bzzr0 metadata in the layout of solc 0.4
+const testRuntimeCode = "0x6080604052600436106049576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806360fe47b114604e5780636d4ce63c146078575b600080fd5b348015605957600080fd5b50607660048036038101908080359060200190929190505050609c565b005b348015608357600080fd5b50608a60a6565b60408051918252519081900360200190f35b8060008190555050565b600080549050905600" +
+	"a165627a7a72305820" + "d1b2ab5c57e2b0cd3f61e6a2b1d7a2d7d6c0e8bfa4bf5f7c58c0b2f2e6b0c8d5" + "0029"
+
+func Test_StripMetadata(t *testing.T) {
+	code := hexutil.MustHexToBytes(testRuntimeCode)
+	stripped := StripMetadata(code)
+	assert.Equal(t, len(stripped), len(code)-43)
+	assert.Equal(t, stripped, code[:len(code)-43])
+
+	// metadata of the other compilation is stripped to the same code
+	other := append(append([]byte{}, stripped...), code[len(code)-43:]...)
+	other[len(other)-3] ^= 0xff
+	assert.Equal(t, StripMetadata(other), stripped)
+
+	// no metadata
+	assert.Equal(t, StripMetadata(stripped), stripped)
+	assert.Equal(t, StripMetadata([]byte{0x60}), []byte{0x60})
+	assert.Equal(t, StripMetadata([]byte{0x60, 0x80, 0x00, 0x10}), []byte{0x60, 0x80, 0x00, 0x10})
+}
+
+func Test_ParseCombinedJSON(t *testing.T) {
+	// abi in json string before solc 0.8
+	output := `{"contracts":{"/tmp/contract.sol:SimpleStorage":{"abi":"[{\"constant\":true,\"inputs\":[],\"name\":\"get\",\"outputs\":[{\"name\":\"\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"view\",\"type\":\"function\"}]","bin-runtime":"6080604052"}},"version":"0.4.25+commit.59dbf8f1.Linux.g++"}`
+	contracts, err := ParseCombinedJSON([]byte(output))
+	assert.NoError(t, err)
+	assert.Equal(t, len(contracts), 1)
+	assert.Equal(t, contracts["SimpleStorage"].Name, "SimpleStorage")
+	assert.Equal(t, contracts["SimpleStorage"].RuntimeCode, []byte{0x60, 0x80, 0x60, 0x40, 0x52})
+	assert.Equal(t, contracts["SimpleStorage"].ABI, `[{"constant":true,"inputs":[],"name":"get","outputs":[{"name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}]`)
+
+	// abi in json array since solc 0.8
+	output = `{"contracts":{"contract.sol:A":{"abi":[],"bin-runtime":"00"},"contract.sol:B":{"abi":[],"bin-runtime":""}}}`
+	contracts, err = ParseCombinedJSON([]byte(output))
+	assert.NoError(t, err)
+	assert.Equal(t, len(contracts), 2)
+	assert.Equal(t, contracts["A"].ABI, "[]")
+	assert.Equal(t, contracts["A"].RuntimeCode, []byte{0})
+
+	// unlinked library
+	output = `{"contracts":{"contract.sol:A":{"abi":[],"bin-runtime":"73__$a1b2c3$__63"}}}`
+	_, err = ParseCombinedJSON([]byte(output))
+	assert.Equal(t, err, ErrUnlinkedLibrary)
+
+	_, err = ParseCombinedJSON([]byte("solc error"))
+	assert.Error(t, err)
+}
+
+func Test_MatchVersion(t *testing.T) {
+	assert.Equal(t, parseSolcVersion("solc, the solidity compiler commandline interface\nVersion: 0.4.25+commit.59dbf8f1.Linux.g++\n"), "0.4.25+commit.59dbf8f1.Linux.g++")
+	assert.Equal(t, parseSolcVersion("unknown"), "")
+
+	assert.Equal(t, matchVersion("0.4.25+commit.59dbf8f1.Linux.g++", ""), true)
+	assert.Equal(t, matchVersion("0.4.25+commit.59dbf8f1.Linux.g++", "0.4.25"), true)
+	assert.Equal(t, matchVersion("0.4.25+commit.59dbf8f1.Linux.g++", "0.4.25+commit.59dbf8f1"), true)
+	assert.Equal(t, matchVersion("0.4.25+commit.59dbf8f1.Linux.g++", "0.4.2"), false)
+	assert.Equal(t, matchVersion("0.4.25+commit.59dbf8f1.Linux.g++", "0.5.0"), false)
+	assert.Equal(t, matchVersion("", ""), false)
+}
+
+func Test_FindSolc_InvalidVersion(t *testing.T) {
+	for _, version := range []string{"../../bin/sh", "0.4", "0.4.25 ", "0.4.25+commit.59dbf8f1", "0.4.25/../x", "v0.4.25", "0.4.x"} {
+		_, _, err := findSolc(version)
+		assert.Equal(t, err, ErrInvalidSolcVersion, version)
+	}
+
+	_, err := CompileSolidity("contract A {}", SolidityOptions{Version: "../solc"})
+	assert.Equal(t, err, ErrInvalidSolcVersion)
+}
@@ -51,6 +51,17 @@ const (
 
 	SmartContractNonceForkHeight = 1100000
 
+	// SystemContractForkHeight after this height we change the commands and storage of system contracts: hardFork
+	SystemContractForkHeight = 2000000
+
+	// MasternodeRewardForkHeight after this height the masternode rewards are distributed at the first block of each
+	// masternode epoch: hardFork. It is the first epoch of 8640 blocks after the system contract fork height.
+	MasternodeRewardForkHeight = 2004480
+
+	// VerifierFeeForkHeight after this height the subchain fee is paid to the verifiers by their commits
+	// at each relay block: hardFork. It is a multiple of the relay interval.
+	VerifierFeeForkHeight = 2000000
+
 	RelayInterval = uint64(20)
 
 	// LightChainDir lightchain data directory based on config.DataRoot
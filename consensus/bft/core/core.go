@@ -86,13 +86,13 @@ func (c *core) checkVerifierSignature(data []byte, sig []byte) (common.Address,
 func (c *core) broadcast(msg *message) {
 	payload, err := c.finalizeMessage(msg) //use msg to prepare commited seal with signature
 	if err != nil {
-		c.log.Error("Failed to finalize message. msg %v. err %s. state %d", msg, err, c.state)
+		c.log.WithFields(log.Fields{"code": msg.Code, "state": c.state}).Error("Failed to finalize message. err %s", err)
 		return
 	}
 
 	// Broadcast payload
 	if err = c.server.Broadcast(c.verSet, payload); err != nil {
-		c.log.Error("Failed to broadcast message. msg %v. err %s. state %d", msg, err, c.state)
+		c.log.WithFields(log.Fields{"code": msg.Code, "state": c.state}).Error("Failed to broadcast message. err %s", err)
 		return
 	}
 }
@@ -196,7 +196,7 @@ func (c *core) startNewRound(round *big.Int) {
 			c.consensusTimer.UpdateSince(c.consensusTimestamp)
 			c.consensusTimestamp = time.Time{}
 		}
-		c.log.Info("catch up latest proposal with height %d hash %s", lastProposal.Height(), lastProposal.Hash())
+		c.log.WithFields(log.Fields{"height": lastProposal.Height(), "hash": lastProposal.Hash().Hex()}).Info("catch up latest proposal")
 	} else if lastProposal.Height() == c.current.Sequence().Uint64()-1 { // consective
 		if round.Cmp(common.Big0) == 0 {
 			// same req and round -> don't need to start new round
@@ -243,7 +243,13 @@ func (c *core) startNewRound(round *big.Int) {
 		}
 	}
 	c.newRoundChangeTimer()
-	c.log.Info("new_round %d, new_seq %d, new_proposer %v, verSet %v, size %d, isProposer %t", newView.Round, newView.Sequence, c.verSet.GetProposer(), c.verSet.List(), c.verSet.Size(), c.isProposer())
+	c.log.WithFields(log.Fields{
+		"round":      newView.Round.Uint64(),
+		"height":     newView.Sequence.Uint64(),
+		"proposer":   c.verSet.GetProposer().Address().Hex(),
+		"verifiers":  c.verSet.Size(),
+		"isProposer": c.isProposer(),
+	}).Info("start new round")
 }
 
 func (c *core) newRoundChangeTimer() {
@@ -272,7 +278,7 @@ func (c *core) catchUpRound(view *bft.View) {
 	c.roundChangeSet.Clear(view.Round) // TODO
 	c.newRoundChangeTimer()
 
-	c.log.Debug("Catch up round. new_round %d. new_seq %d. new_proposer %s", view.Round, view.Sequence, c.verSet)
+	c.log.WithFields(log.Fields{"round": view.Round.Uint64(), "height": view.Sequence.Uint64()}).Debug("Catch up round")
 }
 
 func (c *core) stopFuturePreprepareTimer() {
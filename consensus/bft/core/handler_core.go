@@ -5,6 +5,7 @@ import (
 
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/consensus/bft"
+	"github.com/seeleteam/go-seele/log"
 )
 
 func (c *core) Start() error {
@@ -133,7 +134,7 @@ func (c *core) handleCheckedMsg(msg *message, src bft.Verifier) error {
 		return err
 	}
 	c.log.Debug("msg code types: msgPreprepare %+v msgPrepare %+v, msgCommit %+v, msgRoundChange %+v\n", msgPreprepare, msgPrepare, msgCommit, msgRoundChange)
-	c.log.Info("from %s: msg: %d\n", src, msg.Code)
+	c.log.WithFields(log.Fields{"peer": src.Address().Hex(), "code": msg.Code}).Debug("handle checked message")
 	switch msg.Code {
 	case msgPreprepare:
 		return backlog(c.handlePreprepare(msg, src)) //TODO
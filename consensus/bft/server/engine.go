@@ -172,6 +172,18 @@ func (s *server) Prepare(chain consensus.ChainReader, header *types.BlockHeader)
 	}
 	header.ExtraData = extra
 
+	// carry the committed seals of the parent, so that the verifier fees weighted by them are covered by the block hash
+	if height >= common.VerifierFeeForkHeight {
+		parentExtra, err := types.ExtractBftExtra(parent)
+		if err != nil {
+			return err
+		}
+
+		if err = writeParentCommittedSeals(header, parentExtra.CommittedSeal); err != nil {
+			return err
+		}
+	}
+
 	// set timeStamp at header
 	header.CreateTimestamp = new(big.Int).Add(parent.CreateTimestamp, new(big.Int).SetUint64(s.config.BlockPeriod))
 	// but if creatTimestamp is smaller than current. set to current!
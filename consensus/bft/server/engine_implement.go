@@ -49,6 +49,10 @@ var (
 	errCommittedSealsInvalid = errors.New("committed seals are invalid")
 	// errEmptyCommittedSeals is returned if the field of committed seals is zero.
 	errEmptyCommittedSeals = errors.New("zero committed seals")
+	// errParentCommittedSealsInvalid is returned if the parent committed seals are not signed by a quorum of the parent verifiers.
+	errParentCommittedSealsInvalid = errors.New("parent committed seals are invalid")
+	// errVerifiersMismatch is returned if the verifiers in the extra data mismatch the snapshot.
+	errVerifiersMismatch = errors.New("verifiers mismatch the snapshot")
 	// errMismatchTxhashes is returned if the TxHash in header is mismatch.
 	errMismatchTxhashes = errors.New("mismatch transcations hashes")
 	// errProposalInvalid is returned when a prposal is malformed.
@@ -260,10 +264,61 @@ func (s *server) verifyBFTCore(chain consensus.ChainReader, header *types.BlockH
 	if err := s.verifySigner(chain, header, parents); err != nil {
 		return err
 	}
+	// verify the verifiers and the parent committed seals, which the verifier fees are weighted by
+	if number >= common.VerifierFeeForkHeight {
+		if err := s.verifyParentCommittedSeals(chain, header, parent, snap, parents); err != nil {
+			return err
+		}
+	}
 	// verify committed seals
 	return s.verifyCommittedSeals(chain, header, parents)
 }
 
+// verifyParentCommittedSeals checks the verifiers of the header are the ones in the snapshot, and the parent committed
+// seals carried in the header are signed by a quorum of the distinct verifiers of the parent's snapshot.
+func (s *server) verifyParentCommittedSeals(chain consensus.ChainReader, header *types.BlockHeader, parent *types.BlockHeader, snap *Snapshot, parents []*types.BlockHeader) error {
+	extra, err := types.ExtractBftExtra(header)
+	if err != nil {
+		return err
+	}
+
+	snapVerifiers := snap.verifiers()
+	if len(extra.Verifiers) != len(snapVerifiers) {
+		return errVerifiersMismatch
+	}
+	for i, verifier := range snapVerifiers {
+		if !extra.Verifiers[i].Equal(verifier) {
+			return errVerifiersMismatch
+		}
+	}
+
+	if len(parents) > 0 {
+		parents = parents[:len(parents)-1]
+	}
+	parentSnap, err := s.snapshot(chain, parent.Height-1, parent.PreviousBlockHash, parents)
+	if err != nil {
+		return err
+	}
+
+	verifiers := parentSnap.VerSet.Copy()
+	proposalSeal := bftCore.PrepareCommittedSeal(parent.Hash())
+	for _, seal := range extra.ParentCommittedSeal {
+		addr, err := bft.GetSignatureAddress(proposalSeal, seal)
+		if err != nil {
+			return errInvalidSignature
+		}
+		if !verifiers.RemoveVerifier(addr) {
+			return errParentCommittedSealsInvalid
+		}
+	}
+
+	if len(extra.ParentCommittedSeal) == 0 || len(extra.ParentCommittedSeal) < 2*parentSnap.VerSet.F() {
+		return errParentCommittedSealsInvalid
+	}
+
+	return nil
+}
+
 // verifyCommittedSeals checks whether every committed seal is signed by one of the parent's validators
 func (s *server) verifyCommittedSeals(chain consensus.ChainReader, header *types.BlockHeader, parents []*types.BlockHeader) error {
 	// check height, if 0 (genesis) return nil
@@ -390,7 +445,8 @@ func extractAccount(header *types.BlockHeader) (common.Address, error) {
 // sigHash FIXME : here we use IstanbulFilteredHeader method, should we keep it or implement otherway?
 
 func sigHash(header *types.BlockHeader) (hash common.Hash) {
-	h := types.IstanbulFilteredHeader(header, false) //TODO
+	// the bft extra is the same as the istanbul one except the parent committed seals
+	h := types.BftFilteredHeader(header, false)
 	return crypto.MustHash(h)
 }
 
@@ -621,6 +677,25 @@ func writeSeal(h *types.BlockHeader, seal []byte) error {
 	return nil
 }
 
+// writeParentCommittedSeals writes the committed seals of the parent in the extra-data field of the given header.
+func writeParentCommittedSeals(h *types.BlockHeader, committedSeals [][]byte) error {
+	bftExtra, err := types.ExtractBftExtra(h)
+	if err != nil {
+		return err
+	}
+
+	bftExtra.ParentCommittedSeal = make([][]byte, len(committedSeals))
+	copy(bftExtra.ParentCommittedSeal, committedSeals)
+
+	payload, err := rlp.EncodeToBytes(&bftExtra)
+	if err != nil {
+		return err
+	}
+
+	h.ExtraData = append(h.ExtraData[:types.BftExtraVanity], payload...)
+	return nil
+}
+
 func writeCommittedSeals(h *types.BlockHeader, committedSeals [][]byte) error {
 	if len(committedSeals) == 0 {
 		return errCommittedSealsInvalid
@@ -386,6 +386,27 @@ func (s *server) ParentVerifiers(proposal bft.Proposal) bft.VerifierSet {
 	return verifier.NewVerifierSet(nil, s.config.ProposerPolicy)
 }
 
+// Epoch implements consensus.EpochReader, returns the epoch of the configuration
+func (s *server) Epoch() uint64 {
+	return s.config.Epoch
+}
+
+// CurrentVerifiers implements consensus.VerifierReader, returns the verifiers of the current snapshot
+func (s *server) CurrentVerifiers() []common.Address {
+	if s.currentBlock == nil {
+		return nil
+	}
+
+	block := s.currentBlock()
+	snap, err := s.snapshot(s.chain, block.Height(), block.Hash(), nil)
+	if err != nil {
+		s.log.Warn("failed to get snapshot of current block %d, %s", block.Height(), err)
+		return nil
+	}
+
+	return snap.verifiers()
+}
+
 func (s *server) getVerifiers(height uint64, hash common.Hash) bft.VerifierSet {
 	snap, err := s.snapshot(s.chain, height, hash, nil)
 	if err != nil {
@@ -52,6 +52,24 @@ type Broadcaster interface {
 	FindPeers(map[common.Address]bool) map[common.Address]Peer
 }
 
+// VerifierReader is implemented by the engines which maintain a verifier set, e.g. bft
+type VerifierReader interface {
+	// CurrentVerifiers returns the verifiers of the snapshot at the current block
+	CurrentVerifiers() []common.Address
+}
+
+// EpochReader is implemented by the engines which change the verifiers at each epoch, e.g. bft
+type EpochReader interface {
+	// Epoch returns the number of blocks of an epoch in the engine config
+	Epoch() uint64
+}
+
+// OnDemandSealer is implemented by the engines which may skip sealing blocks without txs, e.g. dev
+type OnDemandSealer interface {
+	// SealEmptyBlock returns whether the block without any tx or debt should be sealed
+	SealEmptyBlock() bool
+}
+
 // Peer defines the interface to communicate with peer
 type Peer interface {
 	// Send sends the message to this peer
@@ -0,0 +1,121 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package dev
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"time"
+
+	"github.com/seeleteam/go-seele/consensus"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/log"
+	"github.com/seeleteam/go-seele/rpc"
+)
+
+// difficulty is the constant difficulty of the blocks sealed by the dev engine
+var difficulty = big.NewInt(1)
+
+// Engine is a trivial consensus engine for development and testing, which seals
+// the block without any proof of work or signature.
+//
+// If the period is 0, the block is sealed immediately and only when there are txs to pack.
+// Otherwise, a block is sealed every period seconds even if it is empty.
+type Engine struct {
+	period uint64
+	log    *log.SeeleLog
+}
+
+// NewEngine returns a dev consensus engine with the block period in seconds.
+func NewEngine(period uint64) *Engine {
+	return &Engine{
+		period: period,
+		log:    log.GetLogger("dev_engine"),
+	}
+}
+
+// SetThreads is not used by the dev engine.
+func (engine *Engine) SetThreads(threads int) {}
+
+// APIs returns no RPC APIs.
+func (engine *Engine) APIs(chain consensus.ChainReader) []rpc.API {
+	return nil
+}
+
+// GetPrivateKey returns nil since the dev engine does not sign the blocks.
+func (engine *Engine) GetPrivateKey() *ecdsa.PrivateKey {
+	return nil
+}
+
+// SealEmptyBlock implements consensus.OnDemandSealer, empty blocks are sealed only with a period.
+func (engine *Engine) SealEmptyBlock() bool {
+	return engine.period > 0
+}
+
+// VerifyHeader validates the height, timestamp and difficulty of the header against its parent.
+func (engine *Engine) VerifyHeader(reader consensus.ChainReader, header *types.BlockHeader) error {
+	parent := reader.GetHeaderByHash(header.PreviousBlockHash)
+	if parent == nil {
+		return consensus.ErrBlockInvalidParentHash
+	}
+
+	if header.Height != parent.Height+1 {
+		return consensus.ErrBlockInvalidHeight
+	}
+
+	if header.CreateTimestamp.Cmp(parent.CreateTimestamp) < 0 {
+		return consensus.ErrBlockCreateTimeOld
+	}
+
+	if header.Difficulty == nil || header.Difficulty.Cmp(difficulty) != 0 {
+		return consensus.ErrBlockDifficultInvalid
+	}
+
+	return nil
+}
+
+// Prepare sets the constant difficulty of the header, and delays the
+// timestamp to at least one period after the parent.
+func (engine *Engine) Prepare(reader consensus.ChainReader, header *types.BlockHeader) error {
+	parent := reader.GetHeaderByHash(header.PreviousBlockHash)
+	if parent == nil {
+		return consensus.ErrBlockInvalidParentHash
+	}
+
+	if engine.period > 0 {
+		earliest := new(big.Int).Add(parent.CreateTimestamp, new(big.Int).SetUint64(engine.period))
+		if header.CreateTimestamp == nil || header.CreateTimestamp.Cmp(earliest) < 0 {
+			header.CreateTimestamp = earliest
+		}
+	}
+
+	header.Difficulty = new(big.Int).Set(difficulty)
+
+	return nil
+}
+
+// Seal returns the block to the results channel once its timestamp is reached.
+func (engine *Engine) Seal(reader consensus.ChainReader, block *types.Block, stop <-chan struct{}, results chan<- *types.Block) error {
+	delay := time.Until(time.Unix(block.Header.CreateTimestamp.Int64(), 0))
+
+	go func() {
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-stop:
+				return
+			}
+		}
+
+		select {
+		case results <- block:
+			engine.log.Debug("sealed block, height %d, hash %v", block.Header.Height, block.HeaderHash.Hex())
+		case <-stop:
+		}
+	}()
+
+	return nil
+}
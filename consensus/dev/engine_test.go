@@ -0,0 +1,110 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package dev
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/consensus"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// testChainReader only contains the parent header
+type testChainReader struct {
+	parent *types.BlockHeader
+}
+
+func (r *testChainReader) CurrentHeader() *types.BlockHeader { return r.parent }
+
+func (r *testChainReader) GetHeaderByHeight(height uint64) *types.BlockHeader {
+	if height == r.parent.Height {
+		return r.parent
+	}
+
+	return nil
+}
+
+func (r *testChainReader) GetHeaderByHash(hash common.Hash) *types.BlockHeader {
+	if hash == r.parent.Hash() {
+		return r.parent
+	}
+
+	return nil
+}
+
+func (r *testChainReader) GetBlockByHash(hash common.Hash) *types.Block { return nil }
+
+func newTestHeader(reader *testChainReader, timestamp int64) *types.BlockHeader {
+	return &types.BlockHeader{
+		PreviousBlockHash: reader.parent.Hash(),
+		Height:            reader.parent.Height + 1,
+		CreateTimestamp:   big.NewInt(timestamp),
+	}
+}
+
+func Test_Engine_Prepare(t *testing.T) {
+	now := time.Now().Unix()
+	reader := &testChainReader{&types.BlockHeader{Height: 5, CreateTimestamp: big.NewInt(now), Difficulty: big.NewInt(1)}}
+
+	// seal on demand
+	engine := NewEngine(0)
+	assert.Equal(t, engine.SealEmptyBlock(), false)
+
+	header := newTestHeader(reader, now+1)
+	assert.NoError(t, engine.Prepare(reader, header))
+	assert.Equal(t, header.CreateTimestamp.Int64(), now+1)
+	assert.Equal(t, header.Difficulty, big.NewInt(1))
+	assert.NoError(t, engine.VerifyHeader(reader, header))
+
+	// seal with period
+	engine = NewEngine(5)
+	assert.Equal(t, engine.SealEmptyBlock(), true)
+
+	header = newTestHeader(reader, now+1)
+	assert.NoError(t, engine.Prepare(reader, header))
+	assert.Equal(t, header.CreateTimestamp.Int64(), now+5)
+	assert.NoError(t, engine.VerifyHeader(reader, header))
+
+	// invalid parent
+	header.PreviousBlockHash = common.StringToHash("unknown")
+	assert.Equal(t, engine.Prepare(reader, header), consensus.ErrBlockInvalidParentHash)
+}
+
+func Test_Engine_Seal(t *testing.T) {
+	reader := &testChainReader{&types.BlockHeader{Height: 5, CreateTimestamp: big.NewInt(0), Difficulty: big.NewInt(1)}}
+	engine := NewEngine(0)
+
+	header := newTestHeader(reader, time.Now().Unix())
+	assert.NoError(t, engine.Prepare(reader, header))
+	block := types.NewBlock(header, nil, nil, nil)
+
+	results := make(chan *types.Block)
+	assert.NoError(t, engine.Seal(reader, block, make(chan struct{}), results))
+
+	select {
+	case sealed := <-results:
+		assert.Equal(t, sealed, block)
+	case <-time.After(time.Second):
+		t.Fatal("block not sealed immediately")
+	}
+
+	// block in the future is not sealed if stopped
+	header = newTestHeader(reader, time.Now().Unix()+60)
+	assert.NoError(t, engine.Prepare(reader, header))
+	stop := make(chan struct{})
+	assert.NoError(t, engine.Seal(reader, types.NewBlock(header, nil, nil, nil), stop, results))
+	close(stop)
+
+	select {
+	case <-results:
+		t.Fatal("block sealed after stopped")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -6,12 +6,21 @@
 package system
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 
+	"github.com/seeleteam/go-seele/accounts/abi"
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/common/hexutil"
 	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/crypto"
 )
 
 const (
@@ -23,168 +32,635 @@ const (
 	CmdStoreBlockHeader
 	// CmdGetBlockHeader is the command byte to get btc block header
 	CmdGetBlockHeader
+	// CmdGetBestBlockHeader is the command byte to get the btc block header of the heaviest chain tip
+	CmdGetBestBlockHeader
 )
 
-const btcConfirm = 6
+const (
+	// btcConfirmations is the number of blocks on top of the block, inclusive, for the tx to be verified
+	btcConfirmations = 6
+
+	// btcHeaderLength is the length of the serialized btc block header
+	btcHeaderLength = 80
+
+	// btcRelayFee is the fee in fan paid to the relayer of the block header to verify tx against it
+	btcRelayFee = 1
+
+	// btcRelayABI is the abi of the contract to relay the verified btc tx to
+	btcRelayABI = `[{"constant":false,"inputs":[{"name":"txBytes","type":"bytes"},{"name":"txHash","type":"uint256"}],"name":"processTransaction","outputs":[{"name":"","type":"int256"}],"payable":false,"stateMutability":"nonpayable","type":"function"}]`
+)
 
 var (
-	brCommands = map[byte]*cmdInfo{
-		CmdVerifyTx:         &cmdInfo{1000, verifyTx},
-		CmdRelayTx:          &cmdInfo{3000, relayTx},
-		CmdStoreBlockHeader: &cmdInfo{10000, storeBlockHeader},
-		CmdGetBlockHeader:   &cmdInfo{200, getBlockHeader},
-	}
+	brCommands = newBTCRelayCommands(btcMainNetParams)
 
 	// function result
-	failure = []byte{0}
 	success = []byte{1}
 
-	// temp storage
-	preBlockHeight uint64
-	keyBlocksHash  = common.BytesToHash([]byte("BTC-Blocks-Hash"))
-	// @todo will change after the relay chain is successfully established.
-	fee uint64 = 1
+	keyBestBlockHash = common.BytesToHash([]byte("BTC-Best-Block-Hash"))
+
+	btcRelayMethods = mustParseABI(btcRelayABI)
+)
+
+var (
+	errInvalidBTCHeader       = errors.New("invalid btc block header")
+	errBTCHeaderExists        = errors.New("btc block header already exists")
+	errBTCHeaderNotFound      = errors.New("btc block header not found")
+	errBTCParentNotFound      = errors.New("btc block header parent not found")
+	errBTCInvalidPoW          = errors.New("btc block hash does not satisfy the target")
+	errBTCInvalidDifficulty   = errors.New("btc block difficulty bits mismatch")
+	errBTCNotMainChain        = errors.New("btc block is not on the heaviest chain")
+	errBTCNotConfirmed        = errors.New("btc block is not confirmed")
+	errBTCInvalidTx           = errors.New("invalid btc tx")
+	errBTCInvalidMerkleProof  = errors.New("btc tx merkle proof mismatch")
+	errRelayContractNotFound  = errors.New("relay address is not a contract")
+	errContractCallNotAllowed = errors.New("contract call is not supported")
+	errBTCRelayNotConfigured  = errors.New("btc relay checkpoint is not configured")
+	errInvalidBTCCheckpoint   = errors.New("btc relay checkpoint is not at a difficulty adjustment height")
 )
 
-// BTCBlock btc block structure
-type BTCBlock struct {
-	BlockHeaderHex   string
-	Height           uint64
-	PreviousBlockHex string
-	TxHexs           []string
-	Relayer          common.Address
+// btcChainParams are the consensus parameters of the bitcoin network to validate headers
+type btcChainParams struct {
+	// powLimit is the highest target of blocks
+	powLimit *big.Int
+	// retargetInterval is the number of blocks between difficulty adjustments
+	retargetInterval uint64
+	// targetTimespan is the expected seconds of the blocks between difficulty adjustments
+	targetTimespan int64
+}
+
+// btcMainNetParams are the parameters of bitcoin main net
+var btcMainNetParams = &btcChainParams{
+	powLimit:         new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 224), big.NewInt(1)),
+	retargetInterval: 2016,
+	targetTimespan:   14 * 24 * 3600,
+}
+
+// BTCRelayCheckpoint is the trusted btc block header that the relay starts from, which is
+// configured in the genesis block. It should be a recent header so that the whole chain
+// since the btc genesis block is not relayed, and the relay is disabled if not configured.
+type BTCRelayCheckpoint struct {
+	// Header is the hex of the serialized header
+	Header string `json:"header"`
+	// Height is the height of the header, which must be at a difficulty adjustment height
+	Height uint64 `json:"height"`
+}
+
+// InitBTCRelay stores the checkpoint of bitcoin main net in the genesis state of the relay
+func InitBTCRelay(statedb *state.Statedb, checkpoint *BTCRelayCheckpoint) error {
+	relay := &btcRelay{btcMainNetParams}
+	return relay.init(statedb, checkpoint)
+}
+
+// btcRelay relays the btc block headers of the network with the params
+type btcRelay struct {
+	params *btcChainParams
+}
+
+// newBTCRelayCommands returns the commands of the btc relay with the params
+func newBTCRelayCommands(params *btcChainParams) map[byte]*cmdInfo {
+	relay := &btcRelay{params}
+
+	return map[byte]*cmdInfo{
+		CmdVerifyTx:           &cmdInfo{10000, relay.verifyTx},
+		CmdRelayTx:            &cmdInfo{30000, relay.relayTx},
+		CmdStoreBlockHeader:   &cmdInfo{50000, relay.storeBlockHeader},
+		CmdGetBlockHeader:     &cmdInfo{2000, relay.getBlockHeader},
+		CmdGetBestBlockHeader: &cmdInfo{2000, relay.getBestBlockHeader},
+	}
+}
+
+// BTCHash is a bitcoin double sha256 hash in the internal byte order, and is
+// displayed in the reversed byte order as bitcoin does.
+type BTCHash [32]byte
+
+// String returns the hex of the hash in the reversed byte order
+func (h BTCHash) String() string {
+	return hex.EncodeToString(h.reversed())
+}
+
+// Big returns the hash as a big endian number of the reversed byte order
+func (h BTCHash) Big() *big.Int {
+	return new(big.Int).SetBytes(h.reversed())
+}
+
+func (h BTCHash) reversed() []byte {
+	reversed := make([]byte, len(h))
+	for i := range h {
+		reversed[i] = h[len(h)-1-i]
+	}
+
+	return reversed
+}
+
+// MarshalText marshals the hash in the reversed byte order
+func (h BTCHash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText unmarshals the hash in the reversed byte order
+func (h *BTCHash) UnmarshalText(text []byte) error {
+	value, err := hex.DecodeString(strings.TrimPrefix(string(text), "0x"))
+	if err != nil || len(value) != len(h) {
+		return fmt.Errorf("invalid btc hash %s", text)
+	}
+
+	for i := range h {
+		h[i] = value[len(value)-1-i]
+	}
+
+	return nil
+}
+
+// BTCBlockHeader is a validated bitcoin block header stored in the relay
+type BTCBlockHeader struct {
+	Hash       BTCHash
+	Version    int32
+	PrevBlock  BTCHash
+	MerkleRoot BTCHash
+	Timestamp  uint32
+	Bits       uint32
+	Nonce      uint32
+
+	Height uint64
+	// ChainWork is the total work of the chain since the checkpoint, inclusive
+	ChainWork *big.Int
+	// PeriodStart is the timestamp of the first block of the difficulty adjustment period
+	PeriodStart uint32
+	// Relayer is the account that stored the header, which is paid to verify tx against it
+	Relayer common.Address
+}
+
+func (h *BTCBlockHeader) String() string {
+	return fmt.Sprintf("BTCBlockHeader[Hash=%v, Height=%v, PrevBlock=%v, MerkleRoot=%v]", h.Hash, h.Height, h.PrevBlock, h.MerkleRoot)
 }
 
-func (b *BTCBlock) String() string {
-	return fmt.Sprintf("Block[BlockHeaderHex=%v, Height=%v, PreviousBlockHex=%v, TxHexs=%v]", b.BlockHeaderHex, b.Height, b.PreviousBlockHex, b.TxHexs)
+// BTCTxProof is the merkle inclusion proof of a btc tx in a block
+type BTCTxProof struct {
+	// TxHex is the serialized tx without witness
+	TxHex     string
+	BlockHash BTCHash
+	// TxIndex is the index of the tx in the block
+	TxIndex uint32
+	// Siblings are the merkle branch of the tx from the leaf to the root
+	Siblings []BTCHash
 }
 
 // RelayRequest is a request structure using btc-relay
 type RelayRequest struct {
-	BTCBlock
-	TxHex string
+	BTCTxProof
 	// This is used to relay the verification of successful tx to the contract address
 	RelayAddress common.Address
 }
 
 func (r *RelayRequest) String() string {
-	return fmt.Sprintf("RelayRequest[BTCBlock=%v, TxHex=%v, RelayAddress=%v]", r.BTCBlock.String(), r.TxHex, r.RelayAddress.Hex())
+	return fmt.Sprintf("RelayRequest[TxHex=%v, BlockHash=%v, TxIndex=%v, RelayAddress=%v]", r.TxHex, r.BlockHash, r.TxIndex, r.RelayAddress.Hex())
+}
+
+// verify that tx is included in a block on the heaviest chain with enough
+// confirmations, the amount is paid to the relayer of the block header.
+func (relay *btcRelay) verifyTx(request []byte, ctx *Context) ([]byte, error) {
+	var relayRequest RelayRequest
+	if err := json.Unmarshal(request, &relayRequest); err != nil {
+		return nil, fmt.Errorf("Invalid request parameter, %s", err)
+	}
+
+	if _, _, err := relay.verifyBTCTx(&relayRequest.BTCTxProof, ctx); err != nil {
+		return nil, err
+	}
+
+	return success, nil
 }
 
-// verify that tx exists based on request, the amount will be transferred
-// to BTCRelayContractAddress if it doesn't match the block. When it
-// matches the block, the amount will be transferred to the relayer. If an
-// error occurs, the amount will be reverted to user(from).
-func verifyTx(request []byte, ctx *Context) ([]byte, error) {
+// relay the verified btc tx to the seele contract, which is called
+// with processTransaction(bytes txBytes, uint256 txHash).
+func (relay *btcRelay) relayTx(request []byte, ctx *Context) ([]byte, error) {
 	var relayRequest RelayRequest
 	if err := json.Unmarshal(request, &relayRequest); err != nil {
-		return failure, fmt.Errorf("Invalid request parameter, %s", err)
+		return nil, fmt.Errorf("Invalid request parameter, %s", err)
+	}
+
+	if ctx.CallContract == nil {
+		return nil, errContractCallNotAllowed
+	}
+
+	if !relayRequest.RelayAddress.IsEVMContract() || len(ctx.statedb.GetCode(relayRequest.RelayAddress)) == 0 {
+		return nil, errRelayContractNotFound
+	}
+
+	tx, txHash, err := relay.verifyBTCTx(&relayRequest.BTCTxProof, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := btcRelayMethods.Pack("processTransaction", tx, txHash.Big())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to pack relay input, %s", err)
+	}
+
+	return ctx.CallContract(BTCRelayContractAddress, relayRequest.RelayAddress, input)
+}
+
+// storage of Bitcoin block headers, anyone could relay the serialized
+// header which is validated against its parent.
+func (relay *btcRelay) storeBlockHeader(request []byte, ctx *Context) ([]byte, error) {
+	if err := checkBTCRelay(ctx.statedb); err != nil {
+		return nil, err
+	}
+
+	header, err := parseBTCHeader(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = getBTCHeader(ctx.statedb, header.Hash); err == nil {
+		return nil, errBTCHeaderExists
+	}
+
+	parent, err := getBTCHeader(ctx.statedb, header.PrevBlock)
+	if err != nil {
+		return nil, errBTCParentNotFound
+	}
+
+	if err = relay.validateBTCHeader(header, parent); err != nil {
+		return nil, err
+	}
+
+	header.Height = parent.Height + 1
+	header.ChainWork = new(big.Int).Add(parent.ChainWork, btcWork(header.Bits))
+	header.PeriodStart = parent.PeriodStart
+	if header.Height%relay.params.retargetInterval == 0 {
+		header.PeriodStart = header.Timestamp
+	}
+	header.Relayer = ctx.tx.Data.From
+
+	if err = setBTCHeader(ctx.statedb, header); err != nil {
+		return nil, err
+	}
+
+	best, err := getBestBTCHeader(ctx.statedb)
+	if err != nil {
+		return nil, err
+	}
+
+	if header.ChainWork.Cmp(best.ChainWork) > 0 {
+		if err = relay.setBestBTCHeader(ctx.statedb, header); err != nil {
+			return nil, err
+		}
+	}
+
+	return success, nil
+}
+
+// get the stored bitcoin block header in json by the block hash in the internal byte order
+func (relay *btcRelay) getBlockHeader(request []byte, ctx *Context) ([]byte, error) {
+	if err := checkBTCRelay(ctx.statedb); err != nil {
+		return nil, err
+	}
+
+	var hash BTCHash
+	if len(request) != len(hash) {
+		return nil, errBTCHeaderNotFound
+	}
+	copy(hash[:], request)
+
+	header, err := getBTCHeader(ctx.statedb, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(header)
+}
+
+// get the bitcoin block header of the heaviest chain tip in json
+func (relay *btcRelay) getBestBlockHeader(request []byte, ctx *Context) ([]byte, error) {
+	if err := checkBTCRelay(ctx.statedb); err != nil {
+		return nil, err
+	}
+
+	header, err := getBestBTCHeader(ctx.statedb)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(header)
+}
+
+// verifyBTCTx verifies the proof and pays the fee, and returns the serialized tx and its hash
+func (relay *btcRelay) verifyBTCTx(proof *BTCTxProof, ctx *Context) ([]byte, BTCHash, error) {
+	var txHash BTCHash
+	if err := checkBTCRelay(ctx.statedb); err != nil {
+		return nil, txHash, err
 	}
 
 	amount := ctx.tx.Data.Amount
-	if amount.Uint64() < fee {
-		return failure, fmt.Errorf("Verify tx fee is not enough, expected[%d], actual[%d]", fee, amount)
+	if amount.Cmp(big.NewInt(btcRelayFee)) < 0 {
+		return nil, txHash, fmt.Errorf("Verify tx fee is not enough, expected[%d], actual[%d]", btcRelayFee, amount)
+	}
+
+	// a 64 bytes tx could be forged from an inner merkle node
+	tx, err := hexutil.HexToBytes(proof.TxHex)
+	if err != nil || len(tx) == 0 || len(tx) == 64 {
+		return nil, txHash, errBTCInvalidTx
+	}
+
+	header, err := getBTCHeader(ctx.statedb, proof.BlockHash)
+	if err != nil {
+		return nil, txHash, err
+	}
+
+	if hash, err := getBTCMainChainHash(ctx.statedb, header.Height); err != nil || hash != header.Hash {
+		return nil, txHash, errBTCNotMainChain
+	}
+
+	best, err := getBestBTCHeader(ctx.statedb)
+	if err != nil {
+		return nil, txHash, err
+	}
+
+	if best.Height+1 < header.Height+btcConfirmations {
+		return nil, txHash, errBTCNotConfirmed
+	}
+
+	txHash = btcDoubleHash(tx)
+	if !verifyBTCMerkleProof(txHash, proof.TxIndex, proof.Siblings, header.MerkleRoot) {
+		return nil, txHash, errBTCInvalidMerkleProof
+	}
+
+	// Tranfer amount to Relayer, the checkpoint has no relayer
+	if !header.Relayer.IsEmpty() {
+		ctx.statedb.AddBalance(header.Relayer, amount)
+		ctx.statedb.SubBalance(BTCRelayContractAddress, amount)
+	}
+
+	return tx, txHash, nil
+}
+
+// parseBTCHeader parses the serialized btc block header
+func parseBTCHeader(raw []byte) (*BTCBlockHeader, error) {
+	if len(raw) != btcHeaderLength {
+		return nil, errInvalidBTCHeader
+	}
+
+	header := &BTCBlockHeader{
+		Hash:      btcDoubleHash(raw),
+		Version:   int32(binary.LittleEndian.Uint32(raw[0:4])),
+		Timestamp: binary.LittleEndian.Uint32(raw[68:72]),
+		Bits:      binary.LittleEndian.Uint32(raw[72:76]),
+		Nonce:     binary.LittleEndian.Uint32(raw[76:80]),
+	}
+	copy(header.PrevBlock[:], raw[4:36])
+	copy(header.MerkleRoot[:], raw[36:68])
+
+	return header, nil
+}
+
+// validateBTCHeader validates the proof of work and the difficulty of the header against its parent.
+// The timestamp is not validated against the median time of past blocks, which is
+// bounded by the proof of work of the honest chain.
+func (relay *btcRelay) validateBTCHeader(header, parent *BTCBlockHeader) error {
+	target := btcCompactToBig(header.Bits)
+	if target.Sign() <= 0 || target.Cmp(relay.params.powLimit) > 0 {
+		return errBTCInvalidPoW
+	}
+
+	if header.Hash.Big().Cmp(target) > 0 {
+		return errBTCInvalidPoW
+	}
+
+	if header.Bits != relay.nextBits(parent) {
+		return errBTCInvalidDifficulty
+	}
+
+	return nil
+}
+
+// nextBits returns the difficulty bits of the block after the parent, which is
+// adjusted by the timespan of the period at every retarget interval.
+func (relay *btcRelay) nextBits(parent *BTCBlockHeader) uint32 {
+	if (parent.Height+1)%relay.params.retargetInterval != 0 {
+		return parent.Bits
+	}
+
+	timespan := int64(parent.Timestamp) - int64(parent.PeriodStart)
+	if min := relay.params.targetTimespan / 4; timespan < min {
+		timespan = min
+	} else if max := relay.params.targetTimespan * 4; timespan > max {
+		timespan = max
+	}
+
+	target := btcCompactToBig(parent.Bits)
+	target.Mul(target, big.NewInt(timespan))
+	target.Div(target, big.NewInt(relay.params.targetTimespan))
+	if target.Cmp(relay.params.powLimit) > 0 {
+		target.Set(relay.params.powLimit)
+	}
+
+	return btcBigToCompact(target)
+}
+
+// btcWork returns the expected number of hashes to find a block of the difficulty bits
+func btcWork(bits uint32) *big.Int {
+	target := btcCompactToBig(bits)
+	if target.Sign() <= 0 {
+		return big.NewInt(0)
+	}
+
+	denominator := new(big.Int).Add(target, big.NewInt(1))
+	return new(big.Int).Div(new(big.Int).Lsh(big.NewInt(1), 256), denominator)
+}
+
+// btcCompactToBig converts the compact difficulty bits to the target
+func btcCompactToBig(compact uint32) *big.Int {
+	mantissa := compact & 0x007fffff
+	isNegative := compact&0x00800000 != 0
+	exponent := uint(compact >> 24)
+
+	var target *big.Int
+	if exponent <= 3 {
+		target = big.NewInt(int64(mantissa >> (8 * (3 - exponent))))
+	} else {
+		target = new(big.Int).Lsh(big.NewInt(int64(mantissa)), 8*(exponent-3))
+	}
+
+	if isNegative {
+		target.Neg(target)
+	}
+
+	return target
+}
+
+// btcBigToCompact converts the non-negative target to the compact difficulty bits
+func btcBigToCompact(target *big.Int) uint32 {
+	if target.Sign() == 0 {
+		return 0
 	}
 
-	// Match block
-	blocks := getBTCBlocks(ctx.statedb)
-	btcBlock, ok := blocks[relayRequest.BlockHeaderHex]
-	if !ok {
-		return failure, nil
+	var mantissa uint32
+	exponent := uint(len(target.Bytes()))
+	if exponent <= 3 {
+		mantissa = uint32(target.Uint64()) << (8 * (3 - exponent))
+	} else {
+		mantissa = uint32(new(big.Int).Rsh(target, 8*(exponent-3)).Uint64())
 	}
 
-	if preBlockHeight < btcConfirm || btcBlock.Height < preBlockHeight-btcConfirm {
-		return failure, fmt.Errorf("Confirmation need more than 6, latestHeight[%d], queryHeight[%d]", preBlockHeight, btcBlock.Height)
+	// the sign bit is reserved
+	if mantissa&0x00800000 != 0 {
+		mantissa >>= 8
+		exponent++
 	}
 
-	// Tranfer amount to Relayer
-	ctx.statedb.AddBalance(btcBlock.Relayer, amount)
-	ctx.statedb.SubBalance(BTCRelayContractAddress, amount)
+	return uint32(exponent<<24) | mantissa
+}
+
+// verifyBTCMerkleProof verifies the tx hash is at the index of the merkle tree with the root
+func verifyBTCMerkleProof(txHash BTCHash, index uint32, siblings []BTCHash, root BTCHash) bool {
+	if len(siblings) < 32 && index>>uint(len(siblings)) != 0 {
+		return false
+	}
 
-	for _, txHex := range btcBlock.TxHexs {
-		if relayRequest.TxHex == txHex {
-			return success, nil
+	hash := txHash
+	for _, sibling := range siblings {
+		if index&1 == 0 {
+			hash = btcDoubleHash(hash[:], sibling[:])
+		} else {
+			hash = btcDoubleHash(sibling[:], hash[:])
 		}
+		index >>= 1
 	}
 
-	return failure, nil
+	return hash == root
 }
 
-// optionally relay the btc transaction to any Seele contract
-func relayTx(request []byte, ctx *Context) ([]byte, error) {
-	ok, err := verifyTx(request, ctx)
+func btcDoubleHash(data ...[]byte) BTCHash {
+	first := sha256.Sum256(bytes.Join(data, nil))
+	return sha256.Sum256(first[:])
+}
+
+// init stores the checkpoint as the heaviest chain tip of the relay
+func (relay *btcRelay) init(statedb *state.Statedb, checkpoint *BTCRelayCheckpoint) error {
+	if checkpoint.Height%relay.params.retargetInterval != 0 {
+		return errInvalidBTCCheckpoint
+	}
+
+	raw, err := hexutil.HexToBytes(checkpoint.Header)
+	if err != nil {
+		return fmt.Errorf("Invalid btc checkpoint, %s", err)
+	}
+
+	header, err := parseBTCHeader(raw)
 	if err != nil {
-		return failure, err
+		return err
 	}
 
-	if len(ok) == len(success) && success[0] == ok[0] {
-		//@todo transfer tx to relay address
-		return success, nil
+	header.Height = checkpoint.Height
+	header.ChainWork = btcWork(header.Bits)
+	header.PeriodStart = header.Timestamp
+
+	statedb.CreateAccount(BTCRelayContractAddress)
+	if err = setBTCHeader(statedb, header); err != nil {
+		return err
 	}
 
-	return failure, nil
+	// all the stored headers descend from the checkpoint, which is always on the main chain
+	statedb.SetData(BTCRelayContractAddress, btcMainChainKey(header.Height), header.Hash[:])
+	statedb.SetData(BTCRelayContractAddress, keyBestBlockHash, header.Hash[:])
+
+	return nil
 }
 
-// storage of Bitcoin block headers
-func storeBlockHeader(request []byte, ctx *Context) ([]byte, error) {
-	if !isRelayer(ctx.tx.Data.From) {
-		return failure, fmt.Errorf("Invaild block relayer[%s]", ctx.tx.Data.From.Hex())
+// checkBTCRelay returns an error if the checkpoint of the relay is not configured
+func checkBTCRelay(statedb *state.Statedb) error {
+	if len(statedb.GetData(BTCRelayContractAddress, keyBestBlockHash)) == 0 {
+		return errBTCRelayNotConfigured
 	}
 
-	var relayRequest RelayRequest
-	if err := json.Unmarshal(request, &relayRequest); err != nil {
-		return failure, fmt.Errorf("Invalid request parameter, %s", err)
+	return nil
+}
+
+func getBTCHeader(statedb *state.Statedb, hash BTCHash) (*BTCBlockHeader, error) {
+	data := statedb.GetData(BTCRelayContractAddress, btcHeaderKey(hash))
+	if len(data) == 0 {
+		return nil, errBTCHeaderNotFound
 	}
 
-	blocks := getBTCBlocks(ctx.statedb)
-	if _, ok := blocks[relayRequest.BlockHeaderHex]; ok {
-		return failure, fmt.Errorf("Block header already exists")
+	var header BTCBlockHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal btc block header, %s", err)
 	}
 
-	relayRequest.BTCBlock.Relayer = ctx.tx.Data.From
-	blocks[relayRequest.BlockHeaderHex] = relayRequest.BTCBlock
-	preBlockHeight = relayRequest.Height
+	return &header, nil
+}
 
-	bytes, err := json.Marshal(blocks)
+func setBTCHeader(statedb *state.Statedb, header *BTCBlockHeader) error {
+	data, err := json.Marshal(header)
 	if err != nil {
-		return failure, fmt.Errorf("Failed to marshal blocks, %s", err)
+		return fmt.Errorf("Failed to marshal btc block header, %s", err)
 	}
 
-	ctx.statedb.SetData(BTCRelayContractAddress, keyBlocksHash, bytes)
-	return success, nil
+	statedb.SetData(BTCRelayContractAddress, btcHeaderKey(header.Hash), data)
+	return nil
 }
 
-// check if there is a stored bitcoin block header in the contract
-func getBlockHeader(request []byte, ctx *Context) ([]byte, error) {
-	amount := ctx.tx.Data.Amount
-	if amount.Uint64() < fee {
-		return failure, fmt.Errorf("getBlockHeader fee is not enough, expected[%d], actual[%d]", fee, amount)
+func getBestBTCHeader(statedb *state.Statedb) (*BTCBlockHeader, error) {
+	var hash BTCHash
+	copy(hash[:], statedb.GetData(BTCRelayContractAddress, keyBestBlockHash))
+
+	return getBTCHeader(statedb, hash)
+}
+
+// setBestBTCHeader sets the header as the heaviest chain tip, and reorganizes the
+// main chain index back to the fork point.
+func (relay *btcRelay) setBestBTCHeader(statedb *state.Statedb, header *BTCBlockHeader) error {
+	// the heavier chain could be shorter than the previous one
+	if best, err := getBestBTCHeader(statedb); err == nil {
+		for height := header.Height + 1; height <= best.Height; height++ {
+			statedb.SetData(BTCRelayContractAddress, btcMainChainKey(height), nil)
+		}
+	}
+
+	statedb.SetData(BTCRelayContractAddress, keyBestBlockHash, header.Hash[:])
+
+	for current := header; ; {
+		if hash, err := getBTCMainChainHash(statedb, current.Height); err == nil && hash == current.Hash {
+			return nil
+		}
+
+		statedb.SetData(BTCRelayContractAddress, btcMainChainKey(current.Height), current.Hash[:])
+		parent, err := getBTCHeader(statedb, current.PrevBlock)
+		if err != nil {
+			return err
+		}
+		current = parent
 	}
+}
 
-	blocks, blockHeaderHex := getBTCBlocks(ctx.statedb), hexutil.BytesToHex(request)
-	btcBlock, ok := blocks[blockHeaderHex]
-	if !ok {
-		return failure, nil
+// getBTCMainChainHash returns the hash of the block at the height on the heaviest chain
+func getBTCMainChainHash(statedb *state.Statedb, height uint64) (BTCHash, error) {
+	var hash BTCHash
+	data := statedb.GetData(BTCRelayContractAddress, btcMainChainKey(height))
+	if len(data) != len(hash) {
+		return hash, errBTCHeaderNotFound
 	}
 
-	// Tranfer amount to Relayer
-	ctx.statedb.AddBalance(btcBlock.Relayer, amount)
-	ctx.statedb.SubBalance(BTCRelayContractAddress, amount)
+	copy(hash[:], data)
+	return hash, nil
+}
 
-	return success, nil
+func btcHeaderKey(hash BTCHash) common.Hash {
+	return crypto.HashBytes([]byte("header"), hash[:])
 }
 
-// @todo
-func isRelayer(addr common.Address) bool {
-	return true
+func btcMainChainKey(height uint64) common.Hash {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, height)
+	return crypto.HashBytes([]byte("height"), key)
 }
 
-func getBTCBlocks(statedb *state.Statedb) map[string]BTCBlock {
-	var blocks map[string]BTCBlock
-	data := statedb.GetData(BTCRelayContractAddress, keyBlocksHash)
-	if err := json.Unmarshal(data, &blocks); err != nil {
-		return make(map[string]BTCBlock)
+func mustParseABI(definition string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic(err)
 	}
 
-	return blocks
+	return parsed
 }
@@ -0,0 +1,182 @@
+/**
+* @file
+* @copyright defined in go-seele/LICENSE
+ */
+
+package system
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/core/state"
+)
+
+// The btc relay before the fork of the system contracts, which stores the blocks in a
+// json map without validation. It is only kept to process the blocks before the fork.
+
+const (
+	legacyBTCConfirm = 6
+	legacyBTCFee     = uint64(1)
+)
+
+var (
+	legacyBRCommands = map[byte]*cmdInfo{
+		CmdVerifyTx:         &cmdInfo{1000, legacyVerifyTx},
+		CmdRelayTx:          &cmdInfo{3000, legacyRelayTx},
+		CmdStoreBlockHeader: &cmdInfo{10000, legacyStoreBlockHeader},
+		CmdGetBlockHeader:   &cmdInfo{200, legacyGetBlockHeader},
+	}
+
+	// function result
+	legacyFailure = []byte{0}
+
+	// temp storage, the height of the last block stored in the process as it was
+	legacyPreBlockHeight uint64
+	keyLegacyBlocksHash  = common.BytesToHash([]byte("BTC-Blocks-Hash"))
+)
+
+// legacyBTCBlock btc block structure before the fork
+type legacyBTCBlock struct {
+	BlockHeaderHex   string
+	Height           uint64
+	PreviousBlockHex string
+	TxHexs           []string
+	Relayer          common.Address
+}
+
+func (b *legacyBTCBlock) String() string {
+	return fmt.Sprintf("Block[BlockHeaderHex=%v, Height=%v, PreviousBlockHex=%v, TxHexs=%v]", b.BlockHeaderHex, b.Height, b.PreviousBlockHex, b.TxHexs)
+}
+
+// legacyRelayRequest is a request structure using btc-relay before the fork
+type legacyRelayRequest struct {
+	legacyBTCBlock
+	TxHex string
+	// This is used to relay the verification of successful tx to the contract address
+	RelayAddress common.Address
+}
+
+func (r *legacyRelayRequest) String() string {
+	return fmt.Sprintf("RelayRequest[BTCBlock=%v, TxHex=%v, RelayAddress=%v]", r.legacyBTCBlock.String(), r.TxHex, r.RelayAddress.Hex())
+}
+
+// verify that tx exists based on request, the amount will be transferred
+// to BTCRelayContractAddress if it doesn't match the block. When it
+// matches the block, the amount will be transferred to the relayer. If an
+// error occurs, the amount will be reverted to user(from).
+func legacyVerifyTx(request []byte, ctx *Context) ([]byte, error) {
+	var relayRequest legacyRelayRequest
+	if err := json.Unmarshal(request, &relayRequest); err != nil {
+		return legacyFailure, fmt.Errorf("Invalid request parameter, %s", err)
+	}
+
+	amount := ctx.tx.Data.Amount
+	if amount.Uint64() < legacyBTCFee {
+		return legacyFailure, fmt.Errorf("Verify tx fee is not enough, expected[%d], actual[%d]", legacyBTCFee, amount)
+	}
+
+	// Match block
+	blocks := getLegacyBTCBlocks(ctx.statedb)
+	btcBlock, ok := blocks[relayRequest.BlockHeaderHex]
+	if !ok {
+		return legacyFailure, nil
+	}
+
+	if legacyPreBlockHeight < legacyBTCConfirm || btcBlock.Height < legacyPreBlockHeight-legacyBTCConfirm {
+		return legacyFailure, fmt.Errorf("Confirmation need more than 6, latestHeight[%d], queryHeight[%d]", legacyPreBlockHeight, btcBlock.Height)
+	}
+
+	// Tranfer amount to Relayer
+	ctx.statedb.AddBalance(btcBlock.Relayer, amount)
+	ctx.statedb.SubBalance(BTCRelayContractAddress, amount)
+
+	for _, txHex := range btcBlock.TxHexs {
+		if relayRequest.TxHex == txHex {
+			return success, nil
+		}
+	}
+
+	return legacyFailure, nil
+}
+
+// optionally relay the btc transaction to any Seele contract
+func legacyRelayTx(request []byte, ctx *Context) ([]byte, error) {
+	ok, err := legacyVerifyTx(request, ctx)
+	if err != nil {
+		return legacyFailure, err
+	}
+
+	if len(ok) == len(success) && success[0] == ok[0] {
+		//@todo transfer tx to relay address
+		return success, nil
+	}
+
+	return legacyFailure, nil
+}
+
+// storage of Bitcoin block headers
+func legacyStoreBlockHeader(request []byte, ctx *Context) ([]byte, error) {
+	if !legacyIsRelayer(ctx.tx.Data.From) {
+		return legacyFailure, fmt.Errorf("Invaild block relayer[%s]", ctx.tx.Data.From.Hex())
+	}
+
+	var relayRequest legacyRelayRequest
+	if err := json.Unmarshal(request, &relayRequest); err != nil {
+		return legacyFailure, fmt.Errorf("Invalid request parameter, %s", err)
+	}
+
+	blocks := getLegacyBTCBlocks(ctx.statedb)
+	if _, ok := blocks[relayRequest.BlockHeaderHex]; ok {
+		return legacyFailure, fmt.Errorf("Block header already exists")
+	}
+
+	relayRequest.legacyBTCBlock.Relayer = ctx.tx.Data.From
+	blocks[relayRequest.BlockHeaderHex] = relayRequest.legacyBTCBlock
+	legacyPreBlockHeight = relayRequest.Height
+
+	bytes, err := json.Marshal(blocks)
+	if err != nil {
+		return legacyFailure, fmt.Errorf("Failed to marshal blocks, %s", err)
+	}
+
+	ctx.statedb.SetData(BTCRelayContractAddress, keyLegacyBlocksHash, bytes)
+	return success, nil
+}
+
+// check if there is a stored bitcoin block header in the contract
+func legacyGetBlockHeader(request []byte, ctx *Context) ([]byte, error) {
+	amount := ctx.tx.Data.Amount
+	if amount.Uint64() < legacyBTCFee {
+		return legacyFailure, fmt.Errorf("getBlockHeader fee is not enough, expected[%d], actual[%d]", legacyBTCFee, amount)
+	}
+
+	blocks, blockHeaderHex := getLegacyBTCBlocks(ctx.statedb), hexutil.BytesToHex(request)
+	btcBlock, ok := blocks[blockHeaderHex]
+	if !ok {
+		return legacyFailure, nil
+	}
+
+	// Tranfer amount to Relayer
+	ctx.statedb.AddBalance(btcBlock.Relayer, amount)
+	ctx.statedb.SubBalance(BTCRelayContractAddress, amount)
+
+	return success, nil
+}
+
+// @todo
+func legacyIsRelayer(addr common.Address) bool {
+	return true
+}
+
+func getLegacyBTCBlocks(statedb *state.Statedb) map[string]legacyBTCBlock {
+	var blocks map[string]legacyBTCBlock
+	data := statedb.GetData(BTCRelayContractAddress, keyLegacyBlocksHash)
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return make(map[string]legacyBTCBlock)
+	}
+
+	return blocks
+}
@@ -6,10 +6,9 @@
 package system
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"math/big"
-	"os"
-	"path/filepath"
 	"testing"
 
 	"github.com/seeleteam/go-seele/common"
@@ -19,201 +18,457 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func Test_verifyTx(t *testing.T) {
-	req, reqBytes := newTestRelayRequest(t)
-	ctx := newTestRelayContext(t)
+const (
+	// difficulty bits of the test chain, about 1/256 hashes satisfy the target
+	testBTCBits = uint32(0x2000ffff)
+	// difficulty bits of the pow limit of the test chain
+	testBTCPowLimit = uint32(0x2001ffff)
+	// block interval of the test chain
+	testBTCInterval = uint32(600)
 
-	// simulate svm transfer amount to contract address
-	ctx.statedb.AddBalance(BTCRelayContractAddress, ctx.tx.Data.Amount)
-	blocks := getBTCBlocks(ctx.statedb)
-	oriRelayerBalance := ctx.statedb.GetBalance(blocks[req.BlockHeaderHex].Relayer)
-	oriContractBalance := ctx.statedb.GetBalance(BTCRelayContractAddress)
+	// the genesis block of bitcoin main net
+	testBTCGenesis = "0x0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c"
+)
+
+// testBTCChain mines headers on top of the tip in the relay
+type testBTCChain struct {
+	t       *testing.T
+	context *Context
+	relay   *btcRelay
+	mined   int
+}
 
-	ok1, err1 := verifyTx(reqBytes, ctx)
-	assert.NoError(t, err1)
-	assert.Equal(t, success, ok1)
+// newTestBTCRelayContext creates a context of the relay with the test params,
+// which adjusts the difficulty every 4 blocks.
+func newTestBTCRelayContext(t *testing.T) (*Context, *testBTCChain) {
+	checkpoint := newTestBTCHeader(BTCHash{}, BTCHash{}, 1000000, testBTCBits)
 
-	curRelayerBalance := ctx.statedb.GetBalance(blocks[req.BlockHeaderHex].Relayer)
-	curContractBalance := ctx.statedb.GetBalance(BTCRelayContractAddress)
-	assert.Equal(t, curContractBalance.Add(curContractBalance, ctx.tx.Data.Amount), oriContractBalance)
-	assert.Equal(t, curRelayerBalance.Sub(curRelayerBalance, ctx.tx.Data.Amount), oriRelayerBalance)
+	relay := &btcRelay{&btcChainParams{
+		powLimit:         btcCompactToBig(testBTCPowLimit),
+		retargetInterval: 4,
+		targetTimespan:   4 * int64(testBTCInterval),
+	}}
 
-	// invalid request
-	ok2, err2 := verifyTx([]byte{0, 1, 2, 3}, ctx)
-	assert.Error(t, err2)
-	assert.Equal(t, failure, ok2)
-
-	// confirmation is less than 6
-	for _, block := range blocks {
-		if block.Height < preBlockHeight-6 {
-			req1, _ := newTestRelayRequest(t)
-			req1.BlockHeaderHex = block.BlockHeaderHex
-			reqBytes1, err1 := json.Marshal(req1)
-			assert.Equal(t, nil, err1)
-
-			ok2, err2 := verifyTx(reqBytes1, ctx)
-			assert.Error(t, err2)
-			assert.Equal(t, failure, ok2)
-			break
+	db, dispose := leveldb.NewTestDatabase()
+	t.Cleanup(dispose)
+
+	context := newTestContext(db, BTCRelayContractAddress)
+	context.statedb.CreateAccount(context.tx.Data.From)
+	context.statedb.AddBalance(BTCRelayContractAddress, context.tx.Data.Amount)
+	assert.Equal(t, relay.init(context.statedb, &BTCRelayCheckpoint{Header: hexutil.BytesToHex(checkpoint)}), nil)
+
+	return context, &testBTCChain{t: t, context: context, relay: relay}
+}
+
+// newTestBTCHeader mines the serialized header
+func newTestBTCHeader(prev BTCHash, merkleRoot BTCHash, timestamp, bits uint32) []byte {
+	raw := make([]byte, btcHeaderLength)
+	binary.LittleEndian.PutUint32(raw[0:4], 1)
+	copy(raw[4:36], prev[:])
+	copy(raw[36:68], merkleRoot[:])
+	binary.LittleEndian.PutUint32(raw[68:72], timestamp)
+	binary.LittleEndian.PutUint32(raw[72:76], bits)
+
+	target := btcCompactToBig(bits)
+	for nonce := uint32(0); ; nonce++ {
+		binary.LittleEndian.PutUint32(raw[76:80], nonce)
+		if btcDoubleHash(raw).Big().Cmp(target) <= 0 {
+			return raw
 		}
 	}
+}
 
-	// fee is not enough
-	ctx.tx.Data.Amount = big.NewInt(0)
-	ok4, err4 := verifyTx(reqBytes, ctx)
-	assert.Error(t, err4)
-	assert.Equal(t, failure, ok4)
-	ctx.tx.Data.Amount = big.NewInt(1)
-
-	// tx doesn't exist
-	req.TxHex = "asdf"
-	reqBytes2, err21 := json.Marshal(req)
-	assert.NoError(t, err21)
-	ok6, err6 := verifyTx(reqBytes2, ctx)
-	assert.NoError(t, err6)
-	assert.Equal(t, failure, ok6)
-
-	// blockheader doesn't exist
-	req.BlockHeaderHex = "asdf"
-	reqBytes1, err11 := json.Marshal(req)
-	assert.NoError(t, err11)
-	ok5, err5 := verifyTx(reqBytes1, ctx)
-	assert.NoError(t, err5)
-	assert.Equal(t, failure, ok5)
-}
-
-func Test_relayTx(t *testing.T) {
-	req, reqBytes := newTestRelayRequest(t)
-	ctx := newTestRelayContext(t)
-
-	// simulate svm transfer amount to contract address
-	ctx.statedb.AddBalance(BTCRelayContractAddress, ctx.tx.Data.Amount)
-	blocks := getBTCBlocks(ctx.statedb)
-	oriRelayerBalance := ctx.statedb.GetBalance(blocks[req.BlockHeaderHex].Relayer)
-	oriContractBalance := ctx.statedb.GetBalance(BTCRelayContractAddress)
-
-	ok1, err1 := relayTx(reqBytes, ctx)
-	assert.NoError(t, err1)
-	assert.Equal(t, success, ok1)
-
-	curRelayerBalance := ctx.statedb.GetBalance(blocks[req.BlockHeaderHex].Relayer)
-	curContractBalance := ctx.statedb.GetBalance(BTCRelayContractAddress)
-	assert.Equal(t, curContractBalance.Add(curContractBalance, ctx.tx.Data.Amount), oriContractBalance)
-	assert.Equal(t, curRelayerBalance.Sub(curRelayerBalance, ctx.tx.Data.Amount), oriRelayerBalance)
-
-	// verifyTx error
-	ok2, err2 := relayTx([]byte{0, 1, 2, 3}, ctx)
-	assert.Error(t, err2)
-	assert.Equal(t, failure, ok2)
-}
-
-func Test_storeBlockHeader(t *testing.T) {
-	req, _ := newTestRelayRequest(t)
-	req.PreviousBlockHex = req.BlockHeaderHex
-	req.BlockHeaderHex = crypto.MustGenerateRandomAddress().Hex()
-	req.TxHexs = []string{crypto.MustGenerateRandomAddress().Hex()}
-	req.Height++
-	reqBytes, err := json.Marshal(req)
-	assert.NoError(t, err)
+// mine stores a header with the merkle root on top of the parent, and returns it
+func (c *testBTCChain) mine(parent BTCHash, merkleRoot BTCHash, interval uint32) *BTCBlockHeader {
+	header, err := getBTCHeader(c.context.statedb, parent)
+	assert.Equal(c.t, err, nil)
 
-	ctx := newTestRelayContext(t)
-	ok1, err1 := storeBlockHeader(reqBytes, ctx)
-	assert.NoError(t, err1)
-	assert.Equal(t, success, ok1)
-	assert.Equal(t, req.Height, preBlockHeight)
+	raw := newTestBTCHeader(parent, merkleRoot, header.Timestamp+interval, c.relay.nextBits(header))
+	_, err = c.relay.storeBlockHeader(raw, c.context)
+	assert.Equal(c.t, err, nil)
 
-	blocks := getBTCBlocks(ctx.statedb)
-	block := blocks[req.BlockHeaderHex]
-	assert.Equal(t, req.BlockHeaderHex, block.BlockHeaderHex)
-	assert.Equal(t, req.Height, block.Height)
-	assert.Equal(t, req.PreviousBlockHex, block.PreviousBlockHex)
-	assert.Equal(t, req.TxHexs, block.TxHexs)
+	stored, err := getBTCHeader(c.context.statedb, btcDoubleHash(raw))
+	assert.Equal(c.t, err, nil)
+	return stored
+}
 
-	// invalid request
-	ok2, err2 := storeBlockHeader([]byte{0, 1, 2, 3}, ctx)
-	assert.Error(t, err2)
-	assert.Equal(t, failure, ok2)
+// extend mines n blocks on top of the parent with the interval, and returns the last one
+func (c *testBTCChain) extend(parent BTCHash, n int, interval uint32) *BTCBlockHeader {
+	var header *BTCBlockHeader
+	for i := 0; i < n; i++ {
+		// distinct merkle roots for the forks
+		c.mined++
+		header = c.mine(parent, BTCHash{byte(c.mined), byte(c.mined >> 8)}, interval)
+		parent = header.Hash
+	}
 
-	// repeat store
-	ok3, err3 := storeBlockHeader(reqBytes, ctx)
-	assert.Error(t, err3)
-	assert.Equal(t, failure, ok3)
-}
-
-func Test_getBlockHeader(t *testing.T) {
-	req, _ := newTestRelayRequest(t)
-	headerBytes, err := hexutil.HexToBytes(req.BlockHeaderHex)
-	assert.NoError(t, err)
-	// simulate svm transfer amount to contract address
-	ctx := newTestRelayContext(t)
-	ctx.statedb.AddBalance(BTCRelayContractAddress, ctx.tx.Data.Amount)
-	blocks := getBTCBlocks(ctx.statedb)
-	oriRelayerBalance := ctx.statedb.GetBalance(blocks[req.BlockHeaderHex].Relayer)
-	oriContractBalance := ctx.statedb.GetBalance(BTCRelayContractAddress)
-
-	ok, err1 := getBlockHeader(headerBytes, ctx)
-	assert.NoError(t, err1)
-	assert.Equal(t, success, ok)
-
-	curRelayerBalance := ctx.statedb.GetBalance(blocks[req.BlockHeaderHex].Relayer)
-	curContractBalance := ctx.statedb.GetBalance(BTCRelayContractAddress)
-	assert.Equal(t, curContractBalance.Add(curContractBalance, ctx.tx.Data.Amount), oriContractBalance)
-	assert.Equal(t, curRelayerBalance.Sub(curRelayerBalance, ctx.tx.Data.Amount), oriRelayerBalance)
+	return header
+}
 
-	// fee is not enough
-	ctx.tx.Data.Amount = big.NewInt(0)
-	ok2, err2 := getBlockHeader(headerBytes, ctx)
-	assert.Error(t, err2)
-	assert.Equal(t, failure, ok2)
-	ctx.tx.Data.Amount = big.NewInt(1)
-
-	// blockheader doesn't exist
-	ok3, err3 := getBlockHeader([]byte("asdf"), ctx)
-	assert.NoError(t, err3)
-	assert.Equal(t, failure, ok3)
-}
-
-func newTestRelayContext(t *testing.T) *Context {
-	dbPath := filepath.Join(common.GetTempFolder(), ".newTestRelayContext")
-	db, err := leveldb.NewLevelDB(dbPath)
-	if err != nil {
-		panic(err)
+func (c *testBTCChain) best() *BTCBlockHeader {
+	header, err := getBestBTCHeader(c.context.statedb)
+	assert.Equal(c.t, err, nil)
+	return header
+}
+
+// newTestBTCTxs returns the txs with the merkle root and the proof of the tx at the index
+func newTestBTCTxs(n int, index int) ([][]byte, BTCHash, []BTCHash) {
+	var txs [][]byte
+	var level []BTCHash
+	for i := 0; i < n; i++ {
+		tx := append([]byte("btc tx"), byte(i))
+		txs = append(txs, tx)
+		level = append(level, btcDoubleHash(tx))
 	}
 
-	defer func() {
-		db.Close()
-		os.RemoveAll(dbPath)
-	}()
-
-	ctx := newTestContext(db, BTCRelayContractAddress)
-	req, reqBytes := newTestRelayRequest(t)
-	storeBlockHeader(reqBytes, ctx)
-	for height := uint64(0); height < 10; height++ {
-		req.PreviousBlockHex = req.BlockHeaderHex
-		req.BlockHeaderHex = crypto.MustGenerateRandomAddress().Hex()
-		req.TxHexs = []string{crypto.MustGenerateRandomAddress().Hex()}
-		req.Height = height
-		reqBytes, _ := json.Marshal(req)
-		storeBlockHeader(reqBytes, ctx)
+	var siblings []BTCHash
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+
+		siblings = append(siblings, level[index^1])
+
+		var next []BTCHash
+		for i := 0; i < len(level); i += 2 {
+			next = append(next, btcDoubleHash(level[i][:], level[i+1][:]))
+		}
+		level, index = next, index/2
 	}
 
-	return ctx
+	return txs, level[0], siblings
 }
 
-func newTestRelayRequest(t *testing.T) (*RelayRequest, []byte) {
+func newTestRelayRequest(t *testing.T, tx []byte, blockHash BTCHash, index uint32, siblings []BTCHash) []byte {
 	req := &RelayRequest{
-		BTCBlock: BTCBlock{
-			BlockHeaderHex:   "0x0f9188f13cb7b2c71f2a335e3a4fc328bf5beb436012afca590b1a11466e2206",
-			Height:           10,
-			PreviousBlockHex: "0x2a53daccb2587168ee58e385e7ba274de1ae37c5d21b6b709a81d019fa2a65b4",
-			TxHexs:           []string{"0x4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b"},
+		BTCTxProof: BTCTxProof{
+			TxHex:     hexutil.BytesToHex(tx),
+			BlockHash: blockHash,
+			TxIndex:   index,
+			Siblings:  siblings,
 		},
-		TxHex:        "0x4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b",
 		RelayAddress: common.EmptyAddress,
 	}
-	reqBytes, err := json.Marshal(req)
-	assert.NoError(t, err)
 	assert.NotEmpty(t, req.String())
 
-	return req, reqBytes
+	reqBytes, err := json.Marshal(req)
+	assert.Equal(t, err, nil)
+	return reqBytes
+}
+
+func Test_BTCHash(t *testing.T) {
+	genesis, err := parseBTCHeader(hexutil.MustHexToBytes(testBTCGenesis))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, genesis.Hash.String(), "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f")
+	assert.Equal(t, genesis.MerkleRoot.String(), "4a5e1e4baab89f3a32518a88c31bc87f618f76673e2cc77ab2127b7afdeda33b")
+
+	data, err := json.Marshal(genesis.Hash)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, string(data), `"000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"`)
+
+	var hash BTCHash
+	assert.Equal(t, json.Unmarshal(data, &hash), nil)
+	assert.Equal(t, hash, genesis.Hash)
+	assert.Error(t, json.Unmarshal([]byte(`"0019d6"`), &hash))
+}
+
+func Test_BTCCompact(t *testing.T) {
+	target := btcCompactToBig(0x1d00ffff)
+	assert.Equal(t, target.Cmp(new(big.Int).Lsh(big.NewInt(0xffff), 208)), 0)
+	assert.Equal(t, btcBigToCompact(target), uint32(0x1d00ffff))
+
+	// the sign bit is moved to the exponent
+	assert.Equal(t, btcBigToCompact(big.NewInt(0x80)), uint32(0x02008000))
+	assert.Equal(t, btcCompactToBig(0x02008000).Cmp(big.NewInt(0x80)), 0)
+	assert.Equal(t, btcCompactToBig(0x01003456).Sign(), 0)
+	assert.Equal(t, btcCompactToBig(0x04923456).Sign(), -1)
+}
+
+func Test_storeBlockHeader_MainNet(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	// the relay of bitcoin main net starts from the checkpoint in the genesis state
+	context := newTestContext(db, BTCRelayContractAddress)
+	context.BlockHeader.Height = common.SystemContractForkHeight
+	assert.Equal(t, InitBTCRelay(context.statedb, &BTCRelayCheckpoint{Header: testBTCGenesis}), nil)
+	c := GetContractByAddress(BTCRelayContractAddress)
+
+	// block 1 and 2 of bitcoin main net
+	block1 := hexutil.MustHexToBytes("0x010000006fe28c0ab6f1b372c1a6a246ae63f74f931e8365e15a089c68d6190000000000982051fd1e4ba744bbbe680e1fee14677ba1a3c3540bf7b1cdb606e857233e0e61bc6649ffff001d01e36299")
+	block2 := hexutil.MustHexToBytes("0x010000004860eb18bf1b1620e37e9490fc8a427514416fd75159ab86688e9a8300000000d5fdcc541e25de1c7a5addedf24858b8bb665c9f36ef744ee42c316022c90f9bb0bc6649ffff001d08d2bd61")
+
+	// parent not found
+	_, err := c.Run(append([]byte{CmdStoreBlockHeader}, block2...), context)
+	assert.Equal(t, err, errBTCParentNotFound)
+
+	for _, raw := range [][]byte{block1, block2} {
+		_, err = c.Run(append([]byte{CmdStoreBlockHeader}, raw...), context)
+		assert.Equal(t, err, nil)
+	}
+
+	result, err := c.Run([]byte{CmdGetBestBlockHeader}, context)
+	assert.Equal(t, err, nil)
+
+	var best BTCBlockHeader
+	assert.Equal(t, json.Unmarshal(result, &best), nil)
+	assert.Equal(t, best.Hash.String(), "000000006a625f06636b8bb6ac7b960a8d03705d1ace08b1a19da3fdcc99ddbd")
+	assert.Equal(t, best.Height, uint64(2))
+	assert.Equal(t, best.Relayer, context.tx.Data.From)
+	assert.Equal(t, best.ChainWork.Cmp(new(big.Int).Mul(btcWork(0x1d00ffff), big.NewInt(3))), 0)
+
+	// get header by hash
+	result, err = c.Run(append([]byte{CmdGetBlockHeader}, best.PrevBlock[:]...), context)
+	assert.Equal(t, err, nil)
+
+	var header BTCBlockHeader
+	assert.Equal(t, json.Unmarshal(result, &header), nil)
+	assert.Equal(t, header.Hash.String(), "00000000839a8e6886ab5951d76f411475428afc90947ee320161bbf18eb6048")
+	assert.Equal(t, header.Height, uint64(1))
+
+	// repeat store
+	_, err = c.Run(append([]byte{CmdStoreBlockHeader}, block1...), context)
+	assert.Equal(t, err, errBTCHeaderExists)
+
+	// invalid proof of work
+	block1[76]++
+	_, err = c.Run(append([]byte{CmdStoreBlockHeader}, block1...), context)
+	assert.Equal(t, err, errBTCInvalidPoW)
+
+	// invalid length
+	_, err = c.Run(append([]byte{CmdStoreBlockHeader}, block1[1:]...), context)
+	assert.Equal(t, err, errInvalidBTCHeader)
+
+	// header not found
+	_, err = c.Run([]byte{CmdGetBlockHeader, 1, 2, 3}, context)
+	assert.Equal(t, err, errBTCHeaderNotFound)
+}
+
+func Test_storeBlockHeader_Difficulty(t *testing.T) {
+	context, chain := newTestBTCRelayContext(t)
+
+	// the period is 4 times faster than expected
+	tip := chain.extend(chain.best().Hash, 2, testBTCInterval/4)
+
+	// the difficulty is not adjusted within the period
+	raw := newTestBTCHeader(tip.Hash, BTCHash{}, tip.Timestamp+testBTCInterval, testBTCBits-1)
+	_, err := chain.relay.storeBlockHeader(raw, context)
+	assert.Equal(t, err, errBTCInvalidDifficulty)
+
+	tip = chain.extend(tip.Hash, 1, testBTCInterval/4)
+	raw = newTestBTCHeader(tip.Hash, BTCHash{}, tip.Timestamp+testBTCInterval, testBTCBits)
+	_, err = chain.relay.storeBlockHeader(raw, context)
+	assert.Equal(t, err, errBTCInvalidDifficulty)
+
+	header := chain.extend(tip.Hash, 1, testBTCInterval)
+	assert.Equal(t, header.Height, uint64(4))
+	assert.Equal(t, header.Bits, uint32(0x1f3fffc0))
+	assert.Equal(t, header.PeriodStart, header.Timestamp)
+
+	// the period is far slower than expected, the timespan is capped to 4 times
+	tip = chain.extend(header.Hash, 3, 100*testBTCInterval)
+	header = chain.extend(tip.Hash, 1, testBTCInterval)
+	assert.Equal(t, header.Bits, testBTCBits)
+
+	// the target is capped to the pow limit
+	tip = chain.extend(header.Hash, 3, 100*testBTCInterval)
+	header = chain.extend(tip.Hash, 1, testBTCInterval)
+	assert.Equal(t, header.Height, uint64(12))
+	assert.Equal(t, header.Bits, testBTCPowLimit)
+}
+
+func Test_storeBlockHeader_Reorg(t *testing.T) {
+	context, chain := newTestBTCRelayContext(t)
+	checkpoint := chain.best()
+
+	main := chain.extend(checkpoint.Hash, 3, testBTCInterval)
+	assert.Equal(t, chain.best().Hash, main.Hash)
+
+	// the fork with the same work does not replace the best
+	fork := chain.extend(checkpoint.Hash, 3, testBTCInterval)
+	assert.Equal(t, chain.best().Hash, main.Hash)
+
+	hash, err := getBTCMainChainHash(context.statedb, 3)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, hash, main.Hash)
+
+	// the heavier fork becomes the best
+	fork = chain.extend(fork.Hash, 1, testBTCInterval)
+	assert.Equal(t, chain.best().Hash, fork.Hash)
+
+	for current := fork; current.Height > checkpoint.Height; {
+		hash, err = getBTCMainChainHash(context.statedb, current.Height)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, hash, current.Hash)
+
+		current, err = getBTCHeader(context.statedb, current.PrevBlock)
+		assert.Equal(t, err, nil)
+	}
+
+	// the previous main chain is still stored
+	header, err := getBTCHeader(context.statedb, main.Hash)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, header.Height, uint64(3))
+}
+
+func Test_BTCRelay_VerifyTx(t *testing.T) {
+	context, chain := newTestBTCRelayContext(t)
+	txs, root, siblings := newTestBTCTxs(5, 2)
+
+	relayer := *crypto.MustGenerateShardAddress(1)
+	context.statedb.CreateAccount(relayer)
+	from := context.tx.Data.From
+	context.tx.Data.From = relayer
+	block := chain.mine(chain.best().Hash, root, testBTCInterval)
+	context.tx.Data.From = from
+
+	reqBytes := newTestRelayRequest(t, txs[2], block.Hash, 2, siblings)
+
+	// not confirmed
+	chain.extend(block.Hash, btcConfirmations-2, testBTCInterval)
+	_, err := chain.relay.verifyTx(reqBytes, context)
+	assert.Equal(t, err, errBTCNotConfirmed)
+
+	tip := chain.extend(chain.best().Hash, 1, testBTCInterval)
+	result, err := chain.relay.verifyTx(reqBytes, context)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result, success)
+
+	// the fee is paid to the relayer
+	assert.Equal(t, context.statedb.GetBalance(relayer).Cmp(context.tx.Data.Amount), 0)
+	assert.Equal(t, context.statedb.GetBalance(BTCRelayContractAddress).Sign(), 0)
+
+	// tx not in the block
+	_, err = chain.relay.verifyTx(newTestRelayRequest(t, txs[3], block.Hash, 2, siblings), context)
+	assert.Equal(t, err, errBTCInvalidMerkleProof)
+
+	_, err = chain.relay.verifyTx(newTestRelayRequest(t, txs[2], block.Hash, 3, siblings), context)
+	assert.Equal(t, err, errBTCInvalidMerkleProof)
+
+	_, err = chain.relay.verifyTx(newTestRelayRequest(t, txs[2], block.Hash, 2+8, siblings), context)
+	assert.Equal(t, err, errBTCInvalidMerkleProof)
+
+	// the inner merkle node is not a tx
+	txHash := btcDoubleHash(txs[2])
+	inner := append(txHash[:], siblings[0][:]...)
+	_, err = chain.relay.verifyTx(newTestRelayRequest(t, inner, block.Hash, 1, siblings[1:]), context)
+	assert.Equal(t, err, errBTCInvalidTx)
+
+	// block not found
+	_, err = chain.relay.verifyTx(newTestRelayRequest(t, txs[2], BTCHash{1}, 2, siblings), context)
+	assert.Equal(t, err, errBTCHeaderNotFound)
+
+	// invalid request
+	_, err = chain.relay.verifyTx([]byte{0, 1, 2, 3}, context)
+	assert.Error(t, err)
+
+	// the fee is compared without truncation
+	context.tx.Data.Amount = new(big.Int).Lsh(big.NewInt(1), 64)
+	context.statedb.AddBalance(BTCRelayContractAddress, context.tx.Data.Amount)
+	_, err = chain.relay.verifyTx(reqBytes, context)
+	assert.Equal(t, err, nil)
+
+	// fee is not enough
+	context.tx.Data.Amount = big.NewInt(0)
+	_, err = chain.relay.verifyTx(reqBytes, context)
+	assert.Error(t, err)
+	context.tx.Data.Amount = big.NewInt(1)
+
+	// the block is orphaned by a heavier fork
+	fork := chain.extend(block.PrevBlock, int(tip.Height-block.Height)+2, testBTCInterval)
+	assert.Equal(t, chain.best().Hash, fork.Hash)
+	_, err = chain.relay.verifyTx(reqBytes, context)
+	assert.Equal(t, err, errBTCNotMainChain)
+}
+
+func Test_BTCRelay_RelayTx(t *testing.T) {
+	context, chain := newTestBTCRelayContext(t)
+	txs, root, siblings := newTestBTCTxs(1, 0)
+	block := chain.mine(chain.best().Hash, root, testBTCInterval)
+	chain.extend(block.Hash, btcConfirmations-1, testBTCInterval)
+
+	target := crypto.CreateAddress(context.tx.Data.From, 1)
+	context.statedb.CreateAccount(target)
+
+	var req RelayRequest
+	assert.Equal(t, json.Unmarshal(newTestRelayRequest(t, txs[0], block.Hash, 0, siblings), &req), nil)
+	req.RelayAddress = target
+	reqBytes, err := json.Marshal(req)
+	assert.Equal(t, err, nil)
+
+	// contract call is not supported
+	_, err = chain.relay.relayTx(reqBytes, context)
+	assert.Equal(t, err, errContractCallNotAllowed)
+
+	var calledTo common.Address
+	var calledInput []byte
+	context.CallContract = func(caller common.Address, to common.Address, input []byte) ([]byte, error) {
+		assert.Equal(t, caller, BTCRelayContractAddress)
+		calledTo, calledInput = to, input
+		return []byte{2}, nil
+	}
+
+	// the relay address has no code
+	_, err = chain.relay.relayTx(reqBytes, context)
+	assert.Equal(t, err, errRelayContractNotFound)
+
+	context.statedb.SetCode(target, []byte{1})
+	result, err := chain.relay.relayTx(reqBytes, context)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result, []byte{2})
+	assert.Equal(t, calledTo, target)
+
+	expected, err := btcRelayMethods.Pack("processTransaction", txs[0], btcDoubleHash(txs[0]).Big())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, calledInput, expected)
+
+	// relay address is empty
+	_, err = chain.relay.relayTx(newTestRelayRequest(t, txs[0], block.Hash, 0, siblings), context)
+	assert.Equal(t, err, errRelayContractNotFound)
+
+	// tx not in the block
+	req.TxHex = "0x1234"
+	reqBytes, _ = json.Marshal(req)
+	_, err = chain.relay.relayTx(reqBytes, context)
+	assert.Equal(t, err, errBTCInvalidMerkleProof)
+
+	// invalid request
+	_, err = chain.relay.relayTx([]byte{0, 1, 2, 3}, context)
+	assert.Error(t, err)
+}
+
+func Test_BTCRelay_Fork(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, BTCRelayContractAddress)
+	c := GetContractByAddress(BTCRelayContractAddress)
+	block1 := hexutil.MustHexToBytes("0x010000006fe28c0ab6f1b372c1a6a246ae63f74f931e8365e15a089c68d6190000000000982051fd1e4ba744bbbe680e1fee14677ba1a3c3540bf7b1cdb606e857233e0e61bc6649ffff001d01e36299")
+
+	// the relay is disabled if the checkpoint is not configured
+	_, err := c.Run(append([]byte{CmdStoreBlockHeader}, block1...), context)
+	assert.Equal(t, err, errBTCRelayNotConfigured)
+	_, err = c.Run([]byte{CmdGetBestBlockHeader}, context)
+	assert.Equal(t, err, errBTCRelayNotConfigured)
+
+	// the checkpoint must be at a difficulty adjustment height
+	assert.Equal(t, InitBTCRelay(context.statedb, &BTCRelayCheckpoint{Header: testBTCGenesis, Height: 1}), errInvalidBTCCheckpoint)
+	assert.Equal(t, InitBTCRelay(context.statedb, &BTCRelayCheckpoint{Header: "0x1234"}), errInvalidBTCHeader)
+
+	// the blocks are stored in the json map before the fork
+	context.BlockHeader.Height = common.SystemContractForkHeight - 1
+	request, err := json.Marshal(&legacyRelayRequest{legacyBTCBlock: legacyBTCBlock{BlockHeaderHex: "0x01", Height: 1}})
+	assert.Equal(t, err, nil)
+
+	result, err := c.Run(append([]byte{CmdStoreBlockHeader}, request...), context)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result, success)
+
+	blocks := getLegacyBTCBlocks(context.statedb)
+	assert.Equal(t, blocks["0x01"].Relayer, context.tx.Data.From)
+	assert.Equal(t, len(context.statedb.GetData(BTCRelayContractAddress, keyBestBlockHash)), 0)
+
+	_, err = c.Run([]byte{CmdGetBestBlockHeader}, context)
+	assert.Equal(t, err, errInvalidCommand)
+	assert.Equal(t, c.RequiredGas([]byte{CmdStoreBlockHeader}, context.BlockHeader.Height), uint64(10000))
 }
@@ -0,0 +1,257 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package system
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	bp "github.com/seeleteam/go-seele/bulletinproof"
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/crypto"
+)
+
+const (
+	// gas used to deposit seele into the confidential balance
+	gasConfidentialDeposit = uint64(50000)
+	// gas used to verify a range proof, which costs hundreds of scalar multiplications
+	gasRangeProofVerify = uint64(500000)
+	// gas used to transfer, which verifies the range proofs of the amount and the remaining balance
+	gasConfidentialTransfer = 2*gasRangeProofVerify + uint64(50000)
+	// gas used to withdraw, which verifies the range proof of the remaining balance
+	gasConfidentialWithdraw = gasRangeProofVerify + uint64(50000)
+	// gas used to get the balance commitment
+	gasGetConfidentialBalance = uint64(5000)
+)
+
+const (
+	// CmdConfidentialDeposit deposit seele into the confidential balance
+	CmdConfidentialDeposit byte = iota
+	// CmdConfidentialTransfer transfer a hidden amount to another confidential balance
+	CmdConfidentialTransfer
+	// CmdConfidentialWithdraw withdraw seele from the confidential balance
+	CmdConfidentialWithdraw
+	// CmdGetConfidentialBalance get the balance commitment of an account
+	CmdGetConfidentialBalance
+)
+
+var (
+	confidentialCommands = map[byte]*cmdInfo{
+		CmdConfidentialDeposit:    &cmdInfo{gasConfidentialDeposit, confidentialDeposit},
+		CmdConfidentialTransfer:   &cmdInfo{gasConfidentialTransfer, confidentialTransfer},
+		CmdConfidentialWithdraw:   &cmdInfo{gasConfidentialWithdraw, confidentialWithdraw},
+		CmdGetConfidentialBalance: &cmdInfo{gasGetConfidentialBalance, getConfidentialBalance},
+	}
+
+	// maxConfidentialAmount is the exclusive upper bound of the amounts proved by the range proofs
+	maxConfidentialAmount = new(big.Int).Lsh(big.NewInt(1), uint(bp.EC.V))
+)
+
+var (
+	errInvalidDepositAmount = errors.New("Failed to deposit, amount is out of range")
+	errInvalidBlinding      = errors.New("Failed to deposit, blinding factor is out of range")
+	errNonZeroAmount        = errors.New("Failed to transfer, amount of the tx should be 0")
+	errInvalidWithdrawal    = errors.New("Failed to withdraw, amount is out of range")
+	errSelfTransfer         = errors.New("Failed to transfer, receiver is the sender")
+	errMalformedProof       = errors.New("Failed to verify, malformed range proof")
+	errBalanceMismatch      = errors.New("Failed to verify, commitments do not match the balance")
+	errInvalidRangeProof    = errors.New("Failed to verify, invalid range proof")
+	errInvalidAddress       = errors.New("invalid address")
+)
+
+// ConfidentialDeposit is the input to deposit the tx amount. The amount is public,
+// and the blinding factor hides the balance once any hidden amount is transferred.
+type ConfidentialDeposit struct {
+	Blinding *big.Int
+}
+
+// ConfidentialTransfer is the input to transfer a hidden amount
+type ConfidentialTransfer struct {
+	To common.Address
+	// Amount proves the commitment of the transferred amount is in range
+	Amount bp.RangeProof
+	// Remaining proves the commitment of the sender balance minus the amount is in range
+	Remaining bp.RangeProof
+}
+
+// ConfidentialWithdrawal is the input to withdraw a public amount
+type ConfidentialWithdrawal struct {
+	Amount *big.Int
+	// Remaining proves the commitment of the sender balance minus the amount is in range
+	Remaining bp.RangeProof
+}
+
+// confidentialDeposit adds the commitment of the tx amount to the sender balance,
+// the amount has been transferred to the contract address before.
+func confidentialDeposit(input []byte, context *Context) ([]byte, error) {
+	var deposit ConfidentialDeposit
+	if err := json.Unmarshal(input, &deposit); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal input, %s", err)
+	}
+
+	amount := context.tx.Data.Amount
+	if amount.Sign() <= 0 || amount.Cmp(maxConfidentialAmount) >= 0 {
+		return nil, errInvalidDepositAmount
+	}
+
+	if !bp.ValidScalars(deposit.Blinding) {
+		return nil, errInvalidBlinding
+	}
+
+	from := context.tx.Data.From
+	balance, err := confidentialBalance(context, from)
+	if err != nil {
+		return nil, err
+	}
+
+	comm := bp.EC.G.Mult(amount).Add(bp.EC.H.Mult(deposit.Blinding))
+	return setConfidentialBalance(context, from, balance.Add(comm))
+}
+
+// confidentialTransfer moves the amount commitment from the sender balance to the receiver balance
+func confidentialTransfer(input []byte, context *Context) ([]byte, error) {
+	var transfer ConfidentialTransfer
+	if err := json.Unmarshal(input, &transfer); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal input, %s", err)
+	}
+
+	if context.tx.Data.Amount.Sign() != 0 {
+		return nil, errNonZeroAmount
+	}
+
+	from := context.tx.Data.From
+	if transfer.To.Equal(from) {
+		return nil, errSelfTransfer
+	}
+
+	if !transfer.Amount.WellFormed() || !transfer.Remaining.WellFormed() {
+		return nil, errMalformedProof
+	}
+
+	balance, err := confidentialBalance(context, from)
+	if err != nil {
+		return nil, err
+	}
+
+	// both the amount and the remaining balance are non-negative
+	if !balance.Equal(transfer.Amount.Comm.Add(transfer.Remaining.Comm)) {
+		return nil, errBalanceMismatch
+	}
+
+	if bp.RPVerify(transfer.Amount) != nil || bp.RPVerify(transfer.Remaining) != nil {
+		return nil, errInvalidRangeProof
+	}
+
+	toBalance, err := confidentialBalance(context, transfer.To)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = setConfidentialBalance(context, transfer.To, toBalance.Add(transfer.Amount.Comm)); err != nil {
+		return nil, err
+	}
+
+	return setConfidentialBalance(context, from, transfer.Remaining.Comm)
+}
+
+// confidentialWithdraw subtracts the commitment of the public amount from the sender
+// balance, and transfers the amount from the contract address to the sender.
+func confidentialWithdraw(input []byte, context *Context) ([]byte, error) {
+	var withdrawal ConfidentialWithdrawal
+	if err := json.Unmarshal(input, &withdrawal); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal input, %s", err)
+	}
+
+	if context.tx.Data.Amount.Sign() != 0 {
+		return nil, errNonZeroAmount
+	}
+
+	amount := withdrawal.Amount
+	if amount == nil || amount.Sign() <= 0 || amount.Cmp(maxConfidentialAmount) >= 0 {
+		return nil, errInvalidWithdrawal
+	}
+
+	if !withdrawal.Remaining.WellFormed() {
+		return nil, errMalformedProof
+	}
+
+	from := context.tx.Data.From
+	balance, err := confidentialBalance(context, from)
+	if err != nil {
+		return nil, err
+	}
+
+	if !balance.Equal(bp.EC.G.Mult(amount).Add(withdrawal.Remaining.Comm)) {
+		return nil, errBalanceMismatch
+	}
+
+	if bp.RPVerify(withdrawal.Remaining) != nil {
+		return nil, errInvalidRangeProof
+	}
+
+	// the public balance of the contract is the total of all the confidential balances
+	if context.statedb.GetBalance(ConfidentialTransferContractAddress).Cmp(amount) < 0 {
+		return nil, errInvalidWithdrawal
+	}
+
+	value, err := setConfidentialBalance(context, from, withdrawal.Remaining.Comm)
+	if err != nil {
+		return nil, err
+	}
+
+	context.statedb.SubBalance(ConfidentialTransferContractAddress, amount)
+	context.statedb.AddBalance(from, amount)
+
+	return value, nil
+}
+
+// getConfidentialBalance returns the balance commitment of the address in json
+func getConfidentialBalance(input []byte, context *Context) ([]byte, error) {
+	addr, err := common.NewAddress(input)
+	if err != nil {
+		return nil, errInvalidAddress
+	}
+
+	balance, err := confidentialBalance(context, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(balance)
+}
+
+// confidentialBalance returns the balance commitment of the address, which is the identity if not found
+func confidentialBalance(context *Context, addr common.Address) (bp.ECPoint, error) {
+	value := context.statedb.GetData(ConfidentialTransferContractAddress, confidentialBalanceKey(addr))
+	if len(value) == 0 {
+		return bp.EC.Zero(), nil
+	}
+
+	var balance bp.ECPoint
+	if err := json.Unmarshal(value, &balance); err != nil {
+		return balance, fmt.Errorf("Failed to unmarshal balance, %s", err)
+	}
+
+	return balance, nil
+}
+
+func setConfidentialBalance(context *Context, addr common.Address, balance bp.ECPoint) ([]byte, error) {
+	value, err := json.Marshal(balance)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal balance, %s", err)
+	}
+
+	context.statedb.CreateAccount(ConfidentialTransferContractAddress)
+	context.statedb.SetData(ConfidentialTransferContractAddress, confidentialBalanceKey(addr), value)
+
+	return value, nil
+}
+
+func confidentialBalanceKey(addr common.Address) common.Hash {
+	return crypto.HashBytes(addr.Bytes())
+}
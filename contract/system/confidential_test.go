@@ -0,0 +1,187 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package system
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	bp "github.com/seeleteam/go-seele/bulletinproof"
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/seeleteam/go-seele/database/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+// newConfidentialTestContext creates a context in which the sender has deposited
+// the amount with the blinding factor.
+func newConfidentialTestContext(t *testing.T, amount, blinding int64) *Context {
+	db, dispose := leveldb.NewTestDatabase()
+	t.Cleanup(dispose)
+
+	context := newTestContext(db, ConfidentialTransferContractAddress)
+	context.tx.Data.Amount = big.NewInt(amount)
+	context.statedb.CreateAccount(context.tx.Data.From)
+
+	// the amount is transferred to the contract before the contract runs
+	context.statedb.AddBalance(ConfidentialTransferContractAddress, big.NewInt(amount))
+
+	input, err := json.Marshal(ConfidentialDeposit{big.NewInt(blinding)})
+	assert.Equal(t, err, nil)
+
+	_, err = confidentialDeposit(input, context)
+	assert.Equal(t, err, nil)
+
+	context.tx.Data.Amount = big.NewInt(0)
+	return context
+}
+
+func commitment(amount, blinding int64) bp.ECPoint {
+	return bp.EC.G.Mult(big.NewInt(amount)).Add(bp.EC.H.Mult(big.NewInt(blinding)))
+}
+
+func Test_ConfidentialDeposit(t *testing.T) {
+	context := newConfidentialTestContext(t, 100, 7)
+	from := context.tx.Data.From
+
+	balance, err := confidentialBalance(context, from)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, balance.Equal(commitment(100, 7)), true)
+
+	// deposit again
+	context.tx.Data.Amount = big.NewInt(50)
+	input, _ := json.Marshal(ConfidentialDeposit{big.NewInt(3)})
+	_, err = confidentialDeposit(input, context)
+	assert.Equal(t, err, nil)
+
+	balance, err = confidentialBalance(context, from)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, balance.Equal(commitment(150, 10)), true)
+
+	// get balance
+	value, err := getConfidentialBalance(from.Bytes(), context)
+	assert.Equal(t, err, nil)
+
+	var result bp.ECPoint
+	assert.Equal(t, json.Unmarshal(value, &result), nil)
+	assert.Equal(t, result.Equal(commitment(150, 10)), true)
+
+	// invalid amount
+	context.tx.Data.Amount = big.NewInt(0)
+	_, err = confidentialDeposit(input, context)
+	assert.Equal(t, err, errInvalidDepositAmount)
+
+	context.tx.Data.Amount = new(big.Int).Set(maxConfidentialAmount)
+	_, err = confidentialDeposit(input, context)
+	assert.Equal(t, err, errInvalidDepositAmount)
+
+	// invalid blinding
+	context.tx.Data.Amount = big.NewInt(1)
+	input, _ = json.Marshal(ConfidentialDeposit{bp.EC.N})
+	_, err = confidentialDeposit(input, context)
+	assert.Equal(t, err, errInvalidBlinding)
+}
+
+func Test_ConfidentialTransfer(t *testing.T) {
+	context := newConfidentialTestContext(t, 100, 7)
+	from := context.tx.Data.From
+	to := *crypto.MustGenerateShardAddress(1)
+
+	transfer := ConfidentialTransfer{
+		To:        to,
+		Amount:    bp.RPProveWithBlinding(big.NewInt(30), big.NewInt(3)),
+		Remaining: bp.RPProveWithBlinding(big.NewInt(70), big.NewInt(4)),
+	}
+	input, err := json.Marshal(transfer)
+	assert.Equal(t, err, nil)
+
+	_, err = confidentialTransfer(input, context)
+	assert.Equal(t, err, nil)
+
+	balance, _ := confidentialBalance(context, from)
+	assert.Equal(t, balance.Equal(commitment(70, 4)), true)
+
+	balance, _ = confidentialBalance(context, to)
+	assert.Equal(t, balance.Equal(commitment(30, 3)), true)
+
+	// replay fails since the balance changed
+	_, err = confidentialTransfer(input, context)
+	assert.Equal(t, err, errBalanceMismatch)
+}
+
+func Test_ConfidentialTransfer_Invalid(t *testing.T) {
+	context := newConfidentialTestContext(t, 100, 7)
+	to := *crypto.MustGenerateShardAddress(1)
+
+	// commitments do not sum up to the balance
+	transfer := ConfidentialTransfer{
+		To:        to,
+		Amount:    bp.RPProveWithBlinding(big.NewInt(40), big.NewInt(3)),
+		Remaining: bp.RPProveWithBlinding(big.NewInt(70), big.NewInt(4)),
+	}
+	input, _ := json.Marshal(transfer)
+	_, err := confidentialTransfer(input, context)
+	assert.Equal(t, err, errBalanceMismatch)
+
+	// valid commitments with an invalid proof
+	transfer.Amount = bp.RPProveWithBlinding(big.NewInt(30), big.NewInt(3))
+	transfer.Amount.IPP = bp.RPProve(big.NewInt(30)).IPP
+	input, _ = json.Marshal(transfer)
+	_, err = confidentialTransfer(input, context)
+	assert.Equal(t, err, errInvalidRangeProof)
+
+	// malformed proof
+	transfer.Amount.IPP.L = nil
+	input, _ = json.Marshal(transfer)
+	_, err = confidentialTransfer(input, context)
+	assert.Equal(t, err, errMalformedProof)
+
+	// self transfer
+	transfer.To = context.tx.Data.From
+	input, _ = json.Marshal(transfer)
+	_, err = confidentialTransfer(input, context)
+	assert.Equal(t, err, errSelfTransfer)
+
+	// non-zero amount
+	context.tx.Data.Amount = big.NewInt(1)
+	_, err = confidentialTransfer(input, context)
+	assert.Equal(t, err, errNonZeroAmount)
+}
+
+func Test_ConfidentialWithdraw(t *testing.T) {
+	context := newConfidentialTestContext(t, 100, 7)
+	from := context.tx.Data.From
+
+	withdrawal := ConfidentialWithdrawal{
+		Amount:    big.NewInt(40),
+		Remaining: bp.RPProveWithBlinding(big.NewInt(60), big.NewInt(7)),
+	}
+	input, err := json.Marshal(withdrawal)
+	assert.Equal(t, err, nil)
+
+	_, err = confidentialWithdraw(input, context)
+	assert.Equal(t, err, nil)
+
+	balance, _ := confidentialBalance(context, from)
+	assert.Equal(t, balance.Equal(commitment(60, 7)), true)
+	assert.Equal(t, context.statedb.GetBalance(from).Cmp(big.NewInt(40)), 0)
+	assert.Equal(t, context.statedb.GetBalance(ConfidentialTransferContractAddress).Cmp(big.NewInt(60)), 0)
+
+	// overdraw, the remaining balance does not match
+	withdrawal = ConfidentialWithdrawal{
+		Amount:    big.NewInt(70),
+		Remaining: bp.RPProveWithBlinding(big.NewInt(0), big.NewInt(7)),
+	}
+	input, _ = json.Marshal(withdrawal)
+	_, err = confidentialWithdraw(input, context)
+	assert.Equal(t, err, errBalanceMismatch)
+
+	// invalid amount
+	withdrawal.Amount = big.NewInt(0)
+	input, _ = json.Marshal(withdrawal)
+	_, err = confidentialWithdraw(input, context)
+	assert.Equal(t, err, errInvalidWithdrawal)
+}
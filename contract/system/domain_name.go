@@ -6,11 +6,16 @@
 package system
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/crypto"
 )
 
 const (
@@ -18,6 +23,20 @@ const (
 	CmdCreateDomainName byte = iota
 	// CmdGetDomainNameOwner query the registrar of specified domain name
 	CmdGetDomainNameOwner
+	// CmdTransferDomainName transfer the domain name to a new owner
+	CmdTransferDomainName
+	// CmdRenewDomainName extend the expiry of the domain name
+	CmdRenewDomainName
+	// CmdReleaseDomainName release the domain name, so that anyone could register it
+	CmdReleaseDomainName
+	// CmdSetDomainNameResolver set the resolver records of the domain name
+	CmdSetDomainNameResolver
+	// CmdGetDomainNameRecord query the record of the domain name
+	CmdGetDomainNameRecord
+	// CmdSetReverseDomainName set the domain name that the sender address resolves to
+	CmdSetReverseDomainName
+	// CmdGetReverseDomainName query the domain name of the address
+	CmdGetReverseDomainName
 )
 
 const (
@@ -25,21 +44,106 @@ const (
 	gasCreateDomainName = uint64(50000)
 	// gas used to get the owner of given domain
 	gasGetDomainNameOwner = uint64(100000)
+	// gas used to transfer a domain name
+	gasTransferDomainName = uint64(50000)
+	// gas used to renew a domain name
+	gasRenewDomainName = uint64(50000)
+	// gas used to release a domain name
+	gasReleaseDomainName = uint64(20000)
+	// gas used to set the resolver records of a domain name
+	gasSetDomainNameResolver = uint64(50000)
+	// gas used to get the record of a domain name
+	gasGetDomainNameRecord = uint64(100000)
+	// gas used to set the reverse domain name of an address
+	gasSetReverseDomainName = uint64(50000)
+	// gas used to get the reverse domain name of an address
+	gasGetReverseDomainName = uint64(100000)
+)
+
+const (
+	// DomainNameSuffix is the suffix to use a domain name in place of an address, e.g. seele-fan.seele
+	DomainNameSuffix = ".seele"
+
+	// DomainNameTerm is the registration or renewal term of a domain name in seconds, 1 year
+	DomainNameTerm = int64(365 * 24 * 3600)
+
+	// maxDomainTextLength is the max length of the text record
+	maxDomainTextLength = 256
 )
 
 var (
-	errNameEmpty   = errors.New("name is empty")
-	errNameTooLong = errors.New("name too long")
-	errInvalidName = errors.New("invalid name, only numbers, letters, and dash lines are allowed")
+	errNameEmpty       = errors.New("name is empty")
+	errNameTooLong     = errors.New("name too long")
+	errInvalidName     = errors.New("invalid name, only numbers, letters, and dash lines are allowed")
+	errNotOwner        = errors.New("only the owner is allowed")
+	errNameExpired     = errors.New("domain name expired")
+	errNameNotExpiring = errors.New("domain name registered without expiry could not be renewed")
+	errInvalidNewOwner = errors.New("invalid new owner")
+	errTextTooLong     = errors.New("text record too long")
+	errAddressMismatch = errors.New("the address record of the domain name is not the sender")
 
 	maxDomainNameLength = len(common.EmptyHash)
 
+	// key prefixes of the domain name records and the reverse records in the contract storage
+	domainRecordPrefix  = []byte("record")
+	domainReversePrefix = []byte("reverse")
+
 	domainNameCommands = map[byte]*cmdInfo{
+		CmdCreateDomainName:      &cmdInfo{gasCreateDomainName, createDomainName},
+		CmdGetDomainNameOwner:    &cmdInfo{gasGetDomainNameOwner, getDomainNameOwner},
+		CmdTransferDomainName:    &cmdInfo{gasTransferDomainName, transferDomainName},
+		CmdRenewDomainName:       &cmdInfo{gasRenewDomainName, renewDomainName},
+		CmdReleaseDomainName:     &cmdInfo{gasReleaseDomainName, releaseDomainName},
+		CmdSetDomainNameResolver: &cmdInfo{gasSetDomainNameResolver, setDomainNameResolver},
+		CmdGetDomainNameRecord:   &cmdInfo{gasGetDomainNameRecord, getDomainNameRecord},
+		CmdSetReverseDomainName:  &cmdInfo{gasSetReverseDomainName, setReverseDomainName},
+		CmdGetReverseDomainName:  &cmdInfo{gasGetReverseDomainName, getReverseDomainName},
+	}
+
+	// the commands before the fork, the other commands are introduced by the fork
+	legacyDomainNameCommands = map[byte]*cmdInfo{
 		CmdCreateDomainName:   &cmdInfo{gasCreateDomainName, createDomainName},
 		CmdGetDomainNameOwner: &cmdInfo{gasGetDomainNameOwner, getDomainNameOwner},
 	}
 )
 
+// DomainNameResolver is the resolver records of a domain name
+type DomainNameResolver struct {
+	// Address is the address the domain name resolves to
+	Address common.Address
+	// Text is an arbitrary text, e.g. a url or an email
+	Text string
+	// ABIHash is the hash of the contract ABI at the address
+	ABIHash common.Hash
+}
+
+// DomainNameRecord is the registration and resolver records of a domain name
+type DomainNameRecord struct {
+	Name  string
+	Owner common.Address
+	// Expiry is the unix timestamp in seconds when the name expires, 0 means never expires,
+	// which is the case of the names registered before the expiry is introduced.
+	Expiry int64
+	DomainNameResolver
+}
+
+// Expired returns true if the name expired at the time
+func (record *DomainNameRecord) Expired(now int64) bool {
+	return record.Expiry > 0 && record.Expiry <= now
+}
+
+// DomainNameTransfer is the input to transfer a domain name
+type DomainNameTransfer struct {
+	Name string
+	To   common.Address
+}
+
+// DomainNameResolverUpdate is the input to set the resolver records of a domain name
+type DomainNameResolverUpdate struct {
+	Name string
+	DomainNameResolver
+}
+
 // createDomainName create a domain name
 func createDomainName(domainName []byte, context *Context) ([]byte, error) {
 	key, err := domainNameToKey(domainName)
@@ -50,31 +154,312 @@ func createDomainName(domainName []byte, context *Context) ([]byte, error) {
 	// create account in statedb for the first time.
 	context.statedb.CreateAccount(DomainNameContractAddress)
 
-	// ensure not exist
-	if value := context.statedb.GetData(DomainNameContractAddress, key); len(value) > 0 {
+	// only the owner is saved before the fork
+	if !context.forked() {
+		if value := context.statedb.GetData(DomainNameContractAddress, key); len(value) > 0 {
+			return nil, errExists
+		}
+
+		value := context.tx.Data.From.Bytes()
+		context.statedb.SetData(DomainNameContractAddress, key, value)
+
+		return value, nil
+	}
+
+	// ensure not exist, an expired name could be registered again
+	now := context.BlockHeader.CreateTimestamp.Int64()
+	if record, err := getRecord(context.statedb, key); err != nil {
+		return nil, err
+	} else if record != nil && !record.Expired(now) {
 		return nil, errExists
 	}
 
 	// save in statedb
-	value := context.tx.Data.From.Bytes()
-	context.statedb.SetData(DomainNameContractAddress, key, value)
+	from := context.tx.Data.From
+	record := &DomainNameRecord{
+		Name:               string(domainName),
+		Owner:              from,
+		Expiry:             now + DomainNameTerm,
+		DomainNameResolver: DomainNameResolver{Address: from},
+	}
+
+	if err = setRecord(context.statedb, key, record); err != nil {
+		return nil, err
+	}
 
-	return value, nil
+	return from.Bytes(), nil
 }
 
 // getDomainNameOwner get domain name owner
 func getDomainNameOwner(domainName []byte, context *Context) ([]byte, error) {
+	record, _, err := activeRecord(context, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	return record.Owner.Bytes(), nil
+}
+
+// transferDomainName transfer the domain name to the new owner, and the resolver records are kept
+func transferDomainName(input []byte, context *Context) ([]byte, error) {
+	var transfer DomainNameTransfer
+	if err := json.Unmarshal(input, &transfer); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal input, %s", err)
+	}
+
+	if transfer.To.IsEmpty() {
+		return nil, errInvalidNewOwner
+	}
+
+	record, key, err := ownedRecord(context, []byte(transfer.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	record.Owner = transfer.To
+	return marshalRecord(context.statedb, key, record)
+}
+
+// renewDomainName extend the expiry of the domain name by a term, the owner
+// could renew the expired name if no one else has registered it.
+func renewDomainName(domainName []byte, context *Context) ([]byte, error) {
 	key, err := domainNameToKey(domainName)
 	if err != nil {
 		return nil, err
 	}
 
-	owner := context.statedb.GetData(DomainNameContractAddress, key)
-	if len(owner) == 0 {
+	record, err := getRecord(context.statedb, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if record == nil {
 		return nil, errNotFound
 	}
 
-	return owner, nil
+	if !record.Owner.Equal(context.tx.Data.From) {
+		return nil, errNotOwner
+	}
+
+	if record.Expiry == 0 {
+		return nil, errNameNotExpiring
+	}
+
+	now := context.BlockHeader.CreateTimestamp.Int64()
+	if record.Expired(now) {
+		record.Expiry = now
+	}
+	record.Expiry += DomainNameTerm
+
+	return marshalRecord(context.statedb, key, record)
+}
+
+// releaseDomainName remove the domain name records, so that anyone could register it
+func releaseDomainName(domainName []byte, context *Context) ([]byte, error) {
+	_, key, err := ownedRecord(context, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	context.statedb.SetData(DomainNameContractAddress, key, nil)
+	context.statedb.SetData(DomainNameContractAddress, recordKey(key), nil)
+
+	return domainName, nil
+}
+
+// setDomainNameResolver set the resolver records of the domain name
+func setDomainNameResolver(input []byte, context *Context) ([]byte, error) {
+	var update DomainNameResolverUpdate
+	if err := json.Unmarshal(input, &update); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal input, %s", err)
+	}
+
+	if len(update.Text) > maxDomainTextLength {
+		return nil, errTextTooLong
+	}
+
+	record, key, err := ownedRecord(context, []byte(update.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	record.DomainNameResolver = update.DomainNameResolver
+	return marshalRecord(context.statedb, key, record)
+}
+
+// getDomainNameRecord get the record of the domain name in json
+func getDomainNameRecord(domainName []byte, context *Context) ([]byte, error) {
+	record, _, err := activeRecord(context, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(record)
+}
+
+// setReverseDomainName set the domain name of the sender address, which should be the address
+// record of the name. The reverse record is removed if the domain name is empty.
+func setReverseDomainName(domainName []byte, context *Context) ([]byte, error) {
+	from := context.tx.Data.From
+	if len(domainName) == 0 {
+		context.statedb.CreateAccount(DomainNameContractAddress)
+		context.statedb.SetData(DomainNameContractAddress, reverseKey(from), nil)
+		return nil, nil
+	}
+
+	record, _, err := activeRecord(context, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !record.Address.Equal(from) {
+		return nil, errAddressMismatch
+	}
+
+	context.statedb.SetData(DomainNameContractAddress, reverseKey(from), []byte(record.Name))
+	return []byte(record.Name), nil
+}
+
+// getReverseDomainName get the domain name of the address
+func getReverseDomainName(input []byte, context *Context) ([]byte, error) {
+	addr, err := common.NewAddress(input)
+	if err != nil {
+		return nil, errInvalidAddress
+	}
+
+	name, err := LookupDomainName(context.statedb, addr, context.BlockHeader.CreateTimestamp.Int64())
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(name), nil
+}
+
+// ResolveDomainName returns the record of the domain name which is not expired at the time
+func ResolveDomainName(statedb *state.Statedb, domainName string, now int64) (*DomainNameRecord, error) {
+	key, err := domainNameToKey([]byte(domainName))
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := getRecord(statedb, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if record == nil {
+		return nil, errNotFound
+	}
+
+	if record.Expired(now) {
+		return nil, errNameExpired
+	}
+
+	return record, nil
+}
+
+// LookupDomainName returns the domain name of the address set by the address itself.
+// The reverse record is only valid if the name still resolves to the address at the time.
+func LookupDomainName(statedb *state.Statedb, addr common.Address, now int64) (string, error) {
+	name := statedb.GetData(DomainNameContractAddress, reverseKey(addr))
+	if len(name) == 0 {
+		return "", errNotFound
+	}
+
+	record, err := ResolveDomainName(statedb, string(name), now)
+	if err != nil {
+		return "", err
+	}
+
+	if !record.Address.Equal(addr) {
+		return "", errNotFound
+	}
+
+	return record.Name, nil
+}
+
+// activeRecord returns the record of the domain name which is not expired at the block time
+func activeRecord(context *Context, domainName []byte) (*DomainNameRecord, common.Hash, error) {
+	key, err := domainNameToKey(domainName)
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+
+	record, err := ResolveDomainName(context.statedb, string(domainName), context.BlockHeader.CreateTimestamp.Int64())
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+
+	return record, key, nil
+}
+
+// ownedRecord returns the active record of the domain name owned by the sender
+func ownedRecord(context *Context, domainName []byte) (*DomainNameRecord, common.Hash, error) {
+	record, key, err := activeRecord(context, domainName)
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+
+	if !record.Owner.Equal(context.tx.Data.From) {
+		return nil, common.EmptyHash, errNotOwner
+	}
+
+	return record, key, nil
+}
+
+// getRecord returns the record of the domain name key, or nil if not found.
+// The names registered before the records are introduced only have the owner,
+// which never expire and resolve to the owner.
+func getRecord(statedb *state.Statedb, key common.Hash) (*DomainNameRecord, error) {
+	owner := statedb.GetData(DomainNameContractAddress, key)
+	if len(owner) == 0 {
+		return nil, nil
+	}
+
+	value := statedb.GetData(DomainNameContractAddress, recordKey(key))
+	if len(value) == 0 {
+		return &DomainNameRecord{
+			Name:               string(bytes.TrimLeft(key.Bytes(), "\x00")),
+			Owner:              common.BytesToAddress(owner),
+			DomainNameResolver: DomainNameResolver{Address: common.BytesToAddress(owner)},
+		}, nil
+	}
+
+	record := new(DomainNameRecord)
+	if err := json.Unmarshal(value, record); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal domain name record, %s", err)
+	}
+
+	return record, nil
+}
+
+// setRecord saves the record, and the owner is also saved with the domain name key as before
+func setRecord(statedb *state.Statedb, key common.Hash, record *DomainNameRecord) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal domain name record, %s", err)
+	}
+
+	statedb.SetData(DomainNameContractAddress, key, record.Owner.Bytes())
+	statedb.SetData(DomainNameContractAddress, recordKey(key), value)
+
+	return nil
+}
+
+func marshalRecord(statedb *state.Statedb, key common.Hash, record *DomainNameRecord) ([]byte, error) {
+	if err := setRecord(statedb, key, record); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(record)
+}
+
+func recordKey(key common.Hash) common.Hash {
+	return crypto.HashBytes(domainRecordPrefix, key.Bytes())
+}
+
+func reverseKey(addr common.Address) common.Hash {
+	return crypto.HashBytes(domainReversePrefix, addr.Bytes())
 }
 
 // ValidateDomainName validate domain name
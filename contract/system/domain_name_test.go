@@ -6,9 +6,12 @@
 package system
 
 import (
+	"encoding/json"
+	"math/big"
 	"testing"
 
 	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/crypto"
 	"github.com/seeleteam/go-seele/database/leveldb"
 	"github.com/stretchr/testify/assert"
 )
@@ -75,3 +78,264 @@ func Test_CreateDomainName(t *testing.T) {
 	assert.Equal(t, result, []byte(nil))
 	assert.Equal(t, err, errNotFound)
 }
+
+func Test_DomainNameExpiry(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, DomainNameContractAddress)
+	owner := context.tx.Data.From
+	now := context.BlockHeader.CreateTimestamp.Int64()
+
+	name := []byte("seele-fan")
+	_, err := createDomainName(name, context)
+	assert.Equal(t, err, nil)
+
+	record, err := ResolveDomainName(context.statedb, string(name), now)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, record.Owner, owner)
+	assert.Equal(t, record.Address, owner)
+	assert.Equal(t, record.Expiry, now+DomainNameTerm)
+
+	// registered already
+	_, err = createDomainName(name, context)
+	assert.Equal(t, err, errExists)
+
+	// renew by the owner
+	_, err = renewDomainName(name, context)
+	assert.Equal(t, err, nil)
+	record, _ = ResolveDomainName(context.statedb, string(name), now)
+	assert.Equal(t, record.Expiry, now+2*DomainNameTerm)
+
+	// renew by others
+	context.tx.Data.From = *crypto.MustGenerateShardAddress(1)
+	_, err = renewDomainName(name, context)
+	assert.Equal(t, err, errNotOwner)
+
+	// expired name could be registered by others
+	expiredTime := now + 2*DomainNameTerm
+	_, err = ResolveDomainName(context.statedb, string(name), expiredTime)
+	assert.Equal(t, err, errNameExpired)
+
+	context.BlockHeader.CreateTimestamp = big.NewInt(expiredTime)
+	_, err = getDomainNameOwner(name, context)
+	assert.Equal(t, err, errNameExpired)
+
+	_, err = createDomainName(name, context)
+	assert.Equal(t, err, nil)
+
+	record, _ = ResolveDomainName(context.statedb, string(name), expiredTime)
+	assert.Equal(t, record.Owner, context.tx.Data.From)
+	assert.Equal(t, record.Expiry, expiredTime+DomainNameTerm)
+}
+
+func Test_DomainNameLegacyRecord(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, DomainNameContractAddress)
+	owner := context.tx.Data.From
+
+	// the names registered before only have the owner
+	name := []byte("legacy")
+	key, _ := domainNameToKey(name)
+	context.statedb.SetData(DomainNameContractAddress, key, owner.Bytes())
+
+	record, err := ResolveDomainName(context.statedb, string(name), context.BlockHeader.CreateTimestamp.Int64())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, record.Name, string(name))
+	assert.Equal(t, record.Owner, owner)
+	assert.Equal(t, record.Address, owner)
+	assert.Equal(t, record.Expiry, int64(0))
+
+	_, err = renewDomainName(name, context)
+	assert.Equal(t, err, errNameNotExpiring)
+
+	// never expires
+	assert.Equal(t, record.Expired(context.BlockHeader.CreateTimestamp.Int64()+10*DomainNameTerm), false)
+}
+
+func Test_DomainNameBeforeFork(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, DomainNameContractAddress)
+	context.BlockHeader.Height = common.SystemContractForkHeight - 1
+	owner := context.tx.Data.From
+
+	// only the owner is saved before the fork
+	name := []byte("beforefork")
+	key, _ := domainNameToKey(name)
+	result, err := createDomainName(name, context)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result, owner.Bytes())
+	assert.Equal(t, context.statedb.GetData(DomainNameContractAddress, key), owner.Bytes())
+	assert.Equal(t, len(context.statedb.GetData(DomainNameContractAddress, recordKey(key))), 0)
+
+	_, err = createDomainName(name, context)
+	assert.Equal(t, err, errExists)
+
+	// the commands introduced by the fork are invalid
+	c := GetContractByAddress(DomainNameContractAddress)
+	_, err = c.Run(append([]byte{CmdRenewDomainName}, name...), context)
+	assert.Equal(t, err, errInvalidCommand)
+
+	context.BlockHeader.Height = common.SystemContractForkHeight
+	_, err = c.Run(append([]byte{CmdRenewDomainName}, name...), context)
+	assert.Equal(t, err, errNameNotExpiring)
+}
+
+func Test_TransferAndReleaseDomainName(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, DomainNameContractAddress)
+	owner := context.tx.Data.From
+	newOwner := *crypto.MustGenerateShardAddress(1)
+
+	name := []byte("seele-fan")
+	_, err := createDomainName(name, context)
+	assert.Equal(t, err, nil)
+
+	// transfer to empty address
+	input, _ := json.Marshal(DomainNameTransfer{string(name), common.EmptyAddress})
+	_, err = transferDomainName(input, context)
+	assert.Equal(t, err, errInvalidNewOwner)
+
+	input, _ = json.Marshal(DomainNameTransfer{string(name), newOwner})
+	_, err = transferDomainName(input, context)
+	assert.Equal(t, err, nil)
+
+	result, err := getDomainNameOwner(name, context)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result, newOwner.Bytes())
+
+	// the resolver records are kept
+	record, _ := ResolveDomainName(context.statedb, string(name), context.BlockHeader.CreateTimestamp.Int64())
+	assert.Equal(t, record.Address, owner)
+
+	// the previous owner could not transfer or release
+	_, err = transferDomainName(input, context)
+	assert.Equal(t, err, errNotOwner)
+	_, err = releaseDomainName(name, context)
+	assert.Equal(t, err, errNotOwner)
+
+	// release by the new owner
+	context.tx.Data.From = newOwner
+	_, err = releaseDomainName(name, context)
+	assert.Equal(t, err, nil)
+
+	_, err = getDomainNameOwner(name, context)
+	assert.Equal(t, err, errNotFound)
+
+	// register again
+	_, err = createDomainName(name, context)
+	assert.Equal(t, err, nil)
+}
+
+func Test_DomainNameResolver(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, DomainNameContractAddress)
+	now := context.BlockHeader.CreateTimestamp.Int64()
+
+	name := []byte("seele-fan")
+	_, err := createDomainName(name, context)
+	assert.Equal(t, err, nil)
+
+	resolver := DomainNameResolver{
+		Address: *crypto.MustGenerateShardAddress(1),
+		Text:    "https://seele.pro",
+		ABIHash: crypto.HashBytes([]byte("abi")),
+	}
+	input, _ := json.Marshal(DomainNameResolverUpdate{string(name), resolver})
+	_, err = setDomainNameResolver(input, context)
+	assert.Equal(t, err, nil)
+
+	value, err := getDomainNameRecord(name, context)
+	assert.Equal(t, err, nil)
+
+	var record DomainNameRecord
+	assert.Equal(t, json.Unmarshal(value, &record), nil)
+	assert.Equal(t, record.DomainNameResolver, resolver)
+	assert.Equal(t, record.Owner, context.tx.Data.From)
+
+	// text too long
+	update := DomainNameResolverUpdate{string(name), resolver}
+	update.Text = string(make([]byte, maxDomainTextLength+1))
+	input, _ = json.Marshal(update)
+	_, err = setDomainNameResolver(input, context)
+	assert.Equal(t, err, errTextTooLong)
+
+	// only the owner
+	context.tx.Data.From = resolver.Address
+	input, _ = json.Marshal(DomainNameResolverUpdate{string(name), resolver})
+	_, err = setDomainNameResolver(input, context)
+	assert.Equal(t, err, errNotOwner)
+
+	// not found
+	_, err = getDomainNameRecord([]byte("unknown"), context)
+	assert.Equal(t, err, errNotFound)
+
+	_, err = ResolveDomainName(context.statedb, string(name), now)
+	assert.Equal(t, err, nil)
+}
+
+func Test_ReverseDomainName(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, DomainNameContractAddress)
+	owner := context.tx.Data.From
+	now := context.BlockHeader.CreateTimestamp.Int64()
+
+	name := []byte("seele-fan")
+	_, err := createDomainName(name, context)
+	assert.Equal(t, err, nil)
+
+	// not set
+	_, err = LookupDomainName(context.statedb, owner, now)
+	assert.Equal(t, err, errNotFound)
+
+	_, err = setReverseDomainName(name, context)
+	assert.Equal(t, err, nil)
+
+	result, err := getReverseDomainName(owner.Bytes(), context)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result, name)
+
+	// others could not claim the name
+	other := *crypto.MustGenerateShardAddress(1)
+	context.tx.Data.From = other
+	_, err = setReverseDomainName(name, context)
+	assert.Equal(t, err, errAddressMismatch)
+
+	// the reverse record is invalid once the name resolves to another address
+	context.tx.Data.From = owner
+	input, _ := json.Marshal(DomainNameResolverUpdate{string(name), DomainNameResolver{Address: other}})
+	_, err = setDomainNameResolver(input, context)
+	assert.Equal(t, err, nil)
+
+	_, err = LookupDomainName(context.statedb, owner, now)
+	assert.Equal(t, err, errNotFound)
+
+	// the reverse record is invalid once the name expired
+	context.tx.Data.From = other
+	_, err = setReverseDomainName(name, context)
+	assert.Equal(t, err, nil)
+
+	name2, err := LookupDomainName(context.statedb, other, now)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, name2, string(name))
+
+	_, err = LookupDomainName(context.statedb, other, now+DomainNameTerm)
+	assert.Equal(t, err, errNameExpired)
+
+	// remove the reverse record
+	_, err = setReverseDomainName(nil, context)
+	assert.Equal(t, err, nil)
+
+	_, err = LookupDomainName(context.statedb, other, now)
+	assert.Equal(t, err, errNotFound)
+}
@@ -8,6 +8,7 @@ package system
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,7 +16,9 @@ import (
 
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/core/state"
 	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/crypto"
 )
 
 const (
@@ -23,8 +26,16 @@ const (
 	gasWithdraw    = uint64(5000)
 	gasRefund      = uint64(5000)
 	gasGetContract = uint64(5000)
+
+	gasNewHeightContract = uint64(150000)
+	gasWithdrawHeight    = uint64(10000)
+	gasRefundHeight      = uint64(10000)
+	gasGetHeightContract = uint64(5000)
 )
 
+// maxHTLCReceivers is the max number of receivers of a height locked HTLC
+const maxHTLCReceivers = 16
+
 const (
 	// CmdNewContract create HTLC
 	CmdNewContract byte = iota
@@ -34,6 +45,14 @@ const (
 	CmdRefund
 	// CmdGetContract get HTLC
 	CmdGetContract
+	// CmdNewHeightContract create HTLC locked by block height with multiple receivers
+	CmdNewHeightContract
+	// CmdWithdrawHeight withdraw seele partially or fully from the height locked HTLC
+	CmdWithdrawHeight
+	// CmdRefundHeight refund the seele not withdrawn from the height locked HTLC
+	CmdRefundHeight
+	// CmdGetHeightContract get the height locked HTLC
+	CmdGetHeightContract
 )
 
 var (
@@ -42,7 +61,24 @@ var (
 		CmdWithdraw:    &cmdInfo{gasWithdraw, withdraw},
 		CmdRefund:      &cmdInfo{gasRefund, refund},
 		CmdGetContract: &cmdInfo{gasGetContract, getContract},
+
+		CmdNewHeightContract: &cmdInfo{gasNewHeightContract, newHeightHTLC},
+		CmdWithdrawHeight:    &cmdInfo{gasWithdrawHeight, withdrawHeight},
+		CmdRefundHeight:      &cmdInfo{gasRefundHeight, refundHeight},
+		CmdGetHeightContract: &cmdInfo{gasGetHeightContract, getHeightContract},
 	}
+
+	// the commands before the fork, the other commands are introduced by the fork
+	legacyHTLCCommands = map[byte]*cmdInfo{
+		CmdNewContract: &cmdInfo{gasNewContract, newHTLC},
+		CmdWithdraw:    &cmdInfo{gasWithdraw, withdraw},
+		CmdRefund:      &cmdInfo{gasRefund, refund},
+		CmdGetContract: &cmdInfo{gasGetContract, getContract},
+	}
+
+	// key prefixes of the height locked HTLCs and the active HTLC index of addresses in the contract storage
+	htlcHeightPrefix = []byte("height")
+	htlcActivePrefix = []byte("active")
 )
 
 var (
@@ -56,9 +92,18 @@ var (
 	errReceiver                = errors.New("Failed to withdraw, only receiver is allowed")
 	errNotFound                = errors.New("Failed to get data with key")
 	errHashMismatch            = errors.New("Failed to use preimage to match hash")
+	errNotFutureHeight         = errors.New("Failed to lock, height is not in future")
+	errHeightLocked            = errors.New("Failed to refund, height lock is not over")
+	errHeightExpired           = errors.New("Failed to withdraw, height lock is over")
+	errInvalidReceivers        = errors.New("Failed to lock, the number of receivers is invalid")
+	errDuplicateReceiver       = errors.New("Failed to lock, duplicate receiver")
+	errReceiverAmount          = errors.New("Failed to lock, the receiver amounts do not sum up to the tx amount")
+	errInvalidHashLock         = errors.New("Failed to lock, invalid hash lock")
+	errWithdrawAmount          = errors.New("Failed to withdraw, the amount exceeds the remaining")
 )
 
-type htlc struct {
+// HTLC is the hash time lock contract created by the tx
+type HTLC struct {
 	Tx *types.Transaction
 	HashTimeLock
 	// Refunded if refunded ture, otherwise false
@@ -87,6 +132,91 @@ type Withdrawing struct {
 	Preimage common.Bytes
 }
 
+// HTLCReceiver is a receiver of the height locked HTLC with its own hash lock
+type HTLCReceiver struct {
+	To       common.Address
+	HashLock common.Bytes
+	Amount   *big.Int
+	// Withdrawn is the amount withdrawn by the receiver so far
+	Withdrawn *big.Int
+	// Preimage is the hashlock preimage revealed by the receiver
+	Preimage common.Bytes
+}
+
+// Remaining returns the amount not withdrawn by the receiver
+func (receiver *HTLCReceiver) Remaining() *big.Int {
+	return new(big.Int).Sub(receiver.Amount, receiver.Withdrawn)
+}
+
+// HeightHashTimeLock payload information of the HTLC locked by block height
+type HeightHashTimeLock struct {
+	// LockHeight is the block height from which the receivers could no longer
+	// withdraw, and the sender could refund the remaining amount.
+	LockHeight uint64
+	Receivers  []*HTLCReceiver
+}
+
+// HeightHTLC is the HTLC locked by block height created by the tx, which could not
+// be skewed by the block proposers like the block timestamp.
+type HeightHTLC struct {
+	Tx *types.Transaction
+	HeightHashTimeLock
+	// Refunded if refunded ture, otherwise false
+	Refunded bool
+}
+
+// Remaining returns the amount not withdrawn by all the receivers
+func (data *HeightHTLC) Remaining() *big.Int {
+	remaining := big.NewInt(0)
+	for _, receiver := range data.Receivers {
+		remaining.Add(remaining, receiver.Remaining())
+	}
+
+	return remaining
+}
+
+// Active returns true if the HTLC is neither refunded nor fully withdrawn
+func (data *HeightHTLC) Active() bool {
+	return !data.Refunded && data.Remaining().Sign() > 0
+}
+
+// receiver returns the receiver of the address, or nil if not found
+func (data *HeightHTLC) receiver(addr common.Address) *HTLCReceiver {
+	for _, receiver := range data.Receivers {
+		if receiver.To.Equal(addr) {
+			return receiver
+		}
+	}
+
+	return nil
+}
+
+// parties returns the sender and the receivers of the HTLC
+func (data *HeightHTLC) parties() []common.Address {
+	addrs := []common.Address{data.Tx.Data.From}
+	for _, receiver := range data.Receivers {
+		addrs = append(addrs, receiver.To)
+	}
+
+	return addrs
+}
+
+// HeightWithdrawing used to withdraw from the height locked HTLC
+type HeightWithdrawing struct {
+	// Hash is the key of data
+	Hash common.Hash
+	// Preimage the hashlock preimage of the receiver
+	Preimage common.Bytes
+	// Amount to withdraw, all the remaining amount of the receiver if nil or 0
+	Amount *big.Int
+}
+
+// ActiveHTLCs are the HTLCs neither withdrawn nor refunded that an address sends or receives
+type ActiveHTLCs struct {
+	HTLCs       []*HTLC
+	HeightHTLCs []*HeightHTLC
+}
+
 // create a HTLC to transfer value by hash-lock and time-lock
 func newHTLC(lockbytes []byte, context *Context) ([]byte, error) {
 	var info HashTimeLock
@@ -102,7 +232,7 @@ func newHTLC(lockbytes []byte, context *Context) ([]byte, error) {
 		return nil, errNotFutureTime
 	}
 
-	var data htlc
+	var data HTLC
 	data.Tx = context.tx
 	data.HashLock = info.HashLock
 	data.TimeLock = info.TimeLock
@@ -115,6 +245,9 @@ func newHTLC(lockbytes []byte, context *Context) ([]byte, error) {
 
 	context.statedb.CreateAccount(HashTimeLockContractAddress)
 	context.statedb.SetData(HashTimeLockContractAddress, data.Tx.Hash, value)
+	if context.forked() {
+		addActiveHTLC(context.statedb, data.Tx.Hash, data.Tx.Data.From, data.To)
+	}
 
 	return value, nil
 }
@@ -131,7 +264,7 @@ func withdraw(jsonWithdraw []byte, context *Context) ([]byte, error) {
 		return nil, err
 	}
 
-	var info htlc
+	var info HTLC
 	if err = json.Unmarshal(databytes, &info); err != nil {
 		return nil, fmt.Errorf("Failed to unmarshal data, %s", err)
 	}
@@ -156,6 +289,9 @@ func withdraw(jsonWithdraw []byte, context *Context) ([]byte, error) {
 	context.statedb.SubBalance(context.tx.Data.To, info.Tx.Data.Amount)
 	// add the amount to the sender account
 	context.statedb.AddBalance(info.To, info.Tx.Data.Amount)
+	if context.forked() {
+		removeActiveHTLC(context.statedb, info.Tx.Hash, info.Tx.Data.From, info.To)
+	}
 
 	return value, nil
 }
@@ -167,7 +303,7 @@ func refund(bytes []byte, context *Context) ([]byte, error) {
 		return nil, err
 	}
 
-	var info htlc
+	var info HTLC
 	if err := json.Unmarshal(databytes, &info); err != nil {
 		return nil, err
 	}
@@ -188,6 +324,10 @@ func refund(bytes []byte, context *Context) ([]byte, error) {
 	context.statedb.SubBalance(context.tx.Data.To, info.Tx.Data.Amount)
 	// add the amount to sender account
 	context.statedb.AddBalance(info.Tx.Data.From, info.Tx.Data.Amount)
+	if context.forked() {
+		removeActiveHTLC(context.statedb, info.Tx.Hash, info.Tx.Data.From, info.To)
+	}
+
 	return value, nil
 }
 
@@ -232,7 +372,7 @@ func hashLockMatches(hashLock []byte, preimage []byte) bool {
 }
 
 // check if withdraw is available
-func withdrawable(data *htlc, context *Context) error {
+func withdrawable(data *HTLC, context *Context) error {
 	if !context.tx.Data.From.Equal(data.To) {
 		return errReceiver
 	}
@@ -249,7 +389,7 @@ func withdrawable(data *htlc, context *Context) error {
 }
 
 // check if refund is available
-func refundable(data *htlc, context *Context) error {
+func refundable(data *HTLC, context *Context) error {
 	if !context.tx.Data.From.Equal(data.Tx.Data.From) {
 		return errSender
 	}
@@ -269,6 +409,329 @@ func refundable(data *htlc, context *Context) error {
 	return nil
 }
 
+// create a HTLC locked by block height to transfer value to multiple receivers, each with its own hash lock
+func newHeightHTLC(lockbytes []byte, context *Context) ([]byte, error) {
+	var info HeightHashTimeLock
+	if err := json.Unmarshal(lockbytes, &info); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal lockbytes, %s", err)
+	}
+
+	if err := validateAmount(context.tx); err != nil {
+		return nil, err
+	}
+
+	if info.LockHeight <= context.BlockHeader.Height {
+		return nil, errNotFutureHeight
+	}
+
+	if err := validateReceivers(info.Receivers, context.tx.Data.Amount); err != nil {
+		return nil, err
+	}
+
+	data := HeightHTLC{Tx: context.tx, HeightHashTimeLock: info}
+	for _, receiver := range data.Receivers {
+		receiver.Withdrawn = big.NewInt(0)
+		receiver.Preimage = common.Bytes{}
+	}
+
+	context.statedb.CreateAccount(HashTimeLockContractAddress)
+	value, err := setHeightHTLC(context.statedb, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	addActiveHTLC(context.statedb, data.Tx.Hash, data.parties()...)
+
+	return value, nil
+}
+
+// withdraw the seele partially or fully from the height locked HTLC by the receiver
+func withdrawHeight(jsonWithdraw []byte, context *Context) ([]byte, error) {
+	var input HeightWithdrawing
+	if err := json.Unmarshal(jsonWithdraw, &input); err != nil {
+		return nil, err
+	}
+
+	info, err := GetHeightHTLC(context.statedb, input.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if context.BlockHeader.Height >= info.LockHeight {
+		return nil, errHeightExpired
+	}
+
+	receiver := info.receiver(context.tx.Data.From)
+	if receiver == nil {
+		return nil, errReceiver
+	}
+
+	if !hashLockMatches(receiver.HashLock, input.Preimage) {
+		return nil, errHashMismatch
+	}
+
+	remaining := receiver.Remaining()
+	if remaining.Sign() == 0 {
+		return nil, errWithdrawAfterWithdrawed
+	}
+
+	amount := input.Amount
+	if amount == nil || amount.Sign() == 0 {
+		amount = remaining
+	} else if amount.Sign() < 0 || amount.Cmp(remaining) > 0 {
+		return nil, errWithdrawAmount
+	}
+
+	receiver.Withdrawn = new(big.Int).Add(receiver.Withdrawn, amount)
+	receiver.Preimage = input.Preimage
+	value, err := setHeightHTLC(context.statedb, info)
+	if err != nil {
+		return nil, err
+	}
+
+	// subtract the amount from the HTLC address
+	context.statedb.SubBalance(context.tx.Data.To, amount)
+	// add the amount to the receiver account
+	context.statedb.AddBalance(receiver.To, amount)
+	if !info.Active() {
+		removeActiveHTLC(context.statedb, info.Tx.Hash, info.parties()...)
+	}
+
+	return value, nil
+}
+
+// refund the seele not withdrawn from the height locked HTLC after the lock height
+func refundHeight(bytes []byte, context *Context) ([]byte, error) {
+	info, err := GetHeightHTLC(context.statedb, common.BytesToHash(bytes))
+	if err != nil {
+		return nil, err
+	}
+
+	if !context.tx.Data.From.Equal(info.Tx.Data.From) {
+		return nil, errSender
+	}
+
+	if context.BlockHeader.Height < info.LockHeight {
+		return nil, errHeightLocked
+	}
+
+	if info.Refunded {
+		return nil, errRedunedAgain
+	}
+
+	remaining := info.Remaining()
+	if remaining.Sign() == 0 {
+		return nil, errRefundAfterWithdrawed
+	}
+
+	info.Refunded = true
+	value, err := setHeightHTLC(context.statedb, info)
+	if err != nil {
+		return nil, err
+	}
+
+	// subtract the amount from the HTLC address
+	context.statedb.SubBalance(context.tx.Data.To, remaining)
+	// add the amount to sender account
+	context.statedb.AddBalance(info.Tx.Data.From, remaining)
+	removeActiveHTLC(context.statedb, info.Tx.Hash, info.parties()...)
+
+	return value, nil
+}
+
+// getHeightContract return the height locked HTLC info
+func getHeightContract(bytes []byte, context *Context) ([]byte, error) {
+	value := context.statedb.GetData(HashTimeLockContractAddress, heightHTLCKey(common.BytesToHash(bytes)))
+	if len(value) == 0 {
+		return nil, errNotFound
+	}
+
+	return value, nil
+}
+
+// GetHeightHTLC returns the height locked HTLC created by the tx
+func GetHeightHTLC(statedb *state.Statedb, hash common.Hash) (*HeightHTLC, error) {
+	value := statedb.GetData(HashTimeLockContractAddress, heightHTLCKey(hash))
+	if len(value) == 0 {
+		return nil, errNotFound
+	}
+
+	info := new(HeightHTLC)
+	if err := json.Unmarshal(value, info); err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal data, %s", err)
+	}
+
+	return info, nil
+}
+
+// GetActiveHTLCs returns the HTLCs neither withdrawn nor refunded that the address sends or receives.
+// The HTLCs created before the fork of the system contracts are not included.
+func GetActiveHTLCs(statedb *state.Statedb, addr common.Address) (*ActiveHTLCs, error) {
+	hashes := getActiveHashes(statedb, addr)
+	result := &ActiveHTLCs{HTLCs: []*HTLC{}, HeightHTLCs: []*HeightHTLC{}}
+	for _, hash := range hashes {
+		if info, err := GetHeightHTLC(statedb, hash); err == nil {
+			result.HeightHTLCs = append(result.HeightHTLCs, info)
+			continue
+		} else if err != errNotFound {
+			return nil, err
+		}
+
+		value := statedb.GetData(HashTimeLockContractAddress, hash)
+		if len(value) == 0 {
+			continue
+		}
+
+		info := new(HTLC)
+		if err := json.Unmarshal(value, info); err != nil {
+			return nil, fmt.Errorf("Failed to unmarshal data, %s", err)
+		}
+		result.HTLCs = append(result.HTLCs, info)
+	}
+
+	return result, nil
+}
+
+// validateReceivers checks the receivers are unique with valid hash locks, and the amounts sum up to the tx amount
+func validateReceivers(receivers []*HTLCReceiver, amount *big.Int) error {
+	if len(receivers) == 0 || len(receivers) > maxHTLCReceivers {
+		return errInvalidReceivers
+	}
+
+	sum := big.NewInt(0)
+	for i, receiver := range receivers {
+		if receiver == nil || receiver.Amount == nil || receiver.Amount.Sign() <= 0 {
+			return errReceiverAmount
+		}
+
+		if len(receiver.HashLock) != sha256.Size {
+			return errInvalidHashLock
+		}
+
+		for _, other := range receivers[:i] {
+			if other.To.Equal(receiver.To) {
+				return errDuplicateReceiver
+			}
+		}
+
+		sum.Add(sum, receiver.Amount)
+	}
+
+	if sum.Cmp(amount) != 0 {
+		return errReceiverAmount
+	}
+
+	return nil
+}
+
+func setHeightHTLC(statedb *state.Statedb, data *HeightHTLC) ([]byte, error) {
+	value, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal data into json, %s", err)
+	}
+
+	statedb.SetData(HashTimeLockContractAddress, heightHTLCKey(data.Tx.Hash), value)
+	return value, nil
+}
+
+// addActiveHTLC adds the HTLC to the active index of the addresses. The index of an address
+// is a list of hash slots with the count, and the position of each hash in the list, so
+// that the HTLC is added or removed with a fixed number of storage operations.
+func addActiveHTLC(statedb *state.Statedb, hash common.Hash, addrs ...common.Address) {
+	for _, addr := range addrs {
+		if getActivePosition(statedb, addr, hash) > 0 {
+			continue
+		}
+
+		count := getActiveCount(statedb, addr)
+		statedb.SetData(HashTimeLockContractAddress, activeHTLCSlotKey(addr, count), hash.Bytes())
+		setActivePosition(statedb, addr, hash, count+1)
+		setActiveCount(statedb, addr, count+1)
+	}
+}
+
+// removeActiveHTLC removes the HTLC from the active index of the addresses,
+// and the last hash of the list is moved to the slot of the removed one.
+func removeActiveHTLC(statedb *state.Statedb, hash common.Hash, addrs ...common.Address) {
+	for _, addr := range addrs {
+		position := getActivePosition(statedb, addr, hash)
+		if position == 0 {
+			continue
+		}
+
+		last := getActiveCount(statedb, addr) - 1
+		if position-1 != last {
+			lastHash := getActiveHash(statedb, addr, last)
+			statedb.SetData(HashTimeLockContractAddress, activeHTLCSlotKey(addr, position-1), lastHash.Bytes())
+			setActivePosition(statedb, addr, lastHash, position)
+		}
+
+		statedb.SetData(HashTimeLockContractAddress, activeHTLCSlotKey(addr, last), nil)
+		setActivePosition(statedb, addr, hash, 0)
+		setActiveCount(statedb, addr, last)
+	}
+}
+
+// getActiveHashes returns the hashes of the active HTLCs of the address
+func getActiveHashes(statedb *state.Statedb, addr common.Address) []common.Hash {
+	count := getActiveCount(statedb, addr)
+	hashes := make([]common.Hash, count)
+	for i := range hashes {
+		hashes[i] = getActiveHash(statedb, addr, uint64(i))
+	}
+
+	return hashes
+}
+
+func getActiveHash(statedb *state.Statedb, addr common.Address, index uint64) common.Hash {
+	return common.BytesToHash(statedb.GetData(HashTimeLockContractAddress, activeHTLCSlotKey(addr, index)))
+}
+
+func getActiveCount(statedb *state.Statedb, addr common.Address) uint64 {
+	return getStorageUint64(statedb, activeHTLCKey(addr))
+}
+
+func setActiveCount(statedb *state.Statedb, addr common.Address, count uint64) {
+	setStorageUint64(statedb, activeHTLCKey(addr), count)
+}
+
+// getActivePosition returns the index plus 1 of the hash in the list, or 0 if not found
+func getActivePosition(statedb *state.Statedb, addr common.Address, hash common.Hash) uint64 {
+	return getStorageUint64(statedb, activeHTLCPositionKey(addr, hash))
+}
+
+func setActivePosition(statedb *state.Statedb, addr common.Address, hash common.Hash, position uint64) {
+	setStorageUint64(statedb, activeHTLCPositionKey(addr, hash), position)
+}
+
+// getStorageUint64 returns the number saved in the contract storage, 0 if not found
+func getStorageUint64(statedb *state.Statedb, key common.Hash) uint64 {
+	return new(big.Int).SetBytes(statedb.GetData(HashTimeLockContractAddress, key)).Uint64()
+}
+
+// setStorageUint64 saves the number in the contract storage, and 0 removes the key
+func setStorageUint64(statedb *state.Statedb, key common.Hash, value uint64) {
+	statedb.SetData(HashTimeLockContractAddress, key, new(big.Int).SetUint64(value).Bytes())
+}
+
+func heightHTLCKey(hash common.Hash) common.Hash {
+	return crypto.HashBytes(htlcHeightPrefix, hash.Bytes())
+}
+
+func activeHTLCKey(addr common.Address) common.Hash {
+	return crypto.HashBytes(htlcActivePrefix, addr.Bytes())
+}
+
+func activeHTLCSlotKey(addr common.Address, index uint64) common.Hash {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, index)
+	return crypto.HashBytes(htlcActivePrefix, addr.Bytes(), key)
+}
+
+func activeHTLCPositionKey(addr common.Address, hash common.Hash) common.Hash {
+	return crypto.HashBytes(htlcActivePrefix, addr.Bytes(), hash.Bytes())
+}
+
 // DecodeHTLC decode HTLC information
 func DecodeHTLC(payload string) (interface{}, error) {
 	databytes, err := hexutil.HexToBytes(payload)
@@ -276,7 +739,7 @@ func DecodeHTLC(payload string) (interface{}, error) {
 		return nil, fmt.Errorf("Failed to convert hex to bytes, %s", err)
 	}
 
-	var result htlc
+	var result HTLC
 	if err = json.Unmarshal(databytes, &result); err != nil {
 		return nil, fmt.Errorf("Failed to unmarshal, %s", err)
 	}
@@ -82,7 +82,7 @@ func newTestBlockHeader() *types.BlockHeader {
 		TxHash:            crypto.MustHash("tx root hash"),
 		ReceiptHash:       crypto.MustHash("receipt root hash"),
 		Difficulty:        big.NewInt(38),
-		Height:            666,
+		Height:            common.SystemContractForkHeight + 666,
 		CreateTimestamp:   big.NewInt(time.Now().Unix()),
 		Witness:           make([]byte, 0),
 		ExtraData:         make([]byte, 0),
@@ -339,3 +339,280 @@ func Test_GetContract(t *testing.T) {
 	_, err = getContract(common.EmptyHash.Bytes(), context)
 	assert.Equal(t, err, errNotFound)
 }
+
+func newTestHeightHTLC(t *testing.T, context *Context, lockHeight uint64, receivers ...*HTLCReceiver) common.Hash {
+	context.tx.Hash = crypto.MustHash(uint64(len(receivers)))
+	context.tx.Data.Amount = big.NewInt(0)
+	for _, receiver := range receivers {
+		context.tx.Data.Amount.Add(context.tx.Data.Amount, receiver.Amount)
+	}
+
+	databytes, err := json.Marshal(HeightHashTimeLock{LockHeight: lockHeight, Receivers: receivers})
+	assert.Equal(t, err, nil)
+
+	_, err = newHeightHTLC(databytes, context)
+	assert.Equal(t, err, nil)
+
+	context.statedb.AddBalance(HashTimeLockContractAddress, context.tx.Data.Amount)
+	return context.tx.Hash
+}
+
+func withdrawTestHeightHTLC(context *Context, from common.Address, hash common.Hash, preimage []byte, amount int64) error {
+	context.tx.Data.From = from
+	databytes, _ := json.Marshal(HeightWithdrawing{Hash: hash, Preimage: preimage, Amount: big.NewInt(amount)})
+	_, err := withdrawHeight(databytes, context)
+	return err
+}
+
+func Test_HeightHTLC_New(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, HashTimeLockContractAddress)
+	hashLock := Sha256Hash([]byte("secret"))
+	receiver := func(to byte, amount int64) *HTLCReceiver {
+		return &HTLCReceiver{To: common.BytesToAddress([]byte{to}), HashLock: hashLock, Amount: big.NewInt(amount)}
+	}
+
+	context.tx.Hash = crypto.MustHash("height htlc")
+	create := func(lockHeight uint64, amount int64, receivers ...*HTLCReceiver) error {
+		context.tx.Data.Amount = big.NewInt(amount)
+		databytes, err := json.Marshal(HeightHashTimeLock{LockHeight: lockHeight, Receivers: receivers})
+		assert.Equal(t, err, nil)
+		_, err = newHeightHTLC(databytes, context)
+		return err
+	}
+
+	height := context.BlockHeader.Height
+	assert.Equal(t, create(height, 10, receiver(1, 10)), errNotFutureHeight)
+	assert.Equal(t, create(height+1, 10), errInvalidReceivers)
+	assert.Equal(t, create(height+1, 10, receiver(1, 4), receiver(2, 5)), errReceiverAmount)
+	assert.Equal(t, create(height+1, 10, receiver(1, 10), receiver(2, 0)), errReceiverAmount)
+	assert.Equal(t, create(height+1, 10, receiver(1, 5), receiver(1, 5)), errDuplicateReceiver)
+
+	invalid := receiver(1, 10)
+	invalid.HashLock = []byte{1}
+	assert.Equal(t, create(height+1, 10, invalid), errInvalidHashLock)
+
+	assert.Equal(t, create(height+1, 10, receiver(1, 4), receiver(2, 6)), nil)
+	value, err := getHeightContract(context.tx.Hash.Bytes(), context)
+	assert.Equal(t, err, nil)
+
+	var info HeightHTLC
+	assert.Equal(t, json.Unmarshal(value, &info), nil)
+	assert.Equal(t, info.LockHeight, height+1)
+	assert.Equal(t, len(info.Receivers), 2)
+	assert.Equal(t, info.Receivers[1].Withdrawn.Sign(), 0)
+	assert.Equal(t, info.Remaining().Cmp(big.NewInt(10)), 0)
+	assert.Equal(t, info.Active(), true)
+
+	_, err = getHeightContract(common.EmptyHash.Bytes(), context)
+	assert.Equal(t, err, errNotFound)
+}
+
+func Test_HeightHTLC_Withdraw(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, HashTimeLockContractAddress)
+	sender := context.tx.Data.From
+	alice, bob := common.BytesToAddress([]byte{1}), common.BytesToAddress([]byte{2})
+	context.statedb.CreateAccount(alice)
+	context.statedb.CreateAccount(bob)
+
+	height := context.BlockHeader.Height
+	hash := newTestHeightHTLC(t, context, height+10,
+		&HTLCReceiver{To: alice, HashLock: Sha256Hash([]byte("alice")), Amount: big.NewInt(60)},
+		&HTLCReceiver{To: bob, HashLock: Sha256Hash([]byte("bob")), Amount: big.NewInt(40)},
+	)
+
+	// each receiver withdraws with its own preimage
+	assert.Equal(t, withdrawTestHeightHTLC(context, sender, hash, []byte("alice"), 0), errReceiver)
+	assert.Equal(t, withdrawTestHeightHTLC(context, alice, hash, []byte("bob"), 0), errHashMismatch)
+	assert.Equal(t, withdrawTestHeightHTLC(context, alice, hash, []byte("alice"), 61), errWithdrawAmount)
+	assert.Equal(t, withdrawTestHeightHTLC(context, alice, hash, []byte("alice"), -1), errWithdrawAmount)
+
+	// partial withdrawal
+	assert.Equal(t, withdrawTestHeightHTLC(context, alice, hash, []byte("alice"), 20), nil)
+	assert.Equal(t, context.statedb.GetBalance(alice).Cmp(big.NewInt(20)), 0)
+	assert.Equal(t, context.statedb.GetBalance(HashTimeLockContractAddress).Cmp(big.NewInt(80)), 0)
+
+	// withdraw all the remaining
+	assert.Equal(t, withdrawTestHeightHTLC(context, alice, hash, []byte("alice"), 0), nil)
+	assert.Equal(t, context.statedb.GetBalance(alice).Cmp(big.NewInt(60)), 0)
+	assert.Equal(t, withdrawTestHeightHTLC(context, alice, hash, []byte("alice"), 0), errWithdrawAfterWithdrawed)
+
+	info, err := GetHeightHTLC(context.statedb, hash)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, info.Receivers[0].Preimage, common.Bytes("alice"))
+	assert.Equal(t, info.Active(), true)
+
+	// bob could not withdraw after the lock height
+	context.BlockHeader.Height = height + 10
+	assert.Equal(t, withdrawTestHeightHTLC(context, bob, hash, []byte("bob"), 0), errHeightExpired)
+
+	context.BlockHeader.Height = height + 9
+	assert.Equal(t, withdrawTestHeightHTLC(context, bob, hash, []byte("bob"), 0), nil)
+	assert.Equal(t, context.statedb.GetBalance(bob).Cmp(big.NewInt(40)), 0)
+	assert.Equal(t, context.statedb.GetBalance(HashTimeLockContractAddress).Sign(), 0)
+
+	// fully withdrawn HTLC is no longer active
+	active, err := GetActiveHTLCs(context.statedb, sender)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(active.HeightHTLCs), 0)
+
+	context.tx.Data.From = sender
+	context.BlockHeader.Height = height + 10
+	_, err = refundHeight(hash.Bytes(), context)
+	assert.Equal(t, err, errRefundAfterWithdrawed)
+}
+
+func Test_HeightHTLC_Refund(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, HashTimeLockContractAddress)
+	sender := context.tx.Data.From
+	alice, bob := common.BytesToAddress([]byte{1}), common.BytesToAddress([]byte{2})
+	context.statedb.CreateAccount(sender)
+	context.statedb.CreateAccount(alice)
+
+	height := context.BlockHeader.Height
+	hash := newTestHeightHTLC(t, context, height+10,
+		&HTLCReceiver{To: alice, HashLock: Sha256Hash([]byte("alice")), Amount: big.NewInt(60)},
+		&HTLCReceiver{To: bob, HashLock: Sha256Hash([]byte("bob")), Amount: big.NewInt(40)},
+	)
+	assert.Equal(t, withdrawTestHeightHTLC(context, alice, hash, []byte("alice"), 50), nil)
+
+	// the HTLC is active for the sender and all the receivers
+	for _, addr := range []common.Address{sender, alice, bob} {
+		active, err := GetActiveHTLCs(context.statedb, addr)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(active.HeightHTLCs), 1)
+		assert.Equal(t, active.HeightHTLCs[0].Tx.Hash, hash)
+	}
+
+	context.tx.Data.From = alice
+	_, err := refundHeight(hash.Bytes(), context)
+	assert.Equal(t, err, errSender)
+
+	context.tx.Data.From = sender
+	_, err = refundHeight(hash.Bytes(), context)
+	assert.Equal(t, err, errHeightLocked)
+
+	// refund the remaining amount after the lock height
+	context.BlockHeader.Height = height + 10
+	_, err = refundHeight(hash.Bytes(), context)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, context.statedb.GetBalance(sender).Cmp(big.NewInt(50)), 0)
+	assert.Equal(t, context.statedb.GetBalance(HashTimeLockContractAddress).Sign(), 0)
+
+	_, err = refundHeight(hash.Bytes(), context)
+	assert.Equal(t, err, errRedunedAgain)
+
+	for _, addr := range []common.Address{sender, alice, bob} {
+		active, err := GetActiveHTLCs(context.statedb, addr)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(active.HeightHTLCs), 0)
+	}
+}
+
+func Test_GetActiveHTLCs(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, HashTimeLockContractAddress)
+	sender := context.tx.Data.From
+	receiver := common.BytesToAddress([]byte{1})
+	context.statedb.CreateAccount(receiver)
+
+	// the time locked HTLC
+	context.tx.Hash = crypto.MustHash("time")
+	lock := HashTimeLock{HashLock: Sha256Hash([]byte("secret")), TimeLock: context.BlockHeader.CreateTimestamp.Int64() + 100, To: receiver}
+	databytes, err := json.Marshal(lock)
+	assert.Equal(t, err, nil)
+	_, err = newHTLC(databytes, context)
+	assert.Equal(t, err, nil)
+	context.statedb.AddBalance(HashTimeLockContractAddress, context.tx.Data.Amount)
+	timeHash := context.tx.Hash
+
+	// the height locked HTLC
+	heightHash := newTestHeightHTLC(t, context, context.BlockHeader.Height+10,
+		&HTLCReceiver{To: receiver, HashLock: Sha256Hash([]byte("secret")), Amount: big.NewInt(5)},
+	)
+
+	active, err := GetActiveHTLCs(context.statedb, receiver)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(active.HTLCs), 1)
+	assert.Equal(t, active.HTLCs[0].Tx.Hash, timeHash)
+	assert.Equal(t, len(active.HeightHTLCs), 1)
+	assert.Equal(t, active.HeightHTLCs[0].Tx.Hash, heightHash)
+
+	// the withdrawn HTLC is removed
+	context.tx.Data.From = receiver
+	databytes, err = json.Marshal(Withdrawing{Hash: timeHash, Preimage: []byte("secret")})
+	assert.Equal(t, err, nil)
+	_, err = withdraw(databytes, context)
+	assert.Equal(t, err, nil)
+
+	for _, addr := range []common.Address{sender, receiver} {
+		active, err = GetActiveHTLCs(context.statedb, addr)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(active.HTLCs), 0)
+		assert.Equal(t, len(active.HeightHTLCs), 1)
+	}
+
+	// no HTLC
+	active, err = GetActiveHTLCs(context.statedb, common.BytesToAddress([]byte{9}))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(active.HTLCs)+len(active.HeightHTLCs), 0)
+}
+
+func Test_ActiveHTLCIndex(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, HashTimeLockContractAddress)
+	statedb, addr := context.statedb, common.BytesToAddress([]byte{1})
+	hashes := []common.Hash{crypto.MustHash("a"), crypto.MustHash("b"), crypto.MustHash("c")}
+
+	for _, hash := range hashes {
+		addActiveHTLC(statedb, hash, addr)
+	}
+	addActiveHTLC(statedb, hashes[0], addr)
+	assert.Equal(t, getActiveHashes(statedb, addr), hashes)
+
+	// the last hash is moved to the slot of the removed one
+	removeActiveHTLC(statedb, hashes[0], addr)
+	assert.Equal(t, getActiveHashes(statedb, addr), []common.Hash{hashes[2], hashes[1]})
+	removeActiveHTLC(statedb, hashes[0], addr)
+	assert.Equal(t, getActiveCount(statedb, addr), uint64(2))
+
+	removeActiveHTLC(statedb, hashes[1], addr)
+	removeActiveHTLC(statedb, hashes[2], addr)
+	assert.Equal(t, len(getActiveHashes(statedb, addr)), 0)
+	assert.Equal(t, len(statedb.GetData(HashTimeLockContractAddress, activeHTLCKey(addr))), 0)
+	assert.Equal(t, len(statedb.GetData(HashTimeLockContractAddress, activeHTLCSlotKey(addr, 0))), 0)
+	assert.Equal(t, getActivePosition(statedb, addr, hashes[2]), uint64(0))
+}
+
+func Test_newHTLC_BeforeFork(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, HashTimeLockContractAddress)
+	context.BlockHeader.Height = common.SystemContractForkHeight - 1
+	receiver := common.BytesToAddress([]byte{1})
+
+	// the active index is not saved before the fork
+	lock := HashTimeLock{HashLock: Sha256Hash([]byte("secret")), TimeLock: context.BlockHeader.CreateTimestamp.Int64() + 100, To: receiver}
+	databytes, err := json.Marshal(lock)
+	assert.Equal(t, err, nil)
+	_, err = newHTLC(databytes, context)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, getActiveCount(context.statedb, receiver), uint64(0))
+
+	c := GetContractByAddress(HashTimeLockContractAddress)
+	_, err = c.Run([]byte{CmdGetHeightContract}, context)
+	assert.Equal(t, err, errInvalidCommand)
+}
@@ -13,21 +13,44 @@ import (
 	"github.com/seeleteam/go-seele/core/types"
 )
 
+// ContractCaller calls the evm contract with the input on behalf of the caller, and returns the result.
+type ContractCaller func(caller common.Address, to common.Address, input []byte) ([]byte, error)
+
 // Context provides information that required in system contract.
 type Context struct {
 	tx          *types.Transaction
 	statedb     *state.Statedb
 	BlockHeader *types.BlockHeader
+
+	// CallContract calls evm contracts from system contracts, nil if not supported.
+	CallContract ContractCaller
+	// UseGas charges the gas used by the command in addition to the required gas, nil if not metered.
+	UseGas func(gas uint64) error
 }
 
 // NewContext creates a system contract context.
 func NewContext(tx *types.Transaction, statedb *state.Statedb, BlockHeader *types.BlockHeader) *Context {
-	return &Context{tx, statedb, BlockHeader}
+	return &Context{tx: tx, statedb: statedb, BlockHeader: BlockHeader}
+}
+
+// forked returns true if the block is at or after the fork height of the system contracts,
+// before which the commands and storage introduced by the fork are not available.
+func (c *Context) forked() bool {
+	return c.BlockHeader == nil || c.BlockHeader.Height >= common.SystemContractForkHeight
+}
+
+// useGas charges the gas used by the command, which depends on the input.
+func (c *Context) useGas(gas uint64) error {
+	if c.UseGas == nil {
+		return nil
+	}
+
+	return c.UseGas(gas)
 }
 
 // Contract is the basic interface for native Go contracts in Seele.
 type Contract interface {
-	RequiredGas(input []byte) uint64
+	RequiredGas(input []byte, height uint64) uint64
 	Run(input []byte, context *Context) ([]byte, error)
 }
 
@@ -50,14 +73,28 @@ var (
 	MasternodeContractAddress = common.BytesToAddress([]byte{1, 4})
 	// BTCRelayContractAddress btc-relay contract address
 	BTCRelayContractAddress = common.BytesToAddress([]byte{1, 5})
+	// ConfidentialTransferContractAddress confidential transfer contract address
+	ConfidentialTransferContractAddress = common.BytesToAddress([]byte{1, 6})
+	// StakingContractAddress verifier staking contract address
+	StakingContractAddress = common.BytesToAddress([]byte{1, 7})
 
 	// Contracts are system contracts
 	contracts = map[common.Address]Contract{
-		DomainNameContractAddress:   &contract{domainNameCommands},
-		SubChainContractAddress:     &contract{subChainCommands},
-		HashTimeLockContractAddress: &contract{htlcCommands},
-		MasternodeContractAddress:   &contract{masternodeCommands},
-		BTCRelayContractAddress:     &contract{brCommands},
+		DomainNameContractAddress:   &contract{domainNameCommands, legacyDomainNameCommands},
+		SubChainContractAddress:     &contract{subChainCommands, legacySubChainCommands},
+		HashTimeLockContractAddress: &contract{htlcCommands, legacyHTLCCommands},
+		MasternodeContractAddress:   &contract{masternodeCommands, legacyMasternodeCommands},
+		BTCRelayContractAddress:     &contract{brCommands, legacyBRCommands},
+
+		ConfidentialTransferContractAddress: &contract{confidentialCommands, nil},
+		StakingContractAddress:              &contract{stakingCommands, nil},
+	}
+
+	// forkContracts are the system contracts introduced by the fork of the system contracts,
+	// the addresses are plain accounts before the fork height.
+	forkContracts = map[common.Address]bool{
+		ConfidentialTransferContractAddress: true,
+		StakingContractAddress:              true,
 	}
 )
 
@@ -70,14 +107,25 @@ type cmdInfo struct {
 
 type contract struct {
 	cmds map[byte]*cmdInfo
+	// legacyCmds are the commands before the fork height of the system contracts, nil if not changed
+	legacyCmds map[byte]*cmdInfo
 }
 
-func (c *contract) RequiredGas(input []byte) uint64 {
+// commands returns the commands of the contract at the block height
+func (c *contract) commands(height uint64) map[byte]*cmdInfo {
+	if c.legacyCmds != nil && height < common.SystemContractForkHeight {
+		return c.legacyCmds
+	}
+
+	return c.cmds
+}
+
+func (c *contract) RequiredGas(input []byte, height uint64) uint64 {
 	if len(input) == 0 {
 		return gasInvalidCommand
 	}
 
-	if info, found := c.cmds[input[0]]; found {
+	if info, found := c.commands(height)[input[0]]; found {
 		return info.cmdUsedGas
 	}
 
@@ -89,7 +137,12 @@ func (c *contract) Run(input []byte, context *Context) ([]byte, error) {
 		return nil, errInvalidCommand
 	}
 
-	if info, found := c.cmds[input[0]]; found {
+	cmds := c.cmds
+	if !context.forked() {
+		cmds = c.commands(context.BlockHeader.Height)
+	}
+
+	if info, found := cmds[input[0]]; found {
 		return info.cmdHandler(input[1:], context)
 	}
 
@@ -100,3 +153,13 @@ func (c *contract) Run(input []byte, context *Context) ([]byte, error) {
 func GetContractByAddress(address common.Address) Contract {
 	return contracts[address]
 }
+
+// GetContractByHeight get system contract by the address at the block height, it returns nil
+// for the contracts introduced by the fork of the system contracts before the fork height.
+func GetContractByHeight(address common.Address, height uint64) Contract {
+	if forkContracts[address] && height < common.SystemContractForkHeight {
+		return nil
+	}
+
+	return contracts[address]
+}
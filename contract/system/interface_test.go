@@ -67,15 +67,21 @@ func Test_RequiredGas(t *testing.T) {
 	assert.Equal(t, ok, true)
 
 	// input is nil
-	gas := c.RequiredGas(nil)
+	gas := c.RequiredGas(nil, common.SystemContractForkHeight)
 	assert.Equal(t, gas, gasInvalidCommand)
 
 	// CmdCreateDomainName is valid command
-	gas = c.RequiredGas([]byte{CmdCreateDomainName})
+	gas = c.RequiredGas([]byte{CmdCreateDomainName}, common.SystemContractForkHeight)
 	assert.Equal(t, gas, gasCreateDomainName)
 
 	// byte(123) is invalid command
-	gas = c.RequiredGas([]byte{byte(123)})
+	gas = c.RequiredGas([]byte{byte(123)}, common.SystemContractForkHeight)
+	assert.Equal(t, gas, gasInvalidCommand)
+
+	// the commands introduced by the fork are invalid before the fork height
+	gas = c.RequiredGas([]byte{CmdRenewDomainName}, common.SystemContractForkHeight)
+	assert.Equal(t, gas, gasRenewDomainName)
+	gas = c.RequiredGas([]byte{CmdRenewDomainName}, common.SystemContractForkHeight-1)
 	assert.Equal(t, gas, gasInvalidCommand)
 }
 
@@ -111,9 +117,22 @@ func Test_Run(t *testing.T) {
 
 func Test_GetContractByAddress(t *testing.T) {
 	c := GetContractByAddress(DomainNameContractAddress)
-	assert.Equal(t, c, &contract{domainNameCommands})
+	assert.Equal(t, c, &contract{domainNameCommands, legacyDomainNameCommands})
 
 	contractAddress := common.BytesToAddress([]byte{123, 1})
 	c1 := GetContractByAddress(contractAddress)
 	assert.Equal(t, c1, nil)
 }
+
+func Test_GetContractByHeight(t *testing.T) {
+	height := uint64(common.SystemContractForkHeight)
+
+	// the contracts introduced by the fork are plain accounts before the fork height
+	assert.Equal(t, GetContractByHeight(ConfidentialTransferContractAddress, height-1) == nil, true)
+	assert.Equal(t, GetContractByHeight(ConfidentialTransferContractAddress, height), GetContractByAddress(ConfidentialTransferContractAddress))
+	assert.Equal(t, GetContractByHeight(StakingContractAddress, height-1) == nil, true)
+	assert.Equal(t, GetContractByHeight(StakingContractAddress, height), GetContractByAddress(StakingContractAddress))
+
+	assert.Equal(t, GetContractByHeight(DomainNameContractAddress, height-1), GetContractByAddress(DomainNameContractAddress))
+	assert.Equal(t, GetContractByHeight(common.BytesToAddress([]byte{123, 1}), height) == nil, true)
+}
@@ -23,11 +23,14 @@ const (
 	CmdRecall
 	// CmdQuit quitCmd masternode
 	CmdQuit
+	// CmdHeartbeat heartbeat of the masternode to prove it is online
+	CmdHeartbeat
 
 	gasCmdDeposit         = uint64(50000) // gas used to deposit
 	gasCmdQueryMasterNode = uint64(5000)  // gas used to query masternode
 	gasCmdRecall          = uint64(50000) // gas used to recallCmd
 	gasCmdQuit            = uint64(50000) // gas used to quitCmd
+	gasCmdHeartbeat       = uint64(5000)  // gas used to heartbeat
 )
 
 var (
@@ -36,20 +39,51 @@ var (
 
 	depositLimit        = big.NewInt(0).Mul(common.SeeleToFan, big.NewInt(20000))
 	recallDistanceLimit = uint64(8640) // generate blocks in about one day
+
+	// MasternodeEpoch is the number of blocks of an epoch, in about one day. The rewards
+	// are distributed to the live masternodes at the first block of the next epoch.
+	MasternodeEpoch = uint64(8640)
+	// MasternodeRewardPoolAddress is the reserved account that the masternode share of the block
+	// rewards is split to, which is distributed to the live masternodes at each epoch.
+	MasternodeRewardPoolAddress = common.BytesToAddress([]byte{2, 1})
+	// masternodeSlot is the number of blocks of a heartbeat slot in an epoch, in about two hours.
+	// The masternode is live in the epoch if it sends heartbeats in at least 2/3 of the slots.
+	masternodeSlot = uint64(720)
+	// maxMasternodes is the max number of the masternodes in the index, which are processed at each epoch
+	maxMasternodes = 1000
+	// maxMissedEpochs is the number of consecutive epochs that a masternode is not live
+	// before it is removed, and the deposit could be recalled later as it quits.
+	maxMissedEpochs = uint64(3)
+
+	// key of the masternode addresses index in the contract storage
+	masternodesKey = crypto.HashBytes([]byte("masternodes"))
+	// key prefix of the masternode liveness in the contract storage
+	livenessPrefix = []byte("liveness")
 )
 
 var (
-	ErrDepositNotRight   = errors.New("deposit amount is not right")
-	ErrAlreadyExist      = errors.New("this address is already masternode")
-	ErrNotExist          = errors.New("this address is not masternode")
-	ErrNotQuit           = errors.New("address doesn't quit")
-	ErrNotEnoughDistance = errors.New("not enough distance")
+	ErrDepositNotRight    = errors.New("deposit amount is not right")
+	ErrAlreadyExist       = errors.New("this address is already masternode")
+	ErrNotExist           = errors.New("this address is not masternode")
+	ErrNotQuit            = errors.New("address doesn't quit")
+	ErrNotEnoughDistance  = errors.New("not enough distance")
+	ErrMasternodeQuit     = errors.New("masternode already quit")
+	ErrTooManyMasternodes = errors.New("too many masternodes")
 
 	masternodeCommands = map[byte]*cmdInfo{
 		CmdDeposit:         &cmdInfo{gasCmdDeposit, deposit},
 		CmdQueryMasternode: &cmdInfo{gasCmdQueryMasterNode, queryMasternodeCmd},
 		CmdRecall:          {gasCmdRecall, recallCmd},
 		CmdQuit:            {gasCmdQuit, quitCmd},
+		CmdHeartbeat:       {gasCmdHeartbeat, heartbeatCmd},
+	}
+
+	// legacyMasternodeCommands are the commands before the system contract fork height
+	legacyMasternodeCommands = map[byte]*cmdInfo{
+		CmdDeposit:         &cmdInfo{gasCmdDeposit, deposit},
+		CmdQueryMasternode: &cmdInfo{gasCmdQueryMasterNode, queryMasternodeCmd},
+		CmdRecall:          {gasCmdRecall, recallCmd},
+		CmdQuit:            {gasCmdQuit, quitCmd},
 	}
 )
 
@@ -79,6 +113,12 @@ func deposit(input []byte, context *Context) ([]byte, error) {
 
 	context.statedb.SetData(MasternodeContractAddress, crypto.MustHash(sender), common.SerializePanic(info))
 
+	if context.forked() {
+		if err = indexMasternode(context, sender); err != nil {
+			return nil, err
+		}
+	}
+
 	return nil, nil
 }
 
@@ -114,6 +154,11 @@ func recallCmd(address []byte, context *Context) ([]byte, error) {
 		context.statedb.SetData(MasternodeContractAddress, crypto.MustHash(address), nil)
 		context.statedb.SubBalance(MasternodeContractAddress, depositLimit)
 		context.statedb.AddBalance(context.tx.Data.From, depositLimit)
+		if context.forked() {
+			if err = removeMasternode(context.statedb, common.BytesToAddress(address)); err != nil {
+				return nil, err
+			}
+		}
 	} else {
 		return nil, ErrNotEnoughDistance
 	}
@@ -162,3 +207,298 @@ func quitCmd(address []byte, context *Context) ([]byte, error) {
 
 	return nil, nil
 }
+
+// MasternodeLiveness is the liveness and rewards of a masternode
+type MasternodeLiveness struct {
+	RegisterHeight uint64
+	LastHeartbeat  uint64
+	// EpochSlots is the bitmap of the slots with heartbeats in the current epoch
+	EpochSlots uint64
+	// ActiveSlots and TotalSlots are the slots with heartbeats and all the slots since registered
+	ActiveSlots  uint64
+	TotalSlots   uint64
+	MissedEpochs uint64
+	Rewards      *big.Int
+}
+
+// MasternodeStatus is the status and uptime of a masternode
+type MasternodeStatus struct {
+	Address   common.Address
+	IsQuit    bool
+	QuitBlock uint64
+	MasternodeLiveness
+}
+
+// indexMasternode adds the masternode to the index, and its liveness is tracked from the next slot
+func indexMasternode(context *Context, addr common.Address) error {
+	if err := addMasternode(context.statedb, addr); err != nil {
+		return err
+	}
+
+	liveness := &MasternodeLiveness{RegisterHeight: context.BlockHeader.Height, Rewards: big.NewInt(0)}
+	return saveLiveness(context.statedb, addr, liveness)
+}
+
+// heartbeatCmd records the heartbeat of the sender in the slot of the current block. The masternodes
+// deposited before the system contract fork height are not indexed, which are indexed at the first heartbeat.
+func heartbeatCmd(input []byte, context *Context) ([]byte, error) {
+	sender := context.tx.Data.From
+	info, err := QueryAddress(sender, context.statedb)
+	if err != nil {
+		return nil, err
+	}
+
+	if info == nil {
+		return nil, ErrNotExist
+	}
+
+	if info.IsQuit {
+		return nil, ErrMasternodeQuit
+	}
+
+	if len(context.statedb.GetData(MasternodeContractAddress, livenessKey(sender))) == 0 {
+		if err = indexMasternode(context, sender); err != nil {
+			return nil, err
+		}
+	}
+
+	liveness, err := getLiveness(context.statedb, sender)
+	if err != nil {
+		return nil, err
+	}
+
+	height := context.BlockHeader.Height
+	liveness.LastHeartbeat = height
+	liveness.EpochSlots |= 1 << ((height % MasternodeEpoch) / masternodeSlot)
+	if err = saveLiveness(context.statedb, sender, liveness); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// MasternodeReward is the reward paid to a masternode for the last epoch
+type MasternodeReward struct {
+	Address common.Address
+	Amount  *big.Int
+}
+
+// ProcessMasternodeEpoch distributes the reward pool to the live masternodes in the last epoch by
+// their heartbeat slots, and removes the masternodes not live for maxMissedEpochs epochs. The pool
+// is kept for the next epoch if no masternode is live. It only takes effect at the first block of
+// an epoch, and returns the paid rewards.
+func ProcessMasternodeEpoch(statedb *state.Statedb, height uint64) ([]*MasternodeReward, error) {
+	if height == 0 || height%MasternodeEpoch != 0 {
+		return nil, nil
+	}
+
+	addrs, err := getMasternodes(statedb)
+	if err != nil || len(addrs) == 0 {
+		return nil, err
+	}
+
+	epochStart := height - MasternodeEpoch
+	weights := make([]uint64, len(addrs))
+	livenesses := make([]*MasternodeLiveness, len(addrs))
+	totalWeight := uint64(0)
+	for i, addr := range addrs {
+		info, err := QueryAddress(addr, statedb)
+		if err != nil {
+			return nil, err
+		}
+
+		if info == nil || info.IsQuit {
+			continue
+		}
+
+		liveness, err := getLiveness(statedb, addr)
+		if err != nil {
+			return nil, err
+		}
+		livenesses[i] = liveness
+
+		// the slots after registered are counted, and the epoch is skipped if registered in the last slot
+		firstSlot := uint64(0)
+		if liveness.RegisterHeight >= epochStart {
+			firstSlot = (liveness.RegisterHeight-epochStart)/masternodeSlot + 1
+		}
+
+		eligible := MasternodeEpoch/masternodeSlot - firstSlot
+		if liveness.RegisterHeight >= height || eligible == 0 {
+			continue
+		}
+
+		active := uint64(0)
+		for slot := firstSlot; slot < MasternodeEpoch/masternodeSlot; slot++ {
+			if liveness.EpochSlots&(1<<slot) != 0 {
+				active++
+			}
+		}
+
+		liveness.ActiveSlots += active
+		liveness.TotalSlots += eligible
+		if active*3 >= eligible*2 {
+			liveness.MissedEpochs = 0
+			weights[i] = active
+			totalWeight += active
+			continue
+		}
+
+		liveness.MissedEpochs++
+		if liveness.MissedEpochs >= maxMissedEpochs {
+			info.IsQuit = true
+			info.QuitBlock = height
+			if err = saveInfo(addr.Bytes(), statedb, info); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var rewards []*MasternodeReward
+	reward := statedb.GetBalance(MasternodeRewardPoolAddress)
+	for i, addr := range addrs {
+		liveness := livenesses[i]
+		if liveness == nil {
+			continue
+		}
+
+		if weights[i] > 0 && reward.Sign() > 0 {
+			amount := new(big.Int).Mul(reward, new(big.Int).SetUint64(weights[i]))
+			amount.Div(amount, new(big.Int).SetUint64(totalWeight))
+			statedb.SubBalance(MasternodeRewardPoolAddress, amount)
+			statedb.CreateAccount(addr)
+			statedb.AddBalance(addr, amount)
+			liveness.Rewards = new(big.Int).Add(liveness.Rewards, amount)
+			rewards = append(rewards, &MasternodeReward{addr, amount})
+		}
+
+		liveness.EpochSlots = 0
+		if err = saveLiveness(statedb, addr, liveness); err != nil {
+			return nil, err
+		}
+	}
+
+	return rewards, nil
+}
+
+// GetMasternodes returns the status and uptime of all the masternodes not recalled. The masternodes
+// deposited before the system contract fork height are listed after the first heartbeat.
+func GetMasternodes(statedb *state.Statedb) ([]*MasternodeStatus, error) {
+	addrs, err := getMasternodes(statedb)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*MasternodeStatus, 0, len(addrs))
+	for _, addr := range addrs {
+		info, err := QueryAddress(addr, statedb)
+		if err != nil {
+			return nil, err
+		}
+
+		if info == nil {
+			continue
+		}
+
+		liveness, err := getLiveness(statedb, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &MasternodeStatus{addr, info.IsQuit, info.QuitBlock, *liveness})
+	}
+
+	return result, nil
+}
+
+func getLiveness(statedb *state.Statedb, addr common.Address) (*MasternodeLiveness, error) {
+	value := statedb.GetData(MasternodeContractAddress, livenessKey(addr))
+	if len(value) == 0 {
+		return &MasternodeLiveness{Rewards: big.NewInt(0)}, nil
+	}
+
+	liveness := new(MasternodeLiveness)
+	if err := common.Deserialize(value, liveness); err != nil {
+		return nil, err
+	}
+
+	return liveness, nil
+}
+
+func saveLiveness(statedb *state.Statedb, addr common.Address, liveness *MasternodeLiveness) error {
+	buf, err := common.Serialize(liveness)
+	if err != nil {
+		return err
+	}
+
+	statedb.SetData(MasternodeContractAddress, livenessKey(addr), buf)
+	return nil
+}
+
+func getMasternodes(statedb *state.Statedb) ([]common.Address, error) {
+	value := statedb.GetData(MasternodeContractAddress, masternodesKey)
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	var addrs []common.Address
+	if err := common.Deserialize(value, &addrs); err != nil {
+		return nil, err
+	}
+
+	return addrs, nil
+}
+
+func setMasternodes(statedb *state.Statedb, addrs []common.Address) error {
+	if len(addrs) == 0 {
+		statedb.SetData(MasternodeContractAddress, masternodesKey, nil)
+		return nil
+	}
+
+	buf, err := common.Serialize(addrs)
+	if err != nil {
+		return err
+	}
+
+	statedb.SetData(MasternodeContractAddress, masternodesKey, buf)
+	return nil
+}
+
+func addMasternode(statedb *state.Statedb, addr common.Address) error {
+	addrs, err := getMasternodes(statedb)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range addrs {
+		if a.Equal(addr) {
+			return nil
+		}
+	}
+
+	if len(addrs) >= maxMasternodes {
+		return ErrTooManyMasternodes
+	}
+
+	return setMasternodes(statedb, append(addrs, addr))
+}
+
+func removeMasternode(statedb *state.Statedb, addr common.Address) error {
+	addrs, err := getMasternodes(statedb)
+	if err != nil {
+		return err
+	}
+
+	for i, a := range addrs {
+		if a.Equal(addr) {
+			statedb.SetData(MasternodeContractAddress, livenessKey(addr), nil)
+			return setMasternodes(statedb, append(addrs[:i], addrs[i+1:]...))
+		}
+	}
+
+	return nil
+}
+
+func livenessKey(addr common.Address) common.Hash {
+	return crypto.HashBytes(livenessPrefix, addr.Bytes())
+}
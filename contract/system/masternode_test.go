@@ -0,0 +1,205 @@
+/**
+* @file
+* @copyright defined in go-seele/LICENSE
+ */
+
+package system
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/database"
+	"github.com/seeleteam/go-seele/database/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+// epochHeight returns the first block height of the epoch after the system contract fork
+func epochHeight(epoch uint64) uint64 {
+	return common.MasternodeRewardForkHeight + epoch*MasternodeEpoch
+}
+
+func newTestMasternodeContext(t *testing.T, db database.Database, nodes ...common.Address) *Context {
+	context := newTestContext(db, MasternodeContractAddress)
+	context.BlockHeader.Height = epochHeight(1) + 1
+	context.tx.Data.Amount = depositLimit
+
+	for _, node := range nodes {
+		context.tx.Data.From = node
+		_, err := deposit(nil, context)
+		assert.Equal(t, err, nil)
+	}
+
+	return context
+}
+
+// fundRewardPool adds the amount to the masternode reward pool
+func fundRewardPool(context *Context, amount *big.Int) {
+	context.statedb.CreateAccount(MasternodeRewardPoolAddress)
+	context.statedb.AddBalance(MasternodeRewardPoolAddress, amount)
+}
+
+// heartbeats sends the heartbeats of the node in the first slots of the epoch
+func heartbeats(t *testing.T, context *Context, node common.Address, epoch uint64, slots uint64) {
+	context.tx.Data.From = node
+	for slot := uint64(0); slot < slots; slot++ {
+		context.BlockHeader.Height = epochHeight(epoch) + slot*masternodeSlot + 1
+		_, err := heartbeatCmd(nil, context)
+		assert.Equal(t, err, nil)
+	}
+}
+
+func Test_MasternodeHeartbeat(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	node := common.BytesToAddress([]byte{1})
+	context := newTestMasternodeContext(t, db, node)
+
+	// not a masternode
+	context.tx.Data.From = common.BytesToAddress([]byte{2})
+	_, err := heartbeatCmd(nil, context)
+	assert.Equal(t, err, ErrNotExist)
+
+	context.tx.Data.From = node
+	context.BlockHeader.Height = epochHeight(2) + masternodeSlot*3 + 5
+	_, err = heartbeatCmd(nil, context)
+	assert.Equal(t, err, nil)
+
+	nodes, err := GetMasternodes(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(nodes), 1)
+	assert.Equal(t, nodes[0].Address, node)
+	assert.Equal(t, nodes[0].RegisterHeight, epochHeight(1)+1)
+	assert.Equal(t, nodes[0].LastHeartbeat, context.BlockHeader.Height)
+	assert.Equal(t, nodes[0].EpochSlots, uint64(1<<3))
+
+	// quit masternode
+	_, err = quitCmd(node.Bytes(), context)
+	assert.Equal(t, err, nil)
+	_, err = heartbeatCmd(nil, context)
+	assert.Equal(t, err, ErrMasternodeQuit)
+}
+
+func Test_ProcessMasternodeEpoch(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	live, partial, offline := common.BytesToAddress([]byte{1}), common.BytesToAddress([]byte{2}), common.BytesToAddress([]byte{3})
+	context := newTestMasternodeContext(t, db, live, partial, offline)
+	slots := MasternodeEpoch / masternodeSlot
+	reward := big.NewInt(3000)
+
+	// not the first block of an epoch
+	fundRewardPool(context, reward)
+	_, err := ProcessMasternodeEpoch(context.statedb, epochHeight(2)+1)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, context.statedb.GetBalance(live).Sign(), 0)
+
+	// the nodes registered in the first slot are eligible for the other slots
+	heartbeats(t, context, live, 1, slots)
+	heartbeats(t, context, partial, 1, slots/2)
+	rewards, err := ProcessMasternodeEpoch(context.statedb, epochHeight(2))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, rewards, []*MasternodeReward{{live, reward}})
+	assert.Equal(t, context.statedb.GetBalance(live), reward)
+	assert.Equal(t, context.statedb.GetBalance(partial).Sign(), 0)
+	assert.Equal(t, context.statedb.GetBalance(MasternodeRewardPoolAddress).Sign(), 0)
+
+	nodes, err := GetMasternodes(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, nodes[0].ActiveSlots, slots-1)
+	assert.Equal(t, nodes[0].TotalSlots, slots-1)
+	assert.Equal(t, nodes[0].EpochSlots, uint64(0))
+	assert.Equal(t, nodes[0].Rewards, reward)
+	assert.Equal(t, nodes[1].ActiveSlots, slots/2-1)
+	assert.Equal(t, nodes[1].MissedEpochs, uint64(1))
+	assert.Equal(t, nodes[2].MissedEpochs, uint64(1))
+
+	// the reward is weighted by the heartbeat slots, and the missed epochs are reset
+	heartbeats(t, context, live, 2, slots)
+	heartbeats(t, context, partial, 2, slots*3/4)
+	fundRewardPool(context, big.NewInt(2100))
+	_, err = ProcessMasternodeEpoch(context.statedb, epochHeight(3))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, context.statedb.GetBalance(live), big.NewInt(3000+1200))
+	assert.Equal(t, context.statedb.GetBalance(partial), big.NewInt(900))
+
+	nodes, err = GetMasternodes(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, nodes[1].MissedEpochs, uint64(0))
+	assert.Equal(t, nodes[2].MissedEpochs, uint64(2))
+	assert.Equal(t, nodes[2].IsQuit, false)
+
+	// removed after missed too many epochs, and the pool is kept if no masternode is live
+	fundRewardPool(context, reward)
+	_, err = ProcessMasternodeEpoch(context.statedb, epochHeight(4))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, context.statedb.GetBalance(MasternodeRewardPoolAddress), reward)
+	nodes, err = GetMasternodes(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, nodes[2].IsQuit, true)
+	assert.Equal(t, nodes[2].QuitBlock, epochHeight(4))
+	assert.Equal(t, nodes[2].TotalSlots, slots*3-1)
+
+	// removed from the masternodes after recalled
+	context.tx.Data.From = offline
+	context.BlockHeader.Height = epochHeight(4) + recallDistanceLimit + 1
+	_, err = recallCmd(offline.Bytes(), context)
+	assert.Equal(t, err, nil)
+
+	nodes, err = GetMasternodes(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(nodes), 2)
+}
+
+func Test_MasternodeBeforeFork(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, MasternodeContractAddress)
+	context.BlockHeader.Height = common.SystemContractForkHeight - 1
+	context.tx.Data.Amount = depositLimit
+	node := context.tx.Data.From
+	c := GetContractByAddress(MasternodeContractAddress)
+
+	// the masternode is not indexed before the fork, and the heartbeat is invalid
+	_, err := c.Run([]byte{CmdDeposit}, context)
+	assert.Equal(t, err, nil)
+	_, err = c.Run([]byte{CmdHeartbeat}, context)
+	assert.Equal(t, err, errInvalidCommand)
+
+	nodes, err := GetMasternodes(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(nodes), 0)
+
+	// indexed at the first heartbeat after the fork
+	context.BlockHeader.Height = epochHeight(1) + 1
+	_, err = c.Run([]byte{CmdHeartbeat}, context)
+	assert.Equal(t, err, nil)
+
+	nodes, err = GetMasternodes(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(nodes), 1)
+	assert.Equal(t, nodes[0].Address, node)
+	assert.Equal(t, nodes[0].RegisterHeight, context.BlockHeader.Height)
+	assert.Equal(t, nodes[0].LastHeartbeat, context.BlockHeader.Height)
+}
+
+func Test_MaxMasternodes(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestMasternodeContext(t, db)
+	addrs := make([]common.Address, maxMasternodes)
+	for i := range addrs {
+		addrs[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+	}
+	assert.Equal(t, setMasternodes(context.statedb, addrs), nil)
+
+	// the masternodes in the index are processed at each epoch, so that it is limited
+	context.tx.Data.From = common.BytesToAddress([]byte{1, 2, 3})
+	_, err := deposit(nil, context)
+	assert.Equal(t, err, ErrTooManyMasternodes)
+}
@@ -0,0 +1,515 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package system
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/crypto"
+)
+
+const (
+	// CmdStakingBond bond the tx amount by the sender to be a verifier candidate
+	CmdStakingBond byte = iota
+	// CmdStakingDelegate delegate the tx amount to a candidate
+	CmdStakingDelegate
+	// CmdStakingUnbond unbond the stake from a candidate, which is released after the unbonding delay
+	CmdStakingUnbond
+	// CmdStakingWithdraw withdraw the released stake of the sender
+	CmdStakingWithdraw
+	// CmdGetStakingCandidate get the candidate and the stake delegated by the sender
+	CmdGetStakingCandidate
+
+	gasStakingBond         = uint64(50000) // gas used to bond
+	gasStakingDelegate     = uint64(50000) // gas used to delegate
+	gasStakingUnbond       = uint64(50000) // gas used to unbond
+	gasStakingWithdraw     = uint64(50000) // gas used to withdraw
+	gasGetStakingCandidate = uint64(5000)  // gas used to get candidate
+)
+
+var (
+	stakingCommands = map[byte]*cmdInfo{
+		CmdStakingBond:         &cmdInfo{gasStakingBond, stakingBond},
+		CmdStakingDelegate:     &cmdInfo{gasStakingDelegate, stakingDelegate},
+		CmdStakingUnbond:       &cmdInfo{gasStakingUnbond, stakingUnbond},
+		CmdStakingWithdraw:     &cmdInfo{gasStakingWithdraw, stakingWithdraw},
+		CmdGetStakingCandidate: &cmdInfo{gasGetStakingCandidate, getStakingCandidate},
+	}
+
+	// MinSelfStake is the minimum stake bonded by the candidate itself to be elected
+	MinSelfStake = new(big.Int).Mul(common.SeeleToFan, big.NewInt(10000))
+	// MaxStakingVerifiers is the number of candidates with the most stake elected as the verifiers
+	MaxStakingVerifiers = 21
+	// MaxStakingCandidates is the max number of the candidates, which are sorted at each election
+	MaxStakingCandidates = 200
+	// UnbondingDelay is the number of blocks before the unbonded stake could be withdrawn, in about one week
+	UnbondingDelay = uint64(8640 * 7)
+
+	// key of the candidate addresses in the contract storage
+	candidatesKey = crypto.HashBytes([]byte("candidates"))
+
+	candidatePrefix  = []byte("candidate")
+	delegationPrefix = []byte("delegation")
+	unbondingPrefix  = []byte("unbonding")
+)
+
+var (
+	errSelfStakeNotEnough = errors.New("self stake of the candidate is not enough")
+	errCandidateNotFound  = errors.New("candidate not found")
+	errStakeAmount        = errors.New("invalid stake amount")
+	errStakeNotEnough     = errors.New("stake delegated to the candidate is not enough")
+	errNoReleasedStake    = errors.New("no released stake to withdraw")
+	errTooManyCandidates  = errors.New("too many candidates")
+)
+
+// StakingCandidate is a verifier candidate with the stake bonded by itself and all the delegators
+type StakingCandidate struct {
+	Address      common.Address
+	SelfStake    *big.Int
+	TotalStake   *big.Int
+	CreateHeight uint64
+}
+
+// Eligible returns whether the candidate has enough self stake to be elected
+func (candidate *StakingCandidate) Eligible() bool {
+	return candidate.SelfStake.Cmp(MinSelfStake) >= 0
+}
+
+// StakingUnbond is the input to unbond the stake delegated to the candidate
+type StakingUnbond struct {
+	Candidate common.Address
+	Amount    *big.Int
+}
+
+// StakingUnbonding is the unbonded stake which could be withdrawn after the release height
+type StakingUnbonding struct {
+	Candidate     common.Address
+	Amount        *big.Int
+	ReleaseHeight uint64
+}
+
+// StakingDelegation is the candidate and the stake delegated by an account
+type StakingDelegation struct {
+	Candidate  *StakingCandidate
+	Delegated  *big.Int
+	Unbondings []*StakingUnbonding
+}
+
+// stakingBond bonds the tx amount to the sender itself, which becomes a candidate
+func stakingBond(input []byte, context *Context) ([]byte, error) {
+	sender := context.tx.Data.From
+	amount := context.tx.Data.Amount
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, errStakeAmount
+	}
+
+	candidate, err := getCandidate(context.statedb, sender)
+	if err != nil {
+		return nil, err
+	}
+
+	if candidate == nil {
+		if amount.Cmp(MinSelfStake) < 0 {
+			return nil, errSelfStakeNotEnough
+		}
+
+		candidate = &StakingCandidate{
+			Address:      sender,
+			SelfStake:    big.NewInt(0),
+			TotalStake:   big.NewInt(0),
+			CreateHeight: context.BlockHeader.Height,
+		}
+
+		if err = addCandidate(context.statedb, sender); err != nil {
+			return nil, err
+		}
+	}
+
+	candidate.SelfStake = new(big.Int).Add(candidate.SelfStake, amount)
+	return nil, delegate(context.statedb, candidate, sender, amount)
+}
+
+// stakingDelegate delegates the tx amount to the candidate of the input address
+func stakingDelegate(input []byte, context *Context) ([]byte, error) {
+	amount := context.tx.Data.Amount
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, errStakeAmount
+	}
+
+	if len(input) != common.AddressLen {
+		return nil, errInvalidAddress
+	}
+
+	candidate, err := getCandidate(context.statedb, common.BytesToAddress(input))
+	if err != nil {
+		return nil, err
+	}
+
+	if candidate == nil {
+		return nil, errCandidateNotFound
+	}
+
+	if candidate.Address.Equal(context.tx.Data.From) {
+		candidate.SelfStake = new(big.Int).Add(candidate.SelfStake, amount)
+	}
+
+	return nil, delegate(context.statedb, candidate, context.tx.Data.From, amount)
+}
+
+// stakingUnbond unbonds the stake delegated to the candidate, which is locked until the unbonding delay passed
+func stakingUnbond(input []byte, context *Context) ([]byte, error) {
+	var unbond StakingUnbond
+	if err := json.Unmarshal(input, &unbond); err != nil {
+		return nil, err
+	}
+
+	if unbond.Amount == nil || unbond.Amount.Sign() <= 0 {
+		return nil, errStakeAmount
+	}
+
+	sender := context.tx.Data.From
+	candidate, err := getCandidate(context.statedb, unbond.Candidate)
+	if err != nil {
+		return nil, err
+	}
+
+	if candidate == nil {
+		return nil, errCandidateNotFound
+	}
+
+	delegated := getDelegation(context.statedb, unbond.Candidate, sender)
+	if delegated.Cmp(unbond.Amount) < 0 {
+		return nil, errStakeNotEnough
+	}
+
+	// the candidate is not elected any more if the self stake is less than the minimum
+	if candidate.Address.Equal(sender) {
+		candidate.SelfStake = new(big.Int).Sub(candidate.SelfStake, unbond.Amount)
+	}
+
+	// the candidate is removed once all the stake is unbonded, and could bond again as a new candidate
+	candidate.TotalStake = new(big.Int).Sub(candidate.TotalStake, unbond.Amount)
+	if candidate.TotalStake.Sign() == 0 {
+		err = removeCandidate(context.statedb, candidate.Address)
+	} else {
+		err = setCandidate(context.statedb, candidate)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	setDelegation(context.statedb, unbond.Candidate, sender, delegated.Sub(delegated, unbond.Amount))
+
+	unbondings, err := getUnbondings(context.statedb, sender)
+	if err != nil {
+		return nil, err
+	}
+
+	unbondings = append(unbondings, &StakingUnbonding{
+		Candidate:     unbond.Candidate,
+		Amount:        unbond.Amount,
+		ReleaseHeight: context.BlockHeader.Height + UnbondingDelay,
+	})
+
+	return nil, setUnbondings(context.statedb, sender, unbondings)
+}
+
+// stakingWithdraw withdraws all the released stake of the sender
+func stakingWithdraw(input []byte, context *Context) ([]byte, error) {
+	sender := context.tx.Data.From
+	unbondings, err := getUnbondings(context.statedb, sender)
+	if err != nil {
+		return nil, err
+	}
+
+	released := big.NewInt(0)
+	locked := make([]*StakingUnbonding, 0, len(unbondings))
+	for _, unbonding := range unbondings {
+		if unbonding.ReleaseHeight <= context.BlockHeader.Height {
+			released.Add(released, unbonding.Amount)
+		} else {
+			locked = append(locked, unbonding)
+		}
+	}
+
+	if released.Sign() == 0 {
+		return nil, errNoReleasedStake
+	}
+
+	if err = setUnbondings(context.statedb, sender, locked); err != nil {
+		return nil, err
+	}
+
+	context.statedb.SubBalance(StakingContractAddress, released)
+	context.statedb.AddBalance(sender, released)
+
+	return released.Bytes(), nil
+}
+
+// getStakingCandidate returns the candidate of the input address with the stake delegated by the sender
+func getStakingCandidate(input []byte, context *Context) ([]byte, error) {
+	if len(input) != common.AddressLen {
+		return nil, errInvalidAddress
+	}
+
+	delegation, err := GetStakingDelegation(context.statedb, common.BytesToAddress(input), context.tx.Data.From)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(delegation)
+}
+
+// GetStakingDelegation returns the candidate with the stake delegated and unbonding by the delegator
+func GetStakingDelegation(statedb *state.Statedb, candidateAddr, delegator common.Address) (*StakingDelegation, error) {
+	candidate, err := getCandidate(statedb, candidateAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if candidate == nil {
+		return nil, errCandidateNotFound
+	}
+
+	unbondings, err := getUnbondings(statedb, delegator)
+	if err != nil {
+		return nil, err
+	}
+
+	delegation := &StakingDelegation{
+		Candidate: candidate,
+		Delegated: getDelegation(statedb, candidateAddr, delegator),
+	}
+
+	for _, unbonding := range unbondings {
+		if unbonding.Candidate.Equal(candidateAddr) {
+			delegation.Unbondings = append(delegation.Unbondings, unbonding)
+		}
+	}
+
+	return delegation, nil
+}
+
+// GetStakingCandidates returns all the candidates sorted by the total stake in descending order
+func GetStakingCandidates(statedb *state.Statedb) ([]*StakingCandidate, error) {
+	addrs, err := getCandidateAddresses(statedb)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*StakingCandidate, 0, len(addrs))
+	for _, addr := range addrs {
+		candidate, err := getCandidate(statedb, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if candidate != nil {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	// the candidate with the smaller address wins with the same stake
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if cmp := candidates[i].TotalStake.Cmp(candidates[j].TotalStake); cmp != 0 {
+			return cmp > 0
+		}
+
+		return bytes.Compare(candidates[i].Address.Bytes(), candidates[j].Address.Bytes()) < 0
+	})
+
+	return candidates, nil
+}
+
+// ElectVerifiers returns at most MaxStakingVerifiers eligible candidates with the most stake,
+// which is empty if no candidate is eligible.
+func ElectVerifiers(statedb *state.Statedb) ([]common.Address, error) {
+	candidates, err := GetStakingCandidates(statedb)
+	if err != nil {
+		return nil, err
+	}
+
+	var verifiers []common.Address
+	for _, candidate := range candidates {
+		if len(verifiers) == MaxStakingVerifiers {
+			break
+		}
+
+		if candidate.Eligible() {
+			verifiers = append(verifiers, candidate.Address)
+		}
+	}
+
+	return verifiers, nil
+}
+
+// VerifierChanges returns the verifiers to add and remove from the current verifiers to the elected ones
+func VerifierChanges(current, elected []common.Address) (added []common.Address, removed []common.Address) {
+	contains := func(addrs []common.Address, addr common.Address) bool {
+		for _, a := range addrs {
+			if a.Equal(addr) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, addr := range elected {
+		if !contains(current, addr) {
+			added = append(added, addr)
+		}
+	}
+
+	for _, addr := range current {
+		if !contains(elected, addr) {
+			removed = append(removed, addr)
+		}
+	}
+
+	return added, removed
+}
+
+func delegate(statedb *state.Statedb, candidate *StakingCandidate, delegator common.Address, amount *big.Int) error {
+	candidate.TotalStake = new(big.Int).Add(candidate.TotalStake, amount)
+	if err := setCandidate(statedb, candidate); err != nil {
+		return err
+	}
+
+	delegated := getDelegation(statedb, candidate.Address, delegator)
+	setDelegation(statedb, candidate.Address, delegator, delegated.Add(delegated, amount))
+
+	return nil
+}
+
+func getCandidate(statedb *state.Statedb, addr common.Address) (*StakingCandidate, error) {
+	value := statedb.GetData(StakingContractAddress, crypto.HashBytes(candidatePrefix, addr.Bytes()))
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	candidate := new(StakingCandidate)
+	if err := json.Unmarshal(value, candidate); err != nil {
+		return nil, err
+	}
+
+	return candidate, nil
+}
+
+func setCandidate(statedb *state.Statedb, candidate *StakingCandidate) error {
+	value, err := json.Marshal(candidate)
+	if err != nil {
+		return err
+	}
+
+	statedb.SetData(StakingContractAddress, crypto.HashBytes(candidatePrefix, candidate.Address.Bytes()), value)
+	return nil
+}
+
+func getCandidateAddresses(statedb *state.Statedb) ([]common.Address, error) {
+	value := statedb.GetData(StakingContractAddress, candidatesKey)
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	var addrs []common.Address
+	if err := json.Unmarshal(value, &addrs); err != nil {
+		return nil, err
+	}
+
+	return addrs, nil
+}
+
+func setCandidateAddresses(statedb *state.Statedb, addrs []common.Address) error {
+	var value []byte
+	if len(addrs) > 0 {
+		var err error
+		if value, err = json.Marshal(addrs); err != nil {
+			return err
+		}
+	}
+
+	statedb.SetData(StakingContractAddress, candidatesKey, value)
+	return nil
+}
+
+func addCandidate(statedb *state.Statedb, addr common.Address) error {
+	addrs, err := getCandidateAddresses(statedb)
+	if err != nil {
+		return err
+	}
+
+	if len(addrs) >= MaxStakingCandidates {
+		return errTooManyCandidates
+	}
+
+	return setCandidateAddresses(statedb, append(addrs, addr))
+}
+
+func removeCandidate(statedb *state.Statedb, addr common.Address) error {
+	addrs, err := getCandidateAddresses(statedb)
+	if err != nil {
+		return err
+	}
+
+	for i, a := range addrs {
+		if a.Equal(addr) {
+			addrs = append(addrs[:i], addrs[i+1:]...)
+			break
+		}
+	}
+
+	statedb.SetData(StakingContractAddress, crypto.HashBytes(candidatePrefix, addr.Bytes()), nil)
+	return setCandidateAddresses(statedb, addrs)
+}
+
+func getDelegation(statedb *state.Statedb, candidate, delegator common.Address) *big.Int {
+	value := statedb.GetData(StakingContractAddress, crypto.HashBytes(delegationPrefix, candidate.Bytes(), delegator.Bytes()))
+	return new(big.Int).SetBytes(value)
+}
+
+func setDelegation(statedb *state.Statedb, candidate, delegator common.Address, amount *big.Int) {
+	var value []byte
+	if amount.Sign() > 0 {
+		value = amount.Bytes()
+	}
+
+	statedb.SetData(StakingContractAddress, crypto.HashBytes(delegationPrefix, candidate.Bytes(), delegator.Bytes()), value)
+}
+
+func getUnbondings(statedb *state.Statedb, delegator common.Address) ([]*StakingUnbonding, error) {
+	value := statedb.GetData(StakingContractAddress, crypto.HashBytes(unbondingPrefix, delegator.Bytes()))
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	var unbondings []*StakingUnbonding
+	if err := json.Unmarshal(value, &unbondings); err != nil {
+		return nil, err
+	}
+
+	return unbondings, nil
+}
+
+func setUnbondings(statedb *state.Statedb, delegator common.Address, unbondings []*StakingUnbonding) error {
+	var value []byte
+	if len(unbondings) > 0 {
+		var err error
+		if value, err = json.Marshal(unbondings); err != nil {
+			return err
+		}
+	}
+
+	statedb.SetData(StakingContractAddress, crypto.HashBytes(unbondingPrefix, delegator.Bytes()), value)
+	return nil
+}
@@ -0,0 +1,176 @@
+/**
+* @file
+* @copyright defined in go-seele/LICENSE
+ */
+
+package system
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/database/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func stakeAs(context *Context, from common.Address, amount *big.Int) *Context {
+	context.tx.Data.From = from
+	context.tx.Data.Amount = amount
+	return context
+}
+
+func Test_StakingBondAndDelegate(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, StakingContractAddress)
+	candidate, delegator := common.BytesToAddress([]byte{1}), common.BytesToAddress([]byte{2})
+
+	// self stake is not enough
+	_, err := stakingBond(nil, stakeAs(context, candidate, big.NewInt(1)))
+	assert.Equal(t, err, errSelfStakeNotEnough)
+
+	_, err = stakingBond(nil, stakeAs(context, candidate, MinSelfStake))
+	assert.Equal(t, err, nil)
+
+	// delegate to an unknown candidate
+	_, err = stakingDelegate(delegator.Bytes(), stakeAs(context, delegator, big.NewInt(100)))
+	assert.Equal(t, err, errCandidateNotFound)
+
+	_, err = stakingDelegate(candidate.Bytes(), stakeAs(context, delegator, big.NewInt(100)))
+	assert.Equal(t, err, nil)
+
+	result, err := getStakingCandidate(candidate.Bytes(), context)
+	assert.Equal(t, err, nil)
+
+	var delegation StakingDelegation
+	assert.Equal(t, json.Unmarshal(result, &delegation), nil)
+	assert.Equal(t, delegation.Candidate.SelfStake.Cmp(MinSelfStake), 0)
+	assert.Equal(t, delegation.Candidate.TotalStake.Cmp(new(big.Int).Add(MinSelfStake, big.NewInt(100))), 0)
+	assert.Equal(t, delegation.Delegated.Cmp(big.NewInt(100)), 0)
+}
+
+func Test_StakingUnbondAndWithdraw(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, StakingContractAddress)
+	candidate := common.BytesToAddress([]byte{1})
+	context.statedb.CreateAccount(candidate)
+	context.statedb.AddBalance(StakingContractAddress, MinSelfStake)
+
+	_, err := stakingBond(nil, stakeAs(context, candidate, MinSelfStake))
+	assert.Equal(t, err, nil)
+
+	// unbond more than delegated
+	unbond, _ := json.Marshal(StakingUnbond{Candidate: candidate, Amount: new(big.Int).Add(MinSelfStake, big.NewInt(1))})
+	_, err = stakingUnbond(unbond, stakeAs(context, candidate, big.NewInt(0)))
+	assert.Equal(t, err, errStakeNotEnough)
+
+	// the candidate is not eligible after the self stake unbonded
+	unbond, _ = json.Marshal(StakingUnbond{Candidate: candidate, Amount: big.NewInt(1)})
+	_, err = stakingUnbond(unbond, context)
+	assert.Equal(t, err, nil)
+
+	elected, err := ElectVerifiers(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(elected), 0)
+
+	// not released yet
+	_, err = stakingWithdraw(nil, context)
+	assert.Equal(t, err, errNoReleasedStake)
+
+	context.BlockHeader.Height += UnbondingDelay
+	_, err = stakingWithdraw(nil, context)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, context.statedb.GetBalance(candidate).Cmp(big.NewInt(1)), 0)
+
+	delegation, err := GetStakingDelegation(context.statedb, candidate, candidate)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(delegation.Unbondings), 0)
+	assert.Equal(t, delegation.Delegated.Cmp(new(big.Int).Sub(MinSelfStake, big.NewInt(1))), 0)
+}
+
+func Test_ElectVerifiers(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, StakingContractAddress)
+	defer func(max int) { MaxStakingVerifiers = max }(MaxStakingVerifiers)
+	MaxStakingVerifiers = 2
+
+	a, b, c := common.BytesToAddress([]byte{1}), common.BytesToAddress([]byte{2}), common.BytesToAddress([]byte{3})
+	for _, addr := range []common.Address{c, b, a} {
+		_, err := stakingBond(nil, stakeAs(context, addr, MinSelfStake))
+		assert.Equal(t, err, nil)
+	}
+
+	// the smaller address wins with the same stake
+	elected, err := ElectVerifiers(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, elected, []common.Address{a, b})
+
+	// the delegated stake counts
+	_, err = stakingDelegate(c.Bytes(), stakeAs(context, common.BytesToAddress([]byte{4}), big.NewInt(1)))
+	assert.Equal(t, err, nil)
+
+	elected, err = ElectVerifiers(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, elected, []common.Address{c, a})
+
+	added, removed := VerifierChanges([]common.Address{a, b}, elected)
+	assert.Equal(t, added, []common.Address{c})
+	assert.Equal(t, removed, []common.Address{b})
+}
+
+func Test_StakingCandidates(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, StakingContractAddress)
+	defer func(max int) { MaxStakingCandidates = max }(MaxStakingCandidates)
+	MaxStakingCandidates = 2
+
+	a, b, c := common.BytesToAddress([]byte{1}), common.BytesToAddress([]byte{2}), common.BytesToAddress([]byte{3})
+	for _, addr := range []common.Address{a, b} {
+		_, err := stakingBond(nil, stakeAs(context, addr, MinSelfStake))
+		assert.Equal(t, err, nil)
+	}
+
+	_, err := stakingBond(nil, stakeAs(context, c, MinSelfStake))
+	assert.Equal(t, err, errTooManyCandidates)
+
+	// the candidate is removed after all the stake unbonded
+	unbond, _ := json.Marshal(StakingUnbond{Candidate: a, Amount: MinSelfStake})
+	_, err = stakingUnbond(unbond, stakeAs(context, a, big.NewInt(0)))
+	assert.Equal(t, err, nil)
+
+	candidates, err := GetStakingCandidates(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(candidates), 1)
+	assert.Equal(t, candidates[0].Address, b)
+
+	_, err = GetStakingDelegation(context.statedb, a, a)
+	assert.Equal(t, err, errCandidateNotFound)
+
+	_, err = stakingBond(nil, stakeAs(context, c, MinSelfStake))
+	assert.Equal(t, err, nil)
+}
+
+func Test_StakingBeforeFork(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	// the staking contract address is a plain account before the fork height
+	height := uint64(common.SystemContractForkHeight)
+	assert.Equal(t, GetContractByHeight(StakingContractAddress, height-1) == nil, true)
+
+	context := newTestContext(db, StakingContractAddress)
+	context.BlockHeader.Height = height
+	c := GetContractByHeight(StakingContractAddress, height)
+
+	_, err := c.Run([]byte{CmdStakingBond}, stakeAs(context, common.BytesToAddress([]byte{1}), MinSelfStake))
+	assert.Equal(t, err, nil)
+}
@@ -6,10 +6,14 @@
 package system
 
 import (
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"math/big"
 
 	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/crypto"
 	"github.com/seeleteam/go-seele/p2p/discovery"
 )
 
@@ -18,14 +22,63 @@ const (
 	CmdSubChainRegister byte = iota
 	// CmdSubChainQuery query a sub-chain.
 	CmdSubChainQuery
+	// CmdSubChainUpdate update the metadata of a sub-chain by the owner.
+	CmdSubChainUpdate
+	// CmdSubChainUpdateVerifiers replace the verifiers of a sub-chain approved by the current verifiers.
+	CmdSubChainUpdateVerifiers
+	// CmdSubChainPause pause a sub-chain by the owner.
+	CmdSubChainPause
+	// CmdSubChainResume resume a paused sub-chain by the owner.
+	CmdSubChainResume
+	// CmdSubChainDeregister deregister a sub-chain by the owner, which could not be resumed.
+	CmdSubChainDeregister
 
-	gasSubChainRegister = uint64(100000) // gas to register a sub-chain.
-	gasSubChainQuery    = uint64(200000) // gas to query sub-chain information.
+	gasSubChainRegister        = uint64(100000) // gas to register a sub-chain.
+	gasSubChainQuery           = uint64(200000) // gas to query sub-chain information.
+	gasSubChainUpdate          = uint64(50000)  // gas to update sub-chain metadata.
+	gasSubChainUpdateVerifiers = uint64(100000) // gas to replace the verifiers.
+	gasSubChainApproval        = uint64(3000)   // gas to recover the signer of each approval, charged by the signatures.
+	gasSubChainStatus          = uint64(20000)  // gas to pause, resume or deregister a sub-chain.
+)
+
+const (
+	// SubChainStatusActive the sub-chain is active
+	SubChainStatusActive = "active"
+	// SubChainStatusPaused the sub-chain is paused by the owner, and could be resumed
+	SubChainStatusPaused = "paused"
+	// SubChainStatusDeregistered the sub-chain is deregistered by the owner. The name is
+	// not released, so that a new sub-chain could not be confused with the deregistered one.
+	SubChainStatusDeregistered = "deregistered"
+
+	// maxSubChainVerifiers is the max number of verifiers of a sub-chain
+	maxSubChainVerifiers = 64
 )
 
 var (
+	errSubChainNotFound     = errors.New("sub-chain not found")
+	errSubChainDeregistered = errors.New("sub-chain is deregistered")
+	errSubChainStatus       = errors.New("invalid sub-chain status for the operation")
+	errInvalidVerifiers     = errors.New("invalid verifiers, it should be unique and no more than 64")
+	errVerifierNonce        = errors.New("verifier nonce mismatch")
+	errVerifierApprovals    = errors.New("not enough approvals from the current verifiers")
+	errTooManyApprovals     = errors.New("more approvals than the current verifiers")
+
+	// key of the sub-chain names index in the contract storage
+	subChainNamesKey = crypto.HashBytes([]byte("names"))
+
 	subChainCommands = map[byte]*cmdInfo{
-		CmdSubChainRegister: &cmdInfo{gasSubChainRegister, registerSubChain},
+		CmdSubChainRegister:        &cmdInfo{gasSubChainRegister, registerSubChain},
+		CmdSubChainQuery:           &cmdInfo{gasSubChainQuery, querySubChain},
+		CmdSubChainUpdate:          &cmdInfo{gasSubChainUpdate, updateSubChain},
+		CmdSubChainUpdateVerifiers: &cmdInfo{gasSubChainUpdateVerifiers, updateSubChainVerifiers},
+		CmdSubChainPause:           &cmdInfo{gasSubChainStatus, pauseSubChain},
+		CmdSubChainResume:          &cmdInfo{gasSubChainStatus, resumeSubChain},
+		CmdSubChainDeregister:      &cmdInfo{gasSubChainStatus, deregisterSubChain},
+	}
+
+	// legacySubChainCommands are the commands before the system contract fork height
+	legacySubChainCommands = map[byte]*cmdInfo{
+		CmdSubChainRegister: &cmdInfo{gasSubChainRegister, registerLegacySubChain},
 		CmdSubChainQuery:    &cmdInfo{gasSubChainQuery, querySubChain},
 	}
 )
@@ -46,6 +99,78 @@ type SubChainInfo struct {
 
 	// SubChain owner publick key
 	Owner common.Address `json:"owner,omitempty"`
+
+	// Endpoints are the rpc endpoints of the sub-chain
+	Endpoints []string `json:"endpoints,omitempty"`
+	// GenesisHash is the hash of the sub-chain genesis block, which is known after the sub-chain starts
+	GenesisHash common.Hash `json:"genesisHash"`
+
+	// Verifiers approve the changes of the verifiers, and VerifierNonce is increased by each change
+	Verifiers     []common.Address `json:"verifiers,omitempty"`
+	VerifierNonce uint64           `json:"verifierNonce,omitempty"`
+
+	// Status is empty for the sub-chains registered before the status is introduced, which are active
+	Status          string   `json:"status,omitempty"`
+	UpdateTimestamp *big.Int `json:"updateTimestamp,omitempty"`
+}
+
+// CurrentStatus returns the status of the sub-chain
+func (info *SubChainInfo) CurrentStatus() string {
+	if len(info.Status) == 0 {
+		return SubChainStatusActive
+	}
+
+	return info.Status
+}
+
+// SubChainUpdate is the input to update the metadata of a sub-chain, and the empty fields are not changed.
+type SubChainUpdate struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version,omitempty"`
+	StaticNodes []*discovery.Node `json:"staticNodes,omitempty"`
+	Endpoints   []string          `json:"endpoints,omitempty"`
+	GenesisHash common.Hash       `json:"genesisHash"`
+}
+
+// SubChainVerifierUpdate is the input to replace the verifiers of a sub-chain. The signatures
+// are signed by the current verifiers on the hash of SubChainVerifiersHash.
+type SubChainVerifierUpdate struct {
+	Name       string             `json:"name"`
+	Verifiers  []common.Address   `json:"verifiers"`
+	Nonce      uint64             `json:"nonce"`
+	Signatures []crypto.Signature `json:"signatures"`
+}
+
+// legacySubChainInfo is the sub-chain registration information before the system contract fork height
+type legacySubChainInfo struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	StaticNodes []*discovery.Node `json:"staticNodes"`
+
+	TokenFullName  string `json:"tokenFullName"`
+	TokenShortName string `json:"tokenShortName"`
+	TokenAmount    uint64 `json:"tokenAmount"`
+
+	GenesisDifficulty uint64                      `json:"genesisDifficulty"`
+	GenesisAccounts   map[common.Address]*big.Int `json:"genesisAccounts"`
+	CreateTimestamp   *big.Int                    `json:"timestamp,omitempty"`
+
+	// SubChain owner publick key
+	Owner common.Address `json:"owner,omitempty"`
+}
+
+// SubChainVerifiersHash returns the hash for the current verifiers to approve the new verifiers.
+// The shard is the shard of the sub-chain owner, where the sub-chain is registered, so that the
+// approvals could not be replayed on the sub-chain contract of other shards or networks.
+func SubChainVerifiersHash(shard uint, name string, nonce uint64, verifiers []common.Address) common.Hash {
+	data := [][]byte{SubChainContractAddress.Bytes(), make([]byte, 8), []byte(name), make([]byte, 8)}
+	binary.BigEndian.PutUint64(data[1], uint64(shard))
+	binary.BigEndian.PutUint64(data[3], nonce)
+	for _, verifier := range verifiers {
+		data = append(data, verifier.Bytes())
+	}
+
+	return crypto.HashBytes(data...)
 }
 
 func registerSubChain(jsonRegInfo []byte, context *Context) ([]byte, error) {
@@ -68,6 +193,46 @@ func registerSubChain(jsonRegInfo []byte, context *Context) ([]byte, error) {
 		return nil, errInvalidSubChainInfo
 	}
 
+	if len(info.Verifiers) > 0 && !validVerifiers(info.Verifiers) {
+		return nil, errInvalidVerifiers
+	}
+
+	// set transaction sender to subchain owner
+	info.Owner = context.tx.Data.From
+	info.CreateTimestamp = context.BlockHeader.CreateTimestamp
+	info.VerifierNonce = 0
+	info.Status = SubChainStatusActive
+	info.UpdateTimestamp = nil
+
+	context.statedb.CreateAccount(SubChainContractAddress)
+	if err = setSubChain(context.statedb, key, &info); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// registerLegacySubChain registers the sub-chain without the status and names index before the fork height
+func registerLegacySubChain(jsonRegInfo []byte, context *Context) ([]byte, error) {
+	var info legacySubChainInfo
+	if err := json.Unmarshal(jsonRegInfo, &info); err != nil {
+		return nil, err
+	}
+
+	key, err := domainNameToKey([]byte(info.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	if value := context.statedb.GetData(SubChainContractAddress, key); len(value) > 0 {
+		return nil, errExists
+	}
+
+	// validate the reg info
+	if len(info.Version) == 0 || len(info.TokenFullName) == 0 || len(info.TokenShortName) == 0 || info.TokenAmount == 0 {
+		return nil, errInvalidSubChainInfo
+	}
+
 	// set transaction sender to subchain owner
 	info.Owner = context.tx.Data.From
 	info.CreateTimestamp = context.BlockHeader.CreateTimestamp
@@ -91,3 +256,282 @@ func querySubChain(subChainName []byte, context *Context) ([]byte, error) {
 
 	return context.statedb.GetData(SubChainContractAddress, key), nil
 }
+
+// updateSubChain updates the version, static nodes, endpoints or genesis hash of the sub-chain
+func updateSubChain(input []byte, context *Context) ([]byte, error) {
+	var update SubChainUpdate
+	if err := json.Unmarshal(input, &update); err != nil {
+		return nil, err
+	}
+
+	info, key, err := ownedSubChain(context, update.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.CurrentStatus() == SubChainStatusDeregistered {
+		return nil, errSubChainDeregistered
+	}
+
+	if len(update.Version) > 0 {
+		info.Version = update.Version
+	}
+
+	if len(update.StaticNodes) > 0 {
+		info.StaticNodes = update.StaticNodes
+	}
+
+	if len(update.Endpoints) > 0 {
+		info.Endpoints = update.Endpoints
+	}
+
+	if !update.GenesisHash.IsEmpty() {
+		info.GenesisHash = update.GenesisHash
+	}
+
+	return updatedSubChain(context, key, info)
+}
+
+// updateSubChainVerifiers replaces the verifiers approved by more than 2/3 of the current verifiers,
+// or by the owner if there are no verifiers yet.
+func updateSubChainVerifiers(input []byte, context *Context) ([]byte, error) {
+	var update SubChainVerifierUpdate
+	if err := json.Unmarshal(input, &update); err != nil {
+		return nil, err
+	}
+
+	info, key, err := getSubChainByName(context.statedb, update.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.CurrentStatus() == SubChainStatusDeregistered {
+		return nil, errSubChainDeregistered
+	}
+
+	if !validVerifiers(update.Verifiers) || len(update.Verifiers) == 0 {
+		return nil, errInvalidVerifiers
+	}
+
+	if update.Nonce != info.VerifierNonce {
+		return nil, errVerifierNonce
+	}
+
+	if len(info.Verifiers) == 0 {
+		if !info.Owner.Equal(context.tx.Data.From) {
+			return nil, errNotOwner
+		}
+	} else {
+		hash := SubChainVerifiersHash(info.Owner.Shard(), info.Name, update.Nonce, update.Verifiers)
+		approvals, err := countApprovals(context, info.Verifiers, hash, update.Signatures)
+		if err != nil {
+			return nil, err
+		}
+
+		if approvals < len(info.Verifiers)*2/3+1 {
+			return nil, errVerifierApprovals
+		}
+	}
+
+	info.Verifiers = update.Verifiers
+	info.VerifierNonce++
+	return updatedSubChain(context, key, info)
+}
+
+// pauseSubChain pauses the active sub-chain
+func pauseSubChain(subChainName []byte, context *Context) ([]byte, error) {
+	return changeSubChainStatus(context, subChainName, SubChainStatusActive, SubChainStatusPaused)
+}
+
+// resumeSubChain resumes the paused sub-chain
+func resumeSubChain(subChainName []byte, context *Context) ([]byte, error) {
+	return changeSubChainStatus(context, subChainName, SubChainStatusPaused, SubChainStatusActive)
+}
+
+// deregisterSubChain deregisters the active or paused sub-chain
+func deregisterSubChain(subChainName []byte, context *Context) ([]byte, error) {
+	return changeSubChainStatus(context, subChainName, "", SubChainStatusDeregistered)
+}
+
+// changeSubChainStatus changes the status of the sub-chain owned by the sender, from any status
+// except the deregistered one if from is empty.
+func changeSubChainStatus(context *Context, subChainName []byte, from, to string) ([]byte, error) {
+	info, key, err := ownedSubChain(context, string(subChainName))
+	if err != nil {
+		return nil, err
+	}
+
+	status := info.CurrentStatus()
+	if status == SubChainStatusDeregistered {
+		return nil, errSubChainDeregistered
+	}
+
+	if len(from) > 0 && status != from {
+		return nil, errSubChainStatus
+	}
+
+	info.Status = to
+	return updatedSubChain(context, key, info)
+}
+
+// GetSubChain returns the registration information of the sub-chain
+func GetSubChain(statedb *state.Statedb, name string) (*SubChainInfo, error) {
+	info, _, err := getSubChainByName(statedb, name)
+	return info, err
+}
+
+// ListSubChains returns all the registered sub-chains with their current status. The sub-chains
+// registered before the system contract fork height are only listed after they are updated.
+func ListSubChains(statedb *state.Statedb) ([]*SubChainInfo, error) {
+	names, err := getSubChainNames(statedb)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*SubChainInfo, 0, len(names))
+	for _, name := range names {
+		info, err := GetSubChain(statedb, name)
+		if err != nil {
+			return nil, err
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// ownedSubChain returns the sub-chain owned by the sender
+func ownedSubChain(context *Context, name string) (*SubChainInfo, common.Hash, error) {
+	info, key, err := getSubChainByName(context.statedb, name)
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+
+	if !info.Owner.Equal(context.tx.Data.From) {
+		return nil, common.EmptyHash, errNotOwner
+	}
+
+	return info, key, nil
+}
+
+func getSubChainByName(statedb *state.Statedb, name string) (*SubChainInfo, common.Hash, error) {
+	key, err := domainNameToKey([]byte(name))
+	if err != nil {
+		return nil, common.EmptyHash, err
+	}
+
+	value := statedb.GetData(SubChainContractAddress, key)
+	if len(value) == 0 {
+		return nil, common.EmptyHash, errSubChainNotFound
+	}
+
+	info := new(SubChainInfo)
+	if err = json.Unmarshal(value, info); err != nil {
+		return nil, common.EmptyHash, err
+	}
+
+	return info, key, nil
+}
+
+// updatedSubChain saves the updated sub-chain, and returns the encoded information
+func updatedSubChain(context *Context, key common.Hash, info *SubChainInfo) ([]byte, error) {
+	info.UpdateTimestamp = context.BlockHeader.CreateTimestamp
+	if err := setSubChain(context.statedb, key, info); err != nil {
+		return nil, err
+	}
+
+	return context.statedb.GetData(SubChainContractAddress, key), nil
+}
+
+// setSubChain saves the sub-chain, and adds it to the names index if not yet
+func setSubChain(statedb *state.Statedb, key common.Hash, info *SubChainInfo) error {
+	value, err := json.MarshalIndent(info, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	statedb.SetData(SubChainContractAddress, key, value)
+
+	names, err := getSubChainNames(statedb)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if name == info.Name {
+			return nil
+		}
+	}
+
+	value, err = json.Marshal(append(names, info.Name))
+	if err != nil {
+		return err
+	}
+
+	statedb.SetData(SubChainContractAddress, subChainNamesKey, value)
+	return nil
+}
+
+func getSubChainNames(statedb *state.Statedb) ([]string, error) {
+	value := statedb.GetData(SubChainContractAddress, subChainNamesKey)
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	var names []string
+	if err := json.Unmarshal(value, &names); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// validVerifiers returns true if the verifiers are unique and no more than maxSubChainVerifiers
+func validVerifiers(verifiers []common.Address) bool {
+	if len(verifiers) > maxSubChainVerifiers {
+		return false
+	}
+
+	for i, verifier := range verifiers {
+		if verifier.IsEmpty() {
+			return false
+		}
+
+		for _, other := range verifiers[:i] {
+			if other.Equal(verifier) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// countApprovals returns the number of the distinct verifiers that signed the hash. The gas is
+// charged by the signatures, which are no more than the verifiers, and each signer is recovered once.
+func countApprovals(context *Context, verifiers []common.Address, hash common.Hash, signatures []crypto.Signature) (int, error) {
+	if len(signatures) > len(verifiers) {
+		return 0, errTooManyApprovals
+	}
+
+	if err := context.useGas(uint64(len(signatures)) * gasSubChainApproval); err != nil {
+		return 0, err
+	}
+
+	pending := make(map[common.Address]bool)
+	for _, verifier := range verifiers {
+		pending[verifier] = true
+	}
+
+	approvals := 0
+	for _, sig := range signatures {
+		signer, err := sig.Signer(hash.Bytes())
+		if err == nil && pending[signer] {
+			delete(pending, signer)
+			approvals++
+		}
+	}
+
+	return approvals, nil
+}
@@ -6,6 +6,7 @@
 package system
 
 import (
+	"crypto/ecdsa"
 	"encoding/json"
 	"math/big"
 	"testing"
@@ -37,6 +38,7 @@ func Test_RegisterSubChain(t *testing.T) {
 
 	regInfo.Owner = context.tx.Data.From
 	regInfo.CreateTimestamp = context.BlockHeader.CreateTimestamp
+	regInfo.Status = SubChainStatusActive
 
 	encoded, err := json.Marshal(regInfo)
 	if err != nil {
@@ -65,3 +67,245 @@ func Test_RegisterSubChain(t *testing.T) {
 	assert.Equal(t, result, []byte(nil))
 	assert.Equal(t, err, errExists)
 }
+
+func Test_RegisterSubChain_BeforeFork(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, SubChainContractAddress)
+	context.BlockHeader.Height = common.SystemContractForkHeight - 1
+	c := GetContractByAddress(SubChainContractAddress)
+
+	// the status and names index are not saved before the fork
+	encoded, err := json.Marshal(SubChainInfo{Name: "test", Version: "1.0", TokenFullName: "TestCoin", TokenShortName: "TC", TokenAmount: 100, Status: SubChainStatusPaused})
+	assert.Equal(t, err, nil)
+	_, err = c.Run(append([]byte{CmdSubChainRegister}, encoded...), context)
+	assert.Equal(t, err, nil)
+
+	result, err := c.Run(append([]byte{CmdSubChainQuery}, []byte("test")...), context)
+	assert.Equal(t, err, nil)
+	var stored map[string]interface{}
+	assert.Equal(t, json.Unmarshal(result, &stored), nil)
+	assert.Equal(t, len(stored), 10)
+	assert.Equal(t, stored["status"], nil)
+
+	infos, err := ListSubChains(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(infos), 0)
+
+	// the commands introduced by the fork are invalid
+	_, err = c.Run(append([]byte{CmdSubChainPause}, []byte("test")...), context)
+	assert.Equal(t, err, errInvalidCommand)
+
+	// the sub-chain is listed once updated after the fork
+	context.BlockHeader.Height = common.SystemContractForkHeight
+	_, err = c.Run(append([]byte{CmdSubChainPause}, []byte("test")...), context)
+	assert.Equal(t, err, nil)
+	infos, err = ListSubChains(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(infos), 1)
+	assert.Equal(t, infos[0].Status, SubChainStatusPaused)
+}
+
+func Test_SubChainVerifiersHash(t *testing.T) {
+	verifiers := []common.Address{*crypto.MustGenerateShardAddress(1)}
+	hash := SubChainVerifiersHash(1, "test", 1, verifiers)
+
+	// the hash is different for other shards, names or nonces
+	assert.NotEqual(t, SubChainVerifiersHash(2, "test", 1, verifiers), hash)
+	assert.NotEqual(t, SubChainVerifiersHash(1, "test2", 1, verifiers), hash)
+	assert.NotEqual(t, SubChainVerifiersHash(1, "test", 2, verifiers), hash)
+	assert.Equal(t, SubChainVerifiersHash(1, "test", 1, verifiers), hash)
+}
+
+func newTestSubChain(t *testing.T, context *Context, name string) {
+	encoded, err := json.Marshal(SubChainInfo{Name: name, Version: "1.0", TokenFullName: "TestCoin", TokenShortName: "TC", TokenAmount: 100})
+	assert.Equal(t, err, nil)
+
+	_, err = registerSubChain(encoded, context)
+	assert.Equal(t, err, nil)
+}
+
+func Test_UpdateSubChain(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, SubChainContractAddress)
+	owner := context.tx.Data.From
+	newTestSubChain(t, context, "test")
+
+	update := SubChainUpdate{Name: "test", Version: "2.0", Endpoints: []string{"127.0.0.1:8027"}, GenesisHash: crypto.MustHash("genesis")}
+	encoded, err := json.Marshal(update)
+	assert.Equal(t, err, nil)
+
+	// only the owner could update
+	context.tx.Data.From = *crypto.MustGenerateShardAddress(1)
+	_, err = updateSubChain(encoded, context)
+	assert.Equal(t, err, errNotOwner)
+
+	context.tx.Data.From = owner
+	_, err = updateSubChain(encoded, context)
+	assert.Equal(t, err, nil)
+
+	info, err := GetSubChain(context.statedb, "test")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, info.Version, "2.0")
+	assert.Equal(t, info.Endpoints, update.Endpoints)
+	assert.Equal(t, info.GenesisHash, update.GenesisHash)
+	assert.Equal(t, info.TokenFullName, "TestCoin")
+	assert.Equal(t, info.UpdateTimestamp, context.BlockHeader.CreateTimestamp)
+
+	// the empty fields are not changed
+	encoded, err = json.Marshal(SubChainUpdate{Name: "test", Version: "3.0"})
+	assert.Equal(t, err, nil)
+	_, err = updateSubChain(encoded, context)
+	assert.Equal(t, err, nil)
+
+	info, err = GetSubChain(context.statedb, "test")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, info.Version, "3.0")
+	assert.Equal(t, info.Endpoints, update.Endpoints)
+
+	encoded, err = json.Marshal(SubChainUpdate{Name: "test2", Version: "3.0"})
+	assert.Equal(t, err, nil)
+	_, err = updateSubChain(encoded, context)
+	assert.Equal(t, err, errSubChainNotFound)
+}
+
+func Test_UpdateSubChainVerifiers(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, SubChainContractAddress)
+	owner := context.tx.Data.From
+	newTestSubChain(t, context, "test")
+
+	var verifiers []common.Address
+	keys := make(map[common.Address]*ecdsa.PrivateKey)
+	for i := 0; i < 4; i++ {
+		addr, key, err := crypto.GenerateKeyPair()
+		assert.Equal(t, err, nil)
+		verifiers = append(verifiers, *addr)
+		keys[*addr] = key
+	}
+
+	updateVerifiers := func(update SubChainVerifierUpdate) error {
+		encoded, err := json.Marshal(update)
+		assert.Equal(t, err, nil)
+		_, err = updateSubChainVerifiers(encoded, context)
+		return err
+	}
+
+	// the owner sets the initial verifiers
+	context.tx.Data.From = verifiers[0]
+	assert.Equal(t, updateVerifiers(SubChainVerifierUpdate{Name: "test", Verifiers: verifiers}), errNotOwner)
+
+	context.tx.Data.From = owner
+	assert.Equal(t, updateVerifiers(SubChainVerifierUpdate{Name: "test", Verifiers: []common.Address{verifiers[0], verifiers[0]}}), errInvalidVerifiers)
+	assert.Equal(t, updateVerifiers(SubChainVerifierUpdate{Name: "test", Verifiers: verifiers, Nonce: 1}), errVerifierNonce)
+	assert.Equal(t, updateVerifiers(SubChainVerifierUpdate{Name: "test", Verifiers: verifiers}), nil)
+
+	// the owner could not replace the verifiers without approvals
+	newVerifiers := []common.Address{verifiers[1], verifiers[2], verifiers[3], *crypto.MustGenerateShardAddress(1)}
+	update := SubChainVerifierUpdate{Name: "test", Verifiers: newVerifiers, Nonce: 1}
+	assert.Equal(t, updateVerifiers(update), errVerifierApprovals)
+
+	// 3 of 4 verifiers are required, and the duplicate signatures are counted once
+	hash := SubChainVerifiersHash(owner.Shard(), "test", 1, newVerifiers)
+	for _, verifier := range verifiers[:2] {
+		update.Signatures = append(update.Signatures, *crypto.MustSign(keys[verifier], hash.Bytes()))
+	}
+	update.Signatures = append(update.Signatures, update.Signatures[0])
+	assert.Equal(t, updateVerifiers(update), errVerifierApprovals)
+
+	// the signature of other verifiers or nonce is not counted
+	stale := SubChainVerifiersHash(owner.Shard(), "test", 0, newVerifiers)
+	update.Signatures = append(update.Signatures, *crypto.MustSign(keys[verifiers[2]], stale.Bytes()))
+	assert.Equal(t, updateVerifiers(update), errVerifierApprovals)
+
+	// the signatures are no more than the verifiers
+	approval := *crypto.MustSign(keys[verifiers[3]], hash.Bytes())
+	update.Signatures = append(update.Signatures, approval)
+	assert.Equal(t, updateVerifiers(update), errTooManyApprovals)
+
+	// the gas is charged by the signatures
+	update.Signatures[2] = approval
+	update.Signatures = update.Signatures[:4]
+	var usedGas uint64
+	context.UseGas = func(gas uint64) error {
+		usedGas += gas
+		return nil
+	}
+	context.tx.Data.From = *crypto.MustGenerateShardAddress(1)
+	assert.Equal(t, updateVerifiers(update), nil)
+
+	info, err := GetSubChain(context.statedb, "test")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, info.Verifiers, newVerifiers)
+	assert.Equal(t, info.VerifierNonce, uint64(2))
+	assert.Equal(t, usedGas, 4*gasSubChainApproval)
+
+	// the approvals could not be replayed
+	assert.Equal(t, updateVerifiers(update), errVerifierNonce)
+}
+
+func Test_SubChainStatus(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	context := newTestContext(db, SubChainContractAddress)
+	owner := context.tx.Data.From
+	newTestSubChain(t, context, "test")
+	newTestSubChain(t, context, "test2")
+
+	status := func(name string) string {
+		info, err := GetSubChain(context.statedb, name)
+		assert.Equal(t, err, nil)
+		return info.CurrentStatus()
+	}
+
+	context.tx.Data.From = *crypto.MustGenerateShardAddress(1)
+	_, err := pauseSubChain([]byte("test"), context)
+	assert.Equal(t, err, errNotOwner)
+
+	context.tx.Data.From = owner
+	_, err = resumeSubChain([]byte("test"), context)
+	assert.Equal(t, err, errSubChainStatus)
+
+	_, err = pauseSubChain([]byte("test"), context)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, status("test"), SubChainStatusPaused)
+
+	_, err = pauseSubChain([]byte("test"), context)
+	assert.Equal(t, err, errSubChainStatus)
+
+	_, err = resumeSubChain([]byte("test"), context)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, status("test"), SubChainStatusActive)
+
+	_, err = deregisterSubChain([]byte("test"), context)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, status("test"), SubChainStatusDeregistered)
+
+	// the deregistered sub-chain could not be changed or registered again
+	_, err = resumeSubChain([]byte("test"), context)
+	assert.Equal(t, err, errSubChainDeregistered)
+
+	encoded, err := json.Marshal(SubChainUpdate{Name: "test", Version: "2.0"})
+	assert.Equal(t, err, nil)
+	_, err = updateSubChain(encoded, context)
+	assert.Equal(t, err, errSubChainDeregistered)
+
+	encoded, err = json.Marshal(SubChainInfo{Name: "test", Version: "1.0", TokenFullName: "TestCoin", TokenShortName: "TC", TokenAmount: 100})
+	assert.Equal(t, err, nil)
+	_, err = registerSubChain(encoded, context)
+	assert.Equal(t, err, errExists)
+
+	infos, err := ListSubChains(context.statedb)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(infos), 2)
+	assert.Equal(t, infos[0].Name, "test")
+	assert.Equal(t, infos[0].CurrentStatus(), SubChainStatusDeregistered)
+	assert.Equal(t, infos[1].Name, "test2")
+	assert.Equal(t, infos[1].CurrentStatus(), SubChainStatusActive)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/seeleteam/go-seele/core/svm"
 	"github.com/seeleteam/go-seele/core/txs"
 	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/core/vm"
 	"github.com/seeleteam/go-seele/database"
 	"github.com/seeleteam/go-seele/event"
 	"github.com/seeleteam/go-seele/log"
@@ -298,6 +299,11 @@ func (bc *Blockchain) WriteHeader(*types.BlockHeader) error {
 	return ErrNotSupported
 }
 
+// WriteCheckpointHeader writes the specified verified head to the blockchain store, only used in lightchain.
+func (bc *Blockchain) WriteCheckpointHeader(*types.BlockHeader, *big.Int) error {
+	return ErrNotSupported
+}
+
 func (bc *Blockchain) doWriteBlock(block *types.Block, pool *Pool) error {
 	bc.lock.Lock()
 	defer bc.lock.Unlock()
@@ -567,6 +573,14 @@ func (bc *Blockchain) applyRewardAndRegularTxs(statedb *state.Statedb, rewardTx
 		return nil, errors.NewStackedError(err, "failed to apply reward tx")
 	}
 	receipts[0] = rewardReceipt
+
+	if err = txs.ApplyMasternodeRewards(statedb, blockHeader, rewardReceipt); err != nil {
+		return nil, errors.NewStackedError(err, "failed to apply masternode rewards")
+	}
+
+	if err = txs.ApplyVerifierFees(bc, statedb, blockHeader, rewardReceipt); err != nil {
+		return nil, errors.NewStackedError(err, "failed to apply verifier fees")
+	}
 	auditor.Audit("succeed to validate and apply reward tx")
 
 	// batch validate signature to improve perf
@@ -593,6 +607,12 @@ func (bc *Blockchain) applyRewardAndRegularTxs(statedb *state.Statedb, rewardTx
 	auditor.Audit("succeed to apply %v txs", len(regularTxs))
 
 	if blockHeader.Consensus == types.BftConsensus {
+		if txs.IsElectionHeight(bc.engine, blockHeader.Height) {
+			if err := txs.ValidateElectedVerifiers(statedb, blockHeader); err != nil {
+				return nil, errors.NewStackedErrorf(err, "failed to validate elected verifiers at height %v", blockHeader.Height)
+			}
+		}
+
 		bc.verifyStemStructureAndUpdateDatabase(regularTxs, prevHeader)
 	}
 	return receipts, nil
@@ -659,12 +679,19 @@ func (bc *Blockchain) verifyStemStructureAndUpdateDatabase(regularTxs []*types.T
 // ApplyTransaction applies a transaction, changes corresponding statedb and generates its receipt
 func (bc *Blockchain) ApplyTransaction(tx *types.Transaction, txIndex int, coinbase common.Address, statedb *state.Statedb,
 	blockHeader *types.BlockHeader) (*types.Receipt, error) {
+	return bc.ApplyTransactionWithTracer(tx, txIndex, coinbase, statedb, blockHeader, nil)
+}
+
+// ApplyTransactionWithTracer applies a transaction with the tracer attached to trace the execution
+func (bc *Blockchain) ApplyTransactionWithTracer(tx *types.Transaction, txIndex int, coinbase common.Address, statedb *state.Statedb,
+	blockHeader *types.BlockHeader, tracer vm.Tracer) (*types.Receipt, error) {
 	ctx := &svm.Context{
 		Tx:          tx,
 		TxIndex:     txIndex,
 		Statedb:     statedb,
 		BlockHeader: blockHeader,
 		BcStore:     bc.bcStore,
+		Tracer:      tracer,
 	}
 	receipt, err := svm.Process(ctx, blockHeader.Height)
 	if err != nil {
@@ -674,6 +701,52 @@ func (bc *Blockchain) ApplyTransaction(tx *types.Transaction, txIndex int, coinb
 	return receipt, nil
 }
 
+// StateAtTransaction returns the statedb right before the tx at txIndex of the block is applied,
+// that is the parent state with the debts, reward tx and the previous txs of the block applied.
+// It is used to re-execute a packed tx, e.g. to trace it.
+func (bc *Blockchain) StateAtTransaction(block *types.Block, txIndex int) (*state.Statedb, error) {
+	if txIndex <= 0 || txIndex >= len(block.Transactions) {
+		return nil, fmt.Errorf("invalid tx index %d, block has %d txs", txIndex, len(block.Transactions))
+	}
+
+	parent, err := bc.bcStore.GetBlockHeader(block.Header.PreviousBlockHash)
+	if err != nil {
+		return nil, errors.NewStackedErrorf(err, "failed to get parent block header %v", block.Header.PreviousBlockHash)
+	}
+
+	statedb, err := state.NewStatedb(parent.StateHash, bc.accountStateDB)
+	if err != nil {
+		return nil, errors.NewStackedErrorf(err, "failed to create statedb by root hash %v", parent.StateHash)
+	}
+
+	for _, d := range block.Debts {
+		if err = applyDebt(statedb, d, block.Header.Creator); err != nil {
+			return nil, errors.NewStackedError(err, "failed to apply debt")
+		}
+	}
+
+	rewardReceipt, err := txs.ApplyRewardTx(block.Transactions[0], statedb)
+	if err != nil {
+		return nil, errors.NewStackedError(err, "failed to apply reward tx")
+	}
+
+	if err = txs.ApplyMasternodeRewards(statedb, block.Header, rewardReceipt); err != nil {
+		return nil, errors.NewStackedError(err, "failed to apply masternode rewards")
+	}
+
+	if err = txs.ApplyVerifierFees(bc, statedb, block.Header, rewardReceipt); err != nil {
+		return nil, errors.NewStackedError(err, "failed to apply verifier fees")
+	}
+
+	for i := 1; i < txIndex; i++ {
+		if _, err = bc.ApplyTransaction(block.Transactions[i], i, block.Header.Creator, statedb, block.Header); err != nil {
+			return nil, errors.NewStackedErrorf(err, "failed to apply tx[%v]", i)
+		}
+	}
+
+	return statedb, nil
+}
+
 // ApplyDebtWithoutVerify applies a debt and update statedb.
 func (bc *Blockchain) ApplyDebtWithoutVerify(statedb *state.Statedb, d *types.Debt, coinbase common.Address) error {
 	debtIndex, _ := bc.bcStore.GetDebtIndex(d.Hash)
@@ -681,6 +754,10 @@ func (bc *Blockchain) ApplyDebtWithoutVerify(statedb *state.Statedb, d *types.De
 		return fmt.Errorf("debt already packed, debt hash %s", d.Hash.Hex())
 	}
 
+	return applyDebt(statedb, d, coinbase)
+}
+
+func applyDebt(statedb *state.Statedb, d *types.Debt, coinbase common.Address) error {
 	if !statedb.Exist(d.Data.Account) {
 		statedb.CreateAccount(d.Data.Account)
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/common/errors"
+	"github.com/seeleteam/go-seele/contract/system"
 	"github.com/seeleteam/go-seele/core/state"
 	"github.com/seeleteam/go-seele/core/store"
 	"github.com/seeleteam/go-seele/core/types"
@@ -28,6 +29,9 @@ var (
 
 	// ErrGenesisNotFound is returned when genesis block not found in the store.
 	ErrGenesisNotFound = errors.New("genesis block not found")
+
+	// ErrEVMForksMismatch is returned when the evm fork schedule between the store and genesis info mismatch.
+	ErrEVMForksMismatch = errors.New("evm fork schedule mismatch")
 )
 
 const genesisBlockHeight = uint64(0)
@@ -68,6 +72,14 @@ type GenesisInfo struct {
 	Balance *big.Int `json:"balance"`
 	// subchain max supply
 	Supply *big.Int `json:"supply"`
+
+	// EVMForks is the activation heights of the EVM hard forks, its hash is included in the
+	// genesis block so that the nodes with mismatched schedules could not connect to each other.
+	EVMForks *types.EVMForkConfig `json:"evmForks,omitempty"`
+
+	// BTCRelay is the btc block header that the btc relay starts from, which is
+	// stored in the genesis state. The btc relay is disabled if it is not set.
+	BTCRelay *system.BTCRelayCheckpoint `json:"btcRelay,omitempty"`
 }
 
 // NewGenesisInfo mainchain genesis block info constructor
@@ -135,6 +147,7 @@ func (info *GenesisInfo) Hash() common.Hash {
 // shardInfo represents the extra data that saved in the genesis block in the blockchain.
 type shardInfo struct {
 	ShardNumber uint
+	EVMForks    []common.Hash `rlp:"tail"` // hash of the evm fork schedule, empty if no fork is scheduled
 }
 
 // genesisExtraVerifyInfo we use header hash to verify genesis block, all other fields not in header can be serialize in this field (e.g. shard)
@@ -143,6 +156,17 @@ type genesisExtraVerifyInfo struct {
 	Master       common.Address
 	Supply       *big.Int
 	RootAccounts []common.Address
+	EVMForks     []common.Hash `rlp:"tail"` // hash of the evm fork schedule, empty if no fork is scheduled
+}
+
+// evmForksHash returns the hash of the evm fork schedule included in the genesis block,
+// which is empty if no fork is scheduled so that the hash of the genesis block is not changed.
+func evmForksHash(forks *types.EVMForkConfig) []common.Hash {
+	if hash := forks.Hash(); !hash.IsEmpty() {
+		return []common.Hash{hash}
+	}
+
+	return nil
 }
 
 // GetGenesis gets the genesis block according to accounts' balance
@@ -183,6 +207,7 @@ func GetGenesis(info *GenesisInfo) *Genesis {
 			Master:       info.Masteraccount,
 			Supply:       info.Supply,
 			RootAccounts: info.Rootaccounts,
+			EVMForks:     evmForksHash(info.EVMForks),
 		})
 		return &Genesis{
 			header: &types.BlockHeader{
@@ -203,6 +228,7 @@ func GetGenesis(info *GenesisInfo) *Genesis {
 
 	shard := common.SerializePanic(shardInfo{ //
 		ShardNumber: info.ShardNumber,
+		EVMForks:    evmForksHash(info.EVMForks),
 	})
 
 	return &Genesis{
@@ -297,6 +323,10 @@ func (genesis *Genesis) GetShardNumber() uint {
 // Otherwise, check if the existing genesis block is valid in the blockchain store.
 // here if consensus is subchain consensus, we will get the validators from inquerying subchain registeration smart contract which has stored the validators
 func (genesis *Genesis) InitializeAndValidate(bcStore store.BlockchainStore, accountStateDB database.Database) error {
+	if err := genesis.info.EVMForks.Validate(); err != nil {
+		return errors.NewStackedError(err, "invalid evm fork schedule")
+	}
+
 	storedGenesisHash, err := bcStore.GetBlockHash(genesisBlockHeight)
 
 	// FIXME use seele-defined common error instead of concrete levelDB error.
@@ -342,6 +372,26 @@ func (genesis *Genesis) InitializeAndValidate(bcStore store.BlockchainStore, acc
 		return ErrGenesisHashMismatch
 	}
 
+	return genesis.updateEVMForks(bcStore)
+}
+
+// updateEVMForks checks the evm fork schedule of the genesis info is the same as the stored one,
+// which could not be rescheduled since it is included in the genesis block. The schedule is
+// persisted if not stored yet, e.g. the chain is created before the schedule is persisted.
+func (genesis *Genesis) updateEVMForks(bcStore store.BlockchainStore) error {
+	storedForks, err := bcStore.GetEVMForkConfig()
+	if err == leveldbErrors.ErrNotFound {
+		return bcStore.PutEVMForkConfig(genesis.info.EVMForks)
+	}
+
+	if err != nil {
+		return errors.NewStackedError(err, "failed to get evm fork schedule")
+	}
+
+	if !storedForks.Equal(genesis.info.EVMForks) {
+		return ErrEVMForksMismatch
+	}
+
 	return nil
 }
 
@@ -364,6 +414,10 @@ func (genesis *Genesis) store(bcStore store.BlockchainStore, accountStateDB data
 		return errors.NewStackedError(err, "failed to put genesis block header into store")
 	}
 
+	if err := bcStore.PutEVMForkConfig(genesis.info.EVMForks); err != nil {
+		return errors.NewStackedError(err, "failed to put evm fork schedule into store")
+	}
+
 	return nil
 }
 
@@ -445,6 +499,13 @@ func getStateDB(info *GenesisInfo) *state.Statedb {
 		}
 	}
 
+	if info.BTCRelay != nil {
+		if err := system.InitBTCRelay(statedb, info.BTCRelay); err != nil {
+			fmt.Println("invalid btc relay checkpoint,", err)
+			return nil
+		}
+	}
+
 	return statedb
 }
 
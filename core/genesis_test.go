@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/contract/system"
 	"github.com/seeleteam/go-seele/core/state"
 	"github.com/seeleteam/go-seele/core/store"
 	"github.com/seeleteam/go-seele/core/types"
@@ -117,6 +118,72 @@ func Test_Genesis_Init_GenesisMismatch(t *testing.T) {
 	assert.Equal(t, err, ErrGenesisHashMismatch)
 }
 
+func newTestEVMForksGenesis(forks *types.EVMForkConfig) *Genesis {
+	var roots []common.Address
+	for i := 0; i < common.SubChainRootAccount; i++ {
+		roots = append(roots, *crypto.MustGenerateShardAddress(1))
+	}
+
+	return GetGenesis(&GenesisInfo{
+		ShardNumber:     1,
+		CreateTimestamp: big.NewInt(0),
+		Consensus:       types.BftConsensus,
+		Rootaccounts:    roots,
+		Supply:          big.NewInt(100),
+		EVMForks:        forks,
+	})
+}
+
+func Test_Genesis_Init_EVMForks(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	bcStore := store.NewBlockchainDatabase(db)
+
+	// the genesis block includes the hash of the schedule
+	forks := &types.EVMForkConfig{ConstantinopleHeight: big.NewInt(100)}
+	genesis := newTestEVMForksGenesis(forks)
+	data, err := getGenesisExtraVerifyInfo(types.NewBlock(genesis.header, nil, nil, nil))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, data.EVMForks, []common.Hash{forks.Hash()})
+
+	assert.Equal(t, genesis.InitializeAndValidate(bcStore, db), nil)
+
+	// the nodes with mismatched schedules have different genesis blocks
+	rescheduled := &Genesis{header: genesis.header.Clone(), info: genesis.info}
+	witness := *data
+	witness.EVMForks = evmForksHash(&types.EVMForkConfig{ConstantinopleHeight: big.NewInt(200)})
+	rescheduled.header.Witness = common.SerializePanic(&witness)
+	assert.Equal(t, rescheduled.header.Hash() != genesis.header.Hash(), true)
+	assert.Equal(t, rescheduled.InitializeAndValidate(bcStore, db), ErrGenesisHashMismatch)
+
+	// the stored schedule could not be changed
+	assert.Equal(t, genesis.InitializeAndValidate(bcStore, db), nil)
+	assert.Equal(t, bcStore.PutEVMForkConfig(&types.EVMForkConfig{ConstantinopleHeight: big.NewInt(200)}), nil)
+	assert.Equal(t, genesis.InitializeAndValidate(bcStore, db), ErrEVMForksMismatch)
+}
+
+func Test_Genesis_EVMForksHash(t *testing.T) {
+	// the witness is not changed if no fork is scheduled
+	assert.Equal(t, common.SerializePanic(shardInfo{ShardNumber: 1, EVMForks: evmForksHash(nil)}), common.SerializePanic(shardInfo{ShardNumber: 1}))
+	assert.Equal(t, len(evmForksHash(&types.EVMForkConfig{})), 0)
+	assert.Equal(t, len(evmForksHash(&types.EVMForkConfig{IstanbulHeight: big.NewInt(0)})), 1)
+}
+
+func Test_Genesis_BTCRelay(t *testing.T) {
+	genesis := newTestEVMForksGenesis(nil)
+
+	// the checkpoint of the btc relay is stored in the genesis state
+	info := *genesis.info
+	info.BTCRelay = &system.BTCRelayCheckpoint{Header: "0x0100000000000000000000000000000000000000000000000000000000000000000000003ba3edfd7a7b12b27ac72c3e67768f617fc81bc3888a51323a9fb8aa4b1e5e4a29ab5f49ffff001d1dac2b7c"}
+	relayed := GetGenesis(&info)
+	assert.Equal(t, relayed.header.StateHash != genesis.header.StateHash, true)
+
+	// the genesis state is not created with an invalid checkpoint
+	info.BTCRelay = &system.BTCRelayCheckpoint{Header: "0x1234"}
+	assert.Equal(t, getStateDB(&info) == nil, true)
+}
+
 func validateGenesisDefaultMembers(t *testing.T, genesis *Genesis) {
 	assert.Equal(t, genesis.header.PreviousBlockHash, common.EmptyHash)
 	assert.Equal(t, genesis.header.Creator, common.EmptyAddress)
@@ -47,6 +47,9 @@ type Statedb struct {
 
 	// State modifications for current processed tx.
 	curJournal *journal
+
+	// retainJournal keeps the state modifications of all the txs in memory.
+	retainJournal bool
 }
 
 // NewStatedb constructs and returns a statedb instance
@@ -204,12 +207,23 @@ func (s *Statedb) SetData(addr common.Address, key common.Hash, value []byte) {
 	object.setState(key, value)
 }
 
+// RetainJournal keeps the state changes of all the txs in the journal without flushing them into
+// the trie, so that the snapshots survive Prepare and Hash, i.e. across txs. It is only used for
+// the calls that are never committed, and Hash returns an empty hash afterwards.
+func (s *Statedb) RetainJournal() {
+	s.retainJournal = true
+}
+
 // Hash flush the dirty data into trie and calculates the intermediate root hash.
 func (s *Statedb) Hash() (common.Hash, error) {
 	if s.dbErr != nil {
 		return common.EmptyHash, s.dbErr
 	}
 
+	if s.retainJournal {
+		return common.EmptyHash, nil
+	}
+
 	for addr := range s.curJournal.dirties {
 		if object, found := s.stateObjects[addr]; found {
 			if err := object.flush(s.trie); err != nil {
@@ -275,7 +289,12 @@ func (s *Statedb) Prepare(txIndex int) int {
 	s.curTxIndex = uint(txIndex)
 	s.curLogs = nil
 
-	s.clearJournalAndRefund()
+	if s.retainJournal {
+		s.refund = 0
+	} else {
+		s.clearJournalAndRefund()
+	}
+
 	return s.Snapshot()
 }
 
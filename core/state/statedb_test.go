@@ -413,3 +413,39 @@ func Test_Log(t *testing.T) {
 	assert.Equal(t, logs[1].TxIndex, uint(38))
 	assert.Equal(t, logs[2].TxIndex, uint(38))
 }
+
+func Test_RetainJournal(t *testing.T) {
+	db, statedb, stateObj, dispose := newTestEVMStateDB()
+	defer dispose()
+
+	addr := stateObj.address
+	key := common.StringToHash("k1")
+	statedb.SetData(addr, key, []byte("v1"))
+	root, statedb := commitAndNewStateDB(db, statedb)
+
+	statedb.RetainJournal()
+	balance := statedb.GetBalance(addr)
+	snapshot := statedb.Snapshot()
+
+	// the changes of the txs survive Prepare and Hash, and the trie is not changed
+	other := *crypto.MustGenerateRandomAddress()
+	for i := 0; i < 2; i++ {
+		statedb.Prepare(i)
+		statedb.AddBalance(addr, big.NewInt(1))
+		statedb.SetData(addr, key, []byte("v2"))
+		statedb.CreateAccount(other)
+
+		hash, err := statedb.Hash()
+		assert.Equal(t, err, nil)
+		assert.Equal(t, hash, common.EmptyHash)
+	}
+
+	assert.Equal(t, statedb.GetBalance(addr), new(big.Int).Add(balance, big.NewInt(2)))
+	assert.Equal(t, statedb.trie.Hash(), root)
+
+	// revert the changes of all the txs
+	statedb.RevertToSnapshot(snapshot)
+	assert.Equal(t, statedb.GetBalance(addr), balance)
+	assert.Equal(t, statedb.GetData(addr, key), []byte("v1"))
+	assert.Equal(t, statedb.Exist(other), false)
+}
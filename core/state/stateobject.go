@@ -144,6 +144,17 @@ func (s *stateObject) dataKey(dataType byte, prefix ...byte) []byte {
 	return append(key, prefix...)
 }
 
+// CodeKey returns the key of the contract code of the specified address in the state trie.
+func CodeKey(address common.Address) []byte {
+	return append(crypto.MustHash(address).Bytes(), dataTypeCode)
+}
+
+// StorageKey returns the key of the contract storage of the specified address in the state trie.
+func StorageKey(address common.Address, key common.Hash) []byte {
+	dataKey := append(crypto.MustHash(address).Bytes(), dataTypeStorage)
+	return append(dataKey, crypto.MustHash(key).Bytes()...)
+}
+
 func (s *stateObject) loadAccount(trie Trie) (bool, error) {
 	value, ok, err := trie.Get(s.dataKey(dataTypeAccount))
 	if err != nil || !ok {
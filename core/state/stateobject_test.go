@@ -82,3 +82,11 @@ func Test_StateObject_AmountDirty(t *testing.T) {
 	assert.Equal(t, so.getAmount(), big.NewInt(0))
 	assert.Equal(t, so.dirtyAccount, true)
 }
+
+func Test_StateObject_DataKey(t *testing.T) {
+	so := newTestStateObject()
+	key := common.StringToHash("key")
+
+	assert.Equal(t, CodeKey(so.address), so.dataKey(dataTypeCode))
+	assert.Equal(t, StorageKey(so.address, key), so.dataKey(dataTypeStorage, crypto.MustHash(key).Bytes()...))
+}
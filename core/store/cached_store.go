@@ -7,6 +7,7 @@ package store
 
 import (
 	"math/big"
+	"sync/atomic"
 
 	"github.com/hashicorp/golang-lru"
 	"github.com/seeleteam/go-seele/common"
@@ -28,6 +29,8 @@ type cachedStore struct {
 	headerCache *lru.Cache // block hash to header cache.
 	tdCache     *lru.Cache // block hash to total difficulty cache.
 	blockCache  *lru.Cache // block hash to block cache.
+
+	forkConfig atomic.Value // EVM hard fork schedule, it is read for every contract tx.
 }
 
 // NewCachedStore returns a cached blockchainDatabase instance based on LRU.
@@ -262,3 +265,31 @@ func (store *cachedStore) GetDebtIndex(txHash common.Hash) (*types.DebtIndex, er
 func (store *cachedStore) DeleteIndices(block *types.Block) error {
 	return store.raw.DeleteIndices(block)
 }
+
+// GetEVMForkConfig retrieves the EVM hard fork schedule persisted with the genesis block.
+func (store *cachedStore) GetEVMForkConfig() (*types.EVMForkConfig, error) {
+	if config, ok := store.forkConfig.Load().(*types.EVMForkConfig); ok {
+		return config, nil
+	}
+
+	config, err := store.raw.GetEVMForkConfig()
+	if err == nil {
+		store.forkConfig.Store(config)
+	}
+
+	return config, err
+}
+
+// PutEVMForkConfig writes the EVM hard fork schedule into the store.
+func (store *cachedStore) PutEVMForkConfig(config *types.EVMForkConfig) error {
+	if config == nil {
+		config = &types.EVMForkConfig{}
+	}
+
+	err := store.raw.PutEVMForkConfig(config)
+	if err == nil {
+		store.forkConfig.Store(config)
+	}
+
+	return err
+}
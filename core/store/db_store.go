@@ -7,6 +7,7 @@ package store
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"math/big"
 
@@ -18,6 +19,7 @@ import (
 
 var (
 	keyHeadBlockHash = []byte("HeadBlockHash")
+	keyEVMForkConfig = []byte("EVMForkConfig")
 
 	keyPrefixHash      = []byte("H")
 	keyPrefixHeader    = []byte("h")
@@ -48,6 +50,7 @@ type blockchainDatabase struct {
 //   5) keyPrefixBody + hash => block body (transactions)
 //   6) keyPrefixReceipts + hash => block receipts
 //   7) keyPrefixTxIndex + txHash => txIndex
+//   8) keyEVMForkConfig => EVM hard fork schedule
 func NewBlockchainDatabase(db database.Database) BlockchainStore {
 	return &blockchainDatabase{db}
 }
@@ -494,3 +497,33 @@ func (store *blockchainDatabase) batchDeleteIndices(batch database.Batch, blockH
 
 	return nil
 }
+
+// GetEVMForkConfig retrieves the EVM hard fork schedule persisted with the genesis block.
+func (store *blockchainDatabase) GetEVMForkConfig() (*types.EVMForkConfig, error) {
+	data, err := store.db.Get(keyEVMForkConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// encoded in json since rlp could not distinguish the nil fork height from 0.
+	config := &types.EVMForkConfig{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// PutEVMForkConfig writes the EVM hard fork schedule into the store.
+func (store *blockchainDatabase) PutEVMForkConfig(config *types.EVMForkConfig) error {
+	if config == nil {
+		config = &types.EVMForkConfig{}
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	return store.db.Put(keyEVMForkConfig, data)
+}
@@ -30,6 +30,7 @@ type MemStore struct {
 	Blocks          map[common.Hash]*memBlock
 	TxLookups       map[common.Hash]types.TxIndex   // tx hash to index mapping
 	DebtLookups     map[common.Hash]types.DebtIndex // debt hash to index mapping
+	ForkConfig      *types.EVMForkConfig            // EVM hard fork schedule
 
 	CorruptOnPutBlock bool // used to test blockchain recovery if program crashed
 }
@@ -279,3 +280,20 @@ func (store *MemStore) DeleteIndices(block *types.Block) error {
 
 	return nil
 }
+
+func (store *MemStore) GetEVMForkConfig() (*types.EVMForkConfig, error) {
+	if store.ForkConfig == nil {
+		return nil, errNotFound
+	}
+
+	return store.ForkConfig, nil
+}
+
+func (store *MemStore) PutEVMForkConfig(config *types.EVMForkConfig) error {
+	if config == nil {
+		config = &types.EVMForkConfig{}
+	}
+
+	store.ForkConfig = config
+	return nil
+}
@@ -81,4 +81,10 @@ type BlockchainStore interface {
 
 	// DeleteIndices deletes tx/debt indices of the specified block.
 	DeleteIndices(block *types.Block) error
+
+	// GetEVMForkConfig retrieves the EVM hard fork schedule persisted with the genesis block.
+	GetEVMForkConfig() (*types.EVMForkConfig, error)
+
+	// PutEVMForkConfig writes the EVM hard fork schedule into the store.
+	PutEVMForkConfig(config *types.EVMForkConfig) error
 }
@@ -18,22 +18,46 @@ import (
 // NewEVMByDefaultConfig returns a new EVM. The returned EVM is not thread safe and should
 // only ever be used *once*.
 func NewEVMByDefaultConfig(tx *types.Transaction, statedb *StateDB, blockHeader *types.BlockHeader, bcStore store.BlockchainStore) *vm.EVM {
+	return NewEVM(tx, statedb, blockHeader, bcStore, vm.Config{})
+}
+
+// NewEVM returns a new EVM with the specified vm config, e.g. to attach a tracer.
+// The returned EVM is not thread safe and should only ever be used *once*.
+func NewEVM(tx *types.Transaction, statedb *StateDB, blockHeader *types.BlockHeader, bcStore store.BlockchainStore, vmConfig vm.Config) *vm.EVM {
 	evmContext := newEVMContext(tx, blockHeader, blockHeader.Creator, bcStore)
-	chainConfig := &params.ChainConfig{
-		ChainID:             big.NewInt(1),
-		HomesteadBlock:      big.NewInt(0),
-		DAOForkBlock:        big.NewInt(0),
-		DAOForkSupport:      true,
-		EIP150Block:         big.NewInt(0),
-		EIP155Block:         big.NewInt(0),
-		EIP158Block:         big.NewInt(0),
-		ByzantiumBlock:      big.NewInt(0),
-		ConstantinopleBlock: nil,
-		Ethash:              new(params.EthashConfig),
+
+	return vm.NewEVM(*evmContext, statedb, newChainConfig(bcStore), vmConfig)
+}
+
+// newChainConfig returns the EVM chain config with the hard fork schedule persisted with the genesis block.
+// Only the forks up to byzantium are activated if the schedule is not found, e.g. the chain is created
+// before the schedule is introduced.
+func newChainConfig(bcStore store.BlockchainStore) *vm.ChainConfig {
+	var forks *types.EVMForkConfig
+	if bcStore != nil {
+		forks, _ = bcStore.GetEVMForkConfig()
+	}
+
+	if forks == nil {
+		forks = &types.EVMForkConfig{}
 	}
-	vmConfig := &vm.Config{}
 
-	return vm.NewEVM(*evmContext, statedb, chainConfig, *vmConfig)
+	return &vm.ChainConfig{
+		ChainConfig: &params.ChainConfig{
+			ChainID:             big.NewInt(1),
+			HomesteadBlock:      big.NewInt(0),
+			DAOForkBlock:        big.NewInt(0),
+			DAOForkSupport:      true,
+			EIP150Block:         big.NewInt(0),
+			EIP155Block:         big.NewInt(0),
+			EIP158Block:         big.NewInt(0),
+			ByzantiumBlock:      big.NewInt(0),
+			ConstantinopleBlock: forks.ConstantinopleHeight,
+			Ethash:              new(params.EthashConfig),
+		},
+		PetersburgBlock: forks.PetersburgHeight,
+		IstanbulBlock:   forks.IstanbulHeight,
+	}
 }
 
 // NewEVMContext creates a new context for use in the EVM.
@@ -7,13 +7,17 @@ package evm
 
 import (
 	"math/big"
+	"testing"
 
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/common/hexutil"
 	"github.com/seeleteam/go-seele/core/state"
 	"github.com/seeleteam/go-seele/core/store"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/core/vm"
 	"github.com/seeleteam/go-seele/crypto"
 	"github.com/seeleteam/go-seele/database/leveldb"
+	"github.com/stretchr/testify/assert"
 )
 
 //////////////////////////////////////////////////////////////////////////////////////////////////
@@ -52,3 +56,46 @@ func preprocessContract(balance, nonce uint64) (*state.Statedb, store.Blockchain
 		dispose()
 	}
 }
+
+func Test_NewEVM_ForkSchedule(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	statedb, err := state.NewStatedb(common.EmptyHash, db)
+	assert.Equal(t, err, nil)
+	bcStore := store.NewBlockchainDatabase(db)
+
+	// CHAINID, PUSH1 0, MSTORE, PUSH1 32, PUSH1 0, RETURN
+	contractAddr := *crypto.MustGenerateRandomAddress()
+	statedb.CreateAccount(contractAddr)
+	statedb.SetCode(contractAddr, mustHexToBytes("0x4660005260206000f3"))
+
+	from := *crypto.MustGenerateRandomAddress()
+	tx, err := types.NewMessageTransaction(from, contractAddr, big.NewInt(0), big.NewInt(1), 100000, 0, []byte{1})
+	assert.Equal(t, err, nil)
+
+	call := func(height uint64) ([]byte, error) {
+		header := &types.BlockHeader{Height: height, CreateTimestamp: big.NewInt(0), Difficulty: big.NewInt(1)}
+		e := NewEVMByDefaultConfig(tx, &StateDB{statedb}, header, bcStore)
+		ret, _, err := e.Call(vm.AccountRef(from), contractAddr, nil, 100000, big.NewInt(0))
+		return ret, err
+	}
+
+	// the forks after byzantium are not activated if the schedule is not found
+	_, err = call(10)
+	assert.Equal(t, err != nil, true)
+
+	forks := &types.EVMForkConfig{
+		ConstantinopleHeight: big.NewInt(0),
+		PetersburgHeight:     big.NewInt(0),
+		IstanbulHeight:       big.NewInt(10),
+	}
+	assert.Equal(t, bcStore.PutEVMForkConfig(forks), nil)
+
+	_, err = call(9)
+	assert.Equal(t, err != nil, true)
+
+	ret, err := call(10)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, new(big.Int).SetBytes(ret), big.NewInt(1))
+}
@@ -7,6 +7,7 @@ package svm
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/common/errors"
@@ -25,6 +26,7 @@ type Context struct {
 	Statedb     *state.Statedb
 	BlockHeader *types.BlockHeader
 	BcStore     store.BlockchainStore
+	Tracer      vm.Tracer // optional, traces the execution of evm and system contracts
 }
 
 // Process the tx
@@ -48,7 +50,7 @@ func Process(ctx *Context, height uint64) (*types.Receipt, error) {
 	snapshot := ctx.Statedb.Prepare(ctx.TxIndex)
 
 	// create or execute contract
-	if contract := system.GetContractByAddress(ctx.Tx.Data.To); contract != nil { // system contract
+	if contract := system.GetContractByHeight(ctx.Tx.Data.To, height); contract != nil { // system contract
 		receipt, err = processSystemContract(ctx, contract, snapshot, leftOverGas)
 	} else if ctx.Tx.IsCrossShardTx() && !ctx.Tx.Data.To.IsEVMContract() { // cross shard tx
 		return processCrossShardTransaction(ctx, snapshot)
@@ -168,16 +170,90 @@ func processSystemContract(ctx *Context, contract system.Contract, snapshot int,
 	ctx.Statedb.AddBalance(recipient, amount)
 
 	// Check used gas is over flow
-	receipt.UsedGas = contract.RequiredGas(ctx.Tx.Data.Payload)
+	receipt.UsedGas = contract.RequiredGas(ctx.Tx.Data.Payload, ctx.BlockHeader.Height)
 	if receipt.UsedGas > leftOverGas {
 		return receipt, vm.ErrOutOfGas
 	}
 	// Run
-	receipt.Result, err = contract.Run(ctx.Tx.Data.Payload, system.NewContext(ctx.Tx, ctx.Statedb, ctx.BlockHeader))
+	if ctx.Tracer != nil {
+		ctx.Tracer.CaptureStart(sender, recipient, false, ctx.Tx.Data.Payload, leftOverGas, amount)
+	}
+
+	start := time.Now()
+	sysCtx := system.NewContext(ctx.Tx, ctx.Statedb, ctx.BlockHeader)
+	meter := &gasMeter{leftOverGas - receipt.UsedGas, &receipt.UsedGas}
+	sysCtx.CallContract = newContractCaller(ctx, meter)
+	sysCtx.UseGas = meter.useGas
+	receipt.Result, err = contract.Run(ctx.Tx.Data.Payload, sysCtx)
+
+	if ctx.Tracer != nil {
+		ctx.Tracer.CaptureEnd(receipt.Result, receipt.UsedGas, time.Since(start), err)
+	}
 
 	return receipt, err
 }
 
+// gasMeter charges the gas used by system contracts in addition to the required gas,
+// which is limited to the gas left, and adds the charged gas to usedGas.
+type gasMeter struct {
+	gas     uint64
+	usedGas *uint64
+}
+
+func (m *gasMeter) useGas(gas uint64) error {
+	if gas > m.gas {
+		*m.usedGas += m.gas
+		m.gas = 0
+		return vm.ErrOutOfGas
+	}
+
+	*m.usedGas += gas
+	m.gas -= gas
+	return nil
+}
+
+// newContractCaller returns the caller for system contracts to call evm contracts,
+// which is limited to the gas left of the meter.
+func newContractCaller(ctx *Context, meter *gasMeter) system.ContractCaller {
+	return func(caller common.Address, to common.Address, input []byte) ([]byte, error) {
+		var vmConfig vm.Config
+		if ctx.Tracer != nil {
+			vmConfig = vm.Config{Debug: true, Tracer: &nestedTracer{ctx.Tracer}}
+		}
+
+		statedb := &evm.StateDB{Statedb: ctx.Statedb}
+		e := evm.NewEVM(ctx.Tx, statedb, ctx.BlockHeader, ctx.BcStore, vmConfig)
+		result, leftOverGas, err := e.Call(vm.AccountRef(caller), to, input, meter.gas, big.NewInt(0))
+
+		*meter.usedGas += meter.gas - leftOverGas
+		meter.gas = leftOverGas
+
+		return result, err
+	}
+}
+
+// nestedTracer traces the evm calls of the system contracts as the inner calls of the tx,
+// so that the start and end of the tx are not captured again by the nested evm.
+type nestedTracer struct {
+	vm.Tracer
+}
+
+func (t *nestedTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	if nested, ok := t.Tracer.(vm.NestedTracer); ok {
+		return nested.CaptureEnter(from, to, input, gas, value)
+	}
+
+	return nil
+}
+
+func (t *nestedTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	if nested, ok := t.Tracer.(vm.NestedTracer); ok {
+		return nested.CaptureExit(output, gasUsed, err)
+	}
+
+	return nil
+}
+
 func processEvmContract(ctx *Context, gas uint64) (*types.Receipt, error) {
 	var err error
 	receipt := &types.Receipt{
@@ -185,7 +261,12 @@ func processEvmContract(ctx *Context, gas uint64) (*types.Receipt, error) {
 	}
 
 	statedb := &evm.StateDB{Statedb: ctx.Statedb}
-	e := evm.NewEVMByDefaultConfig(ctx.Tx, statedb, ctx.BlockHeader, ctx.BcStore)
+	var vmConfig vm.Config
+	if ctx.Tracer != nil {
+		vmConfig = vm.Config{Debug: true, Tracer: ctx.Tracer}
+	}
+
+	e := evm.NewEVM(ctx.Tx, statedb, ctx.BlockHeader, ctx.BcStore, vmConfig)
 	caller := vm.AccountRef(ctx.Tx.Data.From)
 	var leftOverGas uint64
 
@@ -84,7 +84,7 @@ func Test_Process_SysContract(t *testing.T) {
 	// Do not transfer the amount of the run error
 	ctx2 := ctx1
 	ctx2.Tx.Data.AccountNonce++
-	ctx2.Tx.Data.Payload = append([]byte{system.CmdGetDomainNameOwner + 1}, testBytes...) // 0x007365656c652e66616e
+	ctx2.Tx.Data.Payload = append([]byte{system.CmdGetReverseDomainName + 1}, testBytes...) // 0x007365656c652e66616e
 	ctx2.Tx.Data.Amount = big.NewInt(7)
 
 	fromOriginalBalance := ctx2.Statedb.GetBalance(ctx2.Tx.Data.From)
@@ -98,6 +98,31 @@ func Test_Process_SysContract(t *testing.T) {
 	assert.Equal(t, toOriginalBalance, toCurrentBalance)
 }
 
+func Test_Process_ForkContract(t *testing.T) {
+	// the address of the contract introduced by the fork is a plain account before the fork height
+	ctx, _ := newTestContext(big.NewInt(7))
+	ctx.Tx.Data.To = system.ConfidentialTransferContractAddress
+	ctx.Tx.Data.Payload = []byte{1, 2, 3}
+	ctx.Tx.Hash = ctx.Tx.CalculateHash()
+
+	receipt, err := Process(ctx, ctx.BlockHeader.Height)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, receipt.Failed, false)
+	assert.Equal(t, ctx.Statedb.GetBalance(system.ConfidentialTransferContractAddress), big.NewInt(7))
+
+	// the system contract after the fork height
+	ctx, _ = newTestContext(big.NewInt(7))
+	ctx.Tx.Data.To = system.ConfidentialTransferContractAddress
+	ctx.Tx.Data.Payload = []byte{123}
+	ctx.Tx.Hash = ctx.Tx.CalculateHash()
+	ctx.BlockHeader.Height = common.SystemContractForkHeight
+
+	receipt, err = Process(ctx, ctx.BlockHeader.Height)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, receipt.Failed, true)
+	assert.Equal(t, ctx.Statedb.GetBalance(system.ConfidentialTransferContractAddress).Sign(), 0)
+}
+
 func Test_Process_CrossTransfer(t *testing.T) {
 	ctx, err := newTestContext(big.NewInt(1000))
 	assert.NoError(t, err)
@@ -227,6 +252,92 @@ func Test_Process_ErrOutOfGas(t *testing.T) {
 	assert.Equal(t, balanceOri4.Uint64(), balanceCur4.Uint64()+receipt4.TotalFee)
 }
 
+func Test_newContractCaller(t *testing.T) {
+	ctx, _ := newTestContext(big.NewInt(0))
+	receipt, err := Process(ctx, ctx.BlockHeader.Height)
+	assert.Equal(t, err, nil)
+	contractAddr := common.BytesToAddress(receipt.ContractAddress)
+
+	// SimpleStorage.get() returns 5 as initialized in constructor.
+	var usedGas uint64
+	call := newContractCaller(ctx, &gasMeter{100000, &usedGas})
+	result, err := call(system.BTCRelayContractAddress, contractAddr, mustHexToBytes("0x6d4ce63c"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, new(big.Int).SetBytes(result).Uint64(), uint64(5))
+	assert.Equal(t, usedGas > 0 && usedGas < 100000, true)
+
+	// the calls share the gas
+	call = newContractCaller(ctx, &gasMeter{usedGas + 1, &usedGas})
+	_, err = call(system.BTCRelayContractAddress, contractAddr, mustHexToBytes("0x6d4ce63c"))
+	assert.Equal(t, err, nil)
+	_, err = call(system.BTCRelayContractAddress, contractAddr, mustHexToBytes("0x6d4ce63c"))
+	assert.Equal(t, err, vm.ErrOutOfGas)
+}
+
+func Test_gasMeter(t *testing.T) {
+	usedGas := uint64(100)
+	meter := &gasMeter{50, &usedGas}
+
+	assert.Equal(t, meter.useGas(30), nil)
+	assert.Equal(t, usedGas, uint64(130))
+	assert.Equal(t, meter.gas, uint64(20))
+
+	// all the gas left is used if out of gas
+	assert.Equal(t, meter.useGas(30), vm.ErrOutOfGas)
+	assert.Equal(t, usedGas, uint64(150))
+	assert.Equal(t, meter.gas, uint64(0))
+}
+
+// recordTracer records the captured events of the traced tx
+type recordTracer struct {
+	events []string
+}
+
+func (t *recordTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.events = append(t.events, "start")
+	return nil
+}
+
+func (t *recordTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *recordTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+func (t *recordTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	t.events = append(t.events, "end")
+	return nil
+}
+
+func (t *recordTracer) CaptureEnter(from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) error {
+	t.events = append(t.events, "enter")
+	return nil
+}
+
+func (t *recordTracer) CaptureExit(output []byte, gasUsed uint64, err error) error {
+	t.events = append(t.events, "exit")
+	return nil
+}
+
+func Test_newContractCaller_Tracer(t *testing.T) {
+	ctx, _ := newTestContext(big.NewInt(0))
+	receipt, err := Process(ctx, ctx.BlockHeader.Height)
+	assert.Equal(t, err, nil)
+	contractAddr := common.BytesToAddress(receipt.ContractAddress)
+
+	// the evm call of the system contract is traced as an inner call, not the start of the tx
+	tracer := &recordTracer{}
+	ctx.Tracer = tracer
+
+	var usedGas uint64
+	call := newContractCaller(ctx, &gasMeter{100000, &usedGas})
+	_, err = call(system.BTCRelayContractAddress, contractAddr, mustHexToBytes("0x6d4ce63c"))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, tracer.events, []string{"enter", "exit"})
+}
+
 func mustHexToBytes(hex string) []byte {
 	code, err := hexutil.HexToBytes(hex)
 	if err != nil {
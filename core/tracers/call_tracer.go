@@ -0,0 +1,259 @@
+/**
+* @file
+* @copyright defined in go-seele/LICENSE
+ */
+
+package tracers
+
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/core/vm"
+)
+
+// maxCapturedSize is the max size of the input or output captured from memory
+const maxCapturedSize = 1024 * 1024
+
+var errCallFailed = errors.New("call failed")
+
+// CallFrame is a call in the call tree
+type CallFrame struct {
+	Type    string         `json:"type"`
+	From    common.Address `json:"from"`
+	To      common.Address `json:"to"`
+	Value   *big.Int       `json:"value"`
+	Gas     uint64         `json:"gas"`
+	GasUsed uint64         `json:"gasUsed"`
+	Input   string         `json:"input"`
+	Output  string         `json:"output,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Calls   []*CallFrame   `json:"calls,omitempty"`
+
+	depth   int    // depth of the caller when the call is issued
+	gasIn   uint64 // gas of the caller when the call is issued
+	outOff  int64  // memory offset of the output in the caller
+	outSize int64  // memory size of the output in the caller
+	nested  bool   // issued out of the evm, e.g. by the system contracts
+}
+
+// CallTracer traces the call tree of a tx, including the calls of the system contracts.
+// The inner calls are captured from the CALL and CREATE opcodes, and finished when the
+// execution returns to the depth of the caller.
+type CallTracer struct {
+	root  *CallFrame
+	stack []*CallFrame // the inner calls in progress
+}
+
+// NewCallTracer returns a new call tracer
+func NewCallTracer() *CallTracer {
+	return &CallTracer{}
+}
+
+// CaptureStart implements vm.Tracer
+func (t *CallTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	callType := "CALL"
+	if create {
+		callType = "CREATE"
+	} else if system.GetContractByAddress(to) != nil {
+		callType = "SYSTEM"
+	}
+
+	t.root = &CallFrame{
+		Type:  callType,
+		From:  from,
+		To:    to,
+		Value: copyBig(value),
+		Gas:   gas,
+		Input: hexutil.BytesToHex(input),
+	}
+
+	return nil
+}
+
+// CaptureState implements vm.Tracer
+func (t *CallTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	// the execution returns to the caller, so the calls issued at this depth or deeper are finished
+	for len(t.stack) > 0 && t.stack[len(t.stack)-1].depth >= depth {
+		t.finish(gas, memory, stack, depth == t.stack[len(t.stack)-1].depth)
+	}
+
+	if err != nil {
+		return nil
+	}
+
+	frame := &CallFrame{Type: op.String(), From: contract.Address(), depth: depth, gasIn: gas}
+	switch op {
+	case vm.CREATE, vm.CREATE2:
+		frame.Value = copyBig(stack.Back(0))
+		frame.Input = hexutil.BytesToHex(memorySlice(memory, stack.Back(1).Int64(), stack.Back(2).Int64()))
+		frame.Gas = gas - cost
+	case vm.CALL, vm.CALLCODE:
+		frame.Gas = stack.Back(0).Uint64()
+		frame.To = common.BigToAddress(stack.Back(1))
+		frame.Value = copyBig(stack.Back(2))
+		frame.Input = hexutil.BytesToHex(memorySlice(memory, stack.Back(3).Int64(), stack.Back(4).Int64()))
+		frame.outOff, frame.outSize = stack.Back(5).Int64(), stack.Back(6).Int64()
+	case vm.DELEGATECALL, vm.STATICCALL:
+		frame.Gas = stack.Back(0).Uint64()
+		frame.To = common.BigToAddress(stack.Back(1))
+		frame.Input = hexutil.BytesToHex(memorySlice(memory, stack.Back(2).Int64(), stack.Back(3).Int64()))
+		frame.outOff, frame.outSize = stack.Back(4).Int64(), stack.Back(5).Int64()
+	default:
+		return nil
+	}
+
+	if frame.Value == nil {
+		frame.Value = new(big.Int)
+	}
+
+	t.stack = append(t.stack, frame)
+	return nil
+}
+
+// finish pops the innermost call in progress and attaches it to its parent.
+// If returned to the caller, the call result is on the top of the stack.
+func (t *CallTracer) finish(gas uint64, memory *vm.Memory, stack *vm.Stack, returned bool) {
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	if frame.gasIn > gas {
+		frame.GasUsed = frame.gasIn - gas
+	}
+
+	switch {
+	case !returned || len(stack.Data()) == 0:
+		frame.Error = errCallFailed.Error()
+	case frame.Type == vm.CREATE.String() || frame.Type == vm.CREATE2.String():
+		if result := stack.Back(0); result.Sign() != 0 {
+			frame.To = common.BigToAddress(result)
+		} else {
+			frame.Error = errCallFailed.Error()
+		}
+	default:
+		if stack.Back(0).Sign() == 0 {
+			frame.Error = errCallFailed.Error()
+		}
+		frame.Output = hexutil.BytesToHex(memorySlice(memory, frame.outOff, frame.outSize))
+	}
+
+	t.attach(frame)
+}
+
+// attach appends the finished call to the calls of its parent.
+func (t *CallTracer) attach(frame *CallFrame) {
+	parent := t.root
+	if len(t.stack) > 0 {
+		parent = t.stack[len(t.stack)-1]
+	}
+
+	if parent != nil {
+		parent.Calls = append(parent.Calls, frame)
+	}
+}
+
+// CaptureEnter implements vm.NestedTracer, the evm call of the system contract is an inner call of the tx.
+func (t *CallTracer) CaptureEnter(from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) error {
+	t.stack = append(t.stack, &CallFrame{
+		Type:   vm.CALL.String(),
+		From:   from,
+		To:     to,
+		Value:  copyBig(value),
+		Gas:    gas,
+		Input:  hexutil.BytesToHex(input),
+		nested: true,
+	})
+
+	return nil
+}
+
+// CaptureExit implements vm.NestedTracer
+func (t *CallTracer) CaptureExit(output []byte, gasUsed uint64, err error) error {
+	// the calls in progress of the nested call are aborted
+	for len(t.stack) > 0 && !t.stack[len(t.stack)-1].nested {
+		t.finish(0, nil, nil, false)
+	}
+
+	if len(t.stack) == 0 {
+		return nil
+	}
+
+	frame := t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+
+	frame.GasUsed = gasUsed
+	frame.Output = hexutil.BytesToHex(output)
+	if err != nil {
+		frame.Error = err.Error()
+	}
+
+	t.attach(frame)
+	return nil
+}
+
+// CaptureFault implements vm.Tracer
+func (t *CallTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd implements vm.Tracer
+func (t *CallTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	// the calls in progress are aborted, e.g. out of gas
+	for len(t.stack) > 0 {
+		t.finish(0, nil, nil, false)
+	}
+
+	if t.root == nil {
+		return nil
+	}
+
+	t.root.GasUsed = gasUsed
+	t.root.Output = hexutil.BytesToHex(output)
+	if err != nil {
+		t.root.Error = err.Error()
+	}
+
+	return nil
+}
+
+// GetResult implements Tracer, returns the root call frame
+func (t *CallTracer) GetResult(receipt *types.Receipt) (interface{}, error) {
+	if t.root == nil {
+		return nil, errors.New("no call is traced")
+	}
+
+	if len(receipt.ContractAddress) > 0 {
+		t.root.To = common.BytesToAddress(receipt.ContractAddress)
+	}
+
+	return t.root, nil
+}
+
+func copyBig(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Set(v)
+}
+
+// memorySlice returns a copy of the memory, the part out of the memory bounds is zero
+// since the memory is not expanded yet when the call opcode is captured.
+func memorySlice(memory *vm.Memory, offset, size int64) []byte {
+	if size <= 0 || offset < 0 || size > maxCapturedSize {
+		return nil
+	}
+
+	data := memory.Data()
+	result := make([]byte, size)
+	if offset < int64(len(data)) {
+		copy(result, data[offset:])
+	}
+
+	return result
+}
@@ -0,0 +1,139 @@
+/**
+* @file
+* @copyright defined in go-seele/LICENSE
+ */
+
+package tracers
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/core/vm"
+)
+
+// PrestateAccount is the state of an account before the tx is executed
+type PrestateAccount struct {
+	Balance *big.Int                    `json:"balance"`
+	Nonce   uint64                      `json:"nonce"`
+	Code    string                      `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+}
+
+// PrestateTracer collects the accounts and storage slots touched by a tx with
+// their values before the tx is executed, which is enough to replay the tx.
+type PrestateTracer struct {
+	statedb  StateReader
+	accounts map[common.Address]*PrestateAccount
+}
+
+// NewPrestateTracer returns a new prestate tracer which reads the accounts from the statedb
+func NewPrestateTracer(statedb StateReader) *PrestateTracer {
+	return &PrestateTracer{
+		statedb:  statedb,
+		accounts: make(map[common.Address]*PrestateAccount),
+	}
+}
+
+// CaptureStart implements vm.Tracer. The nonce of the sender is increased and the value
+// is transferred before the execution starts, so they are reverted in the prestate.
+func (t *PrestateTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	t.lookupAccount(from)
+	t.lookupAccount(to)
+
+	sender, recipient := t.accounts[from], t.accounts[to]
+	if value != nil && from != to {
+		sender.Balance.Add(sender.Balance, value)
+		recipient.Balance.Sub(recipient.Balance, value)
+	}
+
+	if sender.Nonce > 0 {
+		sender.Nonce--
+	}
+
+	if create {
+		delete(t.accounts, to)
+	}
+
+	return nil
+}
+
+// CaptureEnter implements vm.NestedTracer, the accounts of the evm call of the system contract are touched.
+func (t *PrestateTracer) CaptureEnter(from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) error {
+	t.lookupAccount(from)
+	t.lookupAccount(to)
+
+	return nil
+}
+
+// CaptureExit implements vm.NestedTracer
+func (t *PrestateTracer) CaptureExit(output []byte, gasUsed uint64, err error) error {
+	return nil
+}
+
+// CaptureState implements vm.Tracer
+func (t *PrestateTracer) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if err != nil {
+		return nil
+	}
+
+	switch op {
+	case vm.SLOAD, vm.SSTORE:
+		t.lookupStorage(contract.Address(), common.BigToHash(stack.Back(0)))
+	case vm.EXTCODECOPY, vm.EXTCODESIZE, vm.EXTCODEHASH, vm.BALANCE, vm.SELFDESTRUCT:
+		t.lookupAccount(common.BigToAddress(stack.Back(0)))
+	case vm.CALL, vm.CALLCODE, vm.DELEGATECALL, vm.STATICCALL:
+		t.lookupAccount(common.BigToAddress(stack.Back(1)))
+	}
+
+	return nil
+}
+
+// CaptureFault implements vm.Tracer
+func (t *PrestateTracer) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	return nil
+}
+
+// CaptureEnd implements vm.Tracer
+func (t *PrestateTracer) CaptureEnd(output []byte, gasUsed uint64, d time.Duration, err error) error {
+	return nil
+}
+
+// GetResult implements Tracer, returns the accounts touched by the tx
+func (t *PrestateTracer) GetResult(receipt *types.Receipt) (interface{}, error) {
+	return t.accounts, nil
+}
+
+// lookupAccount records the account if it is not recorded yet
+func (t *PrestateTracer) lookupAccount(addr common.Address) {
+	if _, ok := t.accounts[addr]; ok {
+		return
+	}
+
+	var code string
+	if c := t.statedb.GetCode(addr); len(c) > 0 {
+		code = hexutil.BytesToHex(c)
+	}
+
+	t.accounts[addr] = &PrestateAccount{
+		Balance: copyBig(t.statedb.GetBalance(addr)),
+		Nonce:   t.statedb.GetNonce(addr),
+		Code:    code,
+		Storage: make(map[common.Hash]common.Hash),
+	}
+}
+
+// lookupStorage records the storage slot if it is not recorded yet, it is called
+// before the slot is changed, so the value is the one before the tx is executed.
+func (t *PrestateTracer) lookupStorage(addr common.Address, key common.Hash) {
+	t.lookupAccount(addr)
+
+	if _, ok := t.accounts[addr].Storage[key]; ok {
+		return
+	}
+
+	t.accounts[addr].Storage[key] = common.BytesToHash(t.statedb.GetData(addr, key))
+}
@@ -0,0 +1,102 @@
+/**
+* @file
+* @copyright defined in go-seele/LICENSE
+ */
+
+package tracers
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/core/vm"
+)
+
+const (
+	// CallTracerName is the name of the call tree tracer
+	CallTracerName = "callTracer"
+
+	// PrestateTracerName is the name of the prestate tracer
+	PrestateTracerName = "prestateTracer"
+)
+
+// TraceConfig is the options to trace a tx
+type TraceConfig struct {
+	*vm.LogConfig
+
+	// Tracer is the name of the tracer, could be callTracer or prestateTracer.
+	// The opcode struct logger is used if empty.
+	Tracer string `json:"tracer"`
+}
+
+// Tracer is a vm tracer which returns the trace result after the tx is executed
+type Tracer interface {
+	vm.Tracer
+
+	// GetResult returns the trace result, the receipt is the result of the traced tx
+	GetResult(receipt *types.Receipt) (interface{}, error)
+}
+
+// StateReader is the state to read the accounts before the tx is executed
+type StateReader interface {
+	GetBalance(common.Address) *big.Int
+	GetNonce(common.Address) uint64
+	GetCode(common.Address) []byte
+	GetData(common.Address, common.Hash) []byte
+}
+
+// New returns the tracer specified by the config, the statedb is used by the prestate tracer.
+func New(config *TraceConfig, statedb StateReader) (Tracer, error) {
+	if config == nil {
+		config = &TraceConfig{}
+	}
+
+	switch config.Tracer {
+	case "":
+		return &structTracer{vm.NewStructLogger(config.LogConfig)}, nil
+	case CallTracerName:
+		return NewCallTracer(), nil
+	case PrestateTracerName:
+		return NewPrestateTracer(statedb), nil
+	default:
+		return nil, fmt.Errorf("unknown tracer %s", config.Tracer)
+	}
+}
+
+// ExecutionResult is the trace result of the opcode struct logger
+type ExecutionResult struct {
+	Gas         uint64         `json:"gas"`
+	Failed      bool           `json:"failed"`
+	ReturnValue string         `json:"returnValue"`
+	StructLogs  []vm.StructLog `json:"structLogs"`
+}
+
+// structTracer wraps the opcode struct logger as a Tracer
+type structTracer struct {
+	*vm.StructLogger
+}
+
+// GetResult implements Tracer
+func (t *structTracer) GetResult(receipt *types.Receipt) (interface{}, error) {
+	logs := t.StructLogs()
+	if logs == nil {
+		logs = make([]vm.StructLog, 0)
+	}
+
+	return &ExecutionResult{
+		Gas:         receipt.UsedGas,
+		Failed:      receipt.Failed,
+		ReturnValue: hexutil.BytesToHex(t.Output()),
+		StructLogs:  logs,
+	}, nil
+}
+
+// TxTraceResult is the trace result of a tx in a block
+type TxTraceResult struct {
+	TxHash common.Hash `json:"txHash"`
+	Result interface{} `json:"result,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
@@ -0,0 +1,216 @@
+/**
+* @file
+* @copyright defined in go-seele/LICENSE
+ */
+
+package tracers
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/core/store"
+	"github.com/seeleteam/go-seele/core/svm"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/seeleteam/go-seele/database/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+// simple storage contract, please refer to contract/solidity/simple_storage.sol
+const simpleStorageCode = "0x608060405234801561001057600080fd5b50600560008190555060df806100276000396000f3006080604052600436106049576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806360fe47b114604e5780636d4ce63c146078575b600080fd5b348015605957600080fd5b5060766004803603810190808035906020019092919050505060a0565b005b348015608357600080fd5b50608a60aa565b6040518082815260200191505060405180910390f35b8060008190555050565b600080549050905600a165627a7a723058207f6dc43a0d648e9f5a0cad5071cde46657de72eb87ab4cded53a7f1090f51e6d0029"
+
+type testEnv struct {
+	statedb  *state.Statedb
+	bcStore  store.BlockchainStore
+	header   *types.BlockHeader
+	from     common.Address
+	contract common.Address
+	nonce    uint64
+}
+
+func newTestEnv(t *testing.T) (*testEnv, func()) {
+	db, dispose := leveldb.NewTestDatabase()
+	statedb, err := state.NewStatedb(common.EmptyHash, db)
+	assert.Equal(t, err, nil)
+
+	env := &testEnv{
+		statedb: statedb,
+		bcStore: store.NewBlockchainDatabase(db),
+		header: &types.BlockHeader{
+			Creator:         *crypto.MustGenerateRandomAddress(),
+			Difficulty:      big.NewInt(1),
+			Height:          666,
+			CreateTimestamp: big.NewInt(time.Now().Unix()),
+		},
+		from: *crypto.MustGenerateRandomAddress(),
+	}
+
+	statedb.CreateAccount(env.from)
+	statedb.SetBalance(env.from, common.SeeleToFan)
+
+	tx, err := types.NewContractTransaction(env.from, big.NewInt(0), big.NewInt(1), 5000000, env.nonce, hexutil.MustHexToBytes(simpleStorageCode))
+	assert.Equal(t, err, nil)
+
+	receipt := env.process(t, tx, nil)
+	assert.Equal(t, receipt.Failed, false)
+	env.contract = common.BytesToAddress(receipt.ContractAddress)
+
+	return env, dispose
+}
+
+func (env *testEnv) process(t *testing.T, tx *types.Transaction, tracer Tracer) *types.Receipt {
+	ctx := &svm.Context{
+		Tx:          tx,
+		TxIndex:     1,
+		Statedb:     env.statedb,
+		BlockHeader: env.header,
+		BcStore:     env.bcStore,
+	}
+
+	if tracer != nil {
+		ctx.Tracer = tracer
+	}
+
+	receipt, err := svm.Process(ctx, env.header.Height)
+	assert.Equal(t, err, nil)
+	env.nonce++
+
+	return receipt
+}
+
+// newSetTx returns the tx to call SimpleStorage.set(7)
+func (env *testEnv) newSetTx(t *testing.T, amount int64) *types.Transaction {
+	input := hexutil.MustHexToBytes("0x60fe47b10000000000000000000000000000000000000000000000000000000000000007")
+	tx, err := types.NewMessageTransaction(env.from, env.contract, big.NewInt(amount), big.NewInt(1), 5000000, env.nonce, input)
+	assert.Equal(t, err, nil)
+
+	return tx
+}
+
+func Test_New(t *testing.T) {
+	tracer, err := New(nil, nil)
+	assert.Equal(t, err, nil)
+	_, ok := tracer.(*structTracer)
+	assert.Equal(t, ok, true)
+
+	tracer, err = New(&TraceConfig{Tracer: CallTracerName}, nil)
+	assert.Equal(t, err, nil)
+	_, ok = tracer.(*CallTracer)
+	assert.Equal(t, ok, true)
+
+	tracer, err = New(&TraceConfig{Tracer: PrestateTracerName}, nil)
+	assert.Equal(t, err, nil)
+	_, ok = tracer.(*PrestateTracer)
+	assert.Equal(t, ok, true)
+
+	_, err = New(&TraceConfig{Tracer: "unknown"}, nil)
+	assert.Equal(t, err != nil, true)
+}
+
+func Test_StructTracer(t *testing.T) {
+	env, dispose := newTestEnv(t)
+	defer dispose()
+
+	tracer, err := New(nil, env.statedb)
+	assert.Equal(t, err, nil)
+
+	receipt := env.process(t, env.newSetTx(t, 0), tracer)
+	assert.Equal(t, receipt.Failed, false)
+
+	result, err := tracer.GetResult(receipt)
+	assert.Equal(t, err, nil)
+
+	execution := result.(*ExecutionResult)
+	assert.Equal(t, execution.Gas, receipt.UsedGas)
+	assert.Equal(t, execution.Failed, false)
+	assert.Equal(t, len(execution.StructLogs) > 0, true)
+	assert.Equal(t, execution.StructLogs[len(execution.StructLogs)-1].Op.String(), "STOP")
+}
+
+func Test_CallTracer(t *testing.T) {
+	env, dispose := newTestEnv(t)
+	defer dispose()
+
+	tracer := NewCallTracer()
+	receipt := env.process(t, env.newSetTx(t, 0), tracer)
+	assert.Equal(t, receipt.Failed, false)
+
+	result, err := tracer.GetResult(receipt)
+	assert.Equal(t, err, nil)
+
+	frame := result.(*CallFrame)
+	assert.Equal(t, frame.Type, "CALL")
+	assert.Equal(t, frame.From, env.from)
+	assert.Equal(t, frame.To, env.contract)
+	assert.Equal(t, frame.Error, "")
+	assert.Equal(t, len(frame.Calls), 0)
+
+	// the contract is not payable, so the call is reverted
+	tracer = NewCallTracer()
+	receipt = env.process(t, env.newSetTx(t, 1), tracer)
+	assert.Equal(t, receipt.Failed, true)
+
+	result, err = tracer.GetResult(receipt)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result.(*CallFrame).Error != "", true)
+}
+
+func Test_CallTracer_Nested(t *testing.T) {
+	relay := system.BTCRelayContractAddress
+	from, to := *crypto.MustGenerateRandomAddress(), *crypto.MustGenerateRandomAddress()
+
+	// the evm call of the system contract is a child of the root SYSTEM frame
+	tracer := NewCallTracer()
+	assert.Equal(t, tracer.CaptureStart(from, relay, false, []byte{1}, 100000, big.NewInt(0)), nil)
+	assert.Equal(t, tracer.CaptureEnter(relay, to, []byte{2}, 50000, big.NewInt(0)), nil)
+	assert.Equal(t, tracer.CaptureExit([]byte{3}, 20000, nil), nil)
+	assert.Equal(t, tracer.CaptureEnd([]byte{4}, 30000, time.Second, nil), nil)
+
+	result, err := tracer.GetResult(&types.Receipt{})
+	assert.Equal(t, err, nil)
+
+	root := result.(*CallFrame)
+	assert.Equal(t, root.Type, "SYSTEM")
+	assert.Equal(t, root.From, from)
+	assert.Equal(t, root.GasUsed, uint64(30000))
+	assert.Equal(t, root.Output, "0x04")
+	assert.Equal(t, len(root.Calls), 1)
+
+	child := root.Calls[0]
+	assert.Equal(t, child.Type, "CALL")
+	assert.Equal(t, child.From, relay)
+	assert.Equal(t, child.To, to)
+	assert.Equal(t, child.Gas, uint64(50000))
+	assert.Equal(t, child.GasUsed, uint64(20000))
+	assert.Equal(t, child.Output, "0x03")
+}
+
+func Test_PrestateTracer(t *testing.T) {
+	env, dispose := newTestEnv(t)
+	defer dispose()
+
+	balance := new(big.Int).Set(env.statedb.GetBalance(env.from))
+	nonce := env.statedb.GetNonce(env.from)
+
+	tracer := NewPrestateTracer(env.statedb)
+	receipt := env.process(t, env.newSetTx(t, 0), tracer)
+	assert.Equal(t, receipt.Failed, false)
+
+	result, err := tracer.GetResult(receipt)
+	assert.Equal(t, err, nil)
+
+	accounts := result.(map[common.Address]*PrestateAccount)
+	assert.Equal(t, accounts[env.from].Nonce, nonce)
+	assert.Equal(t, accounts[env.from].Balance, balance)
+
+	// the storage slot 0 is 5 as initialized in constructor, and changed to 7 by the tx
+	storage := accounts[env.contract].Storage
+	assert.Equal(t, storage[common.EmptyHash], common.BigToHash(big.NewInt(5)))
+	assert.Equal(t, common.BytesToHash(env.statedb.GetData(env.contract, common.EmptyHash)), common.BigToHash(big.NewInt(7)))
+}
@@ -0,0 +1,81 @@
+/**
+* @file
+* @copyright defined in go-seele/LICENSE
+ */
+
+package txs
+
+import (
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/errors"
+	"github.com/seeleteam/go-seele/consensus"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/core/types"
+)
+
+var errElectedVerifiersMismatch = errors.New("verifier changes mismatch the staking election")
+
+// IsElectionHeight returns whether the verifiers are elected by stake at the block height, which is
+// the epoch boundary of the engine after the system contract fork height.
+func IsElectionHeight(engine consensus.Engine, height uint64) bool {
+	reader, ok := engine.(consensus.EpochReader)
+	if !ok || height < common.SystemContractForkHeight {
+		return false
+	}
+
+	epoch := reader.Epoch()
+	return epoch > 0 && height%epoch == 0
+}
+
+// ElectVerifierChanges returns the verifiers to add and remove from the verifiers of the header to the candidates
+// of the most stake, and elected is false if no candidate is eligible, so that the verifiers are not changed by stake.
+func ElectVerifierChanges(statedb *state.Statedb, header *types.BlockHeader) (added []common.Address, removed []common.Address, elected bool, err error) {
+	verifiers, err := system.ElectVerifiers(statedb)
+	if err != nil || len(verifiers) == 0 {
+		return nil, nil, false, err
+	}
+
+	bftExtra, err := types.ExtractBftExtra(header)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	added, removed = system.VerifierChanges(bftExtra.Verifiers, verifiers)
+	return added, removed, true, nil
+}
+
+// ValidateElectedVerifiers checks the verifiers added and removed in the second witness of the header
+// are exactly the changes from the current verifiers to the elected ones.
+func ValidateElectedVerifiers(statedb *state.Statedb, header *types.BlockHeader) error {
+	added, removed, elected, err := ElectVerifierChanges(statedb, header)
+	if err != nil || !elected {
+		return err
+	}
+
+	swExtra, err := types.ExtractSecondWitnessInfo(header)
+	if err != nil {
+		return err
+	}
+
+	if !sameAddresses(swExtra.DepositVers, added) || !sameAddresses(swExtra.ExitVers, removed) {
+		return errElectedVerifiersMismatch
+	}
+
+	return nil
+}
+
+// sameAddresses returns whether the addresses are the same in the same order
+func sameAddresses(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
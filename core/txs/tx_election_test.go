@@ -0,0 +1,111 @@
+/**
+* @file
+* @copyright defined in go-seele/LICENSE
+ */
+
+package txs
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/consensus"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/seeleteam/go-seele/database/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+// testEpochEngine is a bft like engine with the epoch in config
+type testEpochEngine struct {
+	consensus.Engine
+	epoch uint64
+}
+
+func (engine *testEpochEngine) Epoch() uint64 {
+	return engine.epoch
+}
+
+func Test_IsElectionHeight(t *testing.T) {
+	engine := &testEpochEngine{epoch: 1000}
+	height := uint64(common.SystemContractForkHeight)
+
+	assert.Equal(t, IsElectionHeight(engine, height), true)
+	assert.Equal(t, IsElectionHeight(engine, height+1000), true)
+	assert.Equal(t, IsElectionHeight(engine, height+1), false)
+	assert.Equal(t, IsElectionHeight(engine, height-1000), false)
+
+	// the epoch of the engine config is used
+	assert.Equal(t, IsElectionHeight(&testEpochEngine{epoch: 7000}, height+1000), false)
+	assert.Equal(t, IsElectionHeight(&testEpochEngine{}, height), false)
+	assert.Equal(t, IsElectionHeight(&struct{ consensus.Engine }{}, height), false)
+}
+
+// newTestElectionHeader returns the bft header with the current verifiers and the verifier changes
+func newTestElectionHeader(t *testing.T, verifiers, deposits, exits []common.Address) *types.BlockHeader {
+	extra, err := rlp.EncodeToBytes(&types.BftExtra{Verifiers: verifiers, Seal: []byte{}, CommittedSeal: [][]byte{}})
+	assert.Equal(t, err, nil)
+
+	witness, err := types.PrepareSecondWitness(nil, deposits, exits, 0, common.EmptyHash, common.EmptyHash, common.EmptyHash, crypto.Signature{Sig: []byte{}})
+	assert.Equal(t, err, nil)
+
+	return &types.BlockHeader{
+		Height:        common.SystemContractForkHeight,
+		Consensus:     types.BftConsensus,
+		ExtraData:     append(make([]byte, types.BftExtraVanity), extra...),
+		SecondWitness: witness,
+	}
+}
+
+func Test_ValidateElectedVerifiers(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	statedb, err := state.NewStatedb(common.EmptyHash, db)
+	assert.Equal(t, err, nil)
+	statedb.CreateAccount(system.StakingContractAddress)
+
+	a, b, c, d := common.BytesToAddress([]byte{1}), common.BytesToAddress([]byte{2}), common.BytesToAddress([]byte{3}), common.BytesToAddress([]byte{4})
+
+	// the verifiers are not changed by stake without any candidate
+	assert.Equal(t, ValidateElectedVerifiers(statedb, newTestElectionHeader(t, []common.Address{a, b}, []common.Address{d}, nil)), nil)
+
+	// the candidates a and c are elected
+	contract := system.GetContractByAddress(system.StakingContractAddress)
+	for _, candidate := range []common.Address{a, c} {
+		tx := &types.Transaction{Data: types.TransactionData{From: candidate, Amount: system.MinSelfStake}}
+		header := &types.BlockHeader{Height: common.SystemContractForkHeight, CreateTimestamp: big.NewInt(1)}
+		_, err = contract.Run([]byte{system.CmdStakingBond}, system.NewContext(tx, statedb, header))
+		assert.Equal(t, err, nil)
+	}
+
+	header := newTestElectionHeader(t, []common.Address{a, b}, nil, nil)
+	added, removed, elected, err := ElectVerifierChanges(statedb, header)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, elected, true)
+	assert.Equal(t, added, []common.Address{c})
+	assert.Equal(t, removed, []common.Address{b})
+
+	// the verifier changes should be exactly the changes of the election
+	cases := []struct {
+		deposits []common.Address
+		exits    []common.Address
+		err      error
+	}{
+		{[]common.Address{c}, []common.Address{b}, nil},
+		{nil, nil, errElectedVerifiersMismatch},
+		{[]common.Address{c}, nil, errElectedVerifiersMismatch},
+		{[]common.Address{c, d}, []common.Address{b}, errElectedVerifiersMismatch},
+		{[]common.Address{c}, []common.Address{b, a}, errElectedVerifiersMismatch},
+		{[]common.Address{c, c}, []common.Address{b}, errElectedVerifiersMismatch},
+	}
+
+	for i, c := range cases {
+		err = ValidateElectedVerifiers(statedb, newTestElectionHeader(t, []common.Address{a, b}, c.deposits, c.exits))
+		assert.Equal(t, err, c.err, "case %d", i)
+	}
+}
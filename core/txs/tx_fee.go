@@ -0,0 +1,195 @@
+/**
+* @file
+* @copyright defined in go-seele/LICENSE
+ */
+
+package txs
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/errors"
+	"github.com/seeleteam/go-seele/consensus"
+	"github.com/seeleteam/go-seele/consensus/bft"
+	bftCore "github.com/seeleteam/go-seele/consensus/bft/core"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/crypto"
+)
+
+// VerifierFeeTopic is the log topic of the fee paid to a verifier at the relay checkpoint,
+// the second topic is the verifier, and the data is the amount and the commits.
+var VerifierFeeTopic = crypto.MustHash("VerifierFee(address,uint256,uint64)")
+
+var (
+	errInvalidFeeLog     = errors.New("invalid verifier fee log")
+	errFeeHeaderNotFound = errors.New("header in the relay period not found")
+)
+
+// VerifierFee is the fee paid to a verifier for the blocks committed in the relay period
+type VerifierFee struct {
+	Verifier common.Address
+	Commits  uint64
+	Amount   *big.Int
+}
+
+// ApplyVerifierFees splits the balance of the subchain fee account to the verifiers at the relay checkpoint after
+// the verifier fee fork height, weighted by the commits of the blocks in the relay period before the header. The fees
+// are recorded as the logs of the reward receipt, and the remainder is kept for the next period.
+func ApplyVerifierFees(chain consensus.ChainReader, statedb *state.Statedb, header *types.BlockHeader, rewardReceipt *types.Receipt) error {
+	if header.Consensus != types.BftConsensus || header.Height < common.VerifierFeeForkHeight || header.Height%common.RelayInterval != 0 {
+		return nil
+	}
+
+	commits, err := countVerifierCommits(chain, header)
+	if err != nil {
+		return errors.NewStackedErrorf(err, "failed to count verifier commits before height %v", header.Height)
+	}
+
+	fees := DistributeVerifierFees(statedb.GetBalance(common.SubchainFeeAccount), commits)
+	for _, fee := range fees {
+		statedb.SubBalance(common.SubchainFeeAccount, fee.Amount)
+		statedb.CreateAccount(fee.Verifier)
+		statedb.AddBalance(fee.Verifier, fee.Amount)
+
+		rewardReceipt.Logs = append(rewardReceipt.Logs, fee.log(header.Height))
+	}
+
+	if len(fees) > 0 {
+		hash, err := statedb.Hash()
+		if err != nil {
+			return errors.NewStackedError(err, "failed to get statedb root hash")
+		}
+
+		rewardReceipt.PostState = hash
+	}
+
+	return nil
+}
+
+// DistributeVerifierFees splits the total fee by the commits of the verifiers, ordered by the verifier address.
+func DistributeVerifierFees(total *big.Int, commits map[common.Address]uint64) []*VerifierFee {
+	sum := uint64(0)
+	for _, count := range commits {
+		sum += count
+	}
+
+	if sum == 0 || total.Sign() <= 0 {
+		return nil
+	}
+
+	fees := make([]*VerifierFee, 0, len(commits))
+	for verifier, count := range commits {
+		amount := new(big.Int).Mul(total, new(big.Int).SetUint64(count))
+		amount.Div(amount, new(big.Int).SetUint64(sum))
+		if amount.Sign() > 0 {
+			fees = append(fees, &VerifierFee{verifier, count, amount})
+		}
+	}
+
+	sort.Slice(fees, func(i, j int) bool {
+		return bytes.Compare(fees[i].Verifier.Bytes(), fees[j].Verifier.Bytes()) < 0
+	})
+
+	return fees
+}
+
+// VerifierFeesFromReceipt returns the verifier fees recorded in the logs of the reward receipt
+func VerifierFeesFromReceipt(receipt *types.Receipt) ([]*VerifierFee, error) {
+	var fees []*VerifierFee
+	for _, log := range receipt.Logs {
+		if !log.Address.Equal(common.SubchainFeeAccount) || len(log.Topics) != 2 || log.Topics[0] != VerifierFeeTopic {
+			continue
+		}
+
+		if len(log.Data) != 2*common.HashLength {
+			return nil, errInvalidFeeLog
+		}
+
+		fees = append(fees, &VerifierFee{
+			Verifier: common.BytesToAddress(log.Topics[1].Bytes()),
+			Amount:   new(big.Int).SetBytes(log.Data[:common.HashLength]),
+			Commits:  new(big.Int).SetBytes(log.Data[common.HashLength:]).Uint64(),
+		})
+	}
+
+	return fees, nil
+}
+
+func (fee *VerifierFee) log(height uint64) *types.Log {
+	data := append(common.BigToHash(fee.Amount).Bytes(), common.BigToHash(new(big.Int).SetUint64(fee.Commits)).Bytes()...)
+
+	return &types.Log{
+		Address:     common.SubchainFeeAccount,
+		Topics:      []common.Hash{VerifierFeeTopic, common.BytesToHash(fee.Verifier.Bytes())},
+		Data:        data,
+		BlockNumber: height,
+	}
+}
+
+// countVerifierCommits counts the commits of each verifier for the blocks in the relay period before the header.
+// The commits of a block are the parent committed seals carried in its child, which are covered by the block hash
+// unlike the committed seals of the block itself, and each verifier of the block is counted once.
+func countVerifierCommits(chain consensus.ChainReader, header *types.BlockHeader) (map[common.Address]uint64, error) {
+	commits := make(map[common.Address]uint64)
+	child := header
+	for i := uint64(0); i < common.RelayInterval; i++ {
+		parent := chain.GetHeaderByHash(child.PreviousBlockHash)
+		if parent == nil {
+			return nil, errors.NewStackedErrorf(errFeeHeaderNotFound, "failed to get header %v", child.PreviousBlockHash)
+		}
+
+		if parent.Height == 0 {
+			break
+		}
+
+		committers, err := parentCommitters(child, parent)
+		if err != nil {
+			return nil, err
+		}
+
+		for verifier := range committers {
+			commits[verifier]++
+		}
+
+		child = parent
+	}
+
+	return commits, nil
+}
+
+// parentCommitters returns the distinct verifiers of the parent that signed the parent committed seals carried in the child
+func parentCommitters(child *types.BlockHeader, parent *types.BlockHeader) (map[common.Address]bool, error) {
+	childExtra, err := types.ExtractBftExtra(child)
+	if err != nil {
+		return nil, err
+	}
+
+	parentExtra, err := types.ExtractBftExtra(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiers := make(map[common.Address]bool)
+	for _, verifier := range parentExtra.Verifiers {
+		verifiers[verifier] = true
+	}
+
+	committers := make(map[common.Address]bool)
+	seal := bftCore.PrepareCommittedSeal(parent.Hash())
+	for _, committed := range childExtra.ParentCommittedSeal {
+		verifier, err := bft.GetSignatureAddress(seal, committed)
+		if err != nil {
+			return nil, err
+		}
+
+		if verifiers[verifier] {
+			committers[verifier] = true
+		}
+	}
+
+	return committers, nil
+}
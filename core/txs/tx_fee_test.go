@@ -0,0 +1,223 @@
+/**
+* @file
+* @copyright defined in go-seele/LICENSE
+ */
+
+package txs
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/consensus"
+	bftCore "github.com/seeleteam/go-seele/consensus/bft/core"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/seeleteam/go-seele/database/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+// testHeaderChain is a chain reader of the headers by hash
+type testHeaderChain struct {
+	consensus.ChainReader
+	headers map[common.Hash]*types.BlockHeader
+}
+
+func (chain *testHeaderChain) GetHeaderByHash(hash common.Hash) *types.BlockHeader {
+	return chain.headers[hash]
+}
+
+// newTestFeeChain returns the bft headers of the verifiers from the genesis to the height, and each header
+// is committed by the verifiers returned by commit, whose committed seals are carried in the child.
+func newTestFeeChain(t *testing.T, height uint64, verifiers []common.Address, commit func(height uint64) []*ecdsa.PrivateKey) (*testHeaderChain, *types.BlockHeader) {
+	chain := &testHeaderChain{headers: make(map[common.Hash]*types.BlockHeader)}
+
+	var parent common.Hash
+	var parentSeals [][]byte
+	for h := uint64(0); ; h++ {
+		header := &types.BlockHeader{
+			PreviousBlockHash: parent,
+			Consensus:         types.BftConsensus,
+			Height:            h,
+			Difficulty:        big.NewInt(1),
+			CreateTimestamp:   big.NewInt(int64(h)),
+		}
+
+		extra := &types.BftExtra{Verifiers: verifiers, Seal: []byte{}, CommittedSeal: [][]byte{}, ParentCommittedSeal: parentSeals}
+		setTestExtra(t, header, extra)
+
+		if h == height {
+			return chain, header
+		}
+
+		seal := crypto.Keccak256(bftCore.PrepareCommittedSeal(header.Hash()))
+		parentSeals = nil
+		for _, key := range commit(h) {
+			parentSeals = append(parentSeals, crypto.MustSign(key, seal).Sig)
+		}
+		extra.CommittedSeal = parentSeals
+		setTestExtra(t, header, extra)
+
+		parent = header.Hash()
+		chain.headers[parent] = header
+	}
+}
+
+func setTestExtra(t *testing.T, header *types.BlockHeader, extra *types.BftExtra) {
+	payload, err := rlp.EncodeToBytes(extra)
+	assert.Equal(t, err, nil)
+	header.ExtraData = append(make([]byte, types.BftExtraVanity), payload...)
+}
+
+func Test_countVerifierCommits(t *testing.T) {
+	a, keyA := crypto.MustGenerateShardKeyPair(1)
+	b, keyB := crypto.MustGenerateShardKeyPair(1)
+	_, keyC := crypto.MustGenerateShardKeyPair(1)
+	verifiers := []common.Address{*a, *b}
+
+	cases := []struct {
+		name    string
+		height  uint64
+		commit  func(height uint64) []*ecdsa.PrivateKey
+		commits map[common.Address]uint64
+	}{
+		{"all committed", common.RelayInterval * 2, func(uint64) []*ecdsa.PrivateKey { return []*ecdsa.PrivateKey{keyA, keyB} },
+			map[common.Address]uint64{*a: common.RelayInterval, *b: common.RelayInterval}},
+		{"committed in turn", common.RelayInterval * 2, func(h uint64) []*ecdsa.PrivateKey { return []*ecdsa.PrivateKey{[]*ecdsa.PrivateKey{keyA, keyB}[h%2]} },
+			map[common.Address]uint64{*a: common.RelayInterval / 2, *b: common.RelayInterval / 2}},
+		{"only the relay period counted", common.RelayInterval * 2, func(h uint64) []*ecdsa.PrivateKey {
+			if h < common.RelayInterval {
+				return []*ecdsa.PrivateKey{keyC}
+			}
+			return []*ecdsa.PrivateKey{keyA}
+		}, map[common.Address]uint64{*a: common.RelayInterval}},
+		{"empty seal set", common.RelayInterval * 2, func(uint64) []*ecdsa.PrivateKey { return nil },
+			map[common.Address]uint64{}},
+		{"genesis not counted", common.RelayInterval, func(uint64) []*ecdsa.PrivateKey { return []*ecdsa.PrivateKey{keyA} },
+			map[common.Address]uint64{*a: common.RelayInterval - 1}},
+		{"duplicated seals counted once", common.RelayInterval * 2, func(uint64) []*ecdsa.PrivateKey { return []*ecdsa.PrivateKey{keyA, keyA} },
+			map[common.Address]uint64{*a: common.RelayInterval}},
+		{"not a verifier", common.RelayInterval * 2, func(uint64) []*ecdsa.PrivateKey { return []*ecdsa.PrivateKey{keyA, keyC} },
+			map[common.Address]uint64{*a: common.RelayInterval}},
+	}
+
+	for _, c := range cases {
+		chain, header := newTestFeeChain(t, c.height, verifiers, c.commit)
+		commits, err := countVerifierCommits(chain, header)
+		assert.Equal(t, err, nil, c.name)
+		assert.Equal(t, commits, c.commits, c.name)
+	}
+
+	// the committed seals of the block are not covered by the hash, so they are not counted
+	chain, header := newTestFeeChain(t, common.RelayInterval*2, verifiers, func(uint64) []*ecdsa.PrivateKey { return []*ecdsa.PrivateKey{keyA} })
+	parent := chain.headers[header.PreviousBlockHash]
+	extra, err := types.ExtractBftExtra(parent)
+	assert.Equal(t, err, nil)
+	extra.CommittedSeal = append(extra.CommittedSeal, crypto.MustSign(keyB, crypto.Keccak256(bftCore.PrepareCommittedSeal(parent.Hash()))).Sig)
+	setTestExtra(t, parent, extra)
+	assert.Equal(t, parent.Hash(), header.PreviousBlockHash)
+
+	commits, err := countVerifierCommits(chain, header)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, commits, map[common.Address]uint64{*a: common.RelayInterval})
+
+	// the headers in the relay period are required
+	chain, header = newTestFeeChain(t, common.RelayInterval*2, verifiers, func(uint64) []*ecdsa.PrivateKey { return nil })
+	delete(chain.headers, header.PreviousBlockHash)
+	_, err = countVerifierCommits(chain, header)
+	assert.NotEqual(t, err, nil)
+}
+
+func Test_DistributeVerifierFees(t *testing.T) {
+	a, b, c := common.BytesToAddress([]byte{1}), common.BytesToAddress([]byte{2}), common.BytesToAddress([]byte{3})
+
+	cases := []struct {
+		name    string
+		total   int64
+		commits map[common.Address]uint64
+		fees    []*VerifierFee
+	}{
+		{"split by commits", 300, map[common.Address]uint64{b: 2, a: 1},
+			[]*VerifierFee{{a, 1, big.NewInt(100)}, {b, 2, big.NewInt(200)}}},
+		{"remainder kept", 100, map[common.Address]uint64{a: 1, b: 1, c: 1},
+			[]*VerifierFee{{a, 1, big.NewInt(33)}, {b, 1, big.NewInt(33)}, {c, 1, big.NewInt(33)}}},
+		{"zero amount not paid", 2, map[common.Address]uint64{a: 1, b: 2},
+			[]*VerifierFee{{b, 2, big.NewInt(1)}}},
+		{"verifier without commits", 100, map[common.Address]uint64{a: 0, b: 4},
+			[]*VerifierFee{{b, 4, big.NewInt(100)}}},
+		{"no commits", 100, map[common.Address]uint64{}, nil},
+		{"no fee", 0, map[common.Address]uint64{a: 1}, nil},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, DistributeVerifierFees(big.NewInt(c.total), c.commits), c.fees, c.name)
+	}
+}
+
+func Test_VerifierFeesFromReceipt(t *testing.T) {
+	fees := []*VerifierFee{
+		{common.BytesToAddress([]byte{1}), 3, big.NewInt(100)},
+		{common.BytesToAddress([]byte{2}), 1, new(big.Int).Lsh(big.NewInt(1), 100)},
+	}
+
+	masternodeLog := &types.Log{Address: system.MasternodeContractAddress, Topics: []common.Hash{MasternodeRewardTopic, common.EmptyHash}}
+	otherLog := &types.Log{Address: common.SubchainFeeAccount, Topics: []common.Hash{common.EmptyHash, common.EmptyHash}}
+	invalidLog := fees[0].log(20)
+	invalidLog.Data = invalidLog.Data[1:]
+
+	cases := []struct {
+		name string
+		logs []*types.Log
+		fees []*VerifierFee
+		err  error
+	}{
+		{"fee logs", []*types.Log{fees[0].log(20), fees[1].log(20)}, fees, nil},
+		{"other logs ignored", []*types.Log{masternodeLog, fees[1].log(20), otherLog}, fees[1:], nil},
+		{"no logs", nil, nil, nil},
+		{"invalid data", []*types.Log{invalidLog}, nil, errInvalidFeeLog},
+	}
+
+	for _, c := range cases {
+		result, err := VerifierFeesFromReceipt(&types.Receipt{Logs: c.logs})
+		assert.Equal(t, err, c.err, c.name)
+		assert.Equal(t, result, c.fees, c.name)
+	}
+}
+
+func Test_ApplyVerifierFees(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	statedb, err := state.NewStatedb(common.EmptyHash, db)
+	assert.Equal(t, err, nil)
+	statedb.CreateAccount(common.SubchainFeeAccount)
+	statedb.AddBalance(common.SubchainFeeAccount, big.NewInt(101))
+
+	a, keyA := crypto.MustGenerateShardKeyPair(1)
+	chain, header := newTestFeeChain(t, common.RelayInterval, []common.Address{*a}, func(uint64) []*ecdsa.PrivateKey { return []*ecdsa.PrivateKey{keyA} })
+
+	// not paid before the fork height
+	receipt := &types.Receipt{}
+	assert.Equal(t, ApplyVerifierFees(chain, statedb, header, receipt), nil)
+	assert.Equal(t, len(receipt.Logs), 0)
+	assert.Equal(t, statedb.GetBalance(*a).Sign(), 0)
+
+	chain, header = newTestFeeChain(t, common.RelayInterval, []common.Address{*a}, func(uint64) []*ecdsa.PrivateKey { return []*ecdsa.PrivateKey{keyA} })
+	header.Height = common.VerifierFeeForkHeight
+	assert.Equal(t, ApplyVerifierFees(chain, statedb, header, receipt), nil)
+	assert.Equal(t, statedb.GetBalance(*a), big.NewInt(101))
+	assert.Equal(t, statedb.GetBalance(common.SubchainFeeAccount).Sign(), 0)
+
+	fees, err := VerifierFeesFromReceipt(receipt)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, fees, []*VerifierFee{{*a, common.RelayInterval - 1, big.NewInt(101)}})
+
+	root, err := statedb.Hash()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, receipt.PostState, root)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/common/errors"
 	"github.com/seeleteam/go-seele/consensus"
+	"github.com/seeleteam/go-seele/contract/system"
 	"github.com/seeleteam/go-seele/core/state"
 	"github.com/seeleteam/go-seele/core/types"
 	"github.com/seeleteam/go-seele/crypto"
@@ -25,8 +26,15 @@ var (
 	errCoinbaseMismatch  = errors.New("coinbase mismatch")
 	errTimestampMismatch = errors.New("timestamp mismatch")
 	errInvalidReward     = errors.New("invalid reward tx")
+
+	// masternodeRewardRatio is the percentage of the block reward split from the coinbase to the masternodes
+	masternodeRewardRatio = big.NewInt(10)
 )
 
+// MasternodeRewardTopic is the log topic of the reward paid to a masternode at the first block of an epoch,
+// the second topic is the masternode, and the data is the amount.
+var MasternodeRewardTopic = crypto.MustHash("MasternodeReward(address,uint256)")
+
 // NewRewardTx creates a reward transaction with the specified coinbase, reward and timestamp.
 func NewRewardTx(coinbase common.Address, reward *big.Int, timestamp uint64) (*types.Transaction, error) {
 	if err := validateReward(reward); err != nil {
@@ -122,3 +130,51 @@ func ApplyRewardTx(tx *types.Transaction, statedb *state.Statedb) (*types.Receip
 
 	return receipt, nil
 }
+
+// ApplyMasternodeRewards splits the masternode share of the block reward from the coinbase to the masternode
+// reward pool after the masternode reward fork height, and distributes the pool of the last epoch at the first
+// block of an epoch with specified statedb. It should be applied right after the reward tx, and the rewards
+// are recorded as the logs of the reward receipt.
+func ApplyMasternodeRewards(statedb *state.Statedb, header *types.BlockHeader, rewardReceipt *types.Receipt) error {
+	if header.Height < common.MasternodeRewardForkHeight {
+		return nil
+	}
+
+	// the pool of the last epoch is distributed before the share of this block is added
+	rewards, err := system.ProcessMasternodeEpoch(statedb, header.Height)
+	if err != nil {
+		return errors.NewStackedErrorf(err, "failed to process masternode epoch at height %v", header.Height)
+	}
+
+	for _, r := range rewards {
+		rewardReceipt.Logs = append(rewardReceipt.Logs, &types.Log{
+			Address:     system.MasternodeContractAddress,
+			Topics:      []common.Hash{MasternodeRewardTopic, common.BytesToHash(r.Address.Bytes())},
+			Data:        common.BigToHash(r.Amount).Bytes(),
+			BlockNumber: header.Height,
+		})
+	}
+
+	if header.Consensus != types.BftConsensus {
+		share := GetMasternodeReward(header.Height)
+		statedb.SubBalance(header.Creator, share)
+		statedb.CreateAccount(system.MasternodeRewardPoolAddress)
+		statedb.AddBalance(system.MasternodeRewardPoolAddress, share)
+	}
+
+	hash, err := statedb.Hash()
+	if err != nil {
+		return errors.NewStackedError(err, "failed to get statedb root hash")
+	}
+
+	rewardReceipt.PostState = hash
+	return nil
+}
+
+// GetMasternodeReward returns the masternode share of the block reward at the block height, which is
+// split from the coinbase to the masternode reward pool. So the masternode rewards are not minted
+// in addition to the block reward.
+func GetMasternodeReward(height uint64) *big.Int {
+	reward := new(big.Int).Mul(consensus.GetReward(height), masternodeRewardRatio)
+	return reward.Div(reward, big.NewInt(100))
+}
@@ -0,0 +1,89 @@
+/**
+* @file
+* @copyright defined in go-seele/LICENSE
+ */
+
+package txs
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/consensus"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/database/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ApplyMasternodeRewards(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	statedb, err := state.NewStatedb(common.EmptyHash, db)
+	assert.Equal(t, err, nil)
+	statedb.CreateAccount(system.MasternodeContractAddress)
+
+	// the masternode registers at the first slot, and sends heartbeats in the other slots of the epoch
+	node := common.BytesToAddress([]byte{1})
+	tx := &types.Transaction{Data: types.TransactionData{
+		From:   node,
+		To:     system.MasternodeContractAddress,
+		Amount: new(big.Int).Mul(common.SeeleToFan, big.NewInt(20000)),
+	}}
+	start := uint64(common.MasternodeRewardForkHeight)
+	header := &types.BlockHeader{Height: start + 1, CreateTimestamp: big.NewInt(1)}
+	contract := system.GetContractByAddress(system.MasternodeContractAddress)
+	_, err = contract.Run([]byte{system.CmdDeposit}, system.NewContext(tx, statedb, header))
+	assert.Equal(t, err, nil)
+
+	for height := start + 721; height < start+system.MasternodeEpoch; height += 720 {
+		header.Height = height
+		_, err = contract.Run([]byte{system.CmdHeartbeat}, system.NewContext(tx, statedb, header))
+		assert.Equal(t, err, nil)
+	}
+
+	// not split nor distributed before the fork height
+	coinbase := common.BytesToAddress([]byte{2})
+	statedb.CreateAccount(coinbase)
+	receipt := &types.Receipt{}
+	assert.Equal(t, ApplyMasternodeRewards(statedb, &types.BlockHeader{Height: start - system.MasternodeEpoch, Creator: coinbase}, receipt), nil)
+	assert.Equal(t, len(receipt.Logs), 0)
+	assert.Equal(t, receipt.PostState, common.EmptyHash)
+
+	// the pool of the last epoch is paid, and the share of the block reward is split from the coinbase to the pool
+	pool := big.NewInt(1000)
+	statedb.CreateAccount(system.MasternodeRewardPoolAddress)
+	statedb.AddBalance(system.MasternodeRewardPoolAddress, pool)
+
+	header = &types.BlockHeader{Height: start + system.MasternodeEpoch, Creator: coinbase}
+	reward := consensus.GetReward(header.Height)
+	statedb.AddBalance(coinbase, reward)
+	assert.Equal(t, ApplyMasternodeRewards(statedb, header, receipt), nil)
+
+	share := GetMasternodeReward(header.Height)
+	assert.Equal(t, share.Sign() > 0, true)
+	assert.Equal(t, statedb.GetBalance(node), pool)
+	assert.Equal(t, statedb.GetBalance(system.MasternodeRewardPoolAddress), share)
+	assert.Equal(t, statedb.GetBalance(coinbase), new(big.Int).Sub(reward, share))
+
+	// the reward is recorded in the receipt
+	assert.Equal(t, len(receipt.Logs), 1)
+	assert.Equal(t, receipt.Logs[0].Address, system.MasternodeContractAddress)
+	assert.Equal(t, receipt.Logs[0].Topics, []common.Hash{MasternodeRewardTopic, common.BytesToHash(node.Bytes())})
+	assert.Equal(t, new(big.Int).SetBytes(receipt.Logs[0].Data), pool)
+
+	root, err := statedb.Hash()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, receipt.PostState, root)
+
+	// the share is added to the pool at the other blocks of the epoch
+	receipt = &types.Receipt{}
+	header.Height++
+	statedb.AddBalance(coinbase, reward)
+	assert.Equal(t, ApplyMasternodeRewards(statedb, header, receipt), nil)
+	assert.Equal(t, len(receipt.Logs), 0)
+	assert.Equal(t, statedb.GetBalance(system.MasternodeRewardPoolAddress), new(big.Int).Mul(share, big.NewInt(2)))
+}
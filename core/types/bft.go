@@ -26,28 +26,40 @@ type BftExtra struct {
 	Verifiers     []common.Address
 	Seal          []byte
 	CommittedSeal [][]byte
+	// ParentCommittedSeal are the committed seals of the parent block carried after the verifier fee
+	// fork height. Unlike the CommittedSeal, they are covered by the block hash.
+	ParentCommittedSeal [][]byte
 }
 
 // EncodeRLP serializes bftExtra into the Ethereum RLP format.
 func (bftExtra *BftExtra) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, []interface{}{
+	fields := []interface{}{
 		bftExtra.Verifiers,
 		bftExtra.Seal,
 		bftExtra.CommittedSeal,
-	})
+	}
+
+	// the parent seals are appended only if carried, so the blocks before the fork are not changed
+	for _, seal := range bftExtra.ParentCommittedSeal {
+		fields = append(fields, seal)
+	}
+
+	return rlp.Encode(w, fields)
 }
 
 // DecodeRLP implements rlp.Decoder, and load the bft fields from a RLP stream.
 func (bftExtra *BftExtra) DecodeRLP(s *rlp.Stream) error {
 	var bftBlockExtra struct {
-		Verifiers     []common.Address
-		Seal          []byte
-		CommittedSeal [][]byte
+		Verifiers           []common.Address
+		Seal                []byte
+		CommittedSeal       [][]byte
+		ParentCommittedSeal [][]byte `rlp:"tail"`
 	}
 	if err := s.Decode(&bftBlockExtra); err != nil {
 		return err
 	}
 	bftExtra.Verifiers, bftExtra.Seal, bftExtra.CommittedSeal = bftBlockExtra.Verifiers, bftBlockExtra.Seal, bftBlockExtra.CommittedSeal
+	bftExtra.ParentCommittedSeal = bftBlockExtra.ParentCommittedSeal
 	return nil
 }
 
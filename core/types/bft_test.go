@@ -0,0 +1,46 @@
+/**
+* @file
+* @copyright defined in go-seele/LICENSE
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/seeleteam/go-seele/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBftHeader(t *testing.T, extra *BftExtra) *BlockHeader {
+	payload, err := rlp.EncodeToBytes(extra)
+	assert.Equal(t, err, nil)
+
+	return &BlockHeader{Consensus: BftConsensus, ExtraData: append(make([]byte, BftExtraVanity), payload...)}
+}
+
+func Test_BftExtra_ParentCommittedSeal(t *testing.T) {
+	verifiers := []common.Address{common.BytesToAddress([]byte{1})}
+
+	// the extra without parent seals is encoded as before the fork
+	legacy, err := rlp.EncodeToBytes([]interface{}{verifiers, []byte{1}, [][]byte{{2}}})
+	assert.Equal(t, err, nil)
+	payload, err := rlp.EncodeToBytes(&BftExtra{Verifiers: verifiers, Seal: []byte{1}, CommittedSeal: [][]byte{{2}}})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, payload, legacy)
+
+	extra := &BftExtra{Verifiers: verifiers, Seal: []byte{1}, CommittedSeal: [][]byte{{2}}, ParentCommittedSeal: [][]byte{{3}, {4}}}
+	header := newTestBftHeader(t, extra)
+	decoded, err := ExtractBftExtra(header)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, decoded, extra)
+
+	// the parent seals are covered by the hash, but the committed seals are not
+	hash := header.Hash()
+	extra.CommittedSeal = append(extra.CommittedSeal, []byte{5})
+	assert.Equal(t, newTestBftHeader(t, extra).Hash(), hash)
+
+	extra.ParentCommittedSeal = extra.ParentCommittedSeal[:1]
+	assert.NotEqual(t, newTestBftHeader(t, extra).Hash(), hash)
+}
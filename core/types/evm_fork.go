@@ -0,0 +1,108 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/crypto"
+)
+
+// EVMForkConfig is the activation heights of the EVM hard forks, it is declared in the
+// genesis info and persisted with the genesis block. A nil height means the fork is not
+// scheduled, and 0 means the fork is activated since the genesis block.
+type EVMForkConfig struct {
+	// ConstantinopleHeight enables CREATE2, SHL, SHR, SAR, EXTCODEHASH and the EIP-1283 net gas metering of SSTORE
+	ConstantinopleHeight *big.Int `json:"constantinopleHeight,omitempty"`
+
+	// PetersburgHeight disables the EIP-1283 net gas metering of SSTORE
+	PetersburgHeight *big.Int `json:"petersburgHeight,omitempty"`
+
+	// IstanbulHeight enables CHAINID, SELFBALANCE, the EIP-1884 repricing and the EIP-2200 net gas metering of SSTORE
+	IstanbulHeight *big.Int `json:"istanbulHeight,omitempty"`
+}
+
+// Validate checks the forks are scheduled in order.
+func (c *EVMForkConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	forks := []struct {
+		name   string
+		height *big.Int
+	}{
+		{"constantinople", c.ConstantinopleHeight},
+		{"petersburg", c.PetersburgHeight},
+		{"istanbul", c.IstanbulHeight},
+	}
+
+	for i, fork := range forks {
+		if fork.height == nil {
+			continue
+		}
+
+		if fork.height.Sign() < 0 {
+			return fmt.Errorf("invalid %s fork height %v", fork.name, fork.height)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		// the previous fork must be activated no later than this fork
+		prev := forks[i-1]
+		if prev.height == nil {
+			return fmt.Errorf("%s fork is scheduled but %s fork is not", fork.name, prev.name)
+		}
+
+		if fork.height.Cmp(prev.height) < 0 {
+			return fmt.Errorf("%s fork height %v is lower than %s fork height %v", fork.name, fork.height, prev.name, prev.height)
+		}
+	}
+
+	return nil
+}
+
+// Equal returns true if the configs schedule the same forks, a nil config schedules no fork.
+func (c *EVMForkConfig) Equal(other *EVMForkConfig) bool {
+	if c == nil {
+		c = &EVMForkConfig{}
+	}
+
+	if other == nil {
+		other = &EVMForkConfig{}
+	}
+
+	return equalHeight(c.ConstantinopleHeight, other.ConstantinopleHeight) &&
+		equalHeight(c.PetersburgHeight, other.PetersburgHeight) &&
+		equalHeight(c.IstanbulHeight, other.IstanbulHeight)
+}
+
+// Hash returns the hash of the scheduled forks, or an empty hash if no fork is scheduled.
+func (c *EVMForkConfig) Hash() common.Hash {
+	if c.Equal(nil) {
+		return common.EmptyHash
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal evm fork config: %s", err))
+	}
+
+	return crypto.HashBytes(data)
+}
+
+func equalHeight(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return a.Cmp(b) == 0
+}
@@ -0,0 +1,49 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_EVMForkConfig_Validate(t *testing.T) {
+	var config *EVMForkConfig
+	assert.Equal(t, config.Validate(), nil)
+
+	config = &EVMForkConfig{ConstantinopleHeight: big.NewInt(10), PetersburgHeight: big.NewInt(10), IstanbulHeight: big.NewInt(20)}
+	assert.Equal(t, config.Validate(), nil)
+
+	// out of order
+	config = &EVMForkConfig{ConstantinopleHeight: big.NewInt(10), PetersburgHeight: big.NewInt(5)}
+	assert.Equal(t, config.Validate() != nil, true)
+
+	// istanbul without petersburg
+	config = &EVMForkConfig{ConstantinopleHeight: big.NewInt(10), IstanbulHeight: big.NewInt(20)}
+	assert.Equal(t, config.Validate() != nil, true)
+
+	config = &EVMForkConfig{ConstantinopleHeight: big.NewInt(-1)}
+	assert.Equal(t, config.Validate() != nil, true)
+}
+
+func Test_EVMForkConfig_Equal(t *testing.T) {
+	var config *EVMForkConfig
+	assert.Equal(t, config.Equal(&EVMForkConfig{}), true)
+	assert.Equal(t, config.Hash(), common.EmptyHash)
+
+	config = &EVMForkConfig{ConstantinopleHeight: big.NewInt(100)}
+	assert.Equal(t, config.Equal(&EVMForkConfig{ConstantinopleHeight: big.NewInt(100)}), true)
+	assert.Equal(t, config.Hash(), (&EVMForkConfig{ConstantinopleHeight: big.NewInt(100)}).Hash())
+
+	// rescheduled or cancelled forks
+	assert.Equal(t, config.Equal(&EVMForkConfig{ConstantinopleHeight: big.NewInt(200)}), false)
+	assert.Equal(t, config.Equal(nil), false)
+	assert.Equal(t, config.Hash() != (&EVMForkConfig{ConstantinopleHeight: big.NewInt(200)}).Hash(), true)
+	assert.Equal(t, config.Hash() != common.EmptyHash, true)
+}
@@ -6,6 +6,7 @@
 package types
 
 import (
+	"encoding/base64"
 	"encoding/json"
 
 	"github.com/seeleteam/go-seele/common"
@@ -50,3 +51,36 @@ func (log *Log) MarshalJSON() ([]byte, error) {
 	o.TxIndex = log.TxIndex
 	return json.Marshal(&o)
 }
+
+// UnmarshalJSON unmarshal the log encoded by MarshalJSON, the data in
+// base64 of the default json encoding is also accepted for compatibility.
+func (log *Log) UnmarshalJSON(data []byte) error {
+	var o struct {
+		Address     common.Address `json:"address"`
+		Topics      []common.Hash  `json:"topics"`
+		Data        string         `json:"data"`
+		BlockNumber uint64         `json:"blockNumber"`
+		TxIndex     uint           `json:"transactionIndex"`
+	}
+
+	if err := json.Unmarshal(data, &o); err != nil {
+		return err
+	}
+
+	var err error
+	if hexutil.Has0xPrefix(o.Data) {
+		log.Data, err = hexutil.HexToBytes(o.Data)
+	} else {
+		log.Data, err = base64.StdEncoding.DecodeString(o.Data)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	log.Address = o.Address
+	log.Topics = o.Topics
+	log.BlockNumber = o.BlockNumber
+	log.TxIndex = o.TxIndex
+	return nil
+}
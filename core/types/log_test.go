@@ -46,3 +46,23 @@ func Test_MarshalJSON(t *testing.T) {
 	assert.True(t, strings.Contains(str, hash.Hex()))
 	assert.True(t, strings.Contains(str, dataHex))
 }
+
+func Test_UnmarshalJSON(t *testing.T) {
+	address, err := common.HexToAddress("0x6d05ccde7e91439e0de160335ee87a9a219c0002")
+	assert.NoError(t, err)
+
+	log := &Log{
+		Address:     address,
+		Topics:      []common.Hash{common.BytesToHash([]byte("asdf")), common.BytesToHash([]byte("qwer"))},
+		Data:        []byte{1, 2, 3},
+		BlockNumber: 65484,
+		TxIndex:     2,
+	}
+
+	encoded, err := json.Marshal(log)
+	assert.NoError(t, err)
+
+	decoded := new(Log)
+	assert.NoError(t, json.Unmarshal(encoded, decoded))
+	assert.Equal(t, decoded, log)
+}
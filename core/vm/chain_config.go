@@ -0,0 +1,90 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package vm
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// GasTableIstanbul contains the gas prices repriced by EIP-1884 in the istanbul phase.
+var GasTableIstanbul = params.GasTable{
+	ExtcodeSize: 700,
+	ExtcodeCopy: 700,
+	ExtcodeHash: 700,
+	Balance:     700,
+	SLoad:       800,
+	Calls:       700,
+	Suicide:     5000,
+	ExpByte:     50,
+
+	CreateBySuicide: 25000,
+}
+
+// EIP-2200 gas prices of SSTORE in the istanbul phase.
+const (
+	SstoreSentryGasEIP2200   uint64 = 2300  // Minimum gas required to be present for an SSTORE call, not consumed
+	SstoreNoopGasEIP2200     uint64 = 800   // Once per SSTORE operation if the value doesn't change.
+	SstoreDirtyGasEIP2200    uint64 = 800   // Once per SSTORE operation if a dirty value is changed.
+	SstoreInitGasEIP2200     uint64 = 20000 // Once per SSTORE operation from clean zero to non-zero
+	SstoreInitRefundEIP2200  uint64 = 19200 // Once per SSTORE operation for resetting to the original zero value
+	SstoreCleanGasEIP2200    uint64 = 5000  // Once per SSTORE operation from clean non-zero to something else
+	SstoreCleanRefundEIP2200 uint64 = 4200  // Once per SSTORE operation for resetting to the original non-zero value
+	SstoreClearRefundEIP2200 uint64 = 15000 // Once per SSTORE operation for clearing an originally existing storage slot
+)
+
+// ChainConfig is the chain configuration of the EVM. It extends the go-ethereum chain config
+// with the hard forks that are not supported by the vendored go-ethereum.
+type ChainConfig struct {
+	*params.ChainConfig
+
+	PetersburgBlock *big.Int // Petersburg switch block (nil = no fork, 0 = already activated)
+	IstanbulBlock   *big.Int // Istanbul switch block (nil = no fork, 0 = already activated)
+}
+
+// IsPetersburg returns whether num is either equal to the petersburg block or greater.
+func (c *ChainConfig) IsPetersburg(num *big.Int) bool {
+	return isForked(c.PetersburgBlock, num)
+}
+
+// IsIstanbul returns whether num is either equal to the istanbul block or greater.
+func (c *ChainConfig) IsIstanbul(num *big.Int) bool {
+	return isForked(c.IstanbulBlock, num)
+}
+
+// GasTable returns the gas table corresponding to the current phase.
+func (c *ChainConfig) GasTable(num *big.Int) params.GasTable {
+	if c.IsIstanbul(num) {
+		return GasTableIstanbul
+	}
+
+	return c.ChainConfig.GasTable(num)
+}
+
+// Rules is the chain rules of the current phase, including the extended hard forks.
+type Rules struct {
+	params.Rules
+	IsPetersburg, IsIstanbul bool
+}
+
+// Rules returns the chain rules of the specified block number.
+func (c *ChainConfig) Rules(num *big.Int) Rules {
+	return Rules{
+		Rules:        c.ChainConfig.Rules(num),
+		IsPetersburg: c.IsPetersburg(num),
+		IsIstanbul:   c.IsIstanbul(num),
+	}
+}
+
+// isForked returns whether a fork scheduled at block s is active at the given head block.
+func isForked(s, head *big.Int) bool {
+	if s == nil || head == nil {
+		return false
+	}
+
+	return s.Cmp(head) <= 0
+}
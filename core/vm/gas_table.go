@@ -17,6 +17,8 @@
 package vm
 
 import (
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/seeleteam/go-seele/common"
@@ -120,8 +122,13 @@ func gasSStore(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, m
 		y, x    = stack.Back(1), stack.Back(0)
 		current = evm.StateDB.GetState(contract.Address(), common.BigToHash(x))
 	)
-	// The legacy gas metering only takes into consideration the current state
-	if !evm.chainRules.IsConstantinople {
+	// The EIP-2200 net gas metering is enabled since istanbul
+	if evm.chainRules.IsIstanbul {
+		return gasSStoreEIP2200(evm, contract, x, y, current)
+	}
+	// The legacy gas metering only takes into consideration the current state,
+	// the EIP-1283 net gas metering is disabled again since petersburg
+	if !evm.chainRules.IsConstantinople || evm.chainRules.IsPetersburg {
 		// This checks for 3 scenario's and calculates gas accordingly:
 		//
 		// 1. From a zero-value address to a non-zero value         (NEW VALUE)
@@ -182,6 +189,44 @@ func gasSStore(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, m
 	return params.NetSstoreDirtyGas, nil
 }
 
+// gasSStoreEIP2200 is the net gas metering of EIP-1283 with the gas prices repriced by EIP-1884,
+// and the SSTORE is rejected if the gas left is not more than the call stipend (sentry).
+func gasSStoreEIP2200(evm *EVM, contract *Contract, x, y *big.Int, current common.Hash) (uint64, error) {
+	if contract.Gas <= SstoreSentryGasEIP2200 {
+		return 0, ErrSstoreSentry
+	}
+
+	value := common.BigToHash(y)
+	if current == value { // noop (1)
+		return SstoreNoopGasEIP2200, nil
+	}
+	original := evm.StateDB.GetCommittedState(contract.Address(), common.BigToHash(x))
+	if original == current {
+		if original == (common.Hash{}) { // create slot (2.1.1)
+			return SstoreInitGasEIP2200, nil
+		}
+		if value == (common.Hash{}) { // delete slot (2.1.2b)
+			evm.StateDB.AddRefund(SstoreClearRefundEIP2200)
+		}
+		return SstoreCleanGasEIP2200, nil // write existing slot (2.1.2)
+	}
+	if original != (common.Hash{}) {
+		if current == (common.Hash{}) { // recreate slot (2.2.1.1)
+			evm.StateDB.SubRefund(SstoreClearRefundEIP2200)
+		} else if value == (common.Hash{}) { // delete slot (2.2.1.2)
+			evm.StateDB.AddRefund(SstoreClearRefundEIP2200)
+		}
+	}
+	if original == value {
+		if original == (common.Hash{}) { // reset to original inexistent slot (2.2.2.1)
+			evm.StateDB.AddRefund(SstoreInitRefundEIP2200)
+		} else { // reset to original existing slot (2.2.2.2)
+			evm.StateDB.AddRefund(SstoreCleanRefundEIP2200)
+		}
+	}
+	return SstoreDirtyGasEIP2200, nil // dirty update (2.2)
+}
+
 func makeGasLog(n uint64) gasFunc {
 	return func(gt params.GasTable, evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize uint64) (uint64, error) {
 		requestedSize, overflow := bigUint64(stack.Back(1))
@@ -104,6 +104,14 @@ type Tracer interface {
 	CaptureEnd(output []byte, gasUsed uint64, t time.Duration, err error) error
 }
 
+// NestedTracer is implemented by the tracers which trace the evm calls issued out of the evm,
+// e.g. by the system contracts, as the inner calls of the traced tx. The nested calls are
+// captured by CaptureEnter and CaptureExit instead of CaptureStart and CaptureEnd.
+type NestedTracer interface {
+	CaptureEnter(from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) error
+	CaptureExit(output []byte, gasUsed uint64, err error) error
+}
+
 // StructLogger is an EVM state logger and implements Tracer.
 //
 // StructLogger can capture state based on the given Log configuration and also keeps
@@ -8,6 +8,7 @@ package crypto
 import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"errors"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common/math"
@@ -15,6 +16,8 @@ import (
 	"github.com/seeleteam/go-seele/crypto/secp256k1"
 )
 
+var errInvalidSignature = errors.New("invalid signature")
+
 // Signature is a wrapper for the signed message and it is serializable.
 type Signature struct {
 	Sig []byte // [R || S || V] format signature in 65 bytes.
@@ -52,21 +55,28 @@ func Sign(privKey *ecdsa.PrivateKey, buff []byte) (*Signature, error) {
 // Verify verifies the signature against the specified hash.
 // Return true if the signature is valid, otherwise false.
 func (s Signature) Verify(signer common.Address, hash []byte) bool {
+	recovered, err := s.Signer(hash)
+	return err == nil && recovered.Equal(signer)
+}
+
+// Signer recovers the address of the signer from the signature of the specified hash,
+// and returns an error if the signature is invalid.
+func (s Signature) Signer(hash []byte) (common.Address, error) {
 	if len(s.Sig) != 65 {
-		return false
+		return common.EmptyAddress, errInvalidSignature
 	}
 
 	pubKey, err := SigToPub(hash, s.Sig)
 	if err != nil {
-		return false // Signature was modified
+		return common.EmptyAddress, err // Signature was modified
 	}
 
-	if !GetAddress(pubKey).Equal(signer) {
-		return false
+	compressed := secp256k1.CompressPubkey(pubKey.X, pubKey.Y)
+	if !secp256k1.VerifySignature(compressed, hash, s.Sig[:64]) {
+		return common.EmptyAddress, errInvalidSignature
 	}
 
-	compressed := secp256k1.CompressPubkey(pubKey.X, pubKey.Y)
-	return secp256k1.VerifySignature(compressed, hash, s.Sig[:64])
+	return *GetAddress(pubKey), nil
 }
 
 func Ecrecover(hash, sig []byte) ([]byte, error) {
@@ -32,4 +32,13 @@ func Test_Sign(t *testing.T) {
 	privKey2, _ := GenerateKey()
 	signer2 := GetAddress(&privKey2.PublicKey)
 	assert.Equal(t, signature.Verify(*signer2, hash.Bytes()), false)
+
+	// Recover the signer from the signature.
+	recovered, err := signature.Signer(hash.Bytes())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, recovered, *signer)
+
+	// Failed to recover the signer if signature is malformed.
+	_, err = Signature{signature.Sig[:64]}.Signer(hash.Bytes())
+	assert.Equal(t, err, errInvalidSignature)
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/seeleteam/go-seele/database"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
+	"github.com/syndtr/goleveldb/leveldb/storage"
 )
 
 var (
@@ -45,6 +46,20 @@ func NewLevelDB(path string) (database.Database, error) {
 	return result, nil
 }
 
+// NewMemDatabase constructs and returns a LevelDB instance in memory, which is
+// discarded once closed.
+func NewMemDatabase() database.Database {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return &LevelDB{
+		db:       db,
+		quitChan: make(chan struct{}),
+	}
+}
+
 // Close is used to close the db when not used
 func (db *LevelDB) Close() {
 	close(db.quitChan)
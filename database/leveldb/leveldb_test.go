@@ -125,3 +125,23 @@ func newDbInstance(dbPath string) database.Database {
 
 	return db
 }
+
+func Test_MemDatabase(t *testing.T) {
+	db := NewMemDatabase()
+	defer db.Close()
+
+	err := db.PutString("1", "2")
+	assert.Equal(t, err, nil)
+
+	value, err := db.GetString("1")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, value, "2")
+
+	batch := db.NewBatch()
+	batch.Put([]byte("3"), []byte("4"))
+	assert.Equal(t, batch.Commit(), nil)
+
+	found, err := db.HasString("3")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, found, true)
+}
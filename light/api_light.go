@@ -0,0 +1,145 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package light
+
+import (
+	"fmt"
+	"math/big"
+
+	api2 "github.com/seeleteam/go-seele/api"
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/errors"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/core/svm"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/crypto"
+)
+
+// PublicLightAPI provides the contract APIs of the light node, in which the
+// contract state is retrieved from the light servers and proved on demand.
+type PublicLightAPI struct {
+	s *ServiceClient
+}
+
+// NewPublicLightAPI creates a new PublicLightAPI object for rpc service.
+func NewPublicLightAPI(s *ServiceClient) *PublicLightAPI {
+	return &PublicLightAPI{s}
+}
+
+// Call is to execute a given transaction on the statedb of a given block height, the state accessed
+// by the execution is retrieved and proved lazily. When height is -1 the chain head is used.
+func (api *PublicLightAPI) Call(contract, payload string, height int64) (map[string]interface{}, error) {
+	contractAddr, err := common.HexToAddress(contract)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract address: %s", err)
+	}
+
+	msg, err := hexutil.HexToBytes(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload, %s", err)
+	}
+
+	header, err := api.getHeader(height)
+	if err != nil {
+		return nil, err
+	}
+
+	statedb, err := api.s.chain.GetStateByRootAndBlockHash(header.StateHash, header.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	from := crypto.MustGenerateShardAddress(common.LocalShardNumber)
+	statedb.CreateAccount(*from)
+	statedb.SetBalance(*from, common.SeeleToFan)
+
+	// gasLimit = balance / fee
+	tx, err := types.NewMessageTransaction(*from, contractAddr, big.NewInt(0), big.NewInt(1), common.SeeleToFan.Uint64(), uint64(1), msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %s", err)
+	}
+
+	ctx := &svm.Context{
+		Tx:          tx,
+		Statedb:     statedb,
+		BlockHeader: header,
+		BcStore:     api.s.chain.GetStore(),
+	}
+
+	receipt, err := svm.Process(ctx, header.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	// the execution result is not reliable if any state failed to retrieve.
+	if err = statedb.GetDbErr(); err != nil {
+		return nil, errors.NewStackedError(err, "failed to retrieve the state")
+	}
+
+	return api2.PrintableReceipt(receipt)
+}
+
+// GetCode returns the proved contract code of the given block height.
+// When height is -1 the chain head is used.
+func (api *PublicLightAPI) GetCode(contract common.Address, height int64) (string, error) {
+	header, err := api.getHeader(height)
+	if err != nil {
+		return "", err
+	}
+
+	code, err := NewLightBackend(api.s).GetCode(header.Hash(), contract)
+	if err != nil {
+		return "", err
+	}
+
+	return hexutil.BytesToHex(code), nil
+}
+
+// GetStorageAt returns the proved contract storage values of the keys at the given block height.
+// When height is -1 the chain head is used.
+func (api *PublicLightAPI) GetStorageAt(contract common.Address, keys []common.Hash, height int64) ([]string, error) {
+	if len(keys) > maxStorageKeys {
+		return nil, errTooManyStorageKeys
+	}
+
+	header, err := api.getHeader(height)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := NewLightBackend(api.s).GetStorage(header.Hash(), contract, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = hexutil.BytesToHex(value)
+	}
+
+	return result, nil
+}
+
+// getHeader returns the header of the given block height in the canonical chain,
+// when height is -1 the chain head is returned.
+func (api *PublicLightAPI) getHeader(height int64) (*types.BlockHeader, error) {
+	if height < 0 {
+		return api.s.chain.CurrentHeader(), nil
+	}
+
+	bcStore := api.s.chain.GetStore()
+	hash, err := canonicalHash(bcStore, uint64(height))
+	if err != nil {
+		return nil, errors.NewStackedErrorf(err, "failed to get block hash by height %v", height)
+	}
+
+	header, err := bcStore.GetBlockHeader(hash)
+	if err != nil {
+		return nil, errors.NewStackedErrorf(err, "failed to get block header by hash %v", hash)
+	}
+
+	return header, nil
+}
@@ -0,0 +1,159 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package light
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/core/store"
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/seeleteam/go-seele/database"
+	"github.com/seeleteam/go-seele/database/leveldb"
+	"github.com/seeleteam/go-seele/log"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	// returns the storage slot 0 and clears it
+	testLoadAndClearCode = []byte{
+		0x60, 0x00, 0x54, // SLOAD(0)
+		0x60, 0x00, 0x52, // MSTORE(0)
+		0x60, 0x00, 0x60, 0x00, 0x55, // SSTORE(0, 0)
+		0x60, 0x20, 0x60, 0x00, 0xf3, // RETURN(0, 32)
+	}
+
+	// destructs the contract to the caller
+	testSelfDestructCode = []byte{0x33, 0xff}
+)
+
+// testServerChain is the blockchain of the light server with the committed state.
+type testServerChain struct {
+	BlockChain
+	db      database.Database
+	bcStore store.BlockchainStore
+}
+
+func (chain *testServerChain) GetState(root common.Hash) (*state.Statedb, error) {
+	return state.NewStatedb(root, chain.db)
+}
+
+func (chain *testServerChain) GetStore() store.BlockchainStore { return chain.bcStore }
+
+func (chain *testServerChain) AccountDB() database.Database { return chain.db }
+
+// testServerRetriever handles the ODR requests by the light server, and validates
+// the responses against the headers of the light node.
+type testServerRetriever struct {
+	server  *LightProtocol
+	bcStore store.BlockchainStore
+	codes   map[uint16]int // number of requests by code
+}
+
+func (r *testServerRetriever) retrieveWithFilter(request odrRequest, filter peerFilter) (odrResponse, error) {
+	r.codes[request.code()]++
+
+	code, response := request.handle(r.server)
+	if err := response.getError(); err != nil {
+		return nil, err
+	}
+
+	decoded := odrResponseFactories[code]()
+	if err := common.Deserialize(common.SerializePanic(response), decoded); err != nil {
+		return nil, err
+	}
+
+	if err := decoded.validate(request, r.bcStore); err != nil {
+		return nil, err
+	}
+
+	return decoded, nil
+}
+
+func newTestCallClient(t *testing.T, serverDB database.Database, root common.Hash) (*PublicLightAPI, *testServerRetriever, func()) {
+	header := newTestBlockHeader()
+	header.StateHash = root
+	header.Height = 100
+
+	serverStore := store.NewBlockchainDatabase(serverDB)
+	assert.Nil(t, serverStore.PutBlockHeader(header.Hash(), header, big.NewInt(1), true))
+
+	clientDB, dispose := leveldb.NewTestDatabase()
+	clientStore := store.NewBlockchainDatabase(clientDB)
+	assert.Nil(t, clientStore.PutBlockHeader(header.Hash(), header, big.NewInt(1), true))
+
+	retriever := &testServerRetriever{
+		server:  &LightProtocol{chain: &testServerChain{db: serverDB, bcStore: serverStore}},
+		bcStore: clientStore,
+		codes:   make(map[uint16]int),
+	}
+
+	chain := &LightChain{
+		bcStore:       clientStore,
+		odrBackend:    retriever,
+		currentHeader: header,
+		canonicalTD:   big.NewInt(1),
+		log:           log.GetLogger("LightChain"),
+	}
+
+	return NewPublicLightAPI(&ServiceClient{chain: chain}), retriever, dispose
+}
+
+func Test_PublicLightAPI_Call(t *testing.T) {
+	shard := common.LocalShardNumber
+	common.LocalShardNumber = 1
+	defer func() { common.LocalShardNumber = shard }()
+
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	// prepare the contracts on server side
+	owner := *crypto.MustGenerateShardAddress(1)
+	loader, destructor := crypto.CreateAddress(owner, 1), crypto.CreateAddress(owner, 2)
+	slot := common.BigToHash(big.NewInt(0))
+
+	statedb := state.NewEmptyStatedb(db)
+	statedb.CreateAccount(owner)
+	statedb.SetBalance(owner, big.NewInt(100))
+	statedb.CreateAccount(loader)
+	statedb.SetCode(loader, testLoadAndClearCode)
+	statedb.SetData(loader, slot, common.BigToHash(big.NewInt(38)).Bytes())
+	statedb.CreateAccount(destructor)
+	statedb.SetCode(destructor, testSelfDestructCode)
+	statedb.SetBalance(destructor, big.NewInt(10))
+
+	batch := db.NewBatch()
+	root, err := statedb.Commit(batch)
+	assert.Nil(t, err)
+	assert.Nil(t, batch.Commit())
+
+	// the storage is retrieved and cleared locally
+	api, retriever, disposeClient := newTestCallClient(t, db, root)
+	defer disposeClient()
+
+	result, err := api.Call(loader.Hex(), "0x00", -1)
+	assert.Nil(t, err)
+	assert.Equal(t, result["failed"], false)
+	assert.Equal(t, result["result"], hexutil.BytesToHex(common.BigToHash(big.NewInt(38)).Bytes()))
+	assert.True(t, retriever.codes[trieRequestCode] > 0)
+
+	// the state on server side is not changed
+	serverState, err := state.NewStatedb(root, db)
+	assert.Nil(t, err)
+	assert.Equal(t, serverState.GetData(loader, slot), common.BigToHash(big.NewInt(38)).Bytes())
+
+	// the contract is deleted locally, which retrieves the trie nodes not in the proofs on demand
+	api, retriever, disposeClient2 := newTestCallClient(t, db, root)
+	defer disposeClient2()
+
+	result, err = api.Call(destructor.Hex(), "0x00", -1)
+	assert.Nil(t, err)
+	assert.Equal(t, result["failed"], false)
+	assert.True(t, retriever.codes[trieRequestCode] > 0)
+}
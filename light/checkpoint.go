@@ -0,0 +1,136 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package light
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/errors"
+	"github.com/seeleteam/go-seele/consensus/bft"
+	bftCore "github.com/seeleteam/go-seele/consensus/bft/core"
+	"github.com/seeleteam/go-seele/consensus/bft/verifier"
+	"github.com/seeleteam/go-seele/core/types"
+)
+
+var (
+	errCheckpointMismatch  = errors.New("checkpoint header mismatch")
+	errVerifiersMismatch   = errors.New("header verifiers mismatch with the tracked verifiers")
+	errNotEnoughCommits    = errors.New("not enough committed seals from the tracked verifiers")
+	errInvalidCheckpointTD = errors.New("invalid total difficulty of the checkpoint")
+)
+
+// changesVerifiers returns whether the header deposits or exits any verifier in its second witness.
+func changesVerifiers(header *types.BlockHeader) bool {
+	if header.Consensus != types.BftConsensus || len(header.SecondWitness) <= types.BftExtraVanity {
+		return false
+	}
+
+	swInfo, err := types.ExtractSecondWitnessInfo(header)
+	if err != nil {
+		return false
+	}
+
+	return len(swInfo.DepositVers) > 0 || len(swInfo.ExitVers) > 0
+}
+
+// verifiersAfter returns the verifiers of the blocks after the header, which
+// are the verifiers of the header updated by its verifier changes.
+func verifiersAfter(header *types.BlockHeader) ([]common.Address, error) {
+	extra, err := types.ExtractBftExtra(header)
+	if err != nil {
+		return nil, errors.NewStackedErrorf(err, "failed to extract bft extra of header %v", header.Hash())
+	}
+
+	if !changesVerifiers(header) {
+		return extra.Verifiers, nil
+	}
+
+	swInfo, err := types.ExtractSecondWitnessInfo(header)
+	if err != nil {
+		return nil, errors.NewStackedErrorf(err, "failed to extract second witness of header %v", header.Hash())
+	}
+
+	exits := make(map[common.Address]bool)
+	for _, addr := range swInfo.ExitVers {
+		exits[addr] = true
+	}
+
+	verifiers := make([]common.Address, 0, len(extra.Verifiers)+len(swInfo.DepositVers))
+	seen := make(map[common.Address]bool)
+	for _, addr := range append(extra.Verifiers, swInfo.DepositVers...) {
+		if !exits[addr] && !seen[addr] {
+			seen[addr] = true
+			verifiers = append(verifiers, addr)
+		}
+	}
+
+	return verifiers, nil
+}
+
+// verifyCheckpointHeader verifies the header is committed by the tracked verifiers,
+// that is more than 2F distinct verifiers signed the committed seals of the header.
+func verifyCheckpointHeader(header *types.BlockHeader, verifiers []common.Address) error {
+	extra, err := types.ExtractBftExtra(header)
+	if err != nil {
+		return errors.NewStackedErrorf(err, "failed to extract bft extra of header %v", header.Hash())
+	}
+
+	if !sameAddresses(extra.Verifiers, verifiers) {
+		return errVerifiersMismatch
+	}
+
+	tracked := make(map[common.Address]bool)
+	for _, addr := range verifiers {
+		tracked[addr] = true
+	}
+
+	seal := bftCore.PrepareCommittedSeal(header.Hash())
+	signers := make(map[common.Address]bool)
+	for _, committed := range extra.CommittedSeal {
+		addr, err := bft.GetSignatureAddress(seal, committed)
+		if err != nil {
+			return errors.NewStackedErrorf(err, "failed to get signer of committed seal in header %v", header.Hash())
+		}
+
+		if tracked[addr] {
+			signers[addr] = true
+		}
+	}
+
+	if len(signers) <= 2*verifier.NewVerifierSet(verifiers, bft.RoundRobin).F() {
+		return errNotEnoughCommits
+	}
+
+	return nil
+}
+
+// sameAddresses returns whether the addresses are the same regardless of the order,
+// the duplicated addresses are compared as well.
+func sameAddresses(a, b []common.Address) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sortedA, sortedB := sortAddresses(a), sortAddresses(b)
+	for i := range sortedA {
+		if !sortedA[i].Equal(sortedB[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortAddresses(addrs []common.Address) []common.Address {
+	sorted := append([]common.Address(nil), addrs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Bytes(), sorted[j].Bytes()) < 0
+	})
+
+	return sorted
+}
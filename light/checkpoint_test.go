@@ -0,0 +1,137 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package light
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/seeleteam/go-seele/common"
+	bftCore "github.com/seeleteam/go-seele/consensus/bft/core"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestVerifiers(n int) ([]*ecdsa.PrivateKey, []common.Address) {
+	keys := make([]*ecdsa.PrivateKey, n)
+	addrs := make([]common.Address, n)
+	for i := 0; i < n; i++ {
+		keys[i], addrs[i] = randomAccount()
+	}
+
+	return keys, addrs
+}
+
+func newTestBftHeader(t *testing.T, height uint64, verifiers []common.Address, deposits, exits []common.Address) *types.BlockHeader {
+	header := &types.BlockHeader{
+		Consensus:       types.BftConsensus,
+		Height:          height,
+		Difficulty:      big.NewInt(1),
+		CreateTimestamp: big.NewInt(1),
+	}
+
+	setTestBftExtra(t, header, &types.BftExtra{Verifiers: verifiers, Seal: []byte{}, CommittedSeal: [][]byte{}})
+
+	if len(deposits) > 0 || len(exits) > 0 {
+		sw, err := types.PrepareSecondWitness(nil, deposits, exits, 0, common.EmptyHash, common.EmptyHash, common.EmptyHash, crypto.Signature{Sig: []byte{}})
+		assert.Equal(t, err, nil)
+		header.SecondWitness = sw
+	}
+
+	return header
+}
+
+func setTestBftExtra(t *testing.T, header *types.BlockHeader, extra *types.BftExtra) {
+	payload, err := rlp.EncodeToBytes(extra)
+	assert.Equal(t, err, nil)
+	header.ExtraData = append(make([]byte, types.BftExtraVanity), payload...)
+}
+
+func commitTestBftHeader(t *testing.T, header *types.BlockHeader, keys ...*ecdsa.PrivateKey) {
+	extra, err := types.ExtractBftExtra(header)
+	assert.Equal(t, err, nil)
+
+	hash := crypto.Keccak256(bftCore.PrepareCommittedSeal(header.Hash()))
+	for _, key := range keys {
+		sig, err := crypto.Sign(key, hash)
+		assert.Equal(t, err, nil)
+		extra.CommittedSeal = append(extra.CommittedSeal, sig.Sig)
+	}
+
+	setTestBftExtra(t, header, extra)
+}
+
+func Test_VerifyCheckpointHeader(t *testing.T) {
+	keys, verifiers := newTestVerifiers(7)
+
+	// 7 verifiers tolerate 1 faulty, so that 3 committed seals are required
+	header := newTestBftHeader(t, 100, verifiers, nil, nil)
+	commitTestBftHeader(t, header, keys[0], keys[1])
+	assert.Equal(t, verifyCheckpointHeader(header, verifiers), errNotEnoughCommits)
+
+	// duplicated seals and seals of others are not counted
+	other, _ := randomAccount()
+	header = newTestBftHeader(t, 100, verifiers, nil, nil)
+	commitTestBftHeader(t, header, keys[0], keys[1], keys[1], other)
+	assert.Equal(t, verifyCheckpointHeader(header, verifiers), errNotEnoughCommits)
+
+	header = newTestBftHeader(t, 100, verifiers, nil, nil)
+	commitTestBftHeader(t, header, keys[0], keys[1], keys[2])
+	assert.Equal(t, verifyCheckpointHeader(header, verifiers), nil)
+
+	// the header must be verified by the same verifiers as the tracked ones
+	assert.Equal(t, verifyCheckpointHeader(header, verifiers[1:]), errVerifiersMismatch)
+}
+
+func Test_SameAddresses(t *testing.T) {
+	_, addrs := newTestVerifiers(3)
+	a, b, c := addrs[0], addrs[1], addrs[2]
+
+	cases := []struct {
+		x, y []common.Address
+		same bool
+	}{
+		{[]common.Address{a, b, c}, []common.Address{a, b, c}, true},
+		{[]common.Address{a, b, c}, []common.Address{c, a, b}, true},
+		{nil, []common.Address{}, true},
+		{[]common.Address{a, b}, []common.Address{a, b, c}, false},
+		{[]common.Address{a, a, b}, []common.Address{a, b, b}, false},
+		{[]common.Address{a, a, b}, []common.Address{a, b, c}, false},
+		{[]common.Address{a, b, a}, []common.Address{a, a, b}, true},
+	}
+
+	for i, c := range cases {
+		assert.Equal(t, sameAddresses(c.x, c.y), c.same, i)
+		assert.Equal(t, sameAddresses(c.y, c.x), c.same, i)
+	}
+}
+
+func Test_VerifiersAfter(t *testing.T) {
+	_, verifiers := newTestVerifiers(4)
+	_, deposits := newTestVerifiers(2)
+
+	header := newTestBftHeader(t, 100, verifiers, nil, nil)
+	assert.Equal(t, changesVerifiers(header), false)
+	after, err := verifiersAfter(header)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, after, verifiers)
+
+	header = newTestBftHeader(t, 100, verifiers, deposits, verifiers[:1])
+	assert.Equal(t, changesVerifiers(header), true)
+	after, err = verifiersAfter(header)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, after, append(append([]common.Address{}, verifiers[1:]...), deposits...))
+
+	// the header changing the verifiers is still verified by the verifiers before the change
+	keys, verifiers := newTestVerifiers(4)
+	header = newTestBftHeader(t, 100, verifiers, deposits, nil)
+	commitTestBftHeader(t, header, keys[0])
+	assert.Equal(t, verifyCheckpointHeader(header, verifiers), nil)
+	assert.Equal(t, verifyCheckpointHeader(header, append(verifiers, deposits...)), errVerifiersMismatch)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/seeleteam/go-seele/consensus"
 	"github.com/seeleteam/go-seele/core"
 	"github.com/seeleteam/go-seele/core/store"
+	"github.com/seeleteam/go-seele/core/types"
 	"github.com/seeleteam/go-seele/database"
 	"github.com/seeleteam/go-seele/database/leveldb"
 	"github.com/seeleteam/go-seele/log"
@@ -88,6 +89,17 @@ func NewServiceClient(ctx context.Context, conf *node.Config, log *log.SeeleLog,
 		return nil, err
 	}
 
+	if conf.LightCheckpoint != nil && conf.SeeleConfig.GenesisConfig.Consensus == types.BftConsensus {
+		if conf.LightCheckpoint.TD == nil || conf.LightCheckpoint.TD.Sign() <= 0 {
+			s.lightDB.Close()
+			s.odrBackend.close()
+			log.Error("invalid light checkpoint TD in NewServiceClient, %v", conf.LightCheckpoint.TD)
+			return nil, errInvalidCheckpointTD
+		}
+
+		s.seeleProtocol.downloader.checkpoint = conf.LightCheckpoint
+	}
+
 	s.odrBackend.start(s.seeleProtocol.peerSet)
 	log.Info("Light mode started.")
 	return s, nil
@@ -117,5 +129,11 @@ func (s *ServiceClient) Stop() error {
 
 // APIs implements node.Service, returning the collection of RPC services the seele package offers.
 func (s *ServiceClient) APIs() (apis []rpc.API) {
-	return append(apis, api.GetAPIs(NewLightBackend(s))...)
+	apis = append(apis, api.GetAPIs(NewLightBackend(s))...)
+	return append(apis, rpc.API{
+		Namespace: "seele",
+		Version:   "1.0",
+		Service:   NewPublicLightAPI(s),
+		Public:    true,
+	})
 }
@@ -17,8 +17,9 @@ const (
 	// LightProtoName protoName of Seele service
 	LightProtoName = "lightSeele"
 
-	// LightSeeleVersion version number of Seele protocol
-	LightSeeleVersion uint = 1
+	// LightSeeleVersion version number of Seele protocol, the flow control parameters
+	// are exchanged in the status data since version 2
+	LightSeeleVersion uint = 2
 
 	// MaxBlockHashRequest maximum hashes to request per message
 	MaxBlockHashRequest uint64 = 1024
@@ -29,6 +30,9 @@ const (
 	// MaxGapForAnnounce sends AnnounceQuery message if gap is more than this value
 	MaxGapForAnnounce uint64 = 256
 
+	// MaxCheckpointScanRange maximum blocks scanned for the verifier changes per checkpoint headers request
+	MaxCheckpointScanRange uint64 = 8192
+
 	// MinHashesCached minimum items cached in peer for client mode
 	MinHashesCached uint64 = 256
 
@@ -44,6 +48,7 @@ type statusData struct {
 	CurrentBlock    common.Hash
 	CurrentBlockNum uint64
 	GenesisBlock    common.Hash
+	FlowParams      *flowParams `rlp:"nil"` // flow control parameters announced by server
 }
 
 // AnnounceQuery header of AnnounceQuery request
@@ -91,3 +96,24 @@ type DownloadHeader struct {
 	HasFinished bool
 	Hearders    []*types.BlockHeader
 }
+
+// CheckpointHeaderQuery header of CheckpointHeaderQuery request
+type CheckpointHeaderQuery struct {
+	ReqID    uint32
+	BeginNum uint64
+}
+
+// CheckpointHeader body of CheckpointHeaderQuery response, which contains the header at the begin height,
+// the headers changing the verifiers after it, and the last scanned header.
+type CheckpointHeader struct {
+	ReqID       uint32
+	HasFinished bool
+	Headers     []*types.BlockHeader
+}
+
+// RequestRejected body of the reply to a header request rejected by the flow control of server
+type RequestRejected struct {
+	Code  uint16 // code of the rejected request
+	ReqID uint32 // magic or request ID of the rejected request
+	Error string
+}
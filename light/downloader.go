@@ -6,6 +6,7 @@
 package light
 
 import (
+	"math/big"
 	rand2 "math/rand"
 	"sync"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/seeleteam/go-seele/consensus"
 	"github.com/seeleteam/go-seele/core"
 	"github.com/seeleteam/go-seele/log"
+	"github.com/seeleteam/go-seele/node"
 	"github.com/seeleteam/go-seele/p2p"
 )
 
@@ -34,6 +36,10 @@ type Downloader struct {
 	wg         sync.WaitGroup
 	log        *log.SeeleLog
 	lock       sync.RWMutex
+
+	// checkpoint is the trusted BFT checkpoint, if set, the headers are verified
+	// by the committed seals of the verifiers instead of downloaded one by one.
+	checkpoint *node.BFTCheckpoint
 }
 
 // NewDownloader create Downloader
@@ -77,6 +83,11 @@ func (d *Downloader) doSynchronise(p *peer) {
 		d.lock.Unlock()
 	}()
 
+	if d.checkpoint != nil {
+		d.doCheckpointSynchronise(p)
+		return
+	}
+
 	ancestor, err := p.findAncestor()
 	if err != nil {
 		d.log.Info("doSynchronise called, but ancestor not found")
@@ -99,6 +110,11 @@ needQuit:
 	for {
 		select {
 		case msg := <-d.msgCh:
+			if msg.Code == requestRejectedCode && d.requestRejected(msg, reqID) {
+				d.log.Debug("Downloader.doSynchronise request rejected by server, peer: %v", p.peerID.Hex())
+				break needQuit
+			}
+
 			if msg.Code != downloadHeadersResponseCode {
 				break
 			}
@@ -179,6 +195,137 @@ needQuit:
 	return
 }
 
+// doCheckpointSynchronise syncs the headers changing the verifiers and the head from the trusted checkpoint or
+// the local head, and verifies each of them by the committed seals of the verifiers tracked so far.
+func (d *Downloader) doCheckpointSynchronise(p *peer) {
+	beginHeight, beginHash, beginTD, verifiers, err := d.checkpointBegin()
+	if err != nil {
+		d.log.Error("Downloader.doCheckpointSynchronise failed to get the begin header. %s", err)
+		return
+	}
+
+	reqID := rand2.Uint32()
+	if err := p.sendCheckpointHeadersRequest(reqID, beginHeight); err != nil {
+		d.log.Error("doCheckpointSynchronise sendCheckpointHeadersRequest err=%s", err)
+		return
+	}
+
+needQuit:
+	for {
+		select {
+		case msg := <-d.msgCh:
+			if msg.Code == requestRejectedCode && d.requestRejected(msg, reqID) {
+				d.log.Debug("Downloader.doCheckpointSynchronise request rejected by server, peer: %v", p.peerID.Hex())
+				break needQuit
+			}
+
+			if msg.Code != checkpointHeadersResponseCode {
+				break
+			}
+
+			var headMsg CheckpointHeader
+			if err := common.Deserialize(msg.Payload, &headMsg); err != nil {
+				d.log.Debug("Downloader.doCheckpointSynchronise Deserialize error. %s", err)
+				break needQuit
+			}
+
+			if headMsg.ReqID != reqID {
+				d.log.Debug("Downloader.doCheckpointSynchronise received but reqID not match")
+				break
+			}
+
+			if len(headMsg.Headers) == 0 || headMsg.Headers[0].Hash() != beginHash {
+				d.log.Debug("Downloader.doCheckpointSynchronise begin header not match, peer: %v, height: %d", p.peerID.Hex(), beginHeight)
+				break needQuit
+			}
+
+			// the trusted checkpoint header is written as is
+			if beginHeight > d.chain.CurrentHeader().Height {
+				if err := d.chain.WriteCheckpointHeader(headMsg.Headers[0], beginTD); err != nil {
+					d.log.Warn("Downloader.doCheckpointSynchronise WriteCheckpointHeader error. %s", err)
+					break needQuit
+				}
+			}
+
+			for _, head := range headMsg.Headers[1:] {
+				if head.Height <= beginHeight {
+					d.log.Warn("Downloader.doCheckpointSynchronise invalid header height %d, peer: %v", head.Height, p.peerID.Hex())
+					break needQuit
+				}
+
+				if err := verifyCheckpointHeader(head, verifiers); err != nil {
+					d.log.Warn("Downloader.doCheckpointSynchronise failed to verify header %d. %s", head.Height, err)
+					p.Peer.Disconnect("light PeerDownload anormaly")
+					break needQuit
+				}
+
+				// the difficulty of the BFT blocks is constant, so that the TD of the skipped headers is known
+				if head.Difficulty.Cmp(headMsg.Headers[0].Difficulty) != 0 {
+					d.log.Warn("Downloader.doCheckpointSynchronise invalid header difficulty %d, peer: %v", head.Difficulty, p.peerID.Hex())
+					p.Peer.Disconnect("light PeerDownload anormaly")
+					break needQuit
+				}
+
+				td := new(big.Int).Mul(head.Difficulty, new(big.Int).SetUint64(head.Height-beginHeight))
+				td.Add(td, beginTD)
+				if err := d.chain.WriteCheckpointHeader(head, td); err != nil {
+					d.log.Warn("Downloader.doCheckpointSynchronise WriteCheckpointHeader error. %s", err)
+					break needQuit
+				}
+
+				if verifiers, err = verifiersAfter(head); err != nil {
+					d.log.Warn("Downloader.doCheckpointSynchronise failed to update verifiers. %s", err)
+					break needQuit
+				}
+
+				d.log.Debug("Downloader.doCheckpointSynchronise WriteCheckpointHeader to chain, Height=%d, hash=%s, peer ID, %s", head.Height, head.Hash(), p.peerID.Hex())
+				beginHeight, beginHash, beginTD = head.Height, head.Hash(), td
+			}
+
+			if headMsg.HasFinished {
+				d.log.Debug("Downloader.doCheckpointSynchronise, has finished!")
+				break needQuit
+			}
+
+			reqID = rand2.Uint32()
+			if err := p.sendCheckpointHeadersRequest(reqID, beginHeight); err != nil {
+				d.log.Error("doCheckpointSynchronise sendCheckpointHeadersRequest err=%s", err)
+				break needQuit
+			}
+
+		case <-d.cancelCh:
+			d.log.Debug("Downloader.doCheckpointSynchronise received cancelCh")
+			break needQuit
+		case <-p.quitCh:
+			d.log.Debug("Downloader.doCheckpointSynchronise received peer's quitCh")
+			break needQuit
+		}
+	}
+
+	d.log.Debug("Downloader.doCheckpointSynchronise runs out")
+}
+
+// checkpointBegin returns the height, hash and TD to sync from and the verifiers of the blocks after it. It is the
+// trusted checkpoint if the local chain is below it, otherwise the local head which has been verified before.
+func (d *Downloader) checkpointBegin() (uint64, common.Hash, *big.Int, []common.Address, error) {
+	current := d.chain.CurrentHeader()
+	if current.Height < d.checkpoint.Height {
+		return d.checkpoint.Height, d.checkpoint.Hash, d.checkpoint.TD, d.checkpoint.Verifiers, nil
+	}
+
+	td, err := d.chain.GetStore().GetBlockTotalDifficulty(current.Hash())
+	if err != nil {
+		return 0, common.EmptyHash, nil, nil, err
+	}
+
+	verifiers, err := verifiersAfter(current)
+	if err != nil {
+		return 0, common.EmptyHash, nil, nil, err
+	}
+
+	return current.Height, current.Hash(), td, verifiers, nil
+}
+
 // DeliverMsg called by lightprotocol to deliver received msg from network
 func (d *Downloader) deliverMsg(p *peer, msg *p2p.Message) {
 	defer func() {
@@ -190,6 +337,18 @@ func (d *Downloader) deliverMsg(p *peer, msg *p2p.Message) {
 	return
 }
 
+// requestRejected returns true if the rejected request is the one of the specified ID,
+// and the sync is retried later after the buffer of the server recharged.
+func (d *Downloader) requestRejected(msg *p2p.Message, reqID uint32) bool {
+	var rejected RequestRejected
+	if err := common.Deserialize(msg.Payload, &rejected); err != nil {
+		d.log.Debug("Downloader.requestRejected Deserialize error. %s", err)
+		return false
+	}
+
+	return rejected.ReqID == reqID
+}
+
 // cancel cancels current session.
 func (d *Downloader) cancel() {
 	d.lock.Lock()
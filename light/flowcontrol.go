@@ -0,0 +1,222 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package light
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/seeleteam/go-seele/common/errors"
+)
+
+const (
+	// defaultBufLimit is the maximum cost buffered for a client of the light server
+	defaultBufLimit uint64 = 300000
+
+	// defaultMinRecharge is the cost recharged per second for a client of the light server
+	defaultMinRecharge uint64 = 50000
+
+	// minServerRecharge is the minimum cost recharged per second accepted by the client from the light server
+	minServerRecharge uint64 = 1000
+
+	// maxBufferExceeded is the number of requests exceeding the buffer in a row before the client is disconnected
+	maxBufferExceeded = 10
+)
+
+var (
+	errBufferExceeded    = errors.New("request cost exceeds the flow control buffer")
+	errTooManyExceeded   = errors.New("too many requests exceeding the flow control buffer")
+	errInvalidFlowParams = errors.New("invalid flow control parameters")
+	defaultRequestCosts  = []requestCost{
+		{announceRequestCode, 100, 0},
+		{syncHashRequestCode, 1000, 0},
+		{downloadHeadersRequestCode, 5000, 0},
+		{checkpointHeadersRequestCode, 10000, 0},
+		{blockRequestCode, 2000, 0},
+		{addTxRequestCode, 500, 0},
+		{trieRequestCode, 1000, 0},
+		{receiptRequestCode, 1000, 0},
+		{txByHashRequestCode, 1000, 0},
+		{debtRequestCode, 1000, 0},
+		{codeRequestCode, 1000, 0},
+		{storageRequestCode, 500, 500},
+		{trieNodeRequestCode, 500, 0},
+	}
+)
+
+// requestCost is the cost of a request type, charged from the flow control buffer of the client.
+type requestCost struct {
+	Code     uint16
+	BaseCost uint64
+	ItemCost uint64 // cost per item for the requests of multiple items, e.g. storage keys
+}
+
+// flowParams are the flow control parameters of the light server, announced in the status data.
+type flowParams struct {
+	BufLimit    uint64
+	MinRecharge uint64 // cost recharged per second
+	Costs       []requestCost
+}
+
+func newFlowParams() *flowParams {
+	return &flowParams{
+		BufLimit:    defaultBufLimit,
+		MinRecharge: defaultMinRecharge,
+		Costs:       defaultRequestCosts,
+	}
+}
+
+// validate checks the flow control parameters announced by server, so that the client
+// would not wait for the buffer recharged too slowly.
+func (params *flowParams) validate() error {
+	if params.BufLimit == 0 {
+		return errors.NewStackedError(errInvalidFlowParams, "zero buffer limit")
+	}
+
+	if params.MinRecharge < minServerRecharge {
+		return errors.NewStackedErrorf(errInvalidFlowParams, "recharge %d less than %d", params.MinRecharge, minServerRecharge)
+	}
+
+	return nil
+}
+
+// cost returns the cost of the request with the specified items, and false if the request is free.
+func (params *flowParams) cost(code uint16, items uint64) (uint64, bool) {
+	for _, c := range params.Costs {
+		if c.Code == code {
+			return c.BaseCost + c.ItemCost*items, true
+		}
+	}
+
+	return 0, false
+}
+
+// multiItemRequest is implemented by the requests of which the cost depends on the number of items.
+type multiItemRequest interface {
+	itemCount() uint64
+}
+
+// requestItems returns the number of items in the request if any.
+func requestItems(request interface{}) uint64 {
+	if r, ok := request.(multiItemRequest); ok {
+		return r.itemCount()
+	}
+
+	return 0
+}
+
+// flowBuffer is the flow control buffer of a client, which is charged by the requests and recharged over time.
+// The server keeps the buffer of each client, and the client estimates the buffer of each server the same way.
+type flowBuffer struct {
+	lock       sync.Mutex
+	value      uint64
+	limit      uint64
+	recharge   uint64
+	lastUpdate time.Time
+}
+
+func newFlowBuffer(limit, recharge uint64, now time.Time) *flowBuffer {
+	return &flowBuffer{
+		value:      limit,
+		limit:      limit,
+		recharge:   recharge,
+		lastUpdate: now,
+	}
+}
+
+func (b *flowBuffer) update(now time.Time) {
+	if elapsed := now.Sub(b.lastUpdate); elapsed > 0 {
+		b.value += uint64(elapsed.Seconds() * float64(b.recharge))
+		if b.value > b.limit {
+			b.value = b.limit
+		}
+	}
+
+	b.lastUpdate = now
+}
+
+// reserve deducts the cost from the buffer if enough, otherwise returns the time to wait for recharging.
+// The cost more than the limit is reserved when the buffer is full.
+func (b *flowBuffer) reserve(cost uint64, now time.Time) time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.update(now)
+
+	if cost > b.limit {
+		cost = b.limit
+	}
+
+	if b.value >= cost {
+		b.value -= cost
+		return 0
+	}
+
+	if b.recharge == 0 {
+		return time.Second
+	}
+
+	return time.Duration(float64(cost-b.value) / float64(b.recharge) * float64(time.Second))
+}
+
+// accept deducts the cost from the buffer and returns true if enough, otherwise returns false.
+func (b *flowBuffer) accept(cost uint64, now time.Time) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.update(now)
+
+	if cost > b.limit {
+		cost = b.limit
+	}
+
+	if b.value < cost {
+		return false
+	}
+
+	b.value -= cost
+	return true
+}
+
+// wait blocks until the buffer is enough for the cost and deducts it, or the quit channel is closed.
+func (b *flowBuffer) wait(cost uint64, quitCh chan struct{}) {
+	for d := b.reserve(cost, time.Now()); d > 0; d = b.reserve(cost, time.Now()) {
+		select {
+		case <-time.After(d):
+		case <-quitCh:
+			return
+		}
+	}
+}
+
+// clientMetrics are the metrics of the requests from a client of the light server.
+type clientMetrics struct {
+	names    []string
+	requests metrics.Meter
+	rejected metrics.Meter
+	cost     metrics.Meter
+}
+
+func newClientMetrics(peerID string) *clientMetrics {
+	prefix := fmt.Sprintf("light.server.client.%s.", peerID)
+	m := &clientMetrics{
+		names: []string{prefix + "requests", prefix + "rejected", prefix + "cost"},
+	}
+
+	m.requests = metrics.GetOrRegisterMeter(m.names[0], nil)
+	m.rejected = metrics.GetOrRegisterMeter(m.names[1], nil)
+	m.cost = metrics.GetOrRegisterMeter(m.names[2], nil)
+
+	return m
+}
+
+func (m *clientMetrics) unregister() {
+	for _, name := range m.names {
+		metrics.Unregister(name)
+	}
+}
@@ -0,0 +1,122 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package light
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/errors"
+	"github.com/seeleteam/go-seele/p2p"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FlowParams_Cost(t *testing.T) {
+	params := newFlowParams()
+
+	cost, ok := params.cost(trieRequestCode, 0)
+	assert.True(t, ok)
+	assert.Equal(t, cost, uint64(1000))
+
+	cost, ok = params.cost(storageRequestCode, requestItems(&odrStorageRequest{Keys: make([]common.Hash, 3)}))
+	assert.True(t, ok)
+	assert.Equal(t, cost, uint64(2000))
+
+	// responses are free
+	_, ok = params.cost(trieResponseCode, 0)
+	assert.False(t, ok)
+}
+
+func Test_FlowBuffer_Accept(t *testing.T) {
+	now := time.Now()
+	buffer := newFlowBuffer(1000, 100, now)
+
+	assert.True(t, buffer.accept(600, now))
+	assert.False(t, buffer.accept(600, now))
+
+	// recharged 200 in 2 seconds
+	now = now.Add(2 * time.Second)
+	assert.True(t, buffer.accept(600, now))
+	assert.Equal(t, buffer.value, uint64(0))
+
+	// recharged up to the limit
+	now = now.Add(time.Minute)
+	assert.True(t, buffer.accept(1000, now))
+	assert.False(t, buffer.accept(1, now))
+}
+
+func Test_FlowBuffer_Reserve(t *testing.T) {
+	now := time.Now()
+	buffer := newFlowBuffer(1000, 100, now)
+
+	assert.Equal(t, buffer.reserve(800, now), time.Duration(0))
+	assert.Equal(t, buffer.reserve(500, now), 3*time.Second)
+
+	now = now.Add(3 * time.Second)
+	assert.Equal(t, buffer.reserve(500, now), time.Duration(0))
+
+	// the cost more than the limit waits for the full buffer
+	assert.Equal(t, buffer.reserve(2000, now), 10*time.Second)
+}
+
+func Test_StatusData_Serializable(t *testing.T) {
+	status := statusData{
+		ProtocolVersion: uint32(LightSeeleVersion),
+		NetworkID:       "test",
+		IsServer:        true,
+		TD:              big.NewInt(38),
+		CurrentBlock:    common.StringToHash("current"),
+		CurrentBlockNum: 38,
+		GenesisBlock:    common.StringToHash("genesis"),
+		FlowParams:      newFlowParams(),
+	}
+	assertSerializable(t, &status, &statusData{})
+
+	// client announces no flow params
+	status.IsServer, status.FlowParams = false, nil
+	assertSerializable(t, &status, &statusData{})
+}
+
+func Test_FlowParams_Validate(t *testing.T) {
+	assert.Nil(t, newFlowParams().validate())
+
+	params := newFlowParams()
+	params.MinRecharge = 0
+	assert.True(t, errors.IsOrContains(params.validate(), errInvalidFlowParams))
+
+	params.MinRecharge = minServerRecharge - 1
+	assert.True(t, errors.IsOrContains(params.validate(), errInvalidFlowParams))
+
+	params = newFlowParams()
+	params.BufLimit = 0
+	assert.True(t, errors.IsOrContains(params.validate(), errInvalidFlowParams))
+}
+
+func Test_RequestRejected(t *testing.T) {
+	rejected := RequestRejected{
+		Code:  downloadHeadersRequestCode,
+		ReqID: 38,
+		Error: errBufferExceeded.Error(),
+	}
+	assertSerializable(t, &rejected, &RequestRejected{})
+
+	// the request ID is decoded from the rejected request
+	query := &DownloadHeaderQuery{ReqID: 38, BeginNum: 100}
+	msg := &p2p.Message{Code: downloadHeadersRequestCode, Payload: common.SerializePanic(query)}
+	assert.Equal(t, requestID(msg), uint32(38))
+
+	announce := &AnnounceQuery{Magic: 39}
+	msg = &p2p.Message{Code: announceRequestCode, Payload: common.SerializePanic(announce)}
+	assert.Equal(t, requestID(msg), uint32(39))
+
+	// the downloader matches the reply with the request ID
+	d := newDownloader(nil)
+	msg = &p2p.Message{Code: requestRejectedCode, Payload: common.SerializePanic(&rejected)}
+	assert.True(t, d.requestRejected(msg, 38))
+	assert.False(t, d.requestRejected(msg, 39))
+}
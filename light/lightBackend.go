@@ -63,7 +63,7 @@ func (l *LightBackend) GetBlock(hash common.Hash, height int64) (*types.Block, e
 	if hash.IsEmpty() {
 		if height < 0 {
 			request.Hash = l.ChainBackend().CurrentHeader().Hash()
-		} else if request.Hash, err = l.ChainBackend().GetStore().GetBlockHash(uint64(height)); err != nil {
+		} else if request.Hash, err = canonicalHash(l.ChainBackend().GetStore(), uint64(height)); err != nil {
 			return nil, errors.NewStackedErrorf(err, "failed to get block hash by height %v", height)
 		}
 	}
@@ -132,3 +132,25 @@ func (l *LightBackend) GetDebt(debtHash common.Hash) (*types.Debt, *api.BlockInd
 
 	return result.Debt, result.BlockIndex, nil
 }
+
+// GetCode returns the proved contract code of the specified address at the specified block.
+func (l *LightBackend) GetCode(blockHash common.Hash, contract common.Address) ([]byte, error) {
+	filter := peerFilter{blockHash: blockHash}
+	response, err := l.s.odrBackend.retrieveWithFilter(&odrCodeRequest{BlockHash: blockHash, Address: contract}, filter)
+	if err != nil {
+		return nil, errors.NewStackedError(err, "failed to retrieve ODR code")
+	}
+
+	return response.(*odrCodeResponse).Code, nil
+}
+
+// GetStorage returns the proved contract storage values of the specified keys at the specified block.
+func (l *LightBackend) GetStorage(blockHash common.Hash, contract common.Address, keys []common.Hash) ([][]byte, error) {
+	filter := peerFilter{blockHash: blockHash}
+	response, err := l.s.odrBackend.retrieveWithFilter(&odrStorageRequest{BlockHash: blockHash, Address: contract, Keys: keys}, filter)
+	if err != nil {
+		return nil, errors.NewStackedError(err, "failed to retrieve ODR storage")
+	}
+
+	return response.(*odrStorageResponse).Values, nil
+}
@@ -21,11 +21,16 @@ import (
 	"github.com/seeleteam/go-seele/log"
 )
 
+var (
+	errCheckpointTDTooLow = errors.New("checkpoint header TD too low")
+	errHeaderSkipped      = errors.New("header skipped by the checkpoint sync, please query by hash")
+)
+
 // LightChain represents a canonical chain that by default only handles block headers.
 type LightChain struct {
 	mutex                     sync.RWMutex
 	bcStore                   store.BlockchainStore
-	odrBackend                *odrBackend
+	odrBackend                odrRetriever
 	engine                    consensus.Engine
 	currentHeader             *types.BlockHeader
 	canonicalTD               *big.Int
@@ -84,9 +89,14 @@ func (lc *LightChain) GetStore() store.BlockchainStore {
 	return lc.bcStore
 }
 
+// AccountDB returns nil, since the state is retrieved from the light servers on demand.
+func (lc *LightChain) AccountDB() database.Database {
+	return nil
+}
+
 // GetHeader retrieves a block header from the database by hash and number.
 func (lc *LightChain) GetHeaderByHeight(height uint64) *types.BlockHeader {
-	hash, err := lc.bcStore.GetBlockHash(height)
+	hash, err := canonicalHash(lc.bcStore, height)
 	if err != nil {
 		lc.log.Warn("get block header by height failed, err %s. height %d", err, height)
 		return nil
@@ -153,6 +163,33 @@ func (lc *LightChain) WriteHeader(header *types.BlockHeader) error {
 	return nil
 }
 
+// WriteCheckpointHeader writes the header verified by the committed seals of the verifiers as the head with the
+// specified total difficulty. The headers between the current head and it are skipped, so that they could not be
+// found by height, see canonicalHash.
+func (lc *LightChain) WriteCheckpointHeader(header *types.BlockHeader, td *big.Int) error {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	if header.Height <= lc.currentHeader.Height {
+		return errors.NewStackedErrorf(core.ErrBlockAlreadyExists, "checkpoint header height %v not above the head %v", header.Height, lc.currentHeader.Height)
+	}
+
+	if td.Cmp(lc.canonicalTD) <= 0 {
+		return errors.NewStackedErrorf(errCheckpointTDTooLow, "checkpoint header TD %v not above the head TD %v", td, lc.canonicalTD)
+	}
+
+	if err := lc.bcStore.PutBlockHeader(header.Hash(), header, td, true); err != nil {
+		return errors.NewStackedErrorf(err, "failed to put block header, header = %+v", header)
+	}
+
+	lc.canonicalTD = td
+	lc.currentHeader = header
+
+	lc.headerChangedEventManager.Fire(header)
+
+	return nil
+}
+
 // GetCurrentState get current state
 func (lc *LightChain) GetCurrentState() (*state.Statedb, error) {
 	return lc.GetStateByRootAndBlockHash(lc.currentHeader.StateHash, lc.currentHeader.Hash())
@@ -169,4 +206,26 @@ func (lc *LightChain) PutTd(td *big.Int) {
 
 func (lc *LightChain) PutCurrentHeader(header *types.BlockHeader) {
 	lc.currentHeader = header
-}
\ No newline at end of file
+}
+
+// canonicalHash returns the block hash of the given height in the canonical chain. The headers skipped by the
+// checkpoint sync are not stored, so that errHeaderSkipped is returned for the heights below the head without
+// header, and they could only be retrieved by hash from the servers.
+func canonicalHash(bcStore store.BlockchainStore, height uint64) (common.Hash, error) {
+	hash, err := bcStore.GetBlockHash(height)
+	if err == nil {
+		return hash, nil
+	}
+
+	headHash, headErr := bcStore.GetHeadBlockHash()
+	if headErr != nil {
+		return common.EmptyHash, err
+	}
+
+	head, headErr := bcStore.GetBlockHeader(headHash)
+	if headErr != nil || height > head.Height {
+		return common.EmptyHash, err
+	}
+
+	return common.EmptyHash, errors.NewStackedErrorf(errHeaderSkipped, "height %v below the head %v", height, head.Height)
+}
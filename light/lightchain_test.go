@@ -14,6 +14,7 @@ import (
 	"github.com/seeleteam/go-seele/common/errors"
 	"github.com/seeleteam/go-seele/consensus"
 	"github.com/seeleteam/go-seele/consensus/pow"
+	"github.com/seeleteam/go-seele/core"
 	"github.com/seeleteam/go-seele/core/store"
 	"github.com/seeleteam/go-seele/core/types"
 	"github.com/seeleteam/go-seele/crypto"
@@ -117,3 +118,38 @@ func Test_LightChain_WriteHeader(t *testing.T) {
 	err = lc.WriteHeader(blockHeader)
 	assert.Equal(t, err, nil)
 }
+
+func Test_LightChain_WriteCheckpointHeader(t *testing.T) {
+	lc, dispose, _ := newTestLightChain()
+	defer dispose()
+
+	header := newTestNonGensisBlockHeader(newTestBlockHeader(), big.NewInt(1), 10)
+	assert.Equal(t, lc.WriteCheckpointHeader(header, big.NewInt(100)), nil)
+	assert.Equal(t, lc.CurrentHeader(), header)
+
+	td, err := lc.GetStore().GetBlockTotalDifficulty(header.Hash())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, td, big.NewInt(100))
+
+	// the TD must be above the head
+	next := newTestNonGensisBlockHeader(header, big.NewInt(1), 20)
+	err = lc.WriteCheckpointHeader(next, big.NewInt(100))
+	assert.True(t, errors.IsOrContains(err, errCheckpointTDTooLow))
+
+	// the height must be above the head
+	err = lc.WriteCheckpointHeader(newTestNonGensisBlockHeader(header, big.NewInt(1), 10), big.NewInt(200))
+	assert.True(t, errors.IsOrContains(err, core.ErrBlockAlreadyExists))
+
+	// the headers between are skipped
+	_, err = canonicalHash(lc.GetStore(), 5)
+	assert.True(t, errors.IsOrContains(err, errHeaderSkipped))
+	assert.Equal(t, lc.GetHeaderByHeight(5) == nil, true)
+
+	hash, err := canonicalHash(lc.GetStore(), 10)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, hash, header.Hash())
+
+	_, err = canonicalHash(lc.GetStore(), 11)
+	assert.Equal(t, err != nil, true)
+	assert.False(t, errors.IsOrContains(err, errHeaderSkipped))
+}
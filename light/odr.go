@@ -24,6 +24,12 @@ const (
 	txByHashResponseCode
 	debtRequestCode
 	debtResponseCode
+	codeRequestCode
+	codeResponseCode
+	storageRequestCode
+	storageResponseCode
+	trieNodeRequestCode
+	trieNodeResponseCode
 	protocolMsgCodeLength // protocolMsgCodeLength always defined in the end.
 )
 
@@ -35,6 +41,9 @@ var (
 		receiptRequestCode:  func() odrRequest { return &odrReceiptRequest{} },
 		txByHashRequestCode: func() odrRequest { return &odrTxByHashRequest{} },
 		debtRequestCode:     func() odrRequest { return &odrDebtRequest{} },
+		codeRequestCode:     func() odrRequest { return &odrCodeRequest{} },
+		storageRequestCode:  func() odrRequest { return &odrStorageRequest{} },
+		trieNodeRequestCode: func() odrRequest { return &odrTrieNodeRequest{} },
 	}
 
 	odrResponseFactories = map[uint16]func() odrResponse{
@@ -44,6 +53,9 @@ var (
 		receiptResponseCode:  func() odrResponse { return &odrReceiptResponse{} },
 		txByHashResponseCode: func() odrResponse { return &odrTxByHashResponse{} },
 		debtResponseCode:     func() odrResponse { return &odrDebtResponse{} },
+		codeResponseCode:     func() odrResponse { return &odrCodeResponse{} },
+		storageResponseCode:  func() odrResponse { return &odrStorageResponse{} },
+		trieNodeResponseCode: func() odrResponse { return &odrTrieNodeResponse{} },
 	}
 )
 
@@ -16,6 +16,7 @@ import (
 	"github.com/seeleteam/go-seele/core/store"
 	"github.com/seeleteam/go-seele/core/types"
 	"github.com/seeleteam/go-seele/crypto"
+	"github.com/seeleteam/go-seele/database"
 	"github.com/seeleteam/go-seele/database/leveldb"
 	"github.com/seeleteam/go-seele/event"
 	"github.com/seeleteam/go-seele/log"
@@ -130,10 +131,14 @@ func (chain *TestBlockChain) GetStore() store.BlockchainStore {
 	return bcStore
 }
 
+func (chain *TestBlockChain) AccountDB() database.Database { return nil }
+
 func (chain *TestBlockChain) CurrentHeader() *types.BlockHeader { return nil }
 
 func (chain *TestBlockChain) WriteHeader(*types.BlockHeader) error { return nil }
 
+func (chain *TestBlockChain) WriteCheckpointHeader(*types.BlockHeader, *big.Int) error { return nil }
+
 func newTestBlock() *types.Block {
 	header := newTestBlockHeader()
 	txs := []*types.Transaction{
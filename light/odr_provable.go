@@ -29,20 +29,20 @@ func (response *OdrProvableResponse) proveHeader(bcStore store.BlockchainStore)
 		return nil, nil
 	}
 
-	header, err := bcStore.GetBlockHeader(response.BlockIndex.BlockHash)
-	if err != nil {
-		return nil, errors.NewStackedErrorf(err, "failed to get block header by hash %v", response.BlockIndex.BlockHash)
-	}
-
-	canonicalHash, err := bcStore.GetBlockHash(response.BlockIndex.BlockHeight)
+	hash, err := canonicalHash(bcStore, response.BlockIndex.BlockHeight)
 	if err != nil {
 		return nil, errors.NewStackedErrorf(err, "failed to get block hash by height %v", response.BlockIndex.BlockHeight)
 	}
 
-	if !canonicalHash.Equal(response.BlockIndex.BlockHash) {
+	if !hash.Equal(response.BlockIndex.BlockHash) {
 		return nil, types.ErrBlockHashMismatch
 	}
 
+	header, err := bcStore.GetBlockHeader(response.BlockIndex.BlockHash)
+	if err != nil {
+		return nil, errors.NewStackedErrorf(err, "failed to get block header by hash %v", response.BlockIndex.BlockHash)
+	}
+
 	return header, nil
 }
 
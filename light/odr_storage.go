@@ -0,0 +1,155 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package light
+
+import (
+	"fmt"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/errors"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/core/store"
+	"github.com/seeleteam/go-seele/trie"
+)
+
+// maxStorageKeys is the maximum number of storage slots proved in a single request.
+const maxStorageKeys = 64
+
+var errTooManyStorageKeys = fmt.Errorf("too many storage keys, the maximum is %d", maxStorageKeys)
+
+// odrCodeRequest retrieves the contract code with the state trie proof at the specified block.
+type odrCodeRequest struct {
+	OdrItem
+	BlockHash common.Hash
+	Address   common.Address
+}
+
+type odrCodeResponse struct {
+	OdrItem
+	Proof []proofNode
+	Code  []byte // proved code, only set after validated
+}
+
+func (request *odrCodeRequest) code() uint16 {
+	return codeRequestCode
+}
+
+func (request *odrCodeRequest) handle(lp *LightProtocol) (uint16, odrResponse) {
+	proof, err := getStateProof(lp, request.BlockHash, state.CodeKey(request.Address))
+	if err != nil {
+		return newErrorResponse(codeResponseCode, request.ReqID, err)
+	}
+
+	var result odrCodeResponse
+	result.ReqID = request.ReqID
+	result.Proof = mapToArray(proof)
+
+	return codeResponseCode, &result
+}
+
+func (response *odrCodeResponse) validate(request odrRequest, bcStore store.BlockchainStore) error {
+	codeRequest := request.(*odrCodeRequest)
+	header, err := bcStore.GetBlockHeader(codeRequest.BlockHash)
+	if err != nil {
+		return errors.NewStackedErrorf(err, "failed to get block header by hash %v", codeRequest.BlockHash)
+	}
+
+	code, err := trie.VerifyProof(header.StateHash, state.CodeKey(codeRequest.Address), arrayToMap(response.Proof))
+	if err != nil {
+		return errors.NewStackedError(err, "failed to verify the code proof")
+	}
+
+	response.Code = code
+
+	return nil
+}
+
+// odrStorageRequest retrieves the contract storage slots with the state trie proof at the specified block.
+type odrStorageRequest struct {
+	OdrItem
+	BlockHash common.Hash
+	Address   common.Address
+	Keys      []common.Hash
+}
+
+type odrStorageResponse struct {
+	OdrItem
+	Proof  []proofNode // merged proof of all the storage keys
+	Values [][]byte    // proved values in the order of keys, only set after validated
+}
+
+func (request *odrStorageRequest) code() uint16 {
+	return storageRequestCode
+}
+
+func (request *odrStorageRequest) itemCount() uint64 {
+	return uint64(len(request.Keys))
+}
+
+func (request *odrStorageRequest) handle(lp *LightProtocol) (uint16, odrResponse) {
+	if len(request.Keys) > maxStorageKeys {
+		return newErrorResponse(storageResponseCode, request.ReqID, errTooManyStorageKeys)
+	}
+
+	merged := make(map[string][]byte)
+	for _, key := range request.Keys {
+		proof, err := getStateProof(lp, request.BlockHash, state.StorageKey(request.Address, key))
+		if err != nil {
+			return newErrorResponse(storageResponseCode, request.ReqID, err)
+		}
+
+		for k, v := range proof {
+			merged[k] = v
+		}
+	}
+
+	var result odrStorageResponse
+	result.ReqID = request.ReqID
+	result.Proof = mapToArray(merged)
+
+	return storageResponseCode, &result
+}
+
+func (response *odrStorageResponse) validate(request odrRequest, bcStore store.BlockchainStore) error {
+	storageRequest := request.(*odrStorageRequest)
+	header, err := bcStore.GetBlockHeader(storageRequest.BlockHash)
+	if err != nil {
+		return errors.NewStackedErrorf(err, "failed to get block header by hash %v", storageRequest.BlockHash)
+	}
+
+	proof := arrayToMap(response.Proof)
+	response.Values = make([][]byte, len(storageRequest.Keys))
+	for i, key := range storageRequest.Keys {
+		value, err := trie.VerifyProof(header.StateHash, state.StorageKey(storageRequest.Address, key), proof)
+		if err != nil {
+			return errors.NewStackedErrorf(err, "failed to verify the storage proof of key %v", key)
+		}
+
+		response.Values[i] = value
+	}
+
+	return nil
+}
+
+// getStateProof returns the state trie proof of the key at the specified block.
+func getStateProof(lp *LightProtocol, blockHash common.Hash, key []byte) (map[string][]byte, error) {
+	header, err := lp.chain.GetStore().GetBlockHeader(blockHash)
+	if err != nil {
+		return nil, errors.NewStackedErrorf(err, "failed to get block header by hash %v", blockHash)
+	}
+
+	statedb, err := lp.chain.GetState(header.StateHash)
+	if err != nil {
+		return nil, errors.NewStackedErrorf(err, "failed to get statedb by root hash %v", header.StateHash)
+	}
+
+	proof, err := statedb.Trie().GetProof(key)
+	if err != nil {
+		return nil, errors.NewStackedError(err, "failed to get trie proof")
+	}
+
+	return proof, nil
+}
@@ -0,0 +1,91 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package light
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/core/store"
+	"github.com/seeleteam/go-seele/database/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OdrStorage_Serializable(t *testing.T) {
+	codeRequest := odrCodeRequest{
+		OdrItem:   OdrItem{ReqID: 38, Error: "hello"},
+		BlockHash: common.StringToHash("block hash"),
+		Address:   common.BytesToAddress([]byte("contract")),
+	}
+	assertSerializable(t, &codeRequest, &odrCodeRequest{})
+
+	codeResponse := odrCodeResponse{
+		OdrItem: OdrItem{ReqID: 38},
+		Proof:   []proofNode{{"key", []byte("value")}},
+		Code:    make([]byte, 0),
+	}
+	assertSerializable(t, &codeResponse, &odrCodeResponse{})
+
+	storageRequest := odrStorageRequest{
+		OdrItem:   OdrItem{ReqID: 38},
+		BlockHash: common.StringToHash("block hash"),
+		Address:   common.BytesToAddress([]byte("contract")),
+		Keys:      []common.Hash{common.StringToHash("key1"), common.StringToHash("key2")},
+	}
+	assertSerializable(t, &storageRequest, &odrStorageRequest{})
+}
+
+func Test_OdrStorage_Validate(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	// prepare the contract state on server side
+	contract := common.BytesToAddress([]byte("contract"))
+	key1, key2 := common.StringToHash("key1"), common.StringToHash("key2")
+
+	statedb := state.NewEmptyStatedb(db)
+	statedb.CreateAccount(contract)
+	statedb.SetCode(contract, []byte("code"))
+	statedb.SetData(contract, key1, []byte("value1"))
+	root, err := statedb.Hash()
+	assert.Nil(t, err)
+
+	bcStore := store.NewBlockchainDatabase(db)
+	header := newTestBlockHeader()
+	header.StateHash = root
+	assert.Nil(t, bcStore.PutBlockHeader(header.Hash(), header, big.NewInt(1), true))
+
+	// code
+	proof, err := statedb.Trie().GetProof(state.CodeKey(contract))
+	assert.Nil(t, err)
+
+	codeResponse := &odrCodeResponse{Proof: mapToArray(proof)}
+	assert.Nil(t, codeResponse.validate(&odrCodeRequest{BlockHash: header.Hash(), Address: contract}, bcStore))
+	assert.Equal(t, codeResponse.Code, []byte("code"))
+
+	// storage with a missing key
+	merged := make(map[string][]byte)
+	for _, key := range []common.Hash{key1, key2} {
+		proof, err := statedb.Trie().GetProof(state.StorageKey(contract, key))
+		assert.Nil(t, err)
+		for k, v := range proof {
+			merged[k] = v
+		}
+	}
+
+	request := &odrStorageRequest{BlockHash: header.Hash(), Address: contract, Keys: []common.Hash{key1, key2}}
+	storageResponse := &odrStorageResponse{Proof: mapToArray(merged)}
+	assert.Nil(t, storageResponse.validate(request, bcStore))
+	assert.Equal(t, storageResponse.Values, [][]byte{[]byte("value1"), nil})
+
+	// proof of another root
+	header.StateHash = common.StringToHash("other root")
+	assert.Nil(t, bcStore.PutBlockHeader(header.Hash(), header, big.NewInt(1), true))
+	request.BlockHash = header.Hash()
+	assert.NotNil(t, storageResponse.validate(request, bcStore))
+}
@@ -6,12 +6,21 @@
 package light
 
 import (
+	"bytes"
+
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/common/errors"
+	"github.com/seeleteam/go-seele/core/state"
 	"github.com/seeleteam/go-seele/core/store"
+	"github.com/seeleteam/go-seele/crypto"
 	"github.com/seeleteam/go-seele/trie"
 )
 
+var (
+	errTrieNodeUnsupported = errors.New("trie node is not available on the light node")
+	errTrieNodeMismatch    = errors.New("trie node mismatch with the requested hash")
+)
+
 type proofNode struct {
 	Key   string
 	Value []byte
@@ -73,3 +82,45 @@ func (odr *odrTriePoof) validate(request odrRequest, bcStore store.BlockchainSto
 
 	return nil
 }
+
+// odrTrieNodeRequest retrieves the state trie node by hash, e.g. the sibling node
+// required to collapse the trie when deleting keys, which is not in the proofs.
+type odrTrieNodeRequest struct {
+	OdrItem
+	Hash common.Hash
+}
+
+type odrTrieNodeResponse struct {
+	OdrItem
+	Node []byte
+}
+
+func (request *odrTrieNodeRequest) code() uint16 {
+	return trieNodeRequestCode
+}
+
+func (request *odrTrieNodeRequest) handle(lp *LightProtocol) (uint16, odrResponse) {
+	db := lp.chain.AccountDB()
+	if db == nil {
+		return newErrorResponse(trieNodeResponseCode, request.ReqID, errTrieNodeUnsupported)
+	}
+
+	node, err := db.Get(append(state.TrieDbPrefix, request.Hash.Bytes()...))
+	if err != nil {
+		return newErrorResponse(trieNodeResponseCode, request.ReqID, errors.NewStackedErrorf(err, "failed to get trie node %v", request.Hash))
+	}
+
+	var result odrTrieNodeResponse
+	result.ReqID = request.ReqID
+	result.Node = node
+
+	return trieNodeResponseCode, &result
+}
+
+func (response *odrTrieNodeResponse) validate(request odrRequest, bcStore store.BlockchainStore) error {
+	if hash := request.(*odrTrieNodeRequest).Hash; !bytes.Equal(crypto.Keccak256(response.Node), hash.Bytes()) {
+		return errors.NewStackedErrorf(errTrieNodeMismatch, "trie node hash %v", hash)
+	}
+
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 	"testing"
 
 	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/errors"
+	"github.com/seeleteam/go-seele/crypto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,3 +29,17 @@ func Test_odrTriePoof_Rlp(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, proof, proof2)
 }
+
+func Test_OdrTrieNode_Validate(t *testing.T) {
+	node := []byte("trie node")
+	request := &odrTrieNodeRequest{OdrItem: OdrItem{ReqID: 38}, Hash: common.BytesToHash(crypto.Keccak256(node))}
+	assertSerializable(t, request, &odrTrieNodeRequest{})
+
+	response := &odrTrieNodeResponse{OdrItem: OdrItem{ReqID: 38}, Node: node}
+	assertSerializable(t, response, &odrTrieNodeResponse{})
+	assert.Nil(t, response.validate(request, nil))
+
+	// node of another hash
+	response.Node = []byte("other node")
+	assert.True(t, errors.IsOrContains(response.validate(request, nil), errTrieNodeMismatch))
+}
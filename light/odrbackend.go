@@ -129,6 +129,7 @@ func (o *odrBackend) retrieveWithFilter(request odrRequest, filter peerFilter) (
 	request.setRequestID(reqID)
 	code, payload := request.code(), common.SerializePanic(request)
 	for _, p := range peerL {
+		p.waitFlowBuffer(code, requestItems(request))
 		o.log.Debug("peer send request, code = %s, payloadSizeBytes = %v", codeToStr(code), len(payload))
 		if err = p2p.SendMessage(p.rw, code, payload); err != nil {
 			o.log.Info("Failed to send message with peer %s", p.peerStrID)
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"math/big"
 	"sync"
+	"time"
 
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/core/types"
@@ -24,10 +25,14 @@ const (
 
 	// DiscAnnounceErr disconnect due to failed to send announce message
 	DiscAnnounceErr = "disconnect because send announce message err"
+
+	// DiscFlowParamsErr disconnect due to invalid flow control parameters of server
+	DiscFlowParamsErr = "disconnect because invalid flow control parameters of server"
 )
 
 var (
 	errMsgNotMatch     = errors.New("message mismatch")
+	errVersionNotMatch = errors.New("protocol version mismatch")
 	errNetworkNotMatch = errors.New("networkID mismatch")
 	errModeNotMatch    = errors.New("server/client mode mismatch")
 	errGenesisNotMatch = errors.New("genesis hash mismatch")
@@ -62,6 +67,12 @@ type peer struct {
 
 	lastAnnounceCodeTime int64
 	log             *log.SeeleLog
+
+	// flow control: the buffer of the client in server mode, and the estimated buffer of the server in client mode.
+	flowParams     *flowParams
+	flowBuffer     *flowBuffer
+	bufferExceeded int            // requests exceeding the buffer in a row, only in server mode
+	metrics        *clientMetrics // only in server mode
 }
 
 func idToStr(id common.Address) string {
@@ -87,6 +98,10 @@ func newPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter, log *log.SeeleLog,
 func (p *peer) close() {
 	p.lock.Lock()
 	defer p.lock.Unlock()
+	if p.metrics != nil {
+		p.metrics.unregister()
+	}
+
 	if p.quitCh != nil {
 		select {
 		case <-p.quitCh:
@@ -170,6 +185,7 @@ func (p *peer) sendDownloadHeadersRequest(reqID uint32, begin uint64) error {
 
 	buff := common.SerializePanic(query)
 	p.log.Debug("peer send [downloadHeadersRequestCode] query with size %d byte", len(buff))
+	p.waitFlowBuffer(downloadHeadersRequestCode, 0)
 	return p2p.SendMessage(p.rw, downloadHeadersRequestCode, buff)
 }
 
@@ -200,6 +216,72 @@ func (p *peer) handleDownloadHeadersRequest(msg *DownloadHeaderQuery) error {
 	return p2p.SendMessage(p.rw, downloadHeadersResponseCode, buff)
 }
 
+// sendRequestRejected replies the header request rejected by the flow control, only in server mode.
+func (p *peer) sendRequestRejected(code uint16, reqID uint32, err error) error {
+	reply := &RequestRejected{
+		Code:  code,
+		ReqID: reqID,
+		Error: err.Error(),
+	}
+
+	buff := common.SerializePanic(reply)
+	p.log.Debug("peer send [requestRejectedCode] reply with size %d byte", len(buff))
+	return p2p.SendMessage(p.rw, requestRejectedCode, buff)
+}
+
+func (p *peer) sendCheckpointHeadersRequest(reqID uint32, begin uint64) error {
+	query := &CheckpointHeaderQuery{
+		ReqID:    reqID,
+		BeginNum: begin,
+	}
+
+	buff := common.SerializePanic(query)
+	p.log.Debug("peer send [checkpointHeadersRequestCode] query with size %d byte", len(buff))
+	p.waitFlowBuffer(checkpointHeadersRequestCode, 0)
+	return p2p.SendMessage(p.rw, checkpointHeadersRequestCode, buff)
+}
+
+// handleCheckpointHeadersRequest sends the headers changing the verifiers after the begin height,
+// so that the client could verify the last header by the verifiers and skip the others.
+func (p *peer) handleCheckpointHeadersRequest(msg *CheckpointHeaderQuery) error {
+	bcStore := p.protocolManager.chain.GetStore()
+	current := p.protocolManager.chain.CurrentHeader()
+
+	sendMsg := &CheckpointHeader{ReqID: msg.ReqID}
+	var last *types.BlockHeader
+	for height := msg.BeginNum; height <= current.Height && height < msg.BeginNum+MaxCheckpointScanRange; height++ {
+		hash, err := bcStore.GetBlockHash(height)
+		if err != nil {
+			break
+		}
+
+		header, err := bcStore.GetBlockHeader(hash)
+		if err != nil {
+			break
+		}
+
+		last = header
+		if height == msg.BeginNum || changesVerifiers(header) {
+			sendMsg.Headers = append(sendMsg.Headers, header)
+			if uint64(len(sendMsg.Headers)) == MaxBlockHeaderRequest {
+				break
+			}
+		}
+	}
+
+	if last != nil && sendMsg.Headers[len(sendMsg.Headers)-1] != last {
+		sendMsg.Headers = append(sendMsg.Headers, last)
+	}
+
+	if last != nil && last.Hash() == current.Hash() {
+		sendMsg.HasFinished = true
+	}
+
+	buff := common.SerializePanic(sendMsg)
+	p.log.Debug("peer send [checkpointHeadersResponseCode] query with size %d byte", len(buff))
+	return p2p.SendMessage(p.rw, checkpointHeadersResponseCode, buff)
+}
+
 func (p *peer) sendSyncHashRequest(magic uint32, begin uint64) error {
 	sendMsg := &HeaderHashSyncQuery{
 		Magic:    magic,
@@ -208,6 +290,7 @@ func (p *peer) sendSyncHashRequest(magic uint32, begin uint64) error {
 	buff := common.SerializePanic(sendMsg)
 
 	p.log.Debug("peer send [syncHashRequestCode] with length: size:%d byte peerid:%s begin=%d", len(buff), p.peerStrID, begin)
+	p.waitFlowBuffer(syncHashRequestCode, 0)
 	return p2p.SendMessage(p.rw, syncHashRequestCode, buff)
 }
 
@@ -319,6 +402,7 @@ func (p *peer) sendAnnounceQuery(magic uint32, begin uint64, end uint64) error {
 
 	buff := common.SerializePanic(query)
 	p.log.Debug("peer send [announceRequestCode] query with size %d byte,magic:%s, peer:%s", len(buff),magic,p.peerStrID)
+	p.waitFlowBuffer(announceRequestCode, 0)
 	return p2p.SendMessage(p.rw, announceRequestCode, buff)
 }
 
@@ -477,6 +561,10 @@ func (p *peer) handShake(networkID string, td *big.Int, head common.Hash, headBl
 		GenesisBlock:    genesis,
 	}
 
+	if p.protocolManager.bServerMode {
+		msg.FlowParams = p.protocolManager.flowParams
+	}
+
 	if err := p2p.SendMessage(p.rw, statusDataMsgCode, common.SerializePanic(msg)); err != nil {
 		return err
 	}
@@ -495,6 +583,10 @@ func (p *peer) handShake(networkID string, td *big.Int, head common.Hash, headBl
 		return err
 	}
 
+	if retStatusMsg.ProtocolVersion != uint32(LightSeeleVersion) {
+		return errVersionNotMatch
+	}
+
 	if retStatusMsg.NetworkID != networkID {
 		return errNetworkNotMatch
 	}
@@ -508,5 +600,56 @@ func (p *peer) handShake(networkID string, td *big.Int, head common.Hash, headBl
 	}
 
 	p.head, p.td, p.headBlockNum = retStatusMsg.CurrentBlock, retStatusMsg.TD, retStatusMsg.CurrentBlockNum
+
+	if p.protocolManager.bServerMode {
+		p.flowParams = p.protocolManager.flowParams
+		p.metrics = newClientMetrics(p.peerStrID)
+	} else {
+		if retStatusMsg.FlowParams == nil || retStatusMsg.FlowParams.validate() != nil {
+			return errInvalidFlowParams
+		}
+
+		p.flowParams = retStatusMsg.FlowParams
+	}
+
+	if p.flowParams != nil {
+		p.flowBuffer = newFlowBuffer(p.flowParams.BufLimit, p.flowParams.MinRecharge, time.Now())
+	}
+
 	return nil
 }
+
+// waitFlowBuffer blocks until the estimated buffer of the server is enough for the request, only in client mode.
+func (p *peer) waitFlowBuffer(code uint16, items uint64) {
+	if p.protocolManager.bServerMode || p.flowParams == nil || p.flowBuffer == nil {
+		return
+	}
+
+	if cost, ok := p.flowParams.cost(code, items); ok {
+		p.flowBuffer.wait(cost, p.quitCh)
+	}
+}
+
+// acceptRequest charges the request from the buffer of the client, and returns false if exceeded, only in server mode.
+func (p *peer) acceptRequest(code uint16, items uint64) bool {
+	if !p.protocolManager.bServerMode || p.flowParams == nil || p.flowBuffer == nil {
+		return true
+	}
+
+	cost, ok := p.flowParams.cost(code, items)
+	if !ok {
+		return true
+	}
+
+	p.metrics.requests.Mark(1)
+	if !p.flowBuffer.accept(cost, time.Now()) {
+		p.bufferExceeded++
+		p.metrics.rejected.Mark(1)
+		return false
+	}
+
+	p.bufferExceeded = 0
+	p.metrics.cost.Mark(int64(cost))
+
+	return true
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/seeleteam/go-seele/core/state"
 	"github.com/seeleteam/go-seele/core/store"
 	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/database"
 	"github.com/seeleteam/go-seele/event"
 	"github.com/seeleteam/go-seele/log"
 	"github.com/seeleteam/go-seele/p2p"
@@ -32,6 +33,11 @@ const (
 	downloadHeadersRequestCode  uint16 = 5
 	downloadHeadersResponseCode uint16 = 6
 
+	checkpointHeadersRequestCode  uint16 = 7
+	checkpointHeadersResponseCode uint16 = 8
+
+	requestRejectedCode uint16 = 9
+
 	msgWaitTimeout = time.Second * 60
 )
 
@@ -45,9 +51,11 @@ type BlockChain interface {
 	GetState(root common.Hash) (*state.Statedb, error)
 	GetStateByRootAndBlockHash(root, blockHash common.Hash) (*state.Statedb, error)
 	GetStore() store.BlockchainStore
+	AccountDB() database.Database
 	GetHeadRollbackEventManager() *event.EventManager
 	CurrentHeader() *types.BlockHeader
 	WriteHeader(*types.BlockHeader) error
+	WriteCheckpointHeader(*types.BlockHeader, *big.Int) error
 	PutCurrentHeader(*types.BlockHeader)
 	PutTd(*big.Int)
 }
@@ -74,6 +82,12 @@ func codeToStr(code uint16) string {
 		return "downloadHeadersRequestCode"
 	case downloadHeadersResponseCode:
 		return "downloadHeadersResponseCode"
+	case checkpointHeadersRequestCode:
+		return "checkpointHeadersRequestCode"
+	case checkpointHeadersResponseCode:
+		return "checkpointHeadersResponseCode"
+	case requestRejectedCode:
+		return "requestRejectedCode"
 	case blockRequestCode:
 		return "blockRequestCode"
 	case blockResponseCode:
@@ -118,6 +132,7 @@ type LightProtocol struct {
 	syncCh              chan struct{}
 	chainHeaderChangeCh chan common.Hash
 	log                 *log.SeeleLog
+	flowParams          *flowParams // flow control parameters, only in server mode
 
 	shard uint
 }
@@ -144,7 +159,9 @@ func NewLightProtocol(networkID string, txPool TransactionPool, debtPool *core.D
 		shard:       shard,
 	}
 
-	if !serverMode {
+	if serverMode {
+		s.flowParams = newFlowParams()
+	} else {
 		s.downloader = newDownloader(chain)
 	}
 
@@ -277,6 +294,10 @@ func (lp *LightProtocol) handleAddPeer(p2pPeer *p2p.Peer, rw p2p.MsgReadWriter)
 			lp.log.Debug("handleAddPeer err. %s", err)
 		}
 
+		if err == errInvalidFlowParams {
+			newPeer.Disconnect(DiscFlowParamsErr)
+		}
+
 		return false
 	}
 
@@ -324,6 +345,22 @@ handler:
 			break
 		}
 
+		// charge the header requests, and the ODR requests are charged after decoded.
+		if odrRequestFactories[msg.Code] == nil && !peer.acceptRequest(msg.Code, 0) {
+			lp.log.Debug("request exceeds the flow control buffer, code = %s, peer = %s", codeToStr(msg.Code), peer.peerStrID)
+			if peer.bufferExceeded >= maxBufferExceeded {
+				lp.log.Warn("too many requests exceeding the flow control buffer, quit! peer = %s", peer.peerStrID)
+				break handler
+			}
+
+			if err := peer.sendRequestRejected(msg.Code, requestID(msg), errBufferExceeded); err != nil {
+				lp.log.Error("failed to sendRequestRejected, quit! %s", err)
+				break handler
+			}
+
+			continue
+		}
+
 		bNeedDeliverOdr := false
 		switch msg.Code {
 		case announceRequestCode:
@@ -399,6 +436,34 @@ handler:
 		case downloadHeadersResponseCode:
 			lp.downloader.deliverMsg(peer, msg)
 
+		case checkpointHeadersRequestCode:
+			var query CheckpointHeaderQuery
+			err := common.Deserialize(msg.Payload, &query)
+			if err != nil {
+				lp.log.Error("failed to deserialize CheckpointHeaderQuery, quit! %s", err)
+				break handler
+			}
+
+			if err := peer.handleCheckpointHeadersRequest(&query); err != nil {
+				lp.log.Error("failed to CheckpointHeaderQuery, quit! %s", err)
+				break handler
+			}
+
+		case checkpointHeadersResponseCode:
+			lp.downloader.deliverMsg(peer, msg)
+
+		case requestRejectedCode:
+			var rejected RequestRejected
+			if err := common.Deserialize(msg.Payload, &rejected); err != nil {
+				lp.log.Error("failed to deserialize RequestRejected, quit! %s", err)
+				break handler
+			}
+
+			lp.log.Debug("request rejected by server, code = %s, error = %s, peer = %s", codeToStr(rejected.Code), rejected.Error, peer.peerStrID)
+			if rejected.Code == downloadHeadersRequestCode || rejected.Code == checkpointHeadersRequestCode {
+				lp.downloader.deliverMsg(peer, msg)
+			}
+
 		default:
 			if odrResponseFactories[msg.Code] != nil {
 				bNeedDeliverOdr = true
@@ -418,6 +483,34 @@ handler:
 	peer.Disconnect(fmt.Sprintf("called from light.protocol.handlemsg. id=%s", peer.peerStrID))
 }
 
+// requestID returns the magic or request ID of the header request, or 0 if failed to decode.
+func requestID(msg *p2p.Message) uint32 {
+	switch msg.Code {
+	case announceRequestCode:
+		var query AnnounceQuery
+		if err := common.Deserialize(msg.Payload, &query); err == nil {
+			return query.Magic
+		}
+	case syncHashRequestCode:
+		var query HeaderHashSyncQuery
+		if err := common.Deserialize(msg.Payload, &query); err == nil {
+			return query.Magic
+		}
+	case downloadHeadersRequestCode:
+		var query DownloadHeaderQuery
+		if err := common.Deserialize(msg.Payload, &query); err == nil {
+			return query.ReqID
+		}
+	case checkpointHeadersRequestCode:
+		var query CheckpointHeaderQuery
+		if err := common.Deserialize(msg.Payload, &query); err == nil {
+			return query.ReqID
+		}
+	}
+
+	return 0
+}
+
 func (lp *LightProtocol) handleOdrRequest(peer *peer, msg *p2p.Message) error {
 	factory, ok := odrRequestFactories[msg.Code]
 	if !ok {
@@ -429,8 +522,18 @@ func (lp *LightProtocol) handleOdrRequest(peer *peer, msg *p2p.Message) error {
 		return fmt.Errorf("deserialize request failed with %s", err)
 	}
 
-	lp.log.Debug("begin to handle ODR request, code = %v, payloadLen = %v", codeToStr(msg.Code), len(msg.Payload))
-	respCode, response := request.handle(lp)
+	var respCode uint16
+	var response odrResponse
+	if peer.acceptRequest(msg.Code, requestItems(request)) {
+		lp.log.Debug("begin to handle ODR request, code = %v, payloadLen = %v", codeToStr(msg.Code), len(msg.Payload))
+		respCode, response = request.handle(lp)
+	} else if peer.bufferExceeded >= maxBufferExceeded {
+		return errTooManyExceeded
+	} else {
+		// the response code always follows the request code
+		respCode, response = newErrorResponse(msg.Code+1, request.getRequestID(), errBufferExceeded)
+	}
+
 	buff := common.SerializePanic(response)
 	lp.log.Debug("peer send response, code = %v, payloadSizeBytes = %v, peerID = %v", codeToStr(respCode), len(buff), peer.peerStrID)
 
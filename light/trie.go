@@ -6,29 +6,63 @@
 package light
 
 import (
+	"bytes"
+
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/common/errors"
 	"github.com/seeleteam/go-seele/database"
 	"github.com/seeleteam/go-seele/trie"
 )
 
+// odrDatabase caches the proved trie nodes, and retrieves the trie node by hash
+// from the light servers on demand if not cached.
 type odrDatabase struct {
-	kvs map[string][]byte
+	kvs       map[string][]byte
+	odr       odrRetriever
+	dbPrefix  []byte
+	blockHash common.Hash
 }
 
-func newOdrDatabase() *odrDatabase {
-	return &odrDatabase{make(map[string][]byte)}
+func newOdrDatabase(retriever odrRetriever, dbPrefix []byte, blockHash common.Hash) *odrDatabase {
+	return &odrDatabase{
+		kvs:       make(map[string][]byte),
+		odr:       retriever,
+		dbPrefix:  dbPrefix,
+		blockHash: blockHash,
+	}
 }
 
 // Get implements the trie.Database interface to store trie node key-value pairs.
 func (db *odrDatabase) Get(key []byte) ([]byte, error) {
-	return db.kvs[string(key)], nil
+	if value, ok := db.kvs[string(key)]; ok {
+		return value, nil
+	}
+
+	if !bytes.HasPrefix(key, db.dbPrefix) {
+		return nil, nil
+	}
+
+	request := &odrTrieNodeRequest{Hash: common.BytesToHash(key[len(db.dbPrefix):])}
+	filter := peerFilter{blockHash: db.blockHash}
+	response, err := db.odr.retrieveWithFilter(request, filter)
+	if err != nil {
+		return nil, errors.NewStackedError(err, "failed to retrieve ODR trie node")
+	}
+
+	node := response.(*odrTrieNodeResponse).Node
+	db.kvs[string(key)] = node
+
+	return node, nil
 }
 
 type odrRetriever interface {
 	retrieveWithFilter(request odrRequest, filter peerFilter) (odrResponse, error)
 }
 
+// odrTrie retrieves the trie proof of a key on demand and caches the proved nodes, so that
+// the keys are changed locally after proved, e.g. to execute a contract call on light node.
+// The nodes not in the proofs, e.g. the siblings to collapse when deleting keys, are retrieved
+// by hash on demand.
 type odrTrie struct {
 	odr       odrRetriever
 	root      common.Hash
@@ -36,20 +70,26 @@ type odrTrie struct {
 	dbPrefix  []byte
 	trie      *trie.Trie
 	blockHash common.Hash
+	proved    map[string]bool // keys of which the proof is retrieved
 }
 
 func newOdrTrie(retriever odrRetriever, root common.Hash, dbPrefix []byte, blockHash common.Hash) *odrTrie {
 	return &odrTrie{
 		odr:       retriever,
 		root:      root,
-		db:        newOdrDatabase(),
+		db:        newOdrDatabase(retriever, dbPrefix, blockHash),
 		dbPrefix:  dbPrefix,
 		blockHash: blockHash,
+		proved:    make(map[string]bool),
 	}
 }
 
 func (t *odrTrie) Hash() common.Hash {
-	panic("unsupported")
+	if t.trie == nil {
+		return t.root
+	}
+
+	return t.trie.Hash()
 }
 
 func (t *odrTrie) Commit(batch database.Batch) common.Hash {
@@ -57,6 +97,19 @@ func (t *odrTrie) Commit(batch database.Batch) common.Hash {
 }
 
 func (t *odrTrie) Get(key []byte) ([]byte, bool, error) {
+	if err := t.prove(key); err != nil {
+		return nil, false, err
+	}
+
+	return t.trie.Get(key)
+}
+
+// prove retrieves the trie proof of the key if not retrieved yet.
+func (t *odrTrie) prove(key []byte) error {
+	if t.proved[string(key)] {
+		return nil
+	}
+
 	request := &odrTriePoof{
 		Root: t.root,
 		Key:  key,
@@ -66,7 +119,7 @@ func (t *odrTrie) Get(key []byte) ([]byte, bool, error) {
 	filter := peerFilter{blockHash: t.blockHash}
 	response, err := t.odr.retrieveWithFilter(request, filter)
 	if err != nil {
-		return nil, false, errors.NewStackedError(err, "failed to retrieve ODR trie proof")
+		return errors.NewStackedError(err, "failed to retrieve ODR trie proof")
 	}
 
 	// insert the trie proof in databse.
@@ -75,23 +128,43 @@ func (t *odrTrie) Get(key []byte) ([]byte, bool, error) {
 		t.db.kvs[string(key)] = n.Value
 	}
 
-	// construct the MPT for the first time.
-	if t.trie == nil {
-		t.trie, err = trie.NewTrie(t.root, t.dbPrefix, t.db)
-		if err != nil {
-			return nil, false, errors.NewStackedError(err, "failed to create trie")
-		}
+	if err = t.initTrie(); err != nil {
+		return err
 	}
 
-	return t.trie.Get(key)
+	t.proved[string(key)] = true
+
+	return nil
+}
+
+// initTrie constructs the MPT for the first time.
+func (t *odrTrie) initTrie() error {
+	if t.trie != nil {
+		return nil
+	}
+
+	var err error
+	if t.trie, err = trie.NewTrie(t.root, t.dbPrefix, t.db); err != nil {
+		return errors.NewStackedError(err, "failed to create trie")
+	}
+
+	return nil
 }
 
 func (t *odrTrie) Put(key, value []byte) error {
-	panic("unsupported")
+	if err := t.prove(key); err != nil {
+		return err
+	}
+
+	return t.trie.Put(key, value)
 }
 
 func (t *odrTrie) DeletePrefix(prefix []byte) (bool, error) {
-	panic("unsupported")
+	if err := t.initTrie(); err != nil {
+		return false, err
+	}
+
+	return t.trie.DeletePrefix(prefix)
 }
 
 func (t *odrTrie) GetProof(key []byte) (map[string][]byte, error) {
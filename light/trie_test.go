@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/database"
 	"github.com/seeleteam/go-seele/database/leveldb"
 	"github.com/seeleteam/go-seele/trie"
 	"github.com/stretchr/testify/assert"
@@ -57,3 +58,104 @@ func Test_Trie_Get(t *testing.T) {
 	assert.False(t, ok)
 	assert.Nil(t, v)
 }
+
+type trieOdrRetriever struct {
+	trie     *trie.Trie
+	db       database.Database // committed trie nodes
+	dbPrefix []byte
+	nodes    int // number of the trie nodes retrieved by hash
+}
+
+func (r *trieOdrRetriever) retrieveWithFilter(request odrRequest, filter peerFilter) (odrResponse, error) {
+	if nodeRequest, ok := request.(*odrTrieNodeRequest); ok {
+		node, err := r.db.Get(append(append([]byte{}, r.dbPrefix...), nodeRequest.Hash.Bytes()...))
+		if err != nil {
+			return nil, err
+		}
+
+		r.nodes++
+		response := &odrTrieNodeResponse{Node: node}
+		return response, response.validate(request, nil)
+	}
+
+	proof, err := r.trie.GetProof(request.(*odrTriePoof).Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &odrTriePoof{Proof: mapToArray(proof)}, nil
+}
+
+func Test_Trie_Put(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	// prepare trie on server side
+	dbPrefix := []byte("test prefix")
+	serverTrie := trie.NewEmptyTrie(dbPrefix, db)
+	serverTrie.Put([]byte("hello"), []byte("HELLO"))
+	serverTrie.Put([]byte("seele"), []byte("SEELE"))
+	serverTrie.Put([]byte("world"), []byte("WORLD"))
+
+	lightTrie := newOdrTrie(&trieOdrRetriever{trie: serverTrie}, serverTrie.Hash(), dbPrefix, common.EmptyHash)
+	assert.Equal(t, lightTrie.Hash(), serverTrie.Hash())
+
+	// change the keys locally
+	assert.Nil(t, lightTrie.Put([]byte("seele"), []byte("SEELE 2")))
+	assert.Nil(t, lightTrie.Put([]byte("seele 2"), []byte("SEELE 3")))
+
+	v, ok, err := lightTrie.Get([]byte("seele"))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("SEELE 2"), v)
+
+	// the key not changed is still retrieved from server
+	v, ok, err = lightTrie.Get([]byte("world"))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("WORLD"), v)
+
+	serverTrie.Put([]byte("seele"), []byte("SEELE 2"))
+	serverTrie.Put([]byte("seele 2"), []byte("SEELE 3"))
+	assert.Equal(t, lightTrie.Hash(), serverTrie.Hash())
+}
+
+func Test_Trie_DeletePrefix(t *testing.T) {
+	db, dispose := leveldb.NewTestDatabase()
+	defer dispose()
+
+	// prepare trie on server side, "seele" and "world" share the first nibble
+	dbPrefix := []byte("test prefix")
+	serverTrie := trie.NewEmptyTrie(dbPrefix, db)
+	serverTrie.Put([]byte("hello"), []byte("HELLO"))
+	serverTrie.Put([]byte("seele"), []byte("SEELE"))
+	serverTrie.Put([]byte("world"), []byte("WORLD"))
+
+	batch := db.NewBatch()
+	root := serverTrie.Commit(batch)
+	assert.Nil(t, batch.Commit())
+
+	retriever := &trieOdrRetriever{trie: serverTrie, db: db, dbPrefix: dbPrefix}
+	lightTrie := newOdrTrie(retriever, root, dbPrefix, common.EmptyHash)
+
+	_, ok, err := lightTrie.Get([]byte("seele"))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, retriever.nodes, 0)
+
+	// the branch is collapsed into "world", which is not in the proof of "seele"
+	deleted, err := lightTrie.DeletePrefix([]byte("seele"))
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+	assert.Equal(t, retriever.nodes, 1)
+
+	serverTrie.DeletePrefix([]byte("seele"))
+	assert.Equal(t, lightTrie.Hash(), serverTrie.Hash())
+
+	// the retrieved node is cached
+	v, ok, err := lightTrie.trie.Get([]byte("world"))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("WORLD"), v)
+	assert.Equal(t, retriever.nodes, 1)
+}
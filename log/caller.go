@@ -7,6 +7,7 @@ package log
 
 import (
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strconv"
 	"strings"
@@ -15,9 +16,11 @@ import (
 )
 
 const (
-	depth = 9 // Once log invocation stack has changed, depth needs to change as well.
+	maxCallerDepth = 16 // max stack frames to look up for the caller out of the log package
 )
 
+var logPackage = reflect.TypeOf(SeeleLog{}).PkgPath() + "."
+
 // CallerHook a caller hook of logrus
 type CallerHook struct {
 	module string
@@ -35,12 +38,34 @@ func (hook *CallerHook) Levels() []logrus.Level {
 	return logrus.AllLevels
 }
 
-// caller returns the invoker which is being executed
+// caller returns the invoker which is being executed, it skips the frames
+// of logrus and this package so that loggers, entries and samplers all
+// report the real call site.
 func (hook *CallerHook) caller() string {
-	if _, file, line, ok := runtime.Caller(depth); ok {
-		return strings.Join([]string{filepath.Base(file), strconv.Itoa(line)}, ":")
+	pcs := make([]uintptr, maxCallerDepth)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		frame, more := frames.Next()
+		if !isLogFrame(frame) {
+			return strings.Join([]string{filepath.Base(frame.File), strconv.Itoa(frame.Line)}, ":")
+		}
+
+		if !more {
+			break
+		}
 	}
 
 	// not sure what the convention should be here
 	return ""
 }
+
+// isLogFrame returns true if the frame is within logrus or the log package.
+func isLogFrame(frame runtime.Frame) bool {
+	if strings.Contains(frame.Function, "sirupsen/logrus.") {
+		return true
+	}
+
+	return strings.HasPrefix(frame.Function, logPackage) && !strings.HasSuffix(frame.File, "_test.go")
+}
@@ -8,6 +8,15 @@ package comm
 // LogConfiguration is the Configuration of log
 var LogConfiguration = &LogConfig{PrintLog: true, IsDebug: true, DataDir: "log"}
 
+// Log output formats
+const (
+	// FormatText prints human readable log lines, it is the default format
+	FormatText = "text"
+
+	// FormatJSON prints one json object per log entry, structured fields are kept as json fields
+	FormatJSON = "json"
+)
+
 // LogConfig is the Configuration of log
 type LogConfig struct {
 	// If IsDebug is true, the log level will be DebugLevel, otherwise it is InfoLevel
@@ -18,4 +27,29 @@ type LogConfig struct {
 
 	// DataDir default log directory in temp folder
 	DataDir string `json:"-"`
+
+	// Levels overrides the default log level per module, e.g. {"p2p": "warn", "bft_core": "debug"}
+	Levels map[string]string `json:"levels,omitempty"`
+
+	// Format is the log output format, "text" (default) or "json"
+	Format string `json:"format,omitempty"`
+
+	// Rotation is the rotation and retention policy of the log files
+	Rotation RotationConfig `json:"rotation"`
+}
+
+// RotationConfig is the rotation and retention policy of the log files.
+// Zero values fall back to the defaults, that is a new file every day kept for a week.
+type RotationConfig struct {
+	// MaxSize is the maximum size in megabytes of a log file before it gets rotated, 0 means no size limit
+	MaxSize int64 `json:"maxSize,omitempty"`
+
+	// RotationHours is the interval in hours to start a new log file
+	RotationHours int64 `json:"rotationHours,omitempty"`
+
+	// MaxAgeDays is the maximum number of days to retain old log files
+	MaxAgeDays int64 `json:"maxAgeDays,omitempty"`
+
+	// MaxBackups is the maximum number of old log files to retain, 0 means no limit
+	MaxBackups int `json:"maxBackups,omitempty"`
 }
@@ -0,0 +1,109 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Fields is the structured data attached to a log entry, e.g. height, hash and peer.
+type Fields map[string]interface{}
+
+// Entry is a log entry with structured fields, which are printed as
+// key=value pairs in text format or as json fields in json format.
+type Entry struct {
+	entry *logrus.Entry
+}
+
+// WithFields returns a log entry with the specified structured fields.
+func (p *SeeleLog) WithFields(fields Fields) *Entry {
+	return &Entry{p.log.WithFields(logrus.Fields(fields))}
+}
+
+// WithField returns a log entry with the specified structured field.
+func (p *SeeleLog) WithField(key string, value interface{}) *Entry {
+	return &Entry{p.log.WithField(key, value)}
+}
+
+// WithFields returns a new log entry with the specified fields appended.
+func (e *Entry) WithFields(fields Fields) *Entry {
+	return &Entry{e.entry.WithFields(logrus.Fields(fields))}
+}
+
+// WithField returns a new log entry with the specified field appended.
+func (e *Entry) WithField(key string, value interface{}) *Entry {
+	return &Entry{e.entry.WithField(key, value)}
+}
+
+// Error logs the message at error level with the structured fields.
+func (e *Entry) Error(format string, args ...interface{}) {
+	e.entry.Errorf(format, args...)
+}
+
+// Warn logs the message at warn level with the structured fields.
+func (e *Entry) Warn(format string, args ...interface{}) {
+	e.entry.Warnf(format, args...)
+}
+
+// Info logs the message at info level with the structured fields.
+func (e *Entry) Info(format string, args ...interface{}) {
+	e.entry.Infof(format, args...)
+}
+
+// Debug logs the message at debug level with the structured fields.
+func (e *Entry) Debug(format string, args ...interface{}) {
+	e.entry.Debugf(format, args...)
+}
+
+// Sampler logs only one out of every n calls, it is used on hot paths
+// such as per message or per transaction handling to limit the log volume.
+type Sampler struct {
+	log     *SeeleLog
+	every   uint64
+	counter uint64
+}
+
+var samplers sync.Map // map[string]*Sampler, key is module + sample key
+
+// Sample returns the sampler of the specified key which logs one out of every n calls.
+// Samplers are shared by key, so the same key always returns the same counter.
+func (p *SeeleLog) Sample(key string, n uint64) *Sampler {
+	if n == 0 {
+		n = 1
+	}
+
+	s, _ := samplers.LoadOrStore(p.module+"/"+key, &Sampler{log: p, every: n})
+	return s.(*Sampler)
+}
+
+// hit returns true if the current call should be logged.
+func (s *Sampler) hit() bool {
+	return (atomic.AddUint64(&s.counter, 1)-1)%s.every == 0
+}
+
+// Info logs the message at info level if the current call is sampled.
+func (s *Sampler) Info(format string, args ...interface{}) {
+	if s.log.GetLevel() >= logrus.InfoLevel && s.hit() {
+		s.log.Info(format, args...)
+	}
+}
+
+// Warn logs the message at warn level if the current call is sampled.
+func (s *Sampler) Warn(format string, args ...interface{}) {
+	if s.log.GetLevel() >= logrus.WarnLevel && s.hit() {
+		s.log.Warn(format, args...)
+	}
+}
+
+// Debug logs the message at debug level if the current call is sampled.
+func (s *Sampler) Debug(format string, args ...interface{}) {
+	if s.log.GetLevel() >= logrus.DebugLevel && s.hit() {
+		s.log.Debug(format, args...)
+	}
+}
@@ -7,12 +7,12 @@ package log
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
-	"time"
+	"sync/atomic"
 
-	"github.com/lestrrat-go/file-rotatelogs"
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/log/comm"
 	"github.com/sirupsen/logrus"
@@ -28,12 +28,16 @@ var (
 
 // SeeleLog wraps log class
 type SeeleLog struct {
-	log *logrus.Logger
+	log    *logrus.Logger
+	module string
 }
 
 var logMap map[string]*SeeleLog
 var getLogMutex sync.Mutex
 
+// fileWriter is the log file writer shared by all modules
+var fileWriter *rotateWriter
+
 // Panic Level, highest level of severity. Panic logs and then calls panic with the
 // message passed to Debug, Info, ...
 func (p *SeeleLog) Panic(format string, args ...interface{}) {
@@ -75,7 +79,7 @@ func (p *SeeleLog) SetLevel(level logrus.Level) {
 
 // GetLevel get the log level
 func (p *SeeleLog) GetLevel() logrus.Level {
-	return p.log.Level
+	return logrus.Level(atomic.LoadUint32((*uint32)(&p.log.Level)))
 }
 
 // GetLogger gets logrus.Logger object according to module name
@@ -97,37 +101,88 @@ func GetLogger(module string) *SeeleLog {
 	if comm.LogConfiguration.PrintLog {
 		log.Out = os.Stdout
 	} else {
-		logDir := filepath.Join(LogFolder, comm.LogConfiguration.DataDir)
-		err := os.MkdirAll(logDir, os.ModePerm)
-		if err != nil {
-			panic(fmt.Sprintf("failed to create log dir: %s", err.Error()))
-		}
-		logFileName := fmt.Sprintf("%s%s", "%Y%m%d", logExtension)
-
-		writer, err := rotatelogs.New(
-			filepath.Join(logDir, logFileName),
-			rotatelogs.WithClock(rotatelogs.Local),
-			rotatelogs.WithMaxAge(24*7*time.Hour),
-			rotatelogs.WithRotationTime(24*time.Hour),
-		)
-
-		if err != nil {
-			panic(fmt.Sprintf("failed to create log file: %s", err))
-		}
-
-		log.Out = writer
+		log.Out = getFileWriter()
 	}
 
-	if comm.LogConfiguration.IsDebug {
-		log.SetLevel(logrus.DebugLevel)
-	} else {
-		log.SetLevel(logrus.InfoLevel)
+	if comm.LogConfiguration.Format == comm.FormatJSON {
+		log.Formatter = &logrus.JSONFormatter{}
 	}
 
+	log.SetLevel(defaultLevel(module))
 	log.AddHook(&CallerHook{module: module}) // add caller hook to print caller's file and line number
 	curLog = &SeeleLog{
-		log: log,
+		log:    log,
+		module: module,
 	}
 	logMap[module] = curLog
 	return curLog
 }
+
+// getFileWriter returns the log file writer shared by all modules, it creates the writer at the first time.
+func getFileWriter() io.Writer {
+	if fileWriter != nil {
+		return fileWriter
+	}
+
+	logDir := filepath.Join(LogFolder, comm.LogConfiguration.DataDir)
+	writer, err := newRotateWriter(logDir, comm.LogConfiguration.Rotation)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create log file: %s", err))
+	}
+
+	fileWriter = writer
+	return fileWriter
+}
+
+// defaultLevel returns the configured level of the specified module,
+// the level is determined by IsDebug if not configured.
+func defaultLevel(module string) logrus.Level {
+	if name, ok := comm.LogConfiguration.Levels[module]; ok {
+		if level, err := logrus.ParseLevel(name); err == nil {
+			return level
+		}
+	}
+
+	if comm.LogConfiguration.IsDebug {
+		return logrus.DebugLevel
+	}
+
+	return logrus.InfoLevel
+}
+
+// SetModuleLevel changes the log level of the specified module at runtime.
+// The level applies to the logger created later if the module logger is not created yet.
+func SetModuleLevel(module string, level string) error {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	getLogMutex.Lock()
+	defer getLogMutex.Unlock()
+
+	if curLog, ok := logMap[module]; ok {
+		curLog.SetLevel(lvl)
+		return nil
+	}
+
+	if comm.LogConfiguration.Levels == nil {
+		comm.LogConfiguration.Levels = make(map[string]string)
+	}
+	comm.LogConfiguration.Levels[module] = lvl.String()
+
+	return nil
+}
+
+// GetModuleLevels returns the log level of all created module loggers.
+func GetModuleLevels() map[string]string {
+	getLogMutex.Lock()
+	defer getLogMutex.Unlock()
+
+	levels := make(map[string]string)
+	for module, curLog := range logMap {
+		levels[module] = curLog.GetLevel().String()
+	}
+
+	return levels
+}
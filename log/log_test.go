@@ -6,8 +6,13 @@
 package log
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -76,3 +81,66 @@ func Test_LogLevels(t *testing.T) {
 	log = GetLogger("test5")
 	assert.Equal(t, logrus.InfoLevel, log.GetLevel())
 }
+
+func Test_SetModuleLevel(t *testing.T) {
+	log := GetLogger("test6")
+	assert.Equal(t, SetModuleLevel("test6", "warn"), nil)
+	assert.Equal(t, logrus.WarnLevel, log.GetLevel())
+	assert.Equal(t, GetModuleLevels()["test6"], "warning")
+
+	// level of a module not created yet
+	assert.Equal(t, SetModuleLevel("test7", "error"), nil)
+	assert.Equal(t, logrus.ErrorLevel, GetLogger("test7").GetLevel())
+
+	assert.NotEqual(t, SetModuleLevel("test6", "invalid"), nil)
+}
+
+func Test_LogFields(t *testing.T) {
+	log := GetLogger("test8")
+	buff := new(bytes.Buffer)
+	log.log.Out = buff
+	log.log.Formatter = &logrus.JSONFormatter{}
+
+	log.WithFields(Fields{"height": 10, "peer": "abc"}).Info("new block")
+
+	var entry map[string]interface{}
+	assert.Equal(t, json.Unmarshal(buff.Bytes(), &entry), nil)
+	assert.Equal(t, entry["msg"], "new block")
+	assert.Equal(t, entry["height"], float64(10))
+	assert.Equal(t, entry["peer"], "abc")
+	assert.Equal(t, entry["module"], "test8")
+}
+
+func Test_LogSample(t *testing.T) {
+	log := GetLogger("test9")
+	buff := new(bytes.Buffer)
+	log.log.Out = buff
+
+	for i := 0; i < 10; i++ {
+		log.Sample("hot", 5).Info("sampled msg")
+	}
+
+	assert.Equal(t, strings.Count(buff.String(), "sampled msg"), 2)
+}
+
+func Test_RotateWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rotate")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	w, err := newRotateWriter(dir, comm.RotationConfig{MaxBackups: 2})
+	assert.Equal(t, err, nil)
+	defer w.Close()
+	w.maxSize = 10
+
+	for i := 0; i < 5; i++ {
+		_, err = w.Write([]byte("0123456789"))
+		assert.Equal(t, err, nil)
+		w.removeExpired(time.Now())
+	}
+
+	infos, err := ioutil.ReadDir(dir)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(infos), 3)
+	assert.Equal(t, common.FileOrFolderExists(filepath.Join(dir, w.nameOf(time.Now()))), true)
+}
@@ -0,0 +1,200 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package log
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/log/comm"
+)
+
+const (
+	defaultRotationTime = 24 * time.Hour
+	defaultMaxAge       = 24 * 7 * time.Hour
+	megabyte            = 1024 * 1024
+)
+
+// rotateWriter is a log file writer which starts a new file when the rotation
+// time elapses or the current file exceeds the max size, and removes the old
+// files beyond the retention policy.
+type rotateWriter struct {
+	dir          string
+	rotationTime time.Duration
+	maxAge       time.Duration
+	maxSize      int64
+	maxBackups   int
+
+	mutex    sync.Mutex
+	file     *os.File
+	fileName string
+	size     int64
+}
+
+// newRotateWriter creates a rotate writer which writes log files in the specified folder.
+func newRotateWriter(dir string, config comm.RotationConfig) (*rotateWriter, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	w := &rotateWriter{
+		dir:          dir,
+		rotationTime: time.Duration(config.RotationHours) * time.Hour,
+		maxAge:       time.Duration(config.MaxAgeDays) * 24 * time.Hour,
+		maxSize:      config.MaxSize * megabyte,
+		maxBackups:   config.MaxBackups,
+	}
+
+	if w.rotationTime <= 0 {
+		w.rotationTime = defaultRotationTime
+	}
+
+	if w.maxAge <= 0 {
+		w.maxAge = defaultMaxAge
+	}
+
+	if err := w.open(time.Now()); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Write implements io.Writer, rotating the file before the write if necessary.
+func (w *rotateWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	now := time.Now()
+	if name := w.nameOf(now); name != w.fileName {
+		if err := w.open(now); err != nil {
+			return 0, err
+		}
+	} else if w.maxSize > 0 && w.size+int64(len(p)) > w.maxSize && w.size > 0 {
+		if err := w.rotateBySize(now); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the current log file.
+func (w *rotateWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// nameOf returns the log file name of the rotation period the specified time belongs to.
+func (w *rotateWriter) nameOf(t time.Time) string {
+	year, month, day := t.Date()
+	start := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+
+	if w.rotationTime%defaultRotationTime == 0 {
+		// rotate per days, align to the local midnight
+		days := int64(w.rotationTime / defaultRotationTime)
+		start = start.AddDate(0, 0, -int(int64(start.YearDay()-1)%days))
+		return start.Format("20060102") + logExtension
+	}
+
+	start = start.Add(t.Sub(start) / w.rotationTime * w.rotationTime)
+	return start.Format("2006010215") + logExtension
+}
+
+// open closes the current file if any and opens the file of the specified time in append mode.
+func (w *rotateWriter) open(now time.Time) error {
+	if w.file != nil {
+		w.file.Close()
+		w.file = nil
+	}
+
+	name := w.nameOf(now)
+	file, err := os.OpenFile(filepath.Join(w.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	w.file, w.fileName, w.size = file, name, info.Size()
+	w.removeExpired(now)
+
+	return nil
+}
+
+// rotateBySize renames the full file with a timestamp suffix and opens a new one.
+func (w *rotateWriter) rotateBySize(now time.Time) error {
+	w.file.Close()
+	w.file = nil
+
+	current := filepath.Join(w.dir, w.fileName)
+	base := strings.TrimSuffix(w.fileName, logExtension) + "-" + now.Format("150405")
+	backup := filepath.Join(w.dir, base+logExtension)
+	for i := 1; common.FileOrFolderExists(backup); i++ {
+		backup = filepath.Join(w.dir, fmt.Sprintf("%s.%d%s", base, i, logExtension))
+	}
+
+	if err := os.Rename(current, backup); err != nil {
+		return err
+	}
+
+	return w.open(now)
+}
+
+// removeExpired removes the log files older than max age and the oldest files beyond max backups.
+func (w *rotateWriter) removeExpired(now time.Time) {
+	infos, err := ioutil.ReadDir(w.dir)
+	if err != nil {
+		return
+	}
+
+	var backups []os.FileInfo
+	for _, info := range infos {
+		if info.IsDir() || info.Name() == w.fileName || filepath.Ext(info.Name()) != logExtension {
+			continue
+		}
+
+		if now.Sub(info.ModTime()) > w.maxAge {
+			os.Remove(filepath.Join(w.dir, info.Name()))
+			continue
+		}
+
+		backups = append(backups, info)
+	}
+
+	if w.maxBackups <= 0 || len(backups) <= w.maxBackups {
+		return
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].ModTime().After(backups[j].ModTime())
+	})
+
+	for _, info := range backups[w.maxBackups:] {
+		os.Remove(filepath.Join(w.dir, info.Name()))
+	}
+}
@@ -47,6 +47,7 @@ type SeeleBackend interface {
 // Miner defines base elements of miner
 type Miner struct {
 	mining   int32
+	idle     int32 // waiting for txs or debts with an on demand sealer
 	canStart int32
 	stopped  int32
 	stopper  int32 // manually stop miner
@@ -175,6 +176,7 @@ func (miner *Miner) stopMining() {
 	if !atomic.CompareAndSwapInt32(&miner.mining, 1, 0) {
 		return
 	}
+	atomic.StoreInt32(&miner.idle, 0)
 	// notify all threads to terminate
 	if miner.stopChan != nil {
 		close(miner.stopChan)
@@ -220,8 +222,12 @@ func (miner *Miner) downloaderEventCallback(e event.Event) {
 
 // newTxOrDebtCallback handles the new tx event
 func (miner *Miner) newTxOrDebtCallback(e event.Event) {
-	// if not mining, start mining
-	if atomic.LoadInt32(&miner.stopped) == 0 && atomic.LoadInt32(&miner.canStart) == 1 && atomic.CompareAndSwapInt32(&miner.mining, 0, 1) {
+	if atomic.LoadInt32(&miner.stopped) != 0 || atomic.LoadInt32(&miner.canStart) != 1 {
+		return
+	}
+
+	// if not mining or idle, start mining
+	if atomic.CompareAndSwapInt32(&miner.mining, 0, 1) || atomic.CompareAndSwapInt32(&miner.idle, 1, 0) {
 		if err := miner.prepareNewBlock(miner.recv); err != nil {
 			miner.log.Warn(err.Error())
 			atomic.StoreInt32(&miner.mining, 0)
@@ -399,12 +405,35 @@ func (miner *Miner) prepareNewBlock(recv chan *types.Block) error {
 		return fmt.Errorf("failed to apply transaction %s", err)
 	}
 
+	if miner.skipEmptyTask(miner.current) {
+		miner.log.Debug("no tx or debt to pack, waiting for the new ones")
+		atomic.StoreInt32(&miner.idle, 1)
+
+		// the txs inserted before idle is set do not wake up the miner, so check them again
+		if miner.seele.TxPool().GetPendingTxCount() > 0 && atomic.CompareAndSwapInt32(&miner.idle, 1, 0) {
+			return miner.prepareNewBlock(recv)
+		}
+
+		return nil
+	}
+
 	miner.log.Info("committing a new task to engine, height:%d, difficult:%d", header.Height, header.Difficulty)
 	miner.commitTask(miner.current, recv)
 
 	return nil
 }
 
+// skipEmptyTask returns true if the task only contains the reward tx
+// and the engine does not seal the empty blocks.
+func (miner *Miner) skipEmptyTask(task *Task) bool {
+	sealer, ok := miner.engine.(consensus.OnDemandSealer)
+	if !ok || sealer.SealEmptyBlock() {
+		return false
+	}
+
+	return len(task.txs) <= 1 && len(task.debts) == 0
+}
+
 // saveBlock saves the block in the given result to the blockchain
 func (miner *Miner) saveBlock(result *types.Block) error {
 	now := time.Now()
@@ -66,7 +66,7 @@ func (task *Task) applyTransactionsAndDebts(seele SeeleBackend, statedb *state.S
 	}
 
 	// the reward tx will always be at the first of the block's transactions
-	reward, err := task.handleMinerRewardTx(statedb)
+	reward, err := task.handleMinerRewardTx(seele.BlockChain(), statedb)
 	if err != nil {
 		return err
 	}
@@ -120,6 +120,13 @@ func (task *Task) applyTransactionsAndDebts(seele SeeleBackend, statedb *state.S
 		blockInfoHash := crypto.MustHash(blockInfoBytes)
 		blockSig := *crypto.MustSign(engine.GetPrivateKey(), blockInfoHash.Bytes())
 
+		// the candidates with the most stake become the verifiers at the epoch boundary
+		if txs.IsElectionHeight(engine, task.header.Height) {
+			if err = task.electVerifiers(statedb); err != nil {
+				return err
+			}
+		}
+
 		// log.Error("fee account: %v", common.SubchainFeeAccount)
 		// log.Error("blockSig: %v", blockSig.Sig)
 		// update secondWitness
@@ -138,6 +145,18 @@ func (task *Task) applyTransactionsAndDebts(seele SeeleBackend, statedb *state.S
 	return nil
 }
 
+// electVerifiers replaces the verifier changes of the block with the changes to the elected candidates,
+// so that the verifier txs in the block do not change the verifiers if any candidate is elected.
+func (task *Task) electVerifiers(statedb *state.Statedb) error {
+	added, removed, elected, err := txs.ElectVerifierChanges(statedb, task.header)
+	if err != nil || !elected {
+		return err
+	}
+
+	task.depositVers, task.exitVers = added, removed
+	return nil
+}
+
 func (task *Task) getStemHashes(seele SeeleBackend, statedb *state.Statedb, log *log.SeeleLog) (common.Hash, common.Hash, error) {
 	// update hashes for stem
 	var level []common.Hash
@@ -331,7 +350,7 @@ func (task *Task) chooseDebts(seele SeeleBackend, statedb *state.Statedb, log *l
 }
 
 // handleMinerRewardTx handles the miner reward transaction.
-func (task *Task) handleMinerRewardTx(statedb *state.Statedb) (*big.Int, error) {
+func (task *Task) handleMinerRewardTx(chain consensus.ChainReader, statedb *state.Statedb) (*big.Int, error) {
 	reward := consensus.GetReward(task.header.Height)
 	if task.header.Consensus == types.BftConsensus {
 		reward = big.NewInt(int64(0))
@@ -346,6 +365,14 @@ func (task *Task) handleMinerRewardTx(statedb *state.Statedb) (*big.Int, error)
 		return nil, err
 	}
 
+	if err = txs.ApplyMasternodeRewards(statedb, task.header, rewardTxReceipt); err != nil {
+		return nil, err
+	}
+
+	if err = txs.ApplyVerifierFees(chain, statedb, task.header, rewardTxReceipt); err != nil {
+		return nil, err
+	}
+
 	task.txs = append(task.txs, rewardTx)
 
 	// add the receipt of the reward tx
@@ -56,7 +56,7 @@ func Test_handleMinerRewardTx(t *testing.T) {
 
 	task := getTask(10)
 	task.header = newTestBlockHeader()
-	reward, err := task.handleMinerRewardTx(statedb)
+	reward, err := task.handleMinerRewardTx(nil, statedb)
 
 	assert.Equal(t, err, nil)
 	assert.Equal(t, reward, consensus.GetReward(task.header.Height))
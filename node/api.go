@@ -0,0 +1,118 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package node
+
+import (
+	"errors"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/log"
+	"github.com/seeleteam/go-seele/p2p"
+	rpc "github.com/seeleteam/go-seele/rpc"
+)
+
+var errP2PNotStarted = errors.New("p2p server is not started")
+
+// PrivateAdminAPI provides an API to manage the node at runtime.
+type PrivateAdminAPI struct {
+	n *Node
+}
+
+// NewPrivateAdminAPI creates a new PrivateAdminAPI object for rpc service.
+func NewPrivateAdminAPI(n *Node) *PrivateAdminAPI {
+	return &PrivateAdminAPI{n}
+}
+
+// SetLogLevel changes the log level of the specified module, level could be debug, info, warn, error, fatal or panic
+func (api *PrivateAdminAPI) SetLogLevel(module string, level string) (bool, error) {
+	if err := log.SetModuleLevel(module, level); err != nil {
+		return false, err
+	}
+
+	api.n.log.Info("log level of module %s is changed to %s", module, level)
+	return true, nil
+}
+
+// GetLogLevels returns the log level of each module
+func (api *PrivateAdminAPI) GetLogLevels() map[string]string {
+	return log.GetModuleLevels()
+}
+
+// GetPeerPermission returns the allowlist and denylist of the peers
+func (api *PrivateAdminAPI) GetPeerPermission() (*p2p.PermissionList, error) {
+	if api.n.server == nil {
+		return nil, errP2PNotStarted
+	}
+
+	list := api.n.server.Permission().List()
+	return &list, nil
+}
+
+// AddAllowedPeer adds the node ID into the allowlist of the peers
+func (api *PrivateAdminAPI) AddAllowedPeer(id string) (bool, error) {
+	return api.setPeerPermission(id, func(p *p2p.NodePermission, addr common.Address) error {
+		return p.SetAllowed(addr, true)
+	})
+}
+
+// RemoveAllowedPeer removes the node ID from the allowlist of the peers
+func (api *PrivateAdminAPI) RemoveAllowedPeer(id string) (bool, error) {
+	return api.setPeerPermission(id, func(p *p2p.NodePermission, addr common.Address) error {
+		return p.SetAllowed(addr, false)
+	})
+}
+
+// AddDeniedPeer adds the node ID into the denylist of the peers, the peer is disconnected if connected
+func (api *PrivateAdminAPI) AddDeniedPeer(id string) (bool, error) {
+	return api.setPeerPermission(id, func(p *p2p.NodePermission, addr common.Address) error {
+		return p.SetDenied(addr, true)
+	})
+}
+
+// RemoveDeniedPeer removes the node ID from the denylist of the peers
+func (api *PrivateAdminAPI) RemoveDeniedPeer(id string) (bool, error) {
+	return api.setPeerPermission(id, func(p *p2p.NodePermission, addr common.Address) error {
+		return p.SetDenied(addr, false)
+	})
+}
+
+// ReloadPeerPermission reloads the permission file of the peers
+func (api *PrivateAdminAPI) ReloadPeerPermission() (bool, error) {
+	if api.n.server == nil {
+		return false, errP2PNotStarted
+	}
+
+	return api.n.server.Permission().Reload(true)
+}
+
+func (api *PrivateAdminAPI) setPeerPermission(id string, set func(*p2p.NodePermission, common.Address) error) (bool, error) {
+	if api.n.server == nil {
+		return false, errP2PNotStarted
+	}
+
+	addr, err := common.HexToAddress(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err = set(api.n.server.Permission(), addr); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// apis returns the collection of RPC services the node offers.
+func (n *Node) apis() []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateAdminAPI(n),
+			Public:    false,
+		},
+	}
+}
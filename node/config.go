@@ -7,12 +7,14 @@ package node
 
 import (
 	"crypto/ecdsa"
+	"math/big"
 
 	"github.com/seeleteam/go-seele/common"
 	"github.com/seeleteam/go-seele/core"
 	"github.com/seeleteam/go-seele/log/comm"
 	"github.com/seeleteam/go-seele/metrics"
 	"github.com/seeleteam/go-seele/p2p"
+	"github.com/seeleteam/go-seele/rpc"
 )
 
 // Config is the Configuration of node
@@ -40,11 +42,31 @@ type Config struct {
 
 	// metrics config info
 	MetricsConfig *metrics.Config
+
+	// LightCheckpoint is the trusted checkpoint for the light node of a BFT subchain to sync from,
+	// the headers are verified by the committed seals and the headers in between are skipped.
+	LightCheckpoint *BFTCheckpoint
+}
+
+// BFTCheckpoint is a trusted block of a BFT subchain
+type BFTCheckpoint struct {
+	Height uint64      `json:"height"`
+	Hash   common.Hash `json:"hash"`
+
+	// TD is the total difficulty of the checkpoint block
+	TD *big.Int `json:"td"`
+
+	// Verifiers are the verifiers that sign the blocks after the checkpoint
+	Verifiers []common.Address `json:"verifiers"`
 }
 
 // IpcConfig config for ipc rpc service
 type IpcConfig struct {
 	PipeName string `json:"name"`
+
+	// Access is the namespace allowlist of the ipc rpc service,
+	// all the namespaces are served if the allowlist is empty.
+	Access rpc.AccessConfig `json:"access"`
 }
 
 // BasicConfig config for Node
@@ -67,6 +89,11 @@ type BasicConfig struct {
 	// RPCAddr is the address on which to start RPC server.
 	RPCAddr string `json:"address"`
 
+	// RPCAccess is the namespace allowlist and rate limit of the RPC server. The private
+	// namespaces are only served to the local connections, since the tcp connections
+	// could not carry bearer tokens.
+	RPCAccess rpc.AccessConfig `json:"rpcAccess"`
+
 	// coinbase used by the miner
 	Coinbase string `json:"coinbase"`
 
@@ -90,6 +117,12 @@ type HTTPServer struct {
 
 	// HTTPHostFilter is the whitelist of hostnames which are allowed on incoming requests.
 	HTTPWhiteHost []string `json:"whiteHost"`
+
+	// Access is the namespace allowlist, authentication and rate limit of the http rpc service
+	Access rpc.AccessConfig `json:"access"`
+
+	// TLS is the certificate configuration to serve https, plaintext http is served if not specified
+	TLS rpc.TLSConfig `json:"tls"`
 }
 
 // WSServerConfig config for websocket server
@@ -98,6 +131,12 @@ type WSServerConfig struct {
 	Address string `json:"address"`
 
 	CrossOrigins []string `json:"crossorigins"`
+
+	// Access is the namespace allowlist, authentication and rate limit of the websocket rpc service
+	Access rpc.AccessConfig `json:"access"`
+
+	// TLS is the certificate configuration to serve wss, plaintext ws is served if not specified
+	TLS rpc.TLSConfig `json:"tls"`
 }
 
 // Config is the seele's configuration to create seele service
@@ -118,5 +157,11 @@ func (conf *Config) Clone() *Config {
 		cloned.MetricsConfig = &temp
 	}
 
+	if conf.LightCheckpoint != nil {
+		temp := *conf.LightCheckpoint
+		temp.Verifiers = append([]common.Address(nil), conf.LightCheckpoint.Verifiers...)
+		cloned.LightCheckpoint = &temp
+	}
+
 	return &cloned
 }
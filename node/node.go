@@ -50,7 +50,8 @@ type Node struct {
 	lock sync.RWMutex
 
 	tcpListener net.Listener // TCP RPC listener socket to serve API requests
-	tcpHandler  *rpc.Server  // TCP RPC request handler to process the API requests
+	tcpHandler  *rpc.Server  // TCP RPC request handler to process the API requests of remote connections
+	tcpLocal    *rpc.Server  // TCP RPC request handler to process the API requests of local connections
 
 	ipcListener net.Listener // IPC RPC listener socket to serve API requests
 	ipcHandler  *rpc.Server  // IPC RPC request handler to process the API requests
@@ -6,6 +6,7 @@
 package node
 
 import (
+	"crypto/tls"
 	"net"
 	"strings"
 
@@ -19,7 +20,7 @@ import (
 // assumptions about the state of the node.
 func (n *Node) startRPC(services []Service) error {
 	// Gather all the possible APIs to surface
-	apis := []rpc.API{}
+	apis := n.apis()
 	for _, service := range services {
 		apis = append(apis, service.APIs()...)
 	}
@@ -56,21 +57,27 @@ func (n *Node) startTCP(apis []rpc.API) error {
 		return nil
 	}
 
-	// Register all the APIs exposed by the services
-	handler := rpc.NewServer()
-	for _, api := range apis {
-		if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
-			return err
-		}
-		n.log.Debug("registered RPC service namespace %s", api.Namespace)
+	// Register the APIs exposed by the services, the tcp connections could not carry
+	// bearer tokens, so the private APIs are only served to the local connections.
+	access := n.config.BasicConfig.RPCAccess
+	if access.AuthEnabled() {
+		n.log.Warn("TCP endpoint does not support authentication, the private namespaces are only served to local connections")
+		access.Tokens, access.JWTSecret = nil, ""
+	}
+
+	handler, err := n.newAccessServer(apis, access, "TCP")
+	if err != nil {
+		return err
+	}
+
+	local, err := n.newLocalServer(apis, access, "TCP")
+	if err != nil {
+		return err
 	}
 
 	// All APIs registered, start the TCP listener
-	var (
-		listener net.Listener
-		err      error
-	)
-	if listener, err = net.Listen("tcp", endpoint); err != nil {
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
 		return err
 	}
 
@@ -95,17 +102,18 @@ func (n *Node) startTCP(apis []rpc.API) error {
 				continue
 			}
 			n.log.Debug("RPC call from %v", conn)
-			connStr := conn.RemoteAddr().String()
-			if !strings.HasPrefix(connStr, "127.0.0.1") && !strings.HasPrefix(connStr, "localhost") {
-				handler.ChangeMinerRequestStatus()
+			if isLoopback(conn.RemoteAddr()) {
+				go local.ServeConn(conn)
+			} else {
+				go handler.ServeConn(conn)
 			}
-			go handler.ServeCodec(rpc.NewJSONCodec(conn), rpc.OptionMethodInvocation|rpc.OptionSubscriptions)
 		}
 	}()
 
 	// All listeners booted successfully
 	n.tcpListener = listener
 	n.tcpHandler = handler
+	n.tcpLocal = local
 
 	return nil
 }
@@ -118,7 +126,7 @@ func (n *Node) startIPC(apis []rpc.API) error {
 		return nil
 	}
 
-	listener, handler, err := n.startIPCEndpoint(ipcEndpoint, apis)
+	listener, handler, err := n.startIPCEndpoint(ipcEndpoint, apis, n.config.IpcConfig.Access)
 	if err != nil {
 		return err
 	}
@@ -131,14 +139,11 @@ func (n *Node) startIPC(apis []rpc.API) error {
 }
 
 // StartIPCEndpoint starts an IPC endpoint.
-func (n *Node) startIPCEndpoint(ipcEndpoint string, apis []rpc.API) (net.Listener, *rpc.Server, error) {
-	// Register all the APIs exposed by the services.
-	handler := rpc.NewServer()
-	for _, api := range apis {
-		if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
-			return nil, nil, err
-		}
-		n.log.Debug("IPC registered namespace: %s", api.Namespace)
+func (n *Node) startIPCEndpoint(ipcEndpoint string, apis []rpc.API, access rpc.AccessConfig) (net.Listener, *rpc.Server, error) {
+	// Register the APIs exposed by the services, the IPC clients are local.
+	handler, err := n.newLocalServer(apis, access, "IPC")
+	if err != nil {
+		return nil, nil, err
 	}
 	// All APIs registered, start the IPC listener.
 	listener, err := rpc.CreateIPCListener(ipcEndpoint)
@@ -170,6 +175,10 @@ func (n *Node) stopTCP() {
 		n.tcpHandler.Stop()
 		n.tcpHandler = nil
 	}
+	if n.tcpLocal != nil {
+		n.tcpLocal.Stop()
+		n.tcpLocal = nil
+	}
 }
 
 func (n *Node) stopIPC() {
@@ -197,27 +206,19 @@ func (n *Node) startHTTP(apis []rpc.API) error {
 	}
 
 	// Register all the APIs exposed by the services
-	handler := rpc.NewServer()
-	for _, api := range apis {
-		if api.Public {
-			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
-				return err
-			}
-			n.log.Debug("HTTP registered service namespace %s", api.Namespace)
-		}
+	handler, err := n.newAccessServer(apis, n.config.HTTPServer.Access, "HTTP")
+	if err != nil {
+		return err
 	}
 
 	// All APIs registered, start the HTTP listener
-	var (
-		listener net.Listener
-		err      error
-	)
-	if listener, err = net.Listen("tcp", endpoint); err != nil {
+	listener, scheme, err := listen(endpoint, n.config.HTTPServer.TLS, "http")
+	if err != nil {
 		return err
 	}
 
 	go rpc.NewHTTPServer(cors, vhosts, handler).Serve(listener)
-	n.log.Info("HTTP endpoint opened. url http://%s, cors %s, whitehost %s", endpoint, strings.Join(cors, ","), strings.Join(vhosts, ","))
+	n.log.Info("HTTP endpoint opened. url %s://%s, cors %s, whitehost %s", scheme, endpoint, strings.Join(cors, ","), strings.Join(vhosts, ","))
 
 	// All listeners booted successfully
 	n.httpEndpoint = endpoint
@@ -252,27 +253,19 @@ func (n *Node) startWS(apis []rpc.API) error {
 	}
 
 	// Register all the APIs exposed by the services
-	handler := rpc.NewServer()
-	for _, api := range apis {
-		if api.Public {
-			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
-				return err
-			}
-			n.log.Debug("WebSocket registered. service namespace %s", api.Namespace)
-		}
+	handler, err := n.newAccessServer(apis, n.config.WSServerConfig.Access, "WebSocket")
+	if err != nil {
+		return err
 	}
 
 	// All APIs registered, start the HTTP listener
-	var (
-		listener net.Listener
-		err      error
-	)
-	if listener, err = net.Listen("tcp", endpoint); err != nil {
+	listener, scheme, err := listen(endpoint, n.config.WSServerConfig.TLS, "ws")
+	if err != nil {
 		return err
 	}
 
 	go rpc.NewWSServer(wsOrigins, handler).Serve(listener)
-	n.log.Info("WebSocket endpoint opened. url ws://%s", listener.Addr())
+	n.log.Info("WebSocket endpoint opened. url %s://%s", scheme, listener.Addr())
 
 	// All listeners booted successfully
 	n.wsEndpoint = endpoint
@@ -295,3 +288,94 @@ func (n *Node) stopWS() {
 		n.wsHandler = nil
 	}
 }
+
+// newAccessServer creates a rpc server with the APIs allowed by the access config of a transport.
+func (n *Node) newAccessServer(apis []rpc.API, access rpc.AccessConfig, transport string) (*rpc.Server, error) {
+	handler := rpc.NewServer()
+	for _, api := range n.accessAPIs(apis, access, transport) {
+		if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+			return nil, err
+		}
+		n.log.Debug("%s registered service namespace %s", transport, api.Namespace)
+	}
+
+	handler.SetAccessConfig(access)
+	if access.AuthEnabled() {
+		n.log.Info("%s endpoint requires bearer token authentication", transport)
+	}
+
+	return handler, nil
+}
+
+// newLocalServer creates a rpc server for the local clients of a transport, e.g. IPC, which are
+// trusted by the operating system. All the APIs are served unless restricted by the allowlist.
+func (n *Node) newLocalServer(apis []rpc.API, access rpc.AccessConfig, transport string) (*rpc.Server, error) {
+	handler := rpc.NewServer()
+	for _, api := range apis {
+		if len(access.Modules) > 0 && !access.ModuleAllowed(api.Namespace) {
+			continue
+		}
+
+		if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
+			return nil, err
+		}
+		n.log.Debug("%s registered local service namespace %s", transport, api.Namespace)
+	}
+
+	handler.SetAccessConfig(access)
+	return handler, nil
+}
+
+// accessAPIs returns the APIs allowed by the access config of a transport.
+// Only the public APIs are allowed if the config has no allowlist, otherwise the APIs in
+// the allowlist are allowed. The private APIs, e.g. debug APIs, are refused unless the
+// endpoint requires authentication.
+func (n *Node) accessAPIs(apis []rpc.API, access rpc.AccessConfig, transport string) []rpc.API {
+	var result []rpc.API
+	for _, api := range apis {
+		if len(access.Modules) == 0 && !api.Public {
+			continue
+		}
+
+		if len(access.Modules) > 0 && !access.ModuleAllowed(api.Namespace) {
+			continue
+		}
+
+		if !api.Public && !access.AuthEnabled() {
+			n.log.Warn("%s endpoint refused to serve the private namespace %s without authentication", transport, api.Namespace)
+			continue
+		}
+
+		result = append(result, api)
+	}
+
+	return result
+}
+
+// isLoopback returns true if the address is a loopback address, e.g. 127.0.0.1.
+func isLoopback(addr net.Addr) bool {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return false
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// listen starts a tcp listener at the endpoint, which serves TLS if the TLS config is enabled.
+// It returns the listener and the url scheme, e.g. http or https.
+func listen(endpoint string, config rpc.TLSConfig, scheme string) (net.Listener, string, error) {
+	if !config.Enabled() {
+		listener, err := net.Listen("tcp", endpoint)
+		return listener, scheme, err
+	}
+
+	tlsConfig, err := config.ServerConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	listener, err := tls.Listen("tcp", endpoint, tlsConfig)
+	return listener, scheme + "s", err
+}
@@ -6,7 +6,9 @@
 package node
 
 import (
+	"context"
 	"errors"
+	"net"
 	"testing"
 
 	"github.com/seeleteam/go-seele/log/comm"
@@ -179,6 +181,72 @@ func Test_startWS(t *testing.T) {
 	stack.stopWS()
 }
 
+func Test_accessAPIs(t *testing.T) {
+	stack := newNode(validHTTPConfig(), t)
+	apis := []rpc.API{
+		{Namespace: "seele", Service: testServiceA, Public: true},
+		{Namespace: "debug", Service: testServiceA, Public: false},
+	}
+
+	namespaces := func(apis []rpc.API) []string {
+		var result []string
+		for _, api := range apis {
+			result = append(result, api.Namespace)
+		}
+		return result
+	}
+
+	// only the public APIs without allowlist
+	assert.Equal(t, namespaces(stack.accessAPIs(apis, rpc.AccessConfig{}, "HTTP")), []string{"seele"})
+
+	// private APIs are refused without authentication
+	access := rpc.AccessConfig{Modules: []string{rpc.AllModules}}
+	assert.Equal(t, namespaces(stack.accessAPIs(apis, access, "HTTP")), []string{"seele"})
+
+	// private APIs in the allowlist are served with authentication
+	access.Tokens = []string{"secret"}
+	assert.Equal(t, namespaces(stack.accessAPIs(apis, access, "HTTP")), []string{"seele", "debug"})
+
+	access.Modules = []string{"debug"}
+	assert.Equal(t, namespaces(stack.accessAPIs(apis, access, "HTTP")), []string{"debug"})
+}
+
+func Test_startTCP_Access(t *testing.T) {
+	stack := newNode(validTCPConfig(), t)
+	apis := []rpc.API{
+		{Namespace: "seele", Service: testServiceA, Public: true},
+		{Namespace: "debug", Service: testServiceA, Public: false},
+	}
+
+	assert.Nil(t, stack.startTCP(apis))
+	defer stack.stopRPC()
+
+	client, err := rpc.DialTCP(context.Background(), stack.config.BasicConfig.RPCAddr)
+	assert.Nil(t, err)
+	defer client.Close()
+
+	// the private namespaces are served to the local connections
+	modules, err := client.SupportedModules()
+	assert.Nil(t, err)
+	assert.Equal(t, modules["seele"], "1.0")
+	assert.Equal(t, modules["debug"], "1.0")
+
+	// the remote connections are only served the public namespaces
+	remote := rpc.DialInProc(stack.tcpHandler)
+	defer remote.Close()
+
+	modules, err = remote.SupportedModules()
+	assert.Nil(t, err)
+	assert.Equal(t, modules["seele"], "1.0")
+	assert.Equal(t, modules["debug"], "")
+}
+
+func Test_isLoopback(t *testing.T) {
+	assert.True(t, isLoopback(&net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 8027}))
+	assert.True(t, isLoopback(&net.TCPAddr{IP: net.ParseIP("::1"), Port: 8027}))
+	assert.False(t, isLoopback(&net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 8027}))
+}
+
 func newNode(config *Config, t *testing.T) *Node {
 	stack, err := New(config)
 	if err != nil {
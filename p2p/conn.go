@@ -53,6 +53,9 @@ type connection struct {
 	// writeErr if error appeared, tcp connection needs to be closed
 	writeErr error
 
+	// cipher encrypts the frames after handshake, nil if the peer does not support encryption
+	cipher *frameCipher
+
 	// log
 	log *log.SeeleLog
 }
@@ -162,13 +165,22 @@ func (c *connection) ReadMsg() (msgRecv *Message, err error) {
 		return &Message{}, errMagic
 	}
 
-	if size > maxSize {
-		c.log.Debug("Failed to get data, payload size %d exceeds the limit %d, sender is %s", size, maxSize, c.fd.RemoteAddr().String())
+	limit := maxSize
+	if c.cipher != nil {
+		limit += uint32(headBuffCodeEnd - headBuffCodeStart + c.cipher.overhead())
+	}
+
+	if size > limit {
+		c.log.Debug("Failed to get data, payload size %d exceeds the limit %d, sender is %s", size, limit, c.fd.RemoteAddr().String())
 
 		return &Message{}, errSize
 	}
 
-	if size > 0 {
+	if c.cipher != nil {
+		if err = c.readSecurePayload(headbuff, size, msgRecv); err != nil {
+			return &Message{}, err
+		}
+	} else if size > 0 {
 		msgRecv.Payload = make([]byte, size)
 		if err = c.readFull(msgRecv.Payload); err != nil {
 
@@ -198,6 +210,10 @@ func (c *connection) WriteMsg(msg *Message) error {
 			}
 	*/
 
+	if c.cipher != nil {
+		return c.writeSecureMsg(msg)
+	}
+
 	b := make([]byte, headBuffLength)
 	binary.BigEndian.PutUint32(b[headBuffSizeStart:headBuffSizeEnd], uint32(len(msg.Payload)))
 	binary.BigEndian.PutUint16(b[headBuffCodeStart:headBuffCodeEnd], msg.Code)
@@ -218,3 +234,57 @@ func (c *connection) WriteMsg(msg *Message) error {
 
 	return nil
 }
+
+// readSecurePayload reads and decrypts the encrypted frame body, which is the message code
+// followed by the payload. The code in the frame header is always zero.
+func (c *connection) readSecurePayload(headbuff []byte, size uint32, msgRecv *Message) error {
+	cipherText := make([]byte, size)
+	if err := c.readFull(cipherText); err != nil {
+		return err
+	}
+
+	plain, err := c.cipher.open(headbuff, cipherText)
+	if err != nil {
+		c.log.Debug("Failed to decrypt frame, sender is %s", c.fd.RemoteAddr().String())
+		return err
+	}
+
+	codeLen := headBuffCodeEnd - headBuffCodeStart
+	if len(plain) < codeLen {
+		return errDecryptFrame
+	}
+
+	msgRecv.Code = binary.BigEndian.Uint16(plain[:codeLen])
+	if len(plain) > codeLen {
+		msgRecv.Payload = plain[codeLen:]
+	}
+
+	return nil
+}
+
+// writeSecureMsg encrypts the message code and payload into a single frame,
+// the frame header is authenticated along with them.
+func (c *connection) writeSecureMsg(msg *Message) error {
+	if uint32(len(msg.Payload)) > maxSize {
+		return errSize
+	}
+
+	codeLen := headBuffCodeEnd - headBuffCodeStart
+	plain := make([]byte, codeLen+len(msg.Payload))
+	binary.BigEndian.PutUint16(plain, msg.Code)
+	copy(plain[codeLen:], msg.Payload)
+
+	b := make([]byte, headBuffLength, headBuffLength+len(plain)+c.cipher.overhead())
+	binary.BigEndian.PutUint32(b[headBuffSizeStart:headBuffSizeEnd], uint32(len(plain)+c.cipher.overhead()))
+	binary.BigEndian.PutUint16(b[headBuffMagicStart:headBuffMagicEnd], magicNumber)
+
+	frame := append(b, c.cipher.seal(b, plain)...)
+	if err := c.writeFull(frame); err != nil {
+		return err
+	}
+
+	metricsSendMessageCountMeter.Mark(1)
+	metricsSendPortSpeedMeter.Mark(int64(len(frame)))
+
+	return nil
+}
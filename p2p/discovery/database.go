@@ -21,6 +21,9 @@ import (
 // NodeHook some hook funcs
 type NodeHook func(node *Node)
 
+// NodeFilter returns false if the node is not permitted to be added
+type NodeFilter func(id common.Address) bool
+
 // Database definition
 type Database struct {
 	m              map[common.Hash]*Node
@@ -28,6 +31,7 @@ type Database struct {
 	mutex          sync.RWMutex
 	addNodeHook    NodeHook
 	deleteNodeHook NodeHook
+	filter         NodeFilter
 }
 
 const (
@@ -186,6 +190,16 @@ func (db *Database) SetHookForNewNode(hook NodeHook) {
 	db.addNodeHook = hook
 }
 
+// SetNodeFilter set the filter of the nodes to add, all nodes are permitted if nil
+func (db *Database) SetNodeFilter(filter NodeFilter) {
+	db.filter = filter
+}
+
+// allowed returns true if the node is permitted by the filter
+func (db *Database) allowed(id common.Address) bool {
+	return db.filter == nil || db.filter(id)
+}
+
 // SetHookForDeleteNode this hook will be called when we lost a Node's connection
 // Note it will run in a new go routine
 func (db *Database) SetHookForDeleteNode(hook NodeHook) {
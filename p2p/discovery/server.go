@@ -13,7 +13,13 @@ import (
 
 // StartService start node udp service
 func StartService(nodeDir string, myID common.Address, myAddr *net.UDPAddr, bootstrap []*Node, shard uint) *Database {
+	return StartServiceWithFilter(nodeDir, myID, myAddr, bootstrap, shard, nil)
+}
+
+// StartServiceWithFilter start node udp service, only the nodes permitted by the filter are added
+func StartServiceWithFilter(nodeDir string, myID common.Address, myAddr *net.UDPAddr, bootstrap []*Node, shard uint, filter NodeFilter) *Database {
 	udp := newUDP(myID, myAddr, shard)
+	udp.db.SetNodeFilter(filter)
 
 	if bootstrap != nil {
 		udp.trustNodes = bootstrap
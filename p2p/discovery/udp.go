@@ -482,6 +482,11 @@ func (u *udp) addNode(n *Node, notifyConnect bool) {
 		return
 	}
 
+	if !u.db.allowed(n.ID) {
+		u.log.Debug("node %s is not permitted, ignore it", n)
+		return
+	}
+
 	count := u.db.size()
 
 	status := u.table.addNode(n)
@@ -97,8 +97,9 @@ func (msg *Message) UnZip() error {
 	return nil
 }
 
-// ProtoHandShake handshake message for two peer to exchange base information
-// TODO add public key or other information for encryption?
+// ProtoHandShake handshake message for two peer to exchange base information.
+// The encrypted transport is negotiated by the secure cap in Caps, and the session
+// keys are derived from the node keys recovered from the handshake signatures.
 type ProtoHandShake struct {
 	Caps      []Cap
 	NodeID    common.Address
@@ -0,0 +1,263 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package p2p
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/log"
+)
+
+// permissionReloadInterval is the interval to check whether the permission file is modified
+const permissionReloadInterval = 10 * time.Second
+
+// PermissionConfig is the configuration of the permissioned peers, e.g. the verifiers of a subchain.
+type PermissionConfig struct {
+	// File is the path of the json file which contains the allowlist and denylist of node IDs and
+	// the verifiers-only mode, e.g. {"allow": ["0x..."], "deny": ["0x..."], "verifiersOnly": true}.
+	// It is reloaded when modified.
+	File string `json:"file,omitempty"`
+
+	// Allow is the allowlist of node IDs, all nodes are allowed if both Allow and the allowlist in File are empty
+	Allow []common.Address `json:"allow,omitempty"`
+
+	// Deny is the denylist of node IDs, it takes precedence over the allowlist
+	Deny []common.Address `json:"deny,omitempty"`
+
+	// VerifiersOnly restricts the BFT messages to the verifiers of the current snapshot
+	VerifiersOnly bool `json:"verifiersOnly,omitempty"`
+}
+
+// PermissionList is the allowlist and denylist of node IDs and the verifiers-only mode
+type PermissionList struct {
+	Allow         []common.Address `json:"allow"`
+	Deny          []common.Address `json:"deny"`
+	VerifiersOnly bool             `json:"verifiersOnly"`
+}
+
+// NodePermission checks whether a node is permitted to connect. The lists in the config are
+// permanent, while the lists in the file could be reloaded or modified at runtime.
+type NodePermission struct {
+	config PermissionConfig
+
+	lock          sync.RWMutex
+	allow         map[common.Address]bool // allowlist in file
+	deny          map[common.Address]bool // denylist in file
+	verifiersOnly bool                    // verifiers-only mode in file
+	modTime       time.Time
+	listeners     []func()
+
+	log *log.SeeleLog
+}
+
+// NewNodePermission creates the node permission with the config, the file is loaded by Reload.
+func NewNodePermission(config PermissionConfig, log *log.SeeleLog) *NodePermission {
+	return &NodePermission{
+		config: config,
+		allow:  make(map[common.Address]bool),
+		deny:   make(map[common.Address]bool),
+		log:    log,
+	}
+}
+
+// Allowed returns true if the node is permitted to connect.
+func (p *NodePermission) Allowed(id common.Address) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if p.deny[id] || containsAddress(p.config.Deny, id) {
+		return false
+	}
+
+	if len(p.allow) == 0 && len(p.config.Allow) == 0 {
+		return true
+	}
+
+	return p.allow[id] || containsAddress(p.config.Allow, id)
+}
+
+// VerifiersOnly returns true if the BFT messages should only be routed to the verifiers,
+// which is enabled by either the config or the file.
+func (p *NodePermission) VerifiersOnly() bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.config.VerifiersOnly || p.verifiersOnly
+}
+
+// List returns the allowlist and denylist, including the lists in the config.
+func (p *NodePermission) List() PermissionList {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	allow := toAddressSet(p.config.Allow)
+	for id := range p.allow {
+		allow[id] = true
+	}
+
+	deny := toAddressSet(p.config.Deny)
+	for id := range p.deny {
+		deny[id] = true
+	}
+
+	return PermissionList{Allow: sortedAddresses(allow), Deny: sortedAddresses(deny), VerifiersOnly: p.config.VerifiersOnly || p.verifiersOnly}
+}
+
+// Reload reloads the permission file, it is loaded only if modified unless force is true.
+// Returns true if the permission is changed.
+func (p *NodePermission) Reload(force bool) (bool, error) {
+	if len(p.config.File) == 0 {
+		return false, nil
+	}
+
+	info, err := os.Stat(p.config.File)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	p.lock.Lock()
+	if !force && info.ModTime().Equal(p.modTime) {
+		p.lock.Unlock()
+		return false, nil
+	}
+
+	buff, err := ioutil.ReadFile(p.config.File)
+	if err != nil {
+		p.lock.Unlock()
+		return false, err
+	}
+
+	var list PermissionList
+	if err = json.Unmarshal(buff, &list); err != nil {
+		p.lock.Unlock()
+		return false, fmt.Errorf("invalid permission file %s, %s", p.config.File, err)
+	}
+
+	p.allow = toAddressSet(list.Allow)
+	p.deny = toAddressSet(list.Deny)
+	p.verifiersOnly = list.VerifiersOnly
+	p.modTime = info.ModTime()
+	p.lock.Unlock()
+
+	p.log.Info("permission file reloaded, allow %d nodes, deny %d nodes, verifiers only %t", len(list.Allow), len(list.Deny), list.VerifiersOnly)
+	p.notify()
+
+	return true, nil
+}
+
+// SetAllowed adds the node into the allowlist or removes it from the allowlist.
+func (p *NodePermission) SetAllowed(id common.Address, allowed bool) error {
+	return p.update(func() {
+		if allowed {
+			p.allow[id] = true
+		} else {
+			delete(p.allow, id)
+		}
+	})
+}
+
+// SetDenied adds the node into the denylist or removes it from the denylist.
+func (p *NodePermission) SetDenied(id common.Address, denied bool) error {
+	return p.update(func() {
+		if denied {
+			p.deny[id] = true
+		} else {
+			delete(p.deny, id)
+		}
+	})
+}
+
+// update modifies the lists and saves them into the permission file if specified.
+func (p *NodePermission) update(modify func()) error {
+	p.lock.Lock()
+	modify()
+
+	if len(p.config.File) > 0 {
+		list := PermissionList{Allow: sortedAddresses(p.allow), Deny: sortedAddresses(p.deny), VerifiersOnly: p.verifiersOnly}
+		if err := p.save(list); err != nil {
+			p.lock.Unlock()
+			return err
+		}
+	}
+	p.lock.Unlock()
+
+	p.notify()
+	return nil
+}
+
+func (p *NodePermission) save(list PermissionList) error {
+	buff, err := json.MarshalIndent(list, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if err = ioutil.WriteFile(p.config.File, buff, 0600); err != nil {
+		return err
+	}
+
+	// do not reload the file saved by itself
+	if info, err := os.Stat(p.config.File); err == nil {
+		p.modTime = info.ModTime()
+	}
+
+	return nil
+}
+
+// subscribe registers the listener which is called when the permission is changed.
+func (p *NodePermission) subscribe(listener func()) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.listeners = append(p.listeners, listener)
+}
+
+func (p *NodePermission) notify() {
+	p.lock.RLock()
+	listeners := p.listeners
+	p.lock.RUnlock()
+
+	for _, listener := range listeners {
+		listener()
+	}
+}
+
+func containsAddress(list []common.Address, id common.Address) bool {
+	for _, addr := range list {
+		if addr == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toAddressSet(list []common.Address) map[common.Address]bool {
+	set := make(map[common.Address]bool)
+	for _, addr := range list {
+		set[addr] = true
+	}
+
+	return set
+}
+
+func sortedAddresses(set map[common.Address]bool) []common.Address {
+	list := make([]common.Address, 0, len(set))
+	for addr := range set {
+		list = append(list, addr)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Hex() < list[j].Hex() })
+	return list
+}
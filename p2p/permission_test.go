@@ -0,0 +1,111 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package p2p
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/seeleteam/go-seele/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NodePermission_Allowed(t *testing.T) {
+	a, b, c := *crypto.MustGenerateRandomAddress(), *crypto.MustGenerateRandomAddress(), *crypto.MustGenerateRandomAddress()
+
+	// all nodes are allowed by default
+	p := NewNodePermission(PermissionConfig{}, log.GetLogger("p2p"))
+	assert.Equal(t, p.Allowed(a), true)
+
+	p = NewNodePermission(PermissionConfig{Allow: []common.Address{a, b}, Deny: []common.Address{b}}, log.GetLogger("p2p"))
+	assert.Equal(t, p.Allowed(a), true)
+	assert.Equal(t, p.Allowed(b), false)
+	assert.Equal(t, p.Allowed(c), false)
+
+	assert.Equal(t, p.SetAllowed(c, true), nil)
+	assert.Equal(t, p.Allowed(c), true)
+
+	assert.Equal(t, p.SetDenied(c, true), nil)
+	assert.Equal(t, p.Allowed(c), false)
+	assert.Equal(t, len(p.List().Deny), 2)
+}
+
+func Test_NodePermission_File(t *testing.T) {
+	dir, err := ioutil.TempDir("", "permission")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	a, b := *crypto.MustGenerateRandomAddress(), *crypto.MustGenerateRandomAddress()
+	file := filepath.Join(dir, "permission.json")
+	assert.Equal(t, ioutil.WriteFile(file, []byte(`{"allow":["`+a.Hex()+`"]}`), os.ModePerm), nil)
+
+	p := NewNodePermission(PermissionConfig{File: file}, log.GetLogger("p2p"))
+	changes := 0
+	p.subscribe(func() { changes++ })
+
+	changed, err := p.Reload(false)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, changed, true)
+	assert.Equal(t, p.Allowed(a), true)
+	assert.Equal(t, p.Allowed(b), false)
+
+	// not reloaded if the file is not modified
+	changed, err = p.Reload(false)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, changed, false)
+
+	// modifications are saved into the file
+	assert.Equal(t, p.SetAllowed(b, true), nil)
+	assert.Equal(t, changes, 2)
+
+	p = NewNodePermission(PermissionConfig{File: file}, log.GetLogger("p2p"))
+	_, err = p.Reload(true)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, p.Allowed(b), true)
+
+	assert.Equal(t, ioutil.WriteFile(file, []byte("invalid"), os.ModePerm), nil)
+	_, err = p.Reload(true)
+	assert.Equal(t, err != nil, true)
+	assert.Equal(t, p.Allowed(b), true)
+}
+
+func Test_NodePermission_VerifiersOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "permission")
+	assert.Equal(t, err, nil)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "permission.json")
+	p := NewNodePermission(PermissionConfig{File: file}, log.GetLogger("p2p"))
+	assert.Equal(t, p.VerifiersOnly(), false)
+
+	// the mode is changed by reloading the file at runtime
+	assert.Equal(t, ioutil.WriteFile(file, []byte(`{"verifiersOnly":true}`), os.ModePerm), nil)
+	_, err = p.Reload(true)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, p.VerifiersOnly(), true)
+	assert.Equal(t, p.List().VerifiersOnly, true)
+
+	// the mode is kept when the lists are saved into the file
+	assert.Equal(t, p.SetAllowed(*crypto.MustGenerateRandomAddress(), true), nil)
+	_, err = p.Reload(true)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, p.VerifiersOnly(), true)
+
+	assert.Equal(t, ioutil.WriteFile(file, []byte(`{}`), os.ModePerm), nil)
+	_, err = p.Reload(true)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, p.VerifiersOnly(), false)
+
+	// the mode in the config could not be disabled by the file
+	p = NewNodePermission(PermissionConfig{File: file, VerifiersOnly: true}, log.GetLogger("p2p"))
+	_, err = p.Reload(true)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, p.VerifiersOnly(), true)
+}
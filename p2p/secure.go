@@ -0,0 +1,133 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package p2p
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/seeleteam/go-seele/crypto/ecies"
+)
+
+const (
+	// secureCapName is the cap advertised in the handshake by the nodes which support
+	// encrypted transport. It is not a sub protocol, so it is never matched by peerIsValidate.
+	secureCapName = "secure"
+
+	// secureVersion is the version of the encrypted transport
+	secureVersion uint = 1
+
+	// secureNonceLen is the length of the server nonce appended to the extra data of the handshake
+	secureNonceLen = 8
+)
+
+var (
+	errDecryptFrame = errors.New("Failed to decrypt frame")
+
+	// secureCap is the cap of the encrypted transport
+	secureCap = Cap{secureCapName, secureVersion}
+)
+
+// supportsSecure returns true if the caps contain the encrypted transport cap.
+func supportsSecure(caps []Cap) bool {
+	for _, cap := range caps {
+		if cap == secureCap {
+			return true
+		}
+	}
+
+	return false
+}
+
+// frameCipher encrypts and authenticates the frames of a connection with AES-GCM.
+// Each direction has its own key and the nonce is the frame counter of that direction,
+// so the frames could not be replayed, reordered or reflected back to the sender.
+type frameCipher struct {
+	enc      cipher.AEAD
+	dec      cipher.AEAD
+	encCount uint64
+	decCount uint64
+}
+
+// newFrameCipher derives the session keys from the ECDH shared secret of the node keys and
+// the nonces of both sides in the handshake. The initiator is the side which dials the connection.
+func newFrameCipher(privKey *ecdsa.PrivateKey, remotePubKey *ecdsa.PublicKey, clientNonce uint64, serverNonce []byte, initiator bool) (*frameCipher, error) {
+	prv := ecies.ImportECDSA(privKey)
+	shared, err := prv.GenerateShared(ecies.ImportECDSAPublic(remotePubKey), 16, 16)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 8+len(serverNonce))
+	binary.BigEndian.PutUint64(nonce, clientNonce)
+	copy(nonce[8:], serverNonce)
+
+	initiatorAEAD, err := newSessionAEAD(shared, nonce, "initiator")
+	if err != nil {
+		return nil, err
+	}
+
+	recipientAEAD, err := newSessionAEAD(shared, nonce, "recipient")
+	if err != nil {
+		return nil, err
+	}
+
+	if initiator {
+		return &frameCipher{enc: initiatorAEAD, dec: recipientAEAD}, nil
+	}
+
+	return &frameCipher{enc: recipientAEAD, dec: initiatorAEAD}, nil
+}
+
+// newSessionAEAD creates the AES-256-GCM cipher with key sha256(shared || nonce || label).
+func newSessionAEAD(shared, nonce []byte, label string) (cipher.AEAD, error) {
+	hash := sha256.New()
+	hash.Write(shared)
+	hash.Write(nonce)
+	hash.Write([]byte(label))
+
+	block, err := aes.NewCipher(hash.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func counterNonce(aead cipher.AEAD, counter uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], counter)
+	return nonce
+}
+
+// seal encrypts the plain text, the frame header is authenticated as additional data.
+func (fc *frameCipher) seal(header, plain []byte) []byte {
+	nonce := counterNonce(fc.enc, fc.encCount)
+	fc.encCount++
+
+	return fc.enc.Seal(nil, nonce, plain, header)
+}
+
+// open decrypts the cipher text and verifies it together with the frame header.
+func (fc *frameCipher) open(header, cipherText []byte) ([]byte, error) {
+	nonce := counterNonce(fc.dec, fc.decCount)
+	plain, err := fc.dec.Open(nil, nonce, cipherText, header)
+	if err != nil {
+		return nil, errDecryptFrame
+	}
+
+	fc.decCount++
+	return plain, nil
+}
+
+// overhead returns the size of the authentication tag appended to each frame.
+func (fc *frameCipher) overhead() int {
+	return fc.enc.Overhead()
+}
@@ -0,0 +1,121 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/seeleteam/go-seele/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSecureConnections(t *testing.T) (*connection, *connection) {
+	client, ln, err := newConnection()
+	assert.Equal(t, err, nil)
+	defer ln.Close()
+
+	fd, err := ln.Accept()
+	assert.Equal(t, err, nil)
+	server := &connection{fd: fd, log: log.GetLogger("p2p")}
+
+	_, clientKey, _ := crypto.GenerateKeyPair()
+	_, serverKey, _ := crypto.GenerateKeyPair()
+	serverNonce := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+
+	client.cipher, err = newFrameCipher(clientKey, &serverKey.PublicKey, 10, serverNonce, true)
+	assert.Equal(t, err, nil)
+
+	server.cipher, err = newFrameCipher(serverKey, &clientKey.PublicKey, 10, serverNonce, false)
+	assert.Equal(t, err, nil)
+
+	return client, server
+}
+
+func Test_SecureConn_ReadWriteMsg(t *testing.T) {
+	client, server := newSecureConnections(t)
+	defer client.close()
+	defer server.close()
+
+	for i := 0; i < 3; i++ {
+		err := client.WriteMsg(&Message{Code: uint16(i), Payload: []byte("hello")})
+		assert.Equal(t, err, nil)
+
+		msg, err := server.ReadMsg()
+		assert.Equal(t, err, nil)
+		assert.Equal(t, msg.Code, uint16(i))
+		assert.Equal(t, msg.Payload, []byte("hello"))
+	}
+
+	// empty payload in the other direction
+	err := server.WriteMsg(&Message{Code: ctlMsgPingCode})
+	assert.Equal(t, err, nil)
+
+	msg, err := client.ReadMsg()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, msg.Code, ctlMsgPingCode)
+	assert.Equal(t, len(msg.Payload), 0)
+}
+
+func Test_SecureConn_Tampered(t *testing.T) {
+	client, server := newSecureConnections(t)
+	defer client.close()
+	defer server.close()
+
+	header := make([]byte, headBuffLength)
+	frame := client.cipher.seal(header, []byte{0, 1, 'a'})
+	frame[len(frame)-1] ^= 0xff
+
+	_, err := server.cipher.open(header, frame)
+	assert.Equal(t, err, errDecryptFrame)
+
+	// replayed frame is rejected since the counter is changed
+	frame = client.cipher.seal(header, []byte{0, 1, 'a'})
+	_, err = server.cipher.open(header, frame)
+	assert.Equal(t, err, errDecryptFrame)
+}
+
+func Test_SecureConn_Plaintext(t *testing.T) {
+	client, server := newSecureConnections(t)
+	defer client.close()
+	defer server.close()
+
+	// plaintext frame could not be decrypted
+	client.cipher = nil
+	err := client.WriteMsg(&Message{Code: 1, Payload: []byte("hello")})
+	assert.Equal(t, err, nil)
+
+	server.fd.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = server.ReadMsg()
+	assert.Equal(t, err != nil, true)
+}
+
+func Test_WrapHSMsg_ServerNonce(t *testing.T) {
+	config := testConfig()
+	server := &Server{Config: *config, log: log.GetLogger("p2p")}
+	nodeID := crypto.GetAddress(&config.PrivateKey.PublicKey)
+
+	handshakeMsg := &ProtoHandShake{Caps: []Cap{secureCap}, NodeID: *nodeID}
+	message, err := server.packWrapHSMsg(handshakeMsg, nodeID[0:], 1, nil)
+	assert.Equal(t, err, nil)
+
+	recvMsg, nounceCnt, session, err := server.unPackWrapHSMsg(message)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, nounceCnt, uint64(1))
+	assert.Equal(t, supportsSecure(recvMsg.Caps), true)
+	assert.Equal(t, len(session.serverNonce), 0)
+	assert.Equal(t, crypto.GetAddress(session.pubKey), nodeID)
+
+	serverNonce := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	message, err = server.packWrapHSMsg(handshakeMsg, nodeID[0:], 2, serverNonce)
+	assert.Equal(t, err, nil)
+
+	_, nounceCnt, session, err = server.unPackWrapHSMsg(message)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, nounceCnt, uint64(2))
+	assert.Equal(t, session.serverNonce, serverNonce)
+}
@@ -52,6 +52,9 @@ const (
 	// In transferring handshake msg, length of extra data
 	extraDataLen = 24
 
+	// length of the signature in the handshake msg
+	hsSignatureLen = 65
+
 	// Minimum recommended number of peers of one shard
 	minNumOfPeerPerShard = uint(2)
 
@@ -76,6 +79,9 @@ type Config struct {
 
 	// PrivateKey private key for p2p module, do not use it as any accounts
 	PrivateKey *ecdsa.PrivateKey `json:"-"`
+
+	// Permission is the allowlist and denylist of the peers
+	Permission PermissionConfig `json:"permission"`
 }
 
 // Server manages all p2p peer connections.
@@ -122,6 +128,9 @@ type Server struct {
 	maxActiveConnections int
 
 	peerNumLock sync.Mutex // lock for num of peers per shard
+
+	// permission checks whether a node is permitted to connect
+	permission *NodePermission
 }
 
 // NewServer initialize a server
@@ -141,10 +150,11 @@ func NewServer(genesis core.GenesisInfo, config Config, protocols []Protocol) *S
 	genesis.Masteraccount = masteraccount
 	genesis.Balance = balance
 
+	logger := log.GetLogger("p2p")
 	return &Server{
 		Config:               config,
 		running:              false,
-		log:                  log.GetLogger("p2p"),
+		log:                  logger,
 		quit:                 make(chan struct{}),
 		peerSet:              NewPeerSet(),
 		nodeSet:              NewNodeSet(),
@@ -154,9 +164,15 @@ func NewServer(genesis core.GenesisInfo, config Config, protocols []Protocol) *S
 		genesisHash:          hash,
 		maxConnections:       maxConnsPerShard * common.ShardCount,
 		maxActiveConnections: maxActiveConnsPerShard * common.ShardCount,
+		permission:           NewNodePermission(config.Permission, logger),
 	}
 }
 
+// Permission returns the node permission of the server
+func (srv *Server) Permission() *NodePermission {
+	return srv.permission
+}
+
 // PeerCount return the count of peers
 func (srv *Server) PeerCount() int {
 	return srv.peerSet.count()
@@ -177,11 +193,16 @@ func (srv *Server) Start(nodeDir string, shard uint) (err error) {
 		return err
 	}
 
+	if _, err = srv.permission.Reload(true); err != nil {
+		return err
+	}
+	srv.permission.subscribe(srv.dropUnpermittedPeers)
+
 	srv.log.Debug("Starting P2P networking...")
 	srv.SelfNode = discovery.NewNodeWithAddr(*address, addr, shard)
 
 	srv.log.Debug("p2p.Server.Start: MyNodeID [%s]", srv.SelfNode)
-	srv.kadDB = discovery.StartService(nodeDir, *address, addr, srv.StaticNodes, shard)
+	srv.kadDB = discovery.StartServiceWithFilter(nodeDir, *address, addr, srv.StaticNodes, shard, srv.permission.Allowed)
 	// fmt.Println("staticnodes", srv.StaticNodes)
 	srv.kadDB.SetHookForNewNode(srv.addNode)
 	srv.kadDB.SetHookForDeleteNode(srv.deleteNode)
@@ -247,7 +268,7 @@ func (srv *Server) addNode(node *discovery.Node) {
 }
 
 func (srv *Server) connectNode(node *discovery.Node) {
-	if srv.checkPeerExist(node.ID) {
+	if srv.checkPeerExist(node.ID) || !srv.permission.Allowed(node.ID) {
 		return
 	}
 
@@ -356,6 +377,8 @@ func (srv *Server) run() {
 
 	checkTicker := time.NewTicker(checkConnsNumInterval)
 	checkTicker1 := time.NewTicker(12*checkConnsNumInterval + 3)
+	permissionTicker := time.NewTicker(permissionReloadInterval)
+	defer permissionTicker.Stop()
 
 running:
 	for {
@@ -368,6 +391,11 @@ running:
 				go srv.doSelectLocalNodeToConnect()
 			}
 
+		case <-permissionTicker.C:
+			if _, err := srv.permission.Reload(false); err != nil {
+				srv.log.Warn("failed to reload permission file, %s", err)
+			}
+
 		case <-srv.quit:
 			srv.log.Warn("server got quit signal, run cleanup logic")
 			break running
@@ -383,6 +411,16 @@ running:
 	}
 }
 
+// dropUnpermittedPeers disconnects the peers which are not permitted any more
+func (srv *Server) dropUnpermittedPeers() {
+	for id, peer := range srv.peerSet.getPeers() {
+		if peer != nil && !srv.permission.Allowed(id) {
+			srv.log.Info("disconnect peer %s which is not permitted", id.Hex())
+			go peer.Disconnect("node is not permitted")
+		}
+	}
+}
+
 // doSelectNodeToConnect selects one free node from nodeMap to connect
 func (srv *Server) doSelectNodeToConnect() {
 
@@ -519,6 +557,7 @@ func (srv *Server) setupConn(fd net.Conn, flags int, dialDest *discovery.Node) e
 		caps = append(caps, proto.cap())
 	}
 
+	caps = append(caps, secureCap)
 	sort.Sort(capsByNameAndVersion(caps))
 	recvMsg, _, err := srv.doHandShake(caps, peer, flags, dialDest)
 	if err != nil {
@@ -529,6 +568,11 @@ func (srv *Server) setupConn(fd net.Conn, flags int, dialDest *discovery.Node) e
 
 	srv.log.Debug("handshake succeed. %s -> %s", fd.LocalAddr(), fd.RemoteAddr())
 	peerNodeID := recvMsg.NodeID
+	if !srv.permission.Allowed(peerNodeID) {
+		srv.log.Info("p2p.setupConn reject node %s which is not permitted", peerNodeID.Hex())
+		peer.close()
+		return errors.New("node is not permitted")
+	}
 	if flags == inboundConn {
 		peerNode, ok := srv.kadDB.FindByNodeID(peerNodeID)
 		if !ok {
@@ -609,6 +653,7 @@ func (srv *Server) getProtocolsByCaps(capList []Cap) (proList []Protocol) {
 // doHandShake Communicate each other
 func (srv *Server) doHandShake(caps []Cap, peer *Peer, flags int, dialDest *discovery.Node) (recvMsg *ProtoHandShake, nounceCnt uint64, err error) {
 	var renounceCnt uint64
+	var session *hsSession
 	handshakeMsg := &ProtoHandShake{Caps: caps}
 	handshakeMsg.NetworkID = srv.Config.NetworkID
 	handshakeMsg.Params = srv.genesisHash.Bytes()
@@ -620,7 +665,7 @@ func (srv *Server) doHandShake(caps []Cap, peer *Peer, flags int, dialDest *disc
 			return nil, 0, err
 		}
 
-		wrapMsg, err := srv.packWrapHSMsg(handshakeMsg, dialDest.ID[0:], nounceCnt)
+		wrapMsg, err := srv.packWrapHSMsg(handshakeMsg, dialDest.ID[0:], nounceCnt, nil)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -634,7 +679,7 @@ func (srv *Server) doHandShake(caps []Cap, peer *Peer, flags int, dialDest *disc
 			return nil, 0, err
 		}
 
-		recvMsg, renounceCnt, err = srv.unPackWrapHSMsg(recvWrapMsg)
+		recvMsg, renounceCnt, session, err = srv.unPackWrapHSMsg(recvWrapMsg)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -651,6 +696,17 @@ func (srv *Server) doHandShake(caps []Cap, peer *Peer, flags int, dialDest *disc
 		sort.Sort(capsByNameAndVersion(capList))
 		peer.setProtocols(srv.getProtocolsByCaps(capList))
 
+		// the server sends its nonce only if both sides support the encrypted transport
+		if supportsSecure(recvMsg.Caps) && session.serverNonce == nil {
+			return nil, 0, errors.New("server nonce is missing")
+		}
+
+		if session.serverNonce != nil {
+			if err = srv.enableSecure(peer, session, nounceCnt, true); err != nil {
+				return nil, 0, err
+			}
+		}
+
 	} else {
 		// server side. Receive handshake msg first
 		recvWrapMsg, err := peer.rw.ReadMsg()
@@ -658,11 +714,15 @@ func (srv *Server) doHandShake(caps []Cap, peer *Peer, flags int, dialDest *disc
 			return nil, 0, err
 		}
 
-		recvMsg, nounceCnt, err = srv.unPackWrapHSMsg(recvWrapMsg)
+		recvMsg, nounceCnt, session, err = srv.unPackWrapHSMsg(recvWrapMsg)
 		if err != nil {
 			return nil, 0, err
 		}
 
+		if session.serverNonce != nil {
+			return nil, 0, errors.New("unexpected server nonce from client")
+		}
+
 		capList, bValid := srv.peerIsValidate(recvMsg)
 		if !bValid {
 			return nil, 0, errors.New("node is not consistent with groups")
@@ -671,7 +731,14 @@ func (srv *Server) doHandShake(caps []Cap, peer *Peer, flags int, dialDest *disc
 		sort.Sort(capsByNameAndVersion(capList))
 		peer.setProtocols(srv.getProtocolsByCaps(capList))
 
-		wrapMsg, err := srv.packWrapHSMsg(handshakeMsg, recvMsg.NodeID[0:], nounceCnt)
+		if supportsSecure(recvMsg.Caps) {
+			session.serverNonce = make([]byte, secureNonceLen)
+			if _, err = rand.Read(session.serverNonce); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		wrapMsg, err := srv.packWrapHSMsg(handshakeMsg, recvMsg.NodeID[0:], nounceCnt, session.serverNonce)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -679,13 +746,37 @@ func (srv *Server) doHandShake(caps []Cap, peer *Peer, flags int, dialDest *disc
 		if err = peer.rw.WriteMsg(wrapMsg); err != nil {
 			return nil, 0, err
 		}
+
+		if session.serverNonce != nil {
+			if err = srv.enableSecure(peer, session, nounceCnt, false); err != nil {
+				return nil, 0, err
+			}
+		}
 	}
 	return
 }
 
+// hsSession is the key exchange data of a verified handshake msg
+type hsSession struct {
+	pubKey      *ecdsa.PublicKey // public key of the remote node, recovered from the handshake signature
+	serverNonce []byte           // random nonce of the server side, nil if the encrypted transport is not negotiated
+}
+
+// enableSecure derives the session keys and encrypts all the subsequent frames of the peer connection.
+func (srv *Server) enableSecure(peer *Peer, session *hsSession, nounceCnt uint64, initiator bool) error {
+	fc, err := newFrameCipher(srv.PrivateKey, session.pubKey, nounceCnt, session.serverNonce, initiator)
+	if err != nil {
+		return err
+	}
+
+	peer.rw.cipher = fc
+	srv.log.Debug("encrypted transport enabled with peer %s", peer.RemoteAddr())
+	return nil
+}
+
 // packWrapHSMsg compose the wrapped send msg.
 // A 32 byte ExtraData is used for verification process.
-func (srv *Server) packWrapHSMsg(handshakeMsg *ProtoHandShake, peerNodeID []byte, nounceCnt uint64) (*Message, error) {
+func (srv *Server) packWrapHSMsg(handshakeMsg *ProtoHandShake, peerNodeID []byte, nounceCnt uint64, serverNonce []byte) (*Message, error) {
 	// Serialize should handle big-endian
 	hdmsgRLP, err := common.Serialize(handshakeMsg)
 
@@ -699,18 +790,20 @@ func (srv *Server) packWrapHSMsg(handshakeMsg *ProtoHandShake, peerNodeID []byte
 	if _, err := md5Inst.Write(hdmsgRLP); err != nil {
 		return &Message{}, err
 	}
-	extBuf := make([]byte, extraDataLen)
+	extBuf := make([]byte, extraDataLen+len(serverNonce))
 
 	// first 16 bytes, contains md5sum of hdmsgRLP;
 	// then 8 bytes for client side nounce;
+	// then 8 bytes for server side nonce if the encrypted transport is negotiated.
 	copy(extBuf, md5Inst.Sum(nil))
 	binary.BigEndian.PutUint64(extBuf[16:], nounceCnt)
+	copy(extBuf[extraDataLen:], serverNonce)
 
 	// Sign with local privateKey first
 	signature := crypto.MustSign(srv.PrivateKey, crypto.MustHash(extBuf).Bytes())
-	enc := make([]byte, extraDataLen+len(signature.Sig))
+	enc := make([]byte, len(extBuf)+len(signature.Sig))
 	copy(enc, extBuf)
-	copy(enc[extraDataLen:], signature.Sig)
+	copy(enc[len(extBuf):], signature.Sig)
 
 	// Format of wrapMsg payload, [handshake's rlp body, encoded extra data, length of encoded extra data]
 	size := uint32(len(hdmsgRLP) + len(enc) + 4)
@@ -722,7 +815,7 @@ func (srv *Server) packWrapHSMsg(handshakeMsg *ProtoHandShake, peerNodeID []byte
 }
 
 // unPackWrapHSMsg verify received msg, and recover the handshake msg
-func (srv *Server) unPackWrapHSMsg(recvWrapMsg *Message) (recvMsg *ProtoHandShake, nounceCnt uint64, err error) {
+func (srv *Server) unPackWrapHSMsg(recvWrapMsg *Message) (recvMsg *ProtoHandShake, nounceCnt uint64, session *hsSession, err error) {
 	size := uint32(len(recvWrapMsg.Payload))
 	if size < extraDataLen+4 {
 		err = errors.New("received msg with invalid length")
@@ -730,19 +823,31 @@ func (srv *Server) unPackWrapHSMsg(recvWrapMsg *Message) (recvMsg *ProtoHandShak
 	}
 
 	extraEncLen := binary.BigEndian.Uint32(recvWrapMsg.Payload[size-4:])
+	if extraEncLen > size-4 || extraEncLen < extraDataLen+hsSignatureLen {
+		err = errors.New("received msg with invalid extra data length")
+		return
+	}
+
 	recvHSMsgLen := size - extraEncLen - 4
 	nounceCnt = binary.BigEndian.Uint64(recvWrapMsg.Payload[recvHSMsgLen+16:])
 	recvEnc := recvWrapMsg.Payload[recvHSMsgLen : size-4]
+	extLen := extraEncLen - hsSignatureLen
+	if extLen != extraDataLen && extLen != extraDataLen+secureNonceLen {
+		err = errors.New("received msg with invalid extra data length")
+		return
+	}
+
 	recvMsg = &ProtoHandShake{}
 	if err = common.Deserialize(recvWrapMsg.Payload[:recvHSMsgLen], recvMsg); err != nil {
 		return
 	}
 	// verify signature
 	sig := crypto.Signature{
-		Sig: recvEnc[extraDataLen:],
+		Sig: recvEnc[extLen:],
 	}
 
-	if !sig.Verify(recvMsg.NodeID, crypto.MustHash(recvEnc[0:extraDataLen]).Bytes()) {
+	extHash := crypto.MustHash(recvEnc[0:extLen]).Bytes()
+	if !sig.Verify(recvMsg.NodeID, extHash) {
 		err = errors.New("unPackWrapHSMsg: received public key not match")
 		return
 	}
@@ -758,6 +863,15 @@ func (srv *Server) unPackWrapHSMsg(recvWrapMsg *Message) (recvMsg *ProtoHandShak
 		return
 	}
 
+	session = &hsSession{}
+	if session.pubKey, err = crypto.SigToPub(extHash, sig.Sig); err != nil {
+		return
+	}
+
+	if extLen > extraDataLen {
+		session.serverNonce = recvEnc[extraDataLen:extLen]
+	}
+
 	srv.log.Debug("unPackWrapHSMsg: verify OK!")
 	return
 }
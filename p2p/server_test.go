@@ -113,7 +113,7 @@ func Test_peerIsValidate(t *testing.T) {
 	assert.Equal(t, server.PeerCount(), 0)
 
 	var message = &Message{}
-	recvMsg, renounceCnt, err := server.unPackWrapHSMsg(message)
+	recvMsg, renounceCnt, _, err := server.unPackWrapHSMsg(message)
 	assert.Equal(t, strings.Contains(err.Error(), "received msg with invalid length"), true)
 	assert.Equal(t, renounceCnt, uint64(0))
 	assert.Equal(t, recvMsg == nil, true)
@@ -126,10 +126,10 @@ func Test_peerIsValidate(t *testing.T) {
 	handshakeMsg := &ProtoHandShake{Caps: caps}
 	handshakeMsg.NetworkID = server.Config.NetworkID
 	node := discovery.MustNewNodeWithAddr(*crypto.MustGenerateShardAddress(1), "127.0.1.1:9000", 0)
-	message, err = server.packWrapHSMsg(handshakeMsg, node.ID[0:], outboundConn)
+	message, err = server.packWrapHSMsg(handshakeMsg, node.ID[0:], outboundConn, nil)
 	assert.Equal(t, err, nil)
 
-	recvMsg, renounceCnt, err = server.unPackWrapHSMsg(message)
+	recvMsg, renounceCnt, _, err = server.unPackWrapHSMsg(message)
 	assert.Equal(t, strings.Contains(err.Error(), " received public key not match"), true)
 }
 
@@ -0,0 +1,348 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package rpc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// jwtClockSkew is the tolerance of the issued time of a jwt token
+	jwtClockSkew = 60 * time.Second
+
+	// maxLimitedClients is the number of client buckets to trigger the cleanup of idle buckets
+	maxLimitedClients = 1024
+)
+
+var (
+	errMissingToken = errors.New("missing bearer token")
+	errInvalidToken = errors.New("invalid bearer token")
+	errTokenExpired = errors.New("bearer token is expired")
+	errRateLimited  = errors.New("too many requests")
+)
+
+// AllModules in the allowlist allows all the namespaces including the private ones,
+// the private namespaces are only served when authentication is enabled.
+const AllModules = "*"
+
+// AccessConfig is the access control configuration of a rpc endpoint
+type AccessConfig struct {
+	// Modules is the allowlist of namespaces (e.g. "seele") or methods (e.g. "debug_getTPS")
+	// served by the endpoint. If empty, all the public namespaces are served, and
+	// AllModules serves all the namespaces. Private namespaces require authentication.
+	Modules []string `json:"modules,omitempty"`
+
+	// Deny is the denylist of namespaces or methods, it takes precedence over Modules
+	Deny []string `json:"deny,omitempty"`
+
+	// Tokens are the static bearer tokens accepted by the endpoint
+	Tokens []string `json:"tokens,omitempty"`
+
+	// JWTSecret is the secret to verify HS256 signed jwt bearer tokens
+	JWTSecret string `json:"jwtSecret,omitempty"`
+
+	// RateLimit is the max requests per second of each client, 0 means no limit
+	RateLimit float64 `json:"rateLimit,omitempty"`
+
+	// RateBurst is the max requests a client could send at once, default to the rate limit
+	RateBurst int `json:"rateBurst,omitempty"`
+}
+
+// AuthEnabled returns true if the endpoint requires bearer token authentication
+func (config *AccessConfig) AuthEnabled() bool {
+	return len(config.Tokens) > 0 || len(config.JWTSecret) > 0
+}
+
+// ModuleAllowed returns true if the namespace is allowed by the config,
+// that is the namespace or any of its methods is in the allowlist.
+func (config *AccessConfig) ModuleAllowed(namespace string) bool {
+	for _, name := range config.Deny {
+		if name == namespace {
+			return false
+		}
+	}
+
+	for _, name := range config.Modules {
+		if name == AllModules || name == namespace || strings.HasPrefix(name, namespace+serviceMethodSeparator) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// accessControl enforces the access config on the requests of a rpc server
+type accessControl struct {
+	allow     map[string]bool // namespace or namespace_method
+	deny      map[string]bool
+	tokens    [][]byte
+	jwtSecret []byte
+	limiter   *rateLimiter
+}
+
+func newAccessControl(config AccessConfig) *accessControl {
+	ac := &accessControl{
+		allow:     toSet(config.Modules),
+		deny:      toSet(config.Deny),
+		jwtSecret: []byte(config.JWTSecret),
+	}
+
+	for _, token := range config.Tokens {
+		ac.tokens = append(ac.tokens, []byte(token))
+	}
+
+	if config.RateLimit > 0 {
+		ac.limiter = newRateLimiter(config.RateLimit, config.RateBurst)
+	}
+
+	return ac
+}
+
+func toSet(names []string) map[string]bool {
+	set := make(map[string]bool)
+	for _, name := range names {
+		set[name] = true
+	}
+
+	return set
+}
+
+// SetAccessConfig sets the access control of the server, it should be called before serving any request.
+func (s *Server) SetAccessConfig(config AccessConfig) {
+	s.access = newAccessControl(config)
+}
+
+// methodAllowed returns true if the method is allowed by the allowlist and denylist.
+func (ac *accessControl) methodAllowed(service, method string) bool {
+	if service == MetadataApi {
+		return true
+	}
+
+	name := service + serviceMethodSeparator + method
+	if ac.deny[service] || ac.deny[name] {
+		return false
+	}
+
+	return len(ac.allow) == 0 || ac.allow[AllModules] || ac.allow[service] || ac.allow[name]
+}
+
+// authorize checks the bearer token of the http request if authentication is enabled.
+// The token could be specified in the Authorization header or the "token" query parameter
+// for websocket clients which are not able to set headers.
+func (ac *accessControl) authorize(r *http.Request) error {
+	if len(ac.tokens) == 0 && len(ac.jwtSecret) == 0 {
+		return nil
+	}
+
+	token := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); len(auth) > 0 {
+		if !strings.HasPrefix(auth, "Bearer ") {
+			return errInvalidToken
+		}
+		token = strings.TrimPrefix(auth, "Bearer ")
+	}
+
+	if len(token) == 0 {
+		return errMissingToken
+	}
+
+	for _, t := range ac.tokens {
+		if subtle.ConstantTimeCompare(t, []byte(token)) == 1 {
+			return nil
+		}
+	}
+
+	if len(ac.jwtSecret) == 0 {
+		return errInvalidToken
+	}
+
+	return verifyJWT(ac.jwtSecret, token, time.Now())
+}
+
+// allowClient returns false if the client exceeds the rate limit.
+func (ac *accessControl) allowClient(client string) bool {
+	return ac.limiter == nil || ac.limiter.allow(client, time.Now())
+}
+
+// check authorizes the http request and applies the rate limit, returns the http status code if failed.
+func (ac *accessControl) check(r *http.Request) (int, error) {
+	if err := ac.authorize(r); err != nil {
+		return http.StatusUnauthorized, err
+	}
+
+	if !ac.allowClient(clientIP(r)) {
+		return http.StatusTooManyRequests, errRateLimited
+	}
+
+	return 0, nil
+}
+
+// clientIP returns the ip address of the http request.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+}
+
+type jwtClaims struct {
+	IssuedAt  int64 `json:"iat,omitempty"`
+	ExpiresAt int64 `json:"exp,omitempty"`
+}
+
+// NewJWTToken creates a HS256 signed jwt token which expires after the specified duration,
+// the token never expires if the duration is zero.
+func NewJWTToken(secret []byte, expiration time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwtClaims{IssuedAt: now.Unix()}
+	if expiration != 0 {
+		claims.ExpiresAt = now.Add(expiration).Unix()
+	}
+
+	header, err := json.Marshal(jwtHeader{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(jwtSign(secret, signingInput)), nil
+}
+
+func jwtSign(secret []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+// verifyJWT verifies the signature and the time claims of a HS256 signed jwt token.
+func verifyJWT(secret []byte, token string, now time.Time) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errInvalidToken
+	}
+
+	var header jwtHeader
+	if err := decodeJWTPart(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return errInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, jwtSign(secret, parts[0]+"."+parts[1])) {
+		return errInvalidToken
+	}
+
+	var claims jwtClaims
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return errInvalidToken
+	}
+
+	if claims.IssuedAt > now.Add(jwtClockSkew).Unix() {
+		return errInvalidToken
+	}
+
+	if claims.ExpiresAt > 0 && claims.ExpiresAt < now.Unix() {
+		return errTokenExpired
+	}
+
+	return nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	buff, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(buff, v)
+}
+
+// rateLimiter is a token bucket rate limiter per client
+type rateLimiter struct {
+	rate    float64 // tokens per second
+	burst   float64
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst <= 0 {
+		burst = int(rate)
+		if burst < 1 {
+			burst = 1
+		}
+	}
+
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// allow takes a token from the bucket of the client, returns false if the bucket is empty.
+func (l *rateLimiter) allow(client string, now time.Time) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	b, ok := l.buckets[client]
+	if !ok {
+		if len(l.buckets) >= maxLimitedClients {
+			l.removeIdle(now)
+		}
+
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[client] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+// removeIdle removes the buckets which are full again, they behave the same as new buckets.
+func (l *rateLimiter) removeIdle(now time.Time) {
+	for client, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, client)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccessMethodAllowed(t *testing.T) {
+	ac := newAccessControl(AccessConfig{
+		Modules: []string{"seele", "debug_getTPS"},
+		Deny:    []string{"seele_addTx"},
+	})
+
+	cases := map[string]bool{
+		"seele_getBalance": true,
+		"seele_addTx":      false,
+		"debug_getTPS":     true,
+		"debug_dumpHeap":   false,
+		"miner_start":      false,
+		"rpc_modules":      true,
+	}
+
+	for name, expected := range cases {
+		parts := strings.SplitN(name, serviceMethodSeparator, 2)
+		if allowed := ac.methodAllowed(parts[0], parts[1]); allowed != expected {
+			t.Fatalf("method %s allowed should be %t", name, expected)
+		}
+	}
+
+	config := AccessConfig{Modules: []string{"seele", "debug_getTPS"}, Deny: []string{"miner"}}
+	if !config.ModuleAllowed("debug") || config.ModuleAllowed("miner") || config.ModuleAllowed("txpool") {
+		t.Fatal("unexpected module allowlist")
+	}
+
+	ac = newAccessControl(AccessConfig{Modules: []string{AllModules}, Deny: []string{"admin"}})
+	if !ac.methodAllowed("miner", "start") || !ac.methodAllowed("debug", "dumpHeap") || ac.methodAllowed("admin", "peers") {
+		t.Fatal("unexpected methods allowed by all modules")
+	}
+
+	config = AccessConfig{Modules: []string{AllModules}, Deny: []string{"admin"}}
+	if !config.ModuleAllowed("miner") || config.ModuleAllowed("admin") {
+		t.Fatal("unexpected modules allowed by all modules")
+	}
+}
+
+func TestAccessAuthorize(t *testing.T) {
+	secret := []byte("secret")
+	ac := newAccessControl(AccessConfig{Tokens: []string{"static"}, JWTSecret: string(secret)})
+
+	jwtToken, err := NewJWTToken(secret, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiredToken, err := NewJWTToken(secret, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherToken, err := NewJWTToken([]byte("other"), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		header   string
+		query    string
+		expected error
+	}{
+		{"", "", errMissingToken},
+		{"Bearer static", "", nil},
+		{"Bearer " + jwtToken, "", nil},
+		{"", "static", nil},
+		{"Bearer " + expiredToken, "", errTokenExpired},
+		{"Bearer " + otherToken, "", errInvalidToken},
+		{"Basic static", "", errInvalidToken},
+	}
+
+	for i, c := range cases {
+		request := httptest.NewRequest(http.MethodPost, "http://url.com/?token="+c.query, nil)
+		if len(c.header) > 0 {
+			request.Header.Set("Authorization", c.header)
+		}
+
+		if err := ac.authorize(request); err != c.expected {
+			t.Fatalf("case %d: authorize error should be %v not %v", i, c.expected, err)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(1, 2)
+	now := time.Now()
+
+	if !limiter.allow("a", now) || !limiter.allow("a", now) {
+		t.Fatal("requests within burst should be allowed")
+	}
+
+	if limiter.allow("a", now) {
+		t.Fatal("requests beyond burst should be limited")
+	}
+
+	if !limiter.allow("b", now) {
+		t.Fatal("clients should be limited separately")
+	}
+
+	if !limiter.allow("a", now.Add(time.Second)) {
+		t.Fatal("bucket should be recharged over time")
+	}
+}
+
+func TestHTTPAccessControl(t *testing.T) {
+	server := newTestServer("service", new(Service))
+	server.SetAccessConfig(AccessConfig{Tokens: []string{"static"}, Modules: []string{"service_echo"}})
+
+	request := func(token string, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "http://url.com", strings.NewReader(body))
+		r.Header.Set("content-type", contentType)
+		if len(token) > 0 {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := request("", `{"jsonrpc":"2.0","id":1,"method":"service_rets"}`); w.Code != http.StatusUnauthorized {
+		t.Fatalf("response code should be %d not %d", http.StatusUnauthorized, w.Code)
+	}
+
+	w := request("static", `{"jsonrpc":"2.0","id":1,"method":"service_rets"}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "not allowed") {
+		t.Fatalf("method should be denied, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = request("static", `{"jsonrpc":"2.0","id":1,"method":"service_echo","params":["x",1,{"S":"y"}]}`)
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "error") {
+		t.Fatalf("method should be allowed, got %d %s", w.Code, w.Body.String())
+	}
+}
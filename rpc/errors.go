@@ -41,6 +41,25 @@ func (e *methodNotAllowedError) Error() string {
 	return fmt.Sprintf("The method %s%s%s only works for 127.0.0.1 (localhost)", e.service, serviceMethodSeparator, e.method)
 }
 
+// request is for a method denied by the access control
+type methodForbiddenError struct {
+	service string
+	method  string
+}
+
+func (e *methodForbiddenError) ErrorCode() int { return -32598 }
+
+func (e *methodForbiddenError) Error() string {
+	return fmt.Sprintf("The method %s%s%s is not allowed on this endpoint", e.service, serviceMethodSeparator, e.method)
+}
+
+// client sends requests faster than the rate limit
+type rateLimitedError struct{}
+
+func (e *rateLimitedError) ErrorCode() int { return -32005 }
+
+func (e *rateLimitedError) Error() string { return errRateLimited.Error() }
+
 // received message isn't a valid request
 type invalidRequestError struct{ message string }
 
@@ -82,6 +82,21 @@ func DialHTTPWithClient(endpoint string, client *http.Client) (*Client, error) {
 	})
 }
 
+// DialHTTPWithToken creates a new RPC client that connects to an RPC server over HTTP
+// and authenticates with the specified bearer token.
+func DialHTTPWithToken(endpoint string, client *http.Client, token string) (*Client, error) {
+	c, err := DialHTTPWithClient(endpoint, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(token) > 0 {
+		c.writeConn.(*httpConn).req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return c, nil
+}
+
 // DialHTTP creates a new RPC client that connects to an RPC server over HTTP.
 func DialHTTP(endpoint string) (*Client, error) {
 	return DialHTTPWithClient(endpoint, new(http.Client))
@@ -166,6 +181,12 @@ func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), code)
 		return
 	}
+	if srv.access != nil {
+		if code, err := srv.access.check(r); err != nil {
+			http.Error(w, err.Error(), code)
+			return
+		}
+	}
 	// All checks passed, create a codec that reads direct from the request body
 	// untilEOF and writes the response to w and order the server to process a
 	// single request.
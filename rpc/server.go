@@ -410,6 +410,10 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, Error)
 			requests[i] = &serverRequest{id: r.id, err: &methodNotAllowedError{r.service, r.method}}
 			continue
 		}
+		if s.access != nil && !s.access.methodAllowed(r.service, r.method) {
+			requests[i] = &serverRequest{id: r.id, err: &methodForbiddenError{r.service, r.method}}
+			continue
+		}
 		if svc, ok = s.services[r.service]; !ok { // rpc method isn't available
 			requests[i] = &serverRequest{id: r.id, err: &methodNotFoundError{r.service, r.method}}
 			continue
@@ -16,3 +16,25 @@ func DialTCP(ctx context.Context, endpoint string) (*Client, error) {
 		return net.Dial("tcp", endpoint)
 	})
 }
+
+// ServeConn serves the requests of a stream connection, e.g. a tcp connection, and applies
+// the rate limit of the access config to the remote address of the connection.
+func (srv *Server) ServeConn(conn net.Conn) {
+	var codec ServerCodec = NewJSONCodec(conn)
+	if srv.access != nil && srv.access.limiter != nil {
+		codec = &rateLimitedCodec{codec, srv.access, connIP(conn)}
+	}
+
+	srv.ServeCodec(codec, OptionMethodInvocation|OptionSubscriptions)
+}
+
+// connIP returns the ip address of the remote side of the connection.
+func connIP(conn net.Conn) string {
+	addr := conn.RemoteAddr().String()
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
@@ -0,0 +1,136 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+)
+
+// TLSConfig is the TLS configuration of a rpc server
+type TLSConfig struct {
+	// CertFile is the path of the PEM encoded server certificate, TLS is disabled if empty
+	CertFile string `json:"certFile,omitempty"`
+
+	// KeyFile is the path of the PEM encoded private key of the server certificate
+	KeyFile string `json:"keyFile,omitempty"`
+
+	// ClientCAFile is the path of the PEM encoded CA certificates to verify client
+	// certificates. If specified, clients must present a valid certificate (mutual TLS).
+	ClientCAFile string `json:"clientCAFile,omitempty"`
+}
+
+// Enabled returns true if the server should serve over TLS
+func (config *TLSConfig) Enabled() bool {
+	return len(config.CertFile) > 0
+}
+
+// ServerConfig loads the certificates and returns the TLS config for the server listener
+func (config *TLSConfig) ServerConfig() (*tls.Config, error) {
+	if len(config.KeyFile) == 0 {
+		return nil, errors.New("tls key file is not specified")
+	}
+
+	cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tls certificate, %s", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if len(config.ClientCAFile) > 0 {
+		pool, err := loadCertPool(config.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return tlsConfig, nil
+}
+
+// NewClientTLSConfig returns the TLS config for rpc clients. The server certificate is verified
+// with caFile if specified, otherwise the system roots. The client certificate is presented
+// for mutual TLS if certFile and keyFile are specified.
+func NewClientTLSConfig(caFile, certFile, keyFile string, insecureSkipVerify bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if len(caFile) > 0 {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if len(certFile) > 0 || len(keyFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls client certificate, %s", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	buff, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ca file, %s", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(buff) {
+		return nil, fmt.Errorf("no valid certificate found in %s", file)
+	}
+
+	return pool, nil
+}
+
+// DialHTTPWithTLS creates a new RPC client that connects to an RPC server over HTTPS
+// with the specified TLS config and an optional bearer token.
+func DialHTTPWithTLS(endpoint string, tlsConfig *tls.Config, token string) (*Client, error) {
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+
+	return DialHTTPWithToken(endpoint, client, token)
+}
+
+// DialWebsocketWithTLS creates a new RPC client that communicates with a JSON-RPC server over
+// websocket with the specified TLS config (for wss endpoints) and an optional bearer token.
+func DialWebsocketWithTLS(ctx context.Context, endpoint, origin string, tlsConfig *tls.Config, token string) (*Client, error) {
+	config, err := newWebsocketConfig(endpoint, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	config.TlsConfig = tlsConfig
+	if len(token) > 0 {
+		config.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return newClient(ctx, func(ctx context.Context) (net.Conn, error) {
+		return wsDialContext(ctx, config)
+	})
+}
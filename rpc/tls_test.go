@@ -0,0 +1,165 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package rpc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCert writes a self-signed certificate for 127.0.0.1 and its key into dir.
+func writeTestCert(t *testing.T, dir string, name string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	if err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return certFile, keyFile
+}
+
+func startTLSServer(t *testing.T, config TLSConfig, ws bool) net.Listener {
+	tlsConfig, err := config.ServerConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := newTestServer("service", new(Service))
+	if ws {
+		go NewWSServer([]string{"*"}, server).Serve(listener)
+	} else {
+		go NewHTTPServer(nil, nil, server).Serve(listener)
+	}
+
+	return listener
+}
+
+func TestTLSHTTP(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpctls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	serverCert, serverKey := writeTestCert(t, dir, "server")
+	listener := startTLSServer(t, TLSConfig{CertFile: serverCert, KeyFile: serverKey}, false)
+	defer listener.Close()
+
+	tlsConfig, err := NewClientTLSConfig(serverCert, "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := DialHTTPWithTLS("https://"+listener.Addr().String(), tlsConfig, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result string
+	if err = client.Call(&result, "service_rets"); err != nil {
+		t.Fatal(err)
+	}
+
+	// client without the server CA should fail
+	client, err = DialHTTPWithTLS("https://"+listener.Addr().String(), &tls.Config{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = client.Call(&result, "service_rets"); err == nil {
+		t.Fatal("untrusted server certificate should be rejected")
+	}
+}
+
+func TestMutualTLSWebsocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpctls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	serverCert, serverKey := writeTestCert(t, dir, "server")
+	clientCert, clientKey := writeTestCert(t, dir, "client")
+	listener := startTLSServer(t, TLSConfig{CertFile: serverCert, KeyFile: serverKey, ClientCAFile: clientCert}, true)
+	defer listener.Close()
+
+	endpoint := "wss://" + listener.Addr().String()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tlsConfig, err := NewClientTLSConfig(serverCert, "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = DialWebsocketWithTLS(ctx, endpoint, "", tlsConfig, ""); err == nil {
+		t.Fatal("client without certificate should be rejected")
+	}
+
+	tlsConfig, err = NewClientTLSConfig(serverCert, clientCert, clientKey, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := DialWebsocketWithTLS(ctx, endpoint, "", tlsConfig, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var result string
+	if err = client.Call(&result, "service_rets"); err != nil {
+		t.Fatal(err)
+	}
+}
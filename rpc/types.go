@@ -75,6 +75,8 @@ type Server struct {
 	codecsMu          sync.Mutex
 	codecs            *set.Set
 	minerRemoteRequst bool
+
+	access *accessControl // access control of the requests, nil means no restriction
 }
 
 // rpcRequest represents a raw incoming RPC request
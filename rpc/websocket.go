@@ -36,14 +36,51 @@ import (
 // allowedOrigins should be a comma-separated list of allowed origin URLs.
 // To allow connections with any origin, pass "*".
 func (srv *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
+	validator := wsHandshakeValidator(allowedOrigins)
 	return websocket.Server{
-		Handshake: wsHandshakeValidator(allowedOrigins),
+		Handshake: func(cfg *websocket.Config, req *http.Request) error {
+			if err := validator(cfg, req); err != nil {
+				return err
+			}
+			if srv.access != nil {
+				return srv.access.authorize(req)
+			}
+			return nil
+		},
 		Handler: func(conn *websocket.Conn) {
-			srv.ServeCodec(NewJSONCodec(conn), OptionMethodInvocation|OptionSubscriptions)
+			var codec ServerCodec = NewJSONCodec(conn)
+			if srv.access != nil && srv.access.limiter != nil {
+				codec = &rateLimitedCodec{codec, srv.access, clientIP(conn.Request())}
+			}
+			srv.ServeCodec(codec, OptionMethodInvocation|OptionSubscriptions)
 		},
 	}
 }
 
+// rateLimitedCodec rejects the requests of a websocket or tcp connection
+// once the client exceeds its rate limit.
+type rateLimitedCodec struct {
+	ServerCodec
+	access *accessControl
+	client string
+}
+
+// ReadRequestHeaders reads the next requests and marks them as failed if the client is rate limited.
+func (c *rateLimitedCodec) ReadRequestHeaders() ([]rpcRequest, bool, Error) {
+	reqs, batch, err := c.ServerCodec.ReadRequestHeaders()
+	if err != nil {
+		return reqs, batch, err
+	}
+
+	for i := range reqs {
+		if reqs[i].err == nil && !c.access.allowClient(c.client) {
+			reqs[i].err = &rateLimitedError{}
+		}
+	}
+
+	return reqs, batch, nil
+}
+
 // NewWSServer creates a new websocket RPC server around an API provider.
 //
 // Deprecated: use Server.WebsocketHandler
@@ -92,6 +129,18 @@ func wsHandshakeValidator(allowedOrigins []string) func(*websocket.Config, *http
 // The context is used for the initial connection establishment. It does not
 // affect subsequent interactions with the client.
 func DialWebsocket(ctx context.Context, endpoint, origin string) (*Client, error) {
+	config, err := newWebsocketConfig(endpoint, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(ctx, func(ctx context.Context) (net.Conn, error) {
+		return wsDialContext(ctx, config)
+	})
+}
+
+// newWebsocketConfig creates the websocket client config, the origin is the local hostname if not specified.
+func newWebsocketConfig(endpoint, origin string) (*websocket.Config, error) {
 	if origin == "" {
 		var err error
 		if origin, err = os.Hostname(); err != nil {
@@ -103,14 +152,8 @@ func DialWebsocket(ctx context.Context, endpoint, origin string) (*Client, error
 			origin = "http://" + strings.ToLower(origin)
 		}
 	}
-	config, err := websocket.NewConfig(endpoint, origin)
-	if err != nil {
-		return nil, err
-	}
 
-	return newClient(ctx, func(ctx context.Context) (net.Conn, error) {
-		return wsDialContext(ctx, config)
-	})
+	return websocket.NewConfig(endpoint, origin)
 }
 
 func wsDialContext(ctx context.Context, config *websocket.Config) (*websocket.Conn, error) {
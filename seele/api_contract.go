@@ -0,0 +1,32 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package seele
+
+import (
+	"github.com/seeleteam/go-seele/common"
+)
+
+// PrivateContractAPI provides the private seele APIs to verify the contract sources,
+// which are not public since the solidity compilation is expensive.
+type PrivateContractAPI struct {
+	s *SeeleService
+}
+
+// NewPrivateContractAPI creates a new PrivateContractAPI object for rpc service.
+func NewPrivateContractAPI(s *SeeleService) *PrivateContractAPI {
+	return &PrivateContractAPI{s}
+}
+
+// VerifyContract compiles the solidity source with the compiler settings, and persists
+// the verified source record if the bytecode matches the deployed code at the address.
+func (api *PrivateContractAPI) VerifyContract(request ContractVerifyRequest) (*VerifiedContract, error) {
+	return api.s.contractVerifier.Verify(&request)
+}
+
+// GetVerifiedContract returns the verified source of the contract at the address, or nil if not verified.
+func (api *PublicSeeleAPI) GetVerifiedContract(address common.Address) (*VerifiedContract, error) {
+	return api.s.contractVerifier.Get(address)
+}
@@ -13,6 +13,8 @@ import (
 	"runtime/pprof"
 
 	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/hexutil"
+	"github.com/seeleteam/go-seele/core/tracers"
 	"github.com/seeleteam/go-seele/core/types"
 )
 
@@ -120,3 +122,88 @@ func (api *PrivateDebugAPI) DumpHeap(fileName string, gcBeforeDump bool) (string
 func (api *PrivateDebugAPI) GetPendingDebts() ([]*types.Debt, error) {
 	return api.s.DebtPool().GetDebts(false, true), nil
 }
+
+// TraceTransaction re-executes the packed tx with the tracer specified by the config, and returns
+// the trace result, e.g. the opcode logs by default, the call tree or the prestate of the accounts.
+func (api *PrivateDebugAPI) TraceTransaction(txHash string, config *tracers.TraceConfig) (interface{}, error) {
+	hashByte, err := hexutil.HexToBytes(txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	store := api.s.chain.GetStore()
+	txIndex, err := store.GetTxIndex(common.BytesToHash(hashByte))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tx index, %s", err)
+	}
+
+	block, err := store.GetBlock(txIndex.BlockHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %v, %s", txIndex.BlockHash, err)
+	}
+
+	if txIndex.Index == 0 {
+		return nil, fmt.Errorf("reward tx could not be traced")
+	}
+
+	statedb, err := api.s.chain.StateAtTransaction(block, int(txIndex.Index))
+	if err != nil {
+		return nil, err
+	}
+
+	tracer, err := tracers.New(config, statedb)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := block.Transactions[txIndex.Index]
+	receipt, err := api.s.chain.ApplyTransactionWithTracer(tx, int(txIndex.Index), block.Header.Creator, statedb, block.Header, tracer)
+	if err != nil {
+		return nil, err
+	}
+
+	return tracer.GetResult(receipt)
+}
+
+// TraceBlock re-executes the txs of the block at the specified height with the tracer specified
+// by the config, and returns the trace results of the txs except the reward tx.
+// When height is -1 the chain head is traced.
+func (api *PrivateDebugAPI) TraceBlock(height int64, config *tracers.TraceConfig) ([]*tracers.TxTraceResult, error) {
+	block, err := getBlock(api.s.chain, height)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*tracers.TxTraceResult, 0, len(block.Transactions))
+	if len(block.Transactions) <= 1 {
+		return results, nil
+	}
+
+	statedb, err := api.s.chain.StateAtTransaction(block, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 1; i < len(block.Transactions); i++ {
+		tx := block.Transactions[i]
+		result := &tracers.TxTraceResult{TxHash: tx.Hash}
+		results = append(results, result)
+
+		tracer, err := tracers.New(config, statedb)
+		if err != nil {
+			return nil, err
+		}
+
+		receipt, err := api.s.chain.ApplyTransactionWithTracer(tx, i, block.Header.Creator, statedb, block.Header, tracer)
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+
+		if result.Result, err = tracer.GetResult(receipt); err != nil {
+			result.Error = err.Error()
+		}
+	}
+
+	return results, nil
+}
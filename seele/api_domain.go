@@ -0,0 +1,47 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package seele
+
+import (
+	"strings"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/state"
+)
+
+// ResolveDomainName returns the record of the domain name at the current block,
+// and the name could be with or without the domain name suffix, e.g. seele-fan.seele
+func (api *PublicSeeleAPI) ResolveDomainName(name string) (*system.DomainNameRecord, error) {
+	statedb, now, err := api.currentDomainNameState()
+	if err != nil {
+		return nil, err
+	}
+
+	return system.ResolveDomainName(statedb, strings.TrimSuffix(name, system.DomainNameSuffix), now)
+}
+
+// LookupDomainName returns the domain name of the address at the current block, which
+// is set by the address itself and still resolves to the address.
+func (api *PublicSeeleAPI) LookupDomainName(address common.Address) (string, error) {
+	statedb, now, err := api.currentDomainNameState()
+	if err != nil {
+		return "", err
+	}
+
+	return system.LookupDomainName(statedb, address, now)
+}
+
+// currentDomainNameState returns the statedb and the timestamp of the current block
+func (api *PublicSeeleAPI) currentDomainNameState() (*state.Statedb, int64, error) {
+	block := api.s.chain.CurrentBlock()
+	statedb, err := state.NewStatedb(block.Header.StateHash, api.s.accountStateDB)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return statedb, block.Header.CreateTimestamp.Int64(), nil
+}
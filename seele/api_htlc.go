@@ -0,0 +1,23 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package seele
+
+import (
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/state"
+)
+
+// GetHTLCs returns the HTLCs neither withdrawn nor refunded at the current block that
+// the address sends or receives, so that the lock hashes are not required to find them.
+func (api *PublicSeeleAPI) GetHTLCs(address common.Address) (*system.ActiveHTLCs, error) {
+	statedb, err := state.NewStatedb(api.s.chain.CurrentBlock().Header.StateHash, api.s.accountStateDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return system.GetActiveHTLCs(statedb, address)
+}
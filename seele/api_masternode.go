@@ -0,0 +1,21 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package seele
+
+import (
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/state"
+)
+
+// GetMasternodes returns all the masternodes with their liveness and uptime stats at the current block
+func (api *PublicSeeleAPI) GetMasternodes() ([]*system.MasternodeStatus, error) {
+	statedb, err := state.NewStatedb(api.s.chain.CurrentBlock().Header.StateHash, api.s.accountStateDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return system.GetMasternodes(statedb)
+}
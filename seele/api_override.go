@@ -0,0 +1,79 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package seele
+
+import (
+	"math/big"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/core/types"
+)
+
+// AccountOverride is the account fields to override before a call is executed, nil fields are not overridden.
+type AccountOverride struct {
+	Balance *big.Int                    `json:"balance"`
+	Nonce   *uint64                     `json:"nonce"`
+	Code    *common.Bytes               `json:"code"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+// StateOverride is the account overrides of a call, which are applied to a throwaway copy of the statedb.
+type StateOverride map[common.Address]AccountOverride
+
+// Apply overrides the accounts in the statedb.
+func (override StateOverride) Apply(statedb *state.Statedb) {
+	for addr, account := range override {
+		if !statedb.Exist(addr) {
+			statedb.CreateAccount(addr)
+		}
+
+		if account.Balance != nil {
+			statedb.SetBalance(addr, account.Balance)
+		}
+
+		if account.Nonce != nil {
+			statedb.SetNonce(addr, *account.Nonce)
+		}
+
+		if account.Code != nil {
+			statedb.SetCode(addr, *account.Code)
+		}
+
+		for key, value := range account.Storage {
+			statedb.SetData(addr, key, value.Bytes())
+		}
+	}
+}
+
+// BlockOverrides is the block context fields to override before a call is executed, nil fields are not overridden.
+type BlockOverrides struct {
+	Height    *uint64         `json:"height"`
+	Timestamp *big.Int        `json:"timestamp"`
+	Creator   *common.Address `json:"creator"`
+}
+
+// Apply returns a copy of the header with the fields overridden.
+func (override *BlockOverrides) Apply(header *types.BlockHeader) *types.BlockHeader {
+	if override == nil {
+		return header
+	}
+
+	header = header.Clone()
+	if override.Height != nil {
+		header.Height = *override.Height
+	}
+
+	if override.Timestamp != nil {
+		header.CreateTimestamp = new(big.Int).Set(override.Timestamp)
+	}
+
+	if override.Creator != nil {
+		header.Creator = *override.Creator
+	}
+
+	return header
+}
@@ -0,0 +1,69 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package seele
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/core/state"
+	"github.com/seeleteam/go-seele/core/types"
+	"github.com/seeleteam/go-seele/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StateOverride_Apply(t *testing.T) {
+	statedb := state.NewEmptyStatedb(nil)
+	existing, created := *crypto.MustGenerateRandomAddress(), *crypto.MustGenerateRandomAddress()
+	statedb.CreateAccount(existing)
+	statedb.SetBalance(existing, big.NewInt(100))
+	statedb.SetNonce(existing, 3)
+
+	nonce := uint64(8)
+	code := common.Bytes{1, 2, 3}
+	override := StateOverride{
+		existing: AccountOverride{Nonce: &nonce},
+		created: AccountOverride{
+			Balance: big.NewInt(5),
+			Code:    &code,
+			Storage: map[common.Hash]common.Hash{common.EmptyHash: common.BigToHash(big.NewInt(9))},
+		},
+	}
+	override.Apply(statedb)
+
+	// nil fields are not overridden
+	assert.Equal(t, statedb.GetBalance(existing), big.NewInt(100))
+	assert.Equal(t, statedb.GetNonce(existing), nonce)
+
+	assert.Equal(t, statedb.Exist(created), true)
+	assert.Equal(t, statedb.GetBalance(created), big.NewInt(5))
+	assert.Equal(t, statedb.GetCode(created), []byte(code))
+	assert.Equal(t, common.BytesToHash(statedb.GetData(created, common.EmptyHash)), common.BigToHash(big.NewInt(9)))
+}
+
+func Test_BlockOverrides_Apply(t *testing.T) {
+	header := &types.BlockHeader{
+		Creator:         *crypto.MustGenerateRandomAddress(),
+		Height:          10,
+		CreateTimestamp: big.NewInt(100),
+		Difficulty:      big.NewInt(1),
+	}
+
+	var overrides *BlockOverrides
+	assert.Equal(t, overrides.Apply(header), header)
+
+	height, creator := uint64(20), *crypto.MustGenerateRandomAddress()
+	overrides = &BlockOverrides{Height: &height, Timestamp: big.NewInt(200), Creator: &creator}
+	result := overrides.Apply(header)
+	assert.Equal(t, result.Height, height)
+	assert.Equal(t, result.CreateTimestamp, big.NewInt(200))
+	assert.Equal(t, result.Creator, creator)
+
+	// the original header is not changed
+	assert.Equal(t, header.Height, uint64(10))
+	assert.Equal(t, header.CreateTimestamp, big.NewInt(100))
+}
@@ -29,14 +29,18 @@ type PublicSeeleAPI struct {
 
 const maxSizeLimit = 64
 
+// maxMultiCallSize is the maximum number of calls in a multi-call
+const maxMultiCallSize = 1024
+
 // NewPublicSeeleAPI creates a new PublicSeeleAPI object for rpc service.
 func NewPublicSeeleAPI(s *SeeleService) *PublicSeeleAPI {
 	return &PublicSeeleAPI{s}
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the
-// given transaction against the current pending block.
-func (api *PublicSeeleAPI) EstimateGas(tx *types.Transaction) (uint64, error) {
+// given transaction against the current pending block. The optional state and
+// block overrides are applied before the transaction is executed.
+func (api *PublicSeeleAPI) EstimateGas(tx *types.Transaction, stateOverride *StateOverride, blockOverrides *BlockOverrides) (uint64, error) {
 	// Get the block by block height, if the height is less than zero, get the current block.
 	block, err := getBlock(api.s.chain, -1)
 	if err != nil {
@@ -49,9 +53,13 @@ func (api *PublicSeeleAPI) EstimateGas(tx *types.Transaction) (uint64, error) {
 		return 0, err
 	}
 
+	if stateOverride != nil {
+		stateOverride.Apply(statedb)
+	}
+
 	coinbase := api.s.miner.GetCoinbase()
 	// Get the transaction receipt, and the fee give to the miner coinbase
-	receipt, err := api.s.chain.ApplyTransaction(tx, 0, coinbase, statedb, block.Header)
+	receipt, err := api.s.chain.ApplyTransaction(tx, 0, coinbase, statedb, blockOverrides.Apply(block.Header))
 	if err != nil {
 		return 0, err
 	}
@@ -96,7 +104,8 @@ func (api *PublicSeeleAPI) GetInfo() (api2.GetMinerInfo, error) {
 
 // Call is to execute a given transaction on a statedb of a given block height.
 // It does not affect this statedb and blockchain and is useful for executing and retrieve values.
-func (api *PublicSeeleAPI) Call(contract, payload string, height int64) (map[string]interface{}, error) {
+// The optional state and block overrides are applied to simulate the call against a different state.
+func (api *PublicSeeleAPI) Call(contract, payload string, height int64, stateOverride *StateOverride, blockOverrides *BlockOverrides) (map[string]interface{}, error) {
 	contractAddr, err := common.HexToAddress(contract)
 	if err != nil {
 		return nil, fmt.Errorf("invalid contract address: %s", err)
@@ -124,27 +133,110 @@ func (api *PublicSeeleAPI) Call(contract, payload string, height int64) (map[str
 	statedb.CreateAccount(*from)
 	statedb.SetBalance(*from, common.SeeleToFan)
 
-	amount, price, nonce := big.NewInt(0), big.NewInt(1), uint64(1)
+	if stateOverride != nil {
+		stateOverride.Apply(statedb)
+	}
+
+	receipt, err := api.applyCall(statedb, *from, contractAddr, msg, uint64(1), blockOverrides.Apply(block.Header))
+	if err != nil {
+		return nil, err
+	}
+
+	// Format the receipt
+	result, err := api2.PrintableReceipt(receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// applyCall executes a read-only contract call from the specified account on the statedb.
+func (api *PublicSeeleAPI) applyCall(statedb *state.Statedb, from, contract common.Address, msg []byte, nonce uint64, header *types.BlockHeader) (*types.Receipt, error) {
+	amount, price := big.NewInt(0), big.NewInt(1)
 	// gasLimit = balance / fee
 	gasLimit := common.SeeleToFan.Uint64()
-	tx, err := types.NewMessageTransaction(*from, contractAddr, amount, price, gasLimit, nonce, msg)
+	tx, err := types.NewMessageTransaction(from, contract, amount, price, gasLimit, nonce, msg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transaction: %s", err)
 	}
 
 	// Get the transaction receipt, and the fee give to the miner coinbase
-	receipt, err := api.s.chain.ApplyTransaction(tx, 0, coinbase, statedb, block.Header)
+	return api.s.chain.ApplyTransaction(tx, 0, api.s.miner.GetCoinbase(), statedb, header)
+}
+
+// CallArgs is a read-only contract call of the multi-call
+type CallArgs struct {
+	To      common.Address `json:"to"`
+	Payload common.Bytes   `json:"payload"`
+}
+
+// MultiCallResult is the result of a call in the multi-call
+type MultiCallResult struct {
+	Result  string       `json:"result"`
+	Failed  bool         `json:"failed"`
+	UsedGas uint64       `json:"usedGas"`
+	Logs    []*types.Log `json:"logs,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// MultiCall executes the read-only contract calls sequentially on the statedb of the given block height,
+// when height is -1 the chain head is used. Each call is executed against the original state unless cumulative
+// is true, in which case the later calls see the state changes of the earlier calls. The chain is never affected.
+func (api *PublicSeeleAPI) MultiCall(height int64, calls []CallArgs, cumulative *bool) ([]*MultiCallResult, error) {
+	if len(calls) > maxMultiCallSize {
+		return nil, fmt.Errorf("too many calls %d, the maximum is %d", len(calls), maxMultiCallSize)
+	}
+
+	block, err := getBlock(api.s.chain, height)
 	if err != nil {
 		return nil, err
 	}
 
-	// Format the receipt
-	result, err := api2.PrintableReceipt(receipt)
+	coinbase := api.s.miner.GetCoinbase()
+	from := *crypto.MustGenerateShardAddress(coinbase.Shard())
+
+	statedb, err := state.NewStatedb(block.Header.StateHash, api.s.accountStateDB)
 	if err != nil {
 		return nil, err
 	}
 
-	return result, nil
+	statedb.CreateAccount(from)
+
+	// the state changes are kept in memory if not cumulative,
+	// so that each call is reverted to the original state.
+	reverted := cumulative == nil || !*cumulative
+	if reverted {
+		statedb.RetainJournal()
+	}
+
+	snapshot := statedb.Snapshot()
+	results := make([]*MultiCallResult, 0, len(calls))
+	for _, call := range calls {
+		if reverted {
+			statedb.RevertToSnapshot(snapshot)
+		}
+
+		// top up the sender to pay the fee of every call
+		statedb.SetBalance(from, common.SeeleToFan)
+
+		result := &MultiCallResult{}
+		receipt, err := api.applyCall(statedb, from, call.To, call.Payload, statedb.GetNonce(from), block.Header)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Failed, result.UsedGas, result.Logs = receipt.Failed, receipt.UsedGas, receipt.Logs
+			if receipt.Failed {
+				result.Error = string(receipt.Result)
+			} else {
+				result.Result = hexutil.BytesToHex(receipt.Result)
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
 }
 
 // GetLogs Get the logs that satisfies the condition in the block by height and filter
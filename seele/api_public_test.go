@@ -182,7 +182,7 @@ func Test_Call(t *testing.T) {
 
 	// Verify the result = 5
 	result := make(map[string]interface{})
-	result, err = api.Call(contractAddress.Hex(), payload, -1)
+	result, err = api.Call(contractAddress.Hex(), payload, -1, nil, nil)
 	assert.Equal(t, err, nil)
 	assert.Equal(t, result["result"], "0x0000000000000000000000000000000000000000000000000000000000000005")
 
@@ -197,24 +197,87 @@ func Test_Call(t *testing.T) {
 	_ = sendTx(t, api, statedbCur, callContractTx)
 
 	// Verify the result = 23
-	result, err = api.Call(contractAddress.Hex(), payload, -1)
+	result, err = api.Call(contractAddress.Hex(), payload, -1, nil, nil)
 	assert.Equal(t, err, nil)
 	assert.Equal(t, result["result"], "0x0000000000000000000000000000000000000000000000000000000000000017")
 
 	// Verify the history result = 5
 	height, err := api2.NewPublicSeeleAPI(NewSeeleBackend(api.s)).GetBlockHeight()
 	assert.Equal(t, err, nil)
-	result, err = api.Call(contractAddress.Hex(), payload, int64(height-1))
+	result, err = api.Call(contractAddress.Hex(), payload, int64(height-1), nil, nil)
 	assert.Equal(t, err, nil)
 	assert.Equal(t, result["result"], "0x0000000000000000000000000000000000000000000000000000000000000005")
 
 	// Verify the invalid contractAddress and payload
-	result, err = api.Call("contractAddress.Hex()", payload, -1)
+	result, err = api.Call("contractAddress.Hex()", payload, -1, nil, nil)
 	assert.Equal(t, err == nil, false)
-	result, err = api.Call(contractAddress.Hex(), "payload", -1)
+	result, err = api.Call(contractAddress.Hex(), "payload", -1, nil, nil)
 	assert.Equal(t, err == nil, false)
 }
 
+func Test_MultiCall(t *testing.T) {
+	dbPath := filepath.Join(common.GetTempFolder(), ".MultiCall")
+	api := newTestAPI(t, dbPath)
+	defer func() {
+		api.s.Stop()
+		os.RemoveAll(dbPath)
+	}()
+
+	// Create a contract/solidity/simple_storage.sol contract, get = 5
+	bytecode, _ := hexutil.HexToBytes("0x608060405234801561001057600080fd5b50600560008190555060df806100276000396000f3006080604052600436106049576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806360fe47b114604e5780636d4ce63c146078575b600080fd5b348015605957600080fd5b5060766004803603810190808035906020019092919050505060a0565b005b348015608357600080fd5b50608a60aa565b6040518082815260200191505060405180910390f35b8060008190555050565b600080549050905600a165627a7a723058207f6dc43a0d648e9f5a0cad5071cde46657de72eb87ab4cded53a7f1090f51e6d0029")
+	statedb, _ := api.s.chain.GetCurrentState()
+	from := getFromAddress(statedb)
+	createContractTx, _ := types.NewContractTransaction(from, big.NewInt(0), big.NewInt(1), 500000, 0, bytecode)
+	contractAddress := common.BytesToAddress(sendTx(t, api, statedb, createContractTx))
+
+	get := CallArgs{To: contractAddress, Payload: hexutil.MustHexToBytes("0x6d4ce63c")}
+	set := CallArgs{To: contractAddress, Payload: hexutil.MustHexToBytes("0x60fe47b10000000000000000000000000000000000000000000000000000000000000017")}
+
+	value5 := "0x0000000000000000000000000000000000000000000000000000000000000005"
+	value23 := "0x0000000000000000000000000000000000000000000000000000000000000017"
+	assertResults := func(results []*MultiCallResult, expected []string) {
+		assert.Equal(t, len(results), len(expected))
+		for i, result := range results {
+			assert.Equal(t, result.Failed, false)
+			assert.Equal(t, result.Error, "")
+			assert.Equal(t, result.UsedGas > 0, true)
+			assert.Equal(t, result.Result, expected[i])
+		}
+	}
+
+	// the calls are executed against the original state by default
+	results, err := api.MultiCall(-1, []CallArgs{get, set, get, set, get}, nil)
+	assert.Equal(t, err, nil)
+	assertResults(results, []string{value5, "0x", value5, "0x", value5})
+
+	// the later calls see the state changes of the earlier calls
+	cumulative := true
+	results, err = api.MultiCall(-1, []CallArgs{get, set, get, set, get}, &cumulative)
+	assert.Equal(t, err, nil)
+	assertResults(results, []string{value5, "0x", value23, "0x", value23})
+
+	// explicitly not cumulative
+	cumulative = false
+	results, err = api.MultiCall(-1, []CallArgs{set, get}, &cumulative)
+	assert.Equal(t, err, nil)
+	assertResults(results, []string{"0x", value5})
+
+	// the chain is not affected
+	results, err = api.MultiCall(-1, []CallArgs{get}, nil)
+	assert.Equal(t, err, nil)
+	assertResults(results, []string{value5})
+
+	// the failed call does not abort the others
+	results, err = api.MultiCall(-1, []CallArgs{{To: contractAddress, Payload: []byte{1, 2, 3, 4}}, get}, nil)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, results[0].Failed, true)
+	assert.Equal(t, len(results[0].Error) > 0, true)
+	assert.Equal(t, results[1].Result, value5)
+
+	_, err = api.MultiCall(-1, make([]CallArgs, maxMultiCallSize+1), nil)
+	assert.Equal(t, err != nil, true)
+}
+
 func Test_EstimateGas(t *testing.T) {
 	dbPath := filepath.Join(common.GetTempFolder(), ".EstimateGas")
 	api := newTestAPI(t, dbPath)
@@ -239,7 +302,7 @@ func Test_EstimateGas(t *testing.T) {
 	to1 := crypto.MustGenerateShardAddress(from.Shard())
 	transferCSTx, err1 := types.NewTransaction(from, *to1, big.NewInt(1), big.NewInt(1), statedb.GetNonce(from))
 	assert.NoError(t, err1)
-	estimateGas1, err2 := api.EstimateGas(transferCSTx)
+	estimateGas1, err2 := api.EstimateGas(transferCSTx, nil, nil)
 	assert.NoError(t, err2)
 	assert.Equal(t, estimateGas1, types.TransferAmountIntrinsicGas)
 
@@ -250,7 +313,7 @@ func Test_EstimateGas(t *testing.T) {
 	}
 	transferDSTx, err3 := types.NewTransaction(from, *to2, big.NewInt(1), big.NewInt(1), statedb.GetNonce(from))
 	assert.NoError(t, err3)
-	estimateGas2, err4 := api.EstimateGas(transferDSTx)
+	estimateGas2, err4 := api.EstimateGas(transferDSTx, nil, nil)
 	assert.NoError(t, err4)
 	assert.Equal(t, estimateGas2, types.CrossShardTotalGas)
 
@@ -259,7 +322,7 @@ func Test_EstimateGas(t *testing.T) {
 	assert.NoError(t, err5)
 	createContractTx, err6 := types.NewContractTransaction(from, big.NewInt(0), big.NewInt(1), 500000, 0, bytecode)
 	assert.NoError(t, err6)
-	estimateGas3, err7 := api.EstimateGas(createContractTx)
+	estimateGas3, err7 := api.EstimateGas(createContractTx, nil, nil)
 	assert.NoError(t, err7)
 	assert.NotZero(t, estimateGas3)
 
@@ -268,7 +331,7 @@ func Test_EstimateGas(t *testing.T) {
 	assert.NoError(t, err8)
 	callContractTx, err9 := types.NewMessageTransaction(from, createContractTx.Data.To, big.NewInt(0), big.NewInt(1), 500000, 0, bytecode1)
 	assert.NoError(t, err9)
-	estimateGas4, err10 := api.EstimateGas(callContractTx)
+	estimateGas4, err10 := api.EstimateGas(callContractTx, nil, nil)
 	assert.NoError(t, err10)
 	assert.NotZero(t, estimateGas4)
 }
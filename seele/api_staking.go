@@ -0,0 +1,38 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package seele
+
+import (
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/state"
+)
+
+// StakingCandidates is the verifier candidates by stake and the verifiers elected from them
+type StakingCandidates struct {
+	Candidates []*system.StakingCandidate
+	Elected    []common.Address
+}
+
+// GetStakingCandidates returns the verifier candidates sorted by stake, and the verifiers elected at the next epoch
+func (api *PublicSeeleAPI) GetStakingCandidates() (*StakingCandidates, error) {
+	statedb, err := state.NewStatedb(api.s.chain.CurrentBlock().Header.StateHash, api.s.accountStateDB)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := system.GetStakingCandidates(statedb)
+	if err != nil {
+		return nil, err
+	}
+
+	elected, err := system.ElectVerifiers(statedb)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StakingCandidates{candidates, elected}, nil
+}
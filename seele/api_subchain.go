@@ -0,0 +1,21 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package seele
+
+import (
+	"github.com/seeleteam/go-seele/contract/system"
+	"github.com/seeleteam/go-seele/core/state"
+)
+
+// GetSubChains returns all the registered sub-chains with their status at the current block
+func (api *PublicSeeleAPI) GetSubChains() ([]*system.SubChainInfo, error) {
+	statedb, err := state.NewStatedb(api.s.chain.CurrentBlock().Header.StateHash, api.s.accountStateDB)
+	if err != nil {
+		return nil, err
+	}
+
+	return system.ListSubChains(statedb)
+}
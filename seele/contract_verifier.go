@@ -0,0 +1,184 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package seele
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/compiler"
+	"github.com/seeleteam/go-seele/common/errors"
+	"github.com/seeleteam/go-seele/core"
+	"github.com/seeleteam/go-seele/database"
+	"github.com/seeleteam/go-seele/log"
+)
+
+// keyPrefixVerifiedContract is the key prefix of the verified contracts, key = prefix + address
+var keyPrefixVerifiedContract = []byte("vc")
+
+var (
+	errContractNotFound = errors.New("no contract code at the address")
+	errBytecodeMismatch = errors.New("the compiled bytecode does not match the deployed code")
+	errEmptySource      = errors.New("empty solidity source")
+)
+
+// ContractVerifyRequest is the solidity source and compiler settings to verify a deployed contract.
+type ContractVerifyRequest struct {
+	Address common.Address `json:"address"`
+
+	// Name is the contract name in the source, if empty, any contract in the source may match
+	Name string `json:"name"`
+
+	Source   string                   `json:"source"`
+	Compiler compiler.SolidityOptions `json:"compiler"`
+}
+
+// VerifiedContract is the verified source record of a deployed contract.
+type VerifiedContract struct {
+	Address  common.Address           `json:"address"`
+	Name     string                   `json:"name"`
+	Source   string                   `json:"source"`
+	Compiler compiler.SolidityOptions `json:"compiler"`
+	ABI      string                   `json:"abi"`
+
+	// CodeHash is the hash of the deployed code
+	CodeHash common.Hash `json:"codeHash"`
+
+	// Height is the chain height when the contract is verified
+	Height uint64 `json:"height"`
+
+	// Timestamp is the unix time in seconds when the contract is verified
+	Timestamp int64 `json:"timestamp"`
+}
+
+// ContractVerifier compiles the solidity source and compares the bytecode with
+// the deployed code, and persists the verified source records.
+type ContractVerifier struct {
+	chain *core.Blockchain
+	db    database.Database
+	log   *log.SeeleLog
+
+	// compile compiles the solidity source, which could be replaced in tests
+	compile func(source string, options compiler.SolidityOptions) (*compiler.SolidityOutput, error)
+
+	lock sync.Mutex // only one contract is compiled at a time
+}
+
+// NewContractVerifier creates a contract verifier persisting the records in the db.
+func NewContractVerifier(chain *core.Blockchain, db database.Database) *ContractVerifier {
+	return &ContractVerifier{
+		chain:   chain,
+		db:      db,
+		log:     log.GetLogger("contract_verifier"),
+		compile: compiler.CompileSolidity,
+	}
+}
+
+// Verify compiles the source, compares the runtime bytecode without metadata with the
+// deployed code at the current block, and persists the record if they match.
+func (v *ContractVerifier) Verify(request *ContractVerifyRequest) (*VerifiedContract, error) {
+	if len(request.Source) == 0 {
+		return nil, errEmptySource
+	}
+
+	block, statedb, err := v.chain.GetCurrentInfo()
+	if err != nil {
+		return nil, errors.NewStackedError(err, "failed to get the current state")
+	}
+
+	code := statedb.GetCode(request.Address)
+	if len(code) == 0 {
+		return nil, errContractNotFound
+	}
+
+	v.lock.Lock()
+	output, err := v.compile(request.Source, request.Compiler)
+	v.lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	contract := matchContract(output.Contracts, request.Name, compiler.StripMetadata(code))
+	if contract == nil {
+		return nil, errBytecodeMismatch
+	}
+
+	options := request.Compiler
+	options.Version = output.Version
+
+	verified := &VerifiedContract{
+		Address:   request.Address,
+		Name:      contract.Name,
+		Source:    request.Source,
+		Compiler:  options,
+		ABI:       contract.ABI,
+		CodeHash:  statedb.GetCodeHash(request.Address),
+		Height:    block.Header.Height,
+		Timestamp: time.Now().Unix(),
+	}
+
+	encoded, err := json.Marshal(verified)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = v.db.Put(verifiedContractKey(request.Address), encoded); err != nil {
+		return nil, errors.NewStackedError(err, "failed to persist the verified contract")
+	}
+
+	v.log.Info("verified contract %s at %s", contract.Name, request.Address.Hex())
+
+	return verified, nil
+}
+
+// matchContract returns the contract whose runtime bytecode matches the code without metadata.
+// The contracts are checked in the order of names, so the result is deterministic.
+func matchContract(contracts map[string]*compiler.Contract, name string, code []byte) *compiler.Contract {
+	var names []string
+	for n := range contracts {
+		if len(name) == 0 || n == name {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		if bytes.Equal(compiler.StripMetadata(contracts[n].RuntimeCode), code) {
+			return contracts[n]
+		}
+	}
+
+	return nil
+}
+
+// Get returns the verified contract at the address, or nil if not verified.
+func (v *ContractVerifier) Get(address common.Address) (*VerifiedContract, error) {
+	key := verifiedContractKey(address)
+	found, err := v.db.Has(key)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	encoded, err := v.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	verified := new(VerifiedContract)
+	if err = json.Unmarshal(encoded, verified); err != nil {
+		return nil, err
+	}
+
+	return verified, nil
+}
+
+func verifiedContractKey(address common.Address) []byte {
+	return append(append([]byte{}, keyPrefixVerifiedContract...), address.Bytes()...)
+}
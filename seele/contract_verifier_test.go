@@ -0,0 +1,65 @@
+/**
+*  @file
+*  @copyright defined in go-seele/LICENSE
+ */
+
+package seele
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/seeleteam/go-seele/common"
+	"github.com/seeleteam/go-seele/common/compiler"
+	"github.com/seeleteam/go-seele/database/leveldb"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ContractVerifier_MatchContract(t *testing.T) {
+	metadata := []byte{0xa1, 0x65, 'b', 'z', 'z', 'r', '0', 0x58, 0x20}
+	metadata = append(append(metadata, make([]byte, 32)...), 0x00, 0x29)
+
+	contracts := map[string]*compiler.Contract{
+		"A": {Name: "A", RuntimeCode: append([]byte{0x60, 0x01}, metadata...)},
+		"B": {Name: "B", RuntimeCode: append([]byte{0x60, 0x02}, metadata...)},
+		"C": {Name: "C", RuntimeCode: []byte{0x60, 0x02}},
+	}
+
+	assert.Equal(t, matchContract(contracts, "", []byte{0x60, 0x01}), contracts["A"])
+	assert.Equal(t, matchContract(contracts, "", []byte{0x60, 0x02}), contracts["B"])
+	assert.Equal(t, matchContract(contracts, "C", []byte{0x60, 0x02}), contracts["C"])
+	assert.Equal(t, matchContract(contracts, "A", []byte{0x60, 0x02}) == nil, true)
+	assert.Equal(t, matchContract(contracts, "", []byte{0x60, 0x03}) == nil, true)
+}
+
+func Test_ContractVerifier_Get(t *testing.T) {
+	db := leveldb.NewMemDatabase()
+	defer db.Close()
+
+	verifier := NewContractVerifier(nil, db)
+	address := common.BytesToAddress([]byte{1, 2, 3})
+
+	verified, err := verifier.Get(address)
+	assert.NoError(t, err)
+	assert.Equal(t, verified == nil, true)
+
+	record := &VerifiedContract{
+		Address:  address,
+		Name:     "SimpleStorage",
+		Source:   "contract SimpleStorage {}",
+		Compiler: compiler.SolidityOptions{Version: "0.4.25+commit.59dbf8f1", Optimize: true, Runs: 200},
+		ABI:      "[]",
+		CodeHash: common.StringToHash("code"),
+		Height:   10,
+	}
+	encoded, err := json.Marshal(record)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Put(verifiedContractKey(address), encoded))
+
+	verified, err = verifier.Get(address)
+	assert.NoError(t, err)
+	assert.Equal(t, verified, record)
+
+	_, err = verifier.Verify(&ContractVerifyRequest{Address: address})
+	assert.Equal(t, err, errEmptySource)
+}
@@ -32,6 +32,9 @@ const (
 	// DebtManagerDir to-be-sent debt directory based on config.DataRoot
 	DebtManagerDir = "/db/debtManager"
 
+	// VerifiedContractDir verified contract source directory based on config.DataRoot
+	VerifiedContractDir = "/db/verifiedContract"
+
 	// BlockChainRecoveryPointFile is used to store the recovery point info of blockchain.
 	BlockChainRecoveryPointFile = "recoveryPoint.json"
 )
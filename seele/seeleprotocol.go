@@ -86,6 +86,9 @@ type SeeleProtocol struct {
 
 	debtManager *DebtManager
 	engine      consensus.Engine
+
+	// p2pServer is the server started the protocol, whose permission restricts the BFT messages
+	p2pServer *p2p.Server
 }
 
 // Downloader return a pointer of the downloader
@@ -323,7 +326,7 @@ func (p *SeeleProtocol) handleNewTx(e event.Event) {
 	peers := p.peerSet.getPeerByShard(shardId)
 	for _, peer := range peers {
 		if peer.knownTxs.Contains(tx.Hash) {
-			p.log.Debug("seeleprotocol handleNewTx: peer: %s already contains tx %s", peer.peerStrID, tx.Hash.String())
+			p.log.Sample("knownTx", 100).Debug("seeleprotocol handleNewTx: peer: %s already contains tx %s", peer.peerStrID, tx.Hash.String())
 			continue
 		}
 
@@ -493,7 +496,7 @@ handler:
 		}
 
 		// print transaction and debt pool length
-		p.log.Debug("handleMsg tx pool and debt pool length, tx %d, debt %d", p.txPool.GetTxCount(), p.debtPool.GetDebtCount(true, true))
+		p.log.Sample("poolLength", 100).Debug("handleMsg tx pool and debt pool length, tx %d, debt %d", p.txPool.GetTxCount(), p.debtPool.GetDebtCount(true, true))
 
 		// set time now
 		now := time.Now()
@@ -825,11 +828,32 @@ handler:
 	peer.Disconnect(fmt.Sprintf("called from seeleprotocol.handlemsg. id=%s", peer.peerStrID))
 }
 
+// filterVerifiers removes the targets which are not verifiers of the current snapshot
+func (sp *SeeleProtocol) filterVerifiers(targets map[common.Address]bool) map[common.Address]bool {
+	reader, ok := sp.engine.(consensus.VerifierReader)
+	if !ok {
+		return targets
+	}
+
+	verifiers := make(map[common.Address]bool)
+	for _, addr := range reader.CurrentVerifiers() {
+		if targets[addr] {
+			verifiers[addr] = true
+		}
+	}
+
+	return verifiers
+}
+
 func (p *SeeleProtocol) GetProtocolVersion() (uint, error) {
 	return p.Protocol.Version, nil
 }
 
 func (sp *SeeleProtocol) FindPeers(targets map[common.Address]bool) map[common.Address]consensus.Peer {
+	if sp.p2pServer != nil && sp.p2pServer.Permission().VerifiersOnly() {
+		targets = sp.filterVerifiers(targets)
+	}
+
 	m := make(map[common.Address]consensus.Peer)
 	for _, p := range sp.peerSet.getPeerByShard(common.LocalShardNumber) {
 		addr := p.Node.ID
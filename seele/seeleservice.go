@@ -54,7 +54,12 @@ type SeeleService struct {
 	indexAccountDBPath string
 	debtManagerDB      database.Database // database used to store debts in debt manager.
 	debtManagerDBPath  string
-	miner              *miner.Miner
+
+	verifiedContractDB     database.Database // database used to store the verified contract sources.
+	verifiedContractDBPath string
+	contractVerifier       *ContractVerifier
+
+	miner *miner.Miner
 
 	lastHeader               common.Hash
 	chainHeaderChangeChannel chan common.Hash
@@ -66,7 +71,8 @@ type SeeleService struct {
 
 // ServiceContext is a collection of service configuration inherited from node
 type ServiceContext struct {
-	DataDir string
+	DataDir  string
+	InMemory bool // keep all the data in memory, e.g. for the dev mode
 }
 
 // AccountStateDB return account state db
@@ -142,6 +148,11 @@ func NewSeeleService(ctx context.Context, conf *node.Config, log *log.SeeleLog,
 		return nil, err
 	}
 
+	// Initialize verified contract DB.
+	if err = s.initVerifiedContractDB(&serviceContext); err != nil {
+		return nil, err
+	}
+
 	if conf.BasicConfig.MinerAlgorithm == common.BFTSubchainEngine {
 		if err = s.initAccountIndexDB(&serviceContext); err != nil {
 			return nil, err
@@ -161,6 +172,8 @@ func NewSeeleService(ctx context.Context, conf *node.Config, log *log.SeeleLog,
 		return nil, err
 	}
 
+	s.contractVerifier = NewContractVerifier(s.chain, s.verifiedContractDB)
+
 	if s.seeleProtocol, err = NewSeeleProtocol(s, log, engine); err != nil {
 		s.Stop()
 		log.Error("failed to create seeleProtocol in NewSeeleService, %s", err)
@@ -170,11 +183,20 @@ func NewSeeleService(ctx context.Context, conf *node.Config, log *log.SeeleLog,
 	return s, nil
 }
 
+// openDB opens the leveldb in the path, or a memory database if the data is kept in memory.
+func openDB(serviceContext *ServiceContext, path string) (database.Database, error) {
+	if serviceContext.InMemory {
+		return leveldb.NewMemDatabase(), nil
+	}
+
+	return leveldb.NewLevelDB(path)
+}
+
 func (s *SeeleService) initBlockchainDB(serviceContext *ServiceContext) (err error) {
 	s.chainDBPath = filepath.Join(serviceContext.DataDir, BlockChainDir)
 	s.log.Info("NewSeeleService BlockChain datadir is %s", s.chainDBPath)
 
-	if s.chainDB, err = leveldb.NewLevelDB(s.chainDBPath); err != nil {
+	if s.chainDB, err = openDB(serviceContext, s.chainDBPath); err != nil {
 		s.log.Error("NewSeeleService Create BlockChain err. %s", err)
 		return err
 	}
@@ -186,7 +208,7 @@ func (s *SeeleService) initAccountStateDB(serviceContext *ServiceContext) (err e
 	s.accountStateDBPath = filepath.Join(serviceContext.DataDir, AccountStateDir)
 	s.log.Info("NewSeeleService account state datadir is %s", s.accountStateDBPath)
 
-	if s.accountStateDB, err = leveldb.NewLevelDB(s.accountStateDBPath); err != nil {
+	if s.accountStateDB, err = openDB(serviceContext, s.accountStateDBPath); err != nil {
 		s.Stop()
 		s.log.Error("NewSeeleService Create BlockChain err: failed to create account state DB, %s", err)
 		return err
@@ -199,7 +221,7 @@ func (s *SeeleService) initAccountIndexDB(serviceContext *ServiceContext) (err e
 	s.accountIndexDBPath = filepath.Join(serviceContext.DataDir, AccountIndexDir)
 	s.log.Info("NewSeeleService account index datadir is %s", s.accountIndexDBPath)
 
-	if s.accountIndexDB, err = leveldb.NewLevelDB(s.accountIndexDBPath); err != nil {
+	if s.accountIndexDB, err = openDB(serviceContext, s.accountIndexDBPath); err != nil {
 		s.Stop()
 		s.log.Error("NewSeeleService Create BlockChain err: failed to create account index DB, %s", err)
 		return err
@@ -212,7 +234,7 @@ func (s *SeeleService) initIndexAccountDB(serviceContext *ServiceContext) (err e
 	s.indexAccountDBPath = filepath.Join(serviceContext.DataDir, IndexAccountDir)
 	s.log.Info("NewSeeleService index account datadir is %s", s.indexAccountDBPath)
 
-	if s.indexAccountDB, err = leveldb.NewLevelDB(s.indexAccountDBPath); err != nil {
+	if s.indexAccountDB, err = openDB(serviceContext, s.indexAccountDBPath); err != nil {
 		s.Stop()
 		s.log.Error("NewSeeleService Create BlockChain err: failed to create index state DB, %s", err)
 		return err
@@ -229,7 +251,7 @@ func (s *SeeleService) initDebtManagerDB(serviceContext *ServiceContext) (err er
 	s.debtManagerDBPath = filepath.Join(serviceContext.DataDir, DebtManagerDir)
 	s.log.Info("NewSeeleService debt manager datadir is %s", s.debtManagerDBPath)
 
-	if s.debtManagerDB, err = leveldb.NewLevelDB(s.debtManagerDBPath); err != nil {
+	if s.debtManagerDB, err = openDB(serviceContext, s.debtManagerDBPath); err != nil {
 		s.Stop()
 		s.log.Error("NewSeeleService Create BlockChain err: failed to create debt manager DB, %s", err)
 		return err
@@ -238,6 +260,19 @@ func (s *SeeleService) initDebtManagerDB(serviceContext *ServiceContext) (err er
 	return nil
 }
 
+func (s *SeeleService) initVerifiedContractDB(serviceContext *ServiceContext) (err error) {
+	s.verifiedContractDBPath = filepath.Join(serviceContext.DataDir, VerifiedContractDir)
+	s.log.Info("NewSeeleService verified contract datadir is %s", s.verifiedContractDBPath)
+
+	if s.verifiedContractDB, err = openDB(serviceContext, s.verifiedContractDBPath); err != nil {
+		s.Stop()
+		s.log.Error("NewSeeleService Create BlockChain err: failed to create verified contract DB, %s", err)
+		return err
+	}
+
+	return nil
+}
+
 func (s *SeeleService) initGenesisAndChain(serviceContext *ServiceContext, conf *node.Config, startHeight int) (err error) {
 	bcStore := store.NewCachedStore(store.NewBlockchainDatabase(s.chainDB))
 	fmt.Printf("starting to getGenesis with GenesisConfig %+v", conf.SeeleConfig.GenesisConfig)
@@ -258,6 +293,9 @@ func (s *SeeleService) initGenesisAndChain(serviceContext *ServiceContext, conf
 	}
 
 	recoveryPointFile := filepath.Join(serviceContext.DataDir, BlockChainRecoveryPointFile)
+	if serviceContext.InMemory {
+		recoveryPointFile = ""
+	}
 	if s.chain, err = core.NewBlockchain(bcStore, s.accountStateDB, s.accountIndexDB, s.indexAccountDB, recoveryPointFile, s.miner.GetEngine(), s.debtVerifier, startHeight); err != nil {
 		s.Stop()
 		s.log.Error("failed to init chain in NewSeeleService. %s", err)
@@ -357,6 +395,7 @@ func (s *SeeleService) Protocols() (protos []p2p.Protocol) {
 // Start implements node.Service, starting goroutines needed by SeeleService.
 func (s *SeeleService) Start(srvr *p2p.Server) error {
 	s.p2pServer = srvr
+	s.seeleProtocol.p2pServer = srvr
 	s.seeleProtocol.Start()
 
 	return nil
@@ -387,6 +426,11 @@ func (s *SeeleService) Stop() error {
 		s.debtManagerDB = nil
 	}
 
+	if s.verifiedContractDB != nil {
+		s.verifiedContractDB.Close()
+		s.verifiedContractDB = nil
+	}
+
 	return nil
 }
 
@@ -425,6 +469,12 @@ func (s *SeeleService) APIs() (apis []rpc.API) {
 			Service:   NewTransactionPoolAPI(s),
 			Public:    true,
 		},
+		{
+			Namespace: "seele",
+			Version:   "1.0",
+			Service:   NewPrivateContractAPI(s),
+			Public:    false,
+		},
 	}...)
 
 	minerApis := s.miner.GetEngine().APIs(s.chain)
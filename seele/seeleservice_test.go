@@ -8,6 +8,7 @@ package seele
 import (
 	//"context"
 	"context"
+	"math/big"
 	"path/filepath"
 	"testing"
 
@@ -23,10 +24,21 @@ import (
 func getTmpConfig() *node.Config {
 	acctAddr := crypto.MustGenerateRandomAddress()
 
+	// the genesis requires the root accounts in the local shard
+	var rootAccounts []common.Address
+	for i := 0; i < common.SubChainRootAccount; i++ {
+		rootAccounts = append(rootAccounts, *crypto.MustGenerateShardAddress(1))
+	}
+
 	return &node.Config{
 		SeeleConfig: node.SeeleConfig{
 			TxConf:   *core.DefaultTxPoolConfig(),
 			Coinbase: *acctAddr,
+			GenesisConfig: core.GenesisInfo{
+				ShardNumber:  1,
+				Rootaccounts: rootAccounts,
+				Supply:       big.NewInt(0),
+			},
 		},
 	}
 }
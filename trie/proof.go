@@ -53,10 +53,9 @@ func (t *Trie) GetProof(key []byte) (map[string][]byte, error) {
 				return proof, fmt.Errorf("unhandled trie error: %s", err)
 			}
 		case *LeafNode:
+			// the leaf of another key is required to prove the absence of the key.
 			tn = nil
-			if len(key) >= len(n.Key) && bytes.Equal(n.Key, key[:len(n.Key)]) {
-				nodes = append(nodes, n)
-			}
+			nodes = append(nodes, n)
 		default:
 			panic(fmt.Sprintf("%T: invalid node: %v", tn, tn))
 		}
@@ -131,6 +131,28 @@ func BenchmarkProve(b *testing.B) {
 	}
 }
 
+func TestMissingKeyProof(t *testing.T) {
+	trie, _, dispose := randomTrie(500)
+	defer dispose()
+
+	root := trie.Hash()
+	for i := 0; i < 100; i++ {
+		key := randBytes(32)
+		proofs, err := trie.GetProof(key)
+		if err != nil {
+			t.Fatalf("failed to construct proof for missing key %x: %v", key, err)
+		}
+
+		val, err := VerifyProof(root, key, proofs)
+		if err != nil {
+			t.Fatalf("VerifyProof error for missing key %x: %v", key, err)
+		}
+		if val != nil {
+			t.Fatalf("VerifyProof returned value %x for missing key %x", val, key)
+		}
+	}
+}
+
 func BenchmarkVerifyProof(b *testing.B) {
 	trie, vals, dispose := randomTrie(100)
 	defer dispose()